@@ -25,6 +25,14 @@ type Client interface {
 	Proposals() ([]Proposal, error)
 	ProposalById(id string) (Proposal, error)
 	ProposalTally(id string) (Tally, error)
+
+	// State queries at a specific height, used to bootstrap indexing from a height other than genesis
+	ValidatorsAtHeight(height int64) ([]Validator, error)
+	ValidatorDelegationsAtHeight(validatorAddress string, height int64) ([]DelegationResponse, error)
+	AccountAtHeight(accountAddress string, height int64) (*Account, error)
+	BalancesAtHeight(accountAddress string, height int64) (coin.Coins, error)
+	GovParamsAtHeight(height int64) (*GovParams, error)
+	ProposalsAtHeight(height int64) ([]Proposal, error)
 }
 
 var ErrAccountNotFound = errors.New("account not found")
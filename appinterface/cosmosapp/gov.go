@@ -0,0 +1,27 @@
+package cosmosapp
+
+type GovParams struct {
+	DepositParams GovDepositParams `json:"deposit_params"`
+	VotingParams  GovVotingParams  `json:"voting_params"`
+	TallyParams   GovTallyParams   `json:"tally_params"`
+}
+
+type GovDepositParams struct {
+	MinDeposit       []MinDeposit `json:"min_deposit"`
+	MaxDepositPeriod string       `json:"max_deposit_period"`
+}
+
+type MinDeposit struct {
+	Denom  string `json:"denom"`
+	Amount string `json:"amount"`
+}
+
+type GovVotingParams struct {
+	VotingPeriod string `json:"voting_period"`
+}
+
+type GovTallyParams struct {
+	Quorum        string `json:"quorum"`
+	Threshold     string `json:"threshold"`
+	VetoThreshold string `json:"veto_threshold"`
+}
@@ -93,3 +93,41 @@ func (conn *MockClient) ProposalTally(id string) (Tally, error) {
 	result, _ := mockArgs.Get(0).(Tally)
 	return result, mockArgs.Error(1)
 }
+
+func (conn *MockClient) ValidatorsAtHeight(height int64) ([]Validator, error) {
+	mockArgs := conn.Called(height)
+	result, _ := mockArgs.Get(0).([]Validator)
+	return result, mockArgs.Error(1)
+}
+
+func (conn *MockClient) ValidatorDelegationsAtHeight(
+	validatorAddress string, height int64,
+) ([]DelegationResponse, error) {
+	mockArgs := conn.Called(validatorAddress, height)
+	result, _ := mockArgs.Get(0).([]DelegationResponse)
+	return result, mockArgs.Error(1)
+}
+
+func (conn *MockClient) AccountAtHeight(accountAddress string, height int64) (*Account, error) {
+	mockArgs := conn.Called(accountAddress, height)
+	result, _ := mockArgs.Get(0).(*Account)
+	return result, mockArgs.Error(1)
+}
+
+func (conn *MockClient) BalancesAtHeight(accountAddress string, height int64) (coin.Coins, error) {
+	mockArgs := conn.Called(accountAddress, height)
+	result, _ := mockArgs.Get(0).(coin.Coins)
+	return result, mockArgs.Error(1)
+}
+
+func (conn *MockClient) GovParamsAtHeight(height int64) (*GovParams, error) {
+	mockArgs := conn.Called(height)
+	result, _ := mockArgs.Get(0).(*GovParams)
+	return result, mockArgs.Error(1)
+}
+
+func (conn *MockClient) ProposalsAtHeight(height int64) ([]Proposal, error) {
+	mockArgs := conn.Called(height)
+	result, _ := mockArgs.Get(0).([]Proposal)
+	return result, mockArgs.Error(1)
+}
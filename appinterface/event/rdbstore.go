@@ -1,8 +1,10 @@
 package event
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"strconv"
 
 	sq "github.com/Masterminds/squirrel"
 
@@ -12,6 +14,9 @@ import (
 
 const DEFAULT_TABLE = "events"
 
+// LATEST_HEIGHT_CHANNEL is the notification channel of the latest event height
+const LATEST_HEIGHT_CHANNEL = "events_latest_height"
+
 // Events table should have the following schema
 // | Field   | Data Type | Constraint  |
 // | ------- | --------- | ----------- |
@@ -20,15 +25,19 @@ const DEFAULT_TABLE = "events"
 // | name    | VARCHAR   | NOT NULL    |
 // | version | INT64     | NOT NULL    |
 // | payload | JSONB     | NOT NULL    |
+// | digest  | INT64     | NULL        |
 
 var _ entity_event.Store = &RDbStore{}
+var _ entity_event.LatestHeightSubscriber = &RDbStore{}
+var _ entity_event.RangeStore = &RDbStore{}
 
 // EventStore implemented using relational database
 type RDbStore struct {
 	rdbHandle *rdb.Handle
 	Registry  *entity_event.Registry
 
-	table string
+	table         string
+	maybeListener rdb.Listener
 }
 
 func NewRDbStore(handle *rdb.Handle, registry *entity_event.Registry) *RDbStore {
@@ -40,6 +49,72 @@ func NewRDbStore(handle *rdb.Handle, registry *entity_event.Registry) *RDbStore
 	}
 }
 
+// WithListener enables subscribing to the latest event height notified by NotifyLatestHeightWithRDbHandle
+func (store *RDbStore) WithListener(listener rdb.Listener) *RDbStore {
+	store.maybeListener = listener
+	return store
+}
+
+// NotifyLatestHeightWithRDbHandle notifies the subscribers of the latest event height. When the handle is of a
+// transaction, the notification is delivered only after the transaction is committed.
+func (store *RDbStore) NotifyLatestHeightWithRDbHandle(rdbHandle *rdb.Handle, height int64) error {
+	sql, args, err := rdbHandle.StmtBuilder.Select().Column(
+		sq.Expr("pg_notify(?, ?)", LATEST_HEIGHT_CHANNEL, strconv.FormatInt(height, 10)),
+	).ToSql()
+	if err != nil {
+		return fmt.Errorf("error building latest event height notification SQL: %v", err)
+	}
+
+	if _, err = rdbHandle.Exec(sql, args...); err != nil {
+		return fmt.Errorf("error executing latest event height notification SQL: %v", err)
+	}
+
+	return nil
+}
+
+// SubscribeLatestHeight returns a channel receiving the latest event height notified. Only the latest height is kept
+// when the receiver is falling behind. Returns ErrLatestHeightSubscriptionUnsupported when the connection cannot
+// listen, in which case the latest height should be polled instead.
+func (store *RDbStore) SubscribeLatestHeight(ctx context.Context) (<-chan int64, error) {
+	if store.maybeListener == nil {
+		return nil, entity_event.ErrLatestHeightSubscriptionUnsupported
+	}
+
+	latestHeightCh := make(chan int64, 1)
+	listenDoneCh, err := store.maybeListener.Listen(ctx, LATEST_HEIGHT_CHANNEL, func(payload string) {
+		height, parseErr := strconv.ParseInt(payload, 10, 64)
+		if parseErr != nil {
+			return
+		}
+		for {
+			select {
+			case latestHeightCh <- height:
+				return
+			default:
+			}
+			// Drop the stale height not yet received
+			select {
+			case <-latestHeightCh:
+			default:
+			}
+		}
+	})
+	if err != nil {
+		if errors.Is(err, rdb.ErrListenUnsupported) {
+			return nil, entity_event.ErrLatestHeightSubscriptionUnsupported
+		}
+		return nil, fmt.Errorf("error listening to latest event height notification: %v", err)
+	}
+
+	go func() {
+		defer close(latestHeightCh)
+		// The handler is no longer called once the listening has ended
+		<-listenDoneCh
+	}()
+
+	return latestHeightCh, nil
+}
+
 // GetLatestHeight returns latest event height, nil if no event is stored
 func (store *RDbStore) GetLatestHeight() (*int64, error) {
 	sql, args, err := store.rdbHandle.StmtBuilder.Select(
@@ -110,7 +185,67 @@ func (store *RDbStore) GetAllByHeight(height int64) ([]entity_event.Event, error
 	return events, nil
 }
 
+// StreamByHeightRange calls the handler with each event of the event names in the heights from fromHeight to toHeight
+// inclusively, in height order. The event names are filtered in the query so other events are never decoded.
+func (store *RDbStore) StreamByHeightRange(
+	fromHeight int64,
+	toHeight int64,
+	eventNames []string,
+	handler func(event entity_event.Event) error,
+) error {
+	sql, args, err := store.rdbHandle.StmtBuilder.Select(
+		"uuid", "height", "name", "version", "payload",
+	).From(
+		store.table,
+	).Where(sq.And{
+		sq.GtOrEq{"height": fromHeight},
+		sq.LtOrEq{"height": toHeight},
+		sq.Eq{"name": eventNames},
+	}).OrderBy("height", "id").ToSql()
+	if err != nil {
+		return fmt.Errorf("error building events by height range selection SQL: %v", err)
+	}
+
+	rows, err := store.rdbHandle.Query(sql, args...)
+	if err != nil {
+		return fmt.Errorf("error executing events by height range selection SQL: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			uuid    string
+			height  int64
+			name    string
+			version int
+			payload string
+		)
+
+		if err := rows.Scan(&uuid, &height, &name, &version, &payload); err != nil {
+			return fmt.Errorf("error scanning event of height range: %v", err)
+		}
+
+		event, err := store.Registry.DecodeByType(name, version, []byte(payload))
+		if err != nil {
+			return fmt.Errorf("error decoding the event string into type: %v", err)
+		}
+
+		if err := handler(event); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating events of height range: %v", err)
+	}
+
+	return nil
+}
+
 func (store *RDbStore) Insert(event entity_event.Event) error {
+	if err := store.checkDigestTipWithRDbHandle(store.rdbHandle, []int64{event.Height()}); err != nil {
+		return err
+	}
+
 	encodedEvent, err := event.ToJSON()
 	if err != nil {
 		return fmt.Errorf("error encoding event to json: %v", err)
@@ -138,7 +273,7 @@ func (store *RDbStore) Insert(event entity_event.Event) error {
 		return errors.New("error executing event insertion SQL: no rows inserted")
 	}
 
-	return nil
+	return store.insertDigestsWithRDbHandle(store.rdbHandle, []int64{event.Height()})
 }
 
 // InsertAll insert all events into store. It will rollback when the insert fails at any point.
@@ -151,6 +286,13 @@ func (store *RDbStore) InsertAllWithRDbHandle(rdbHandle *rdb.Handle, events []en
 	if len(events) == 0 {
 		return nil
 	}
+	heights := make([]int64, 0, len(events))
+	for _, event := range events {
+		heights = append(heights, event.Height())
+	}
+	if err := store.checkDigestTipWithRDbHandle(rdbHandle, heights); err != nil {
+		return err
+	}
 
 	pendingRowCount := 0
 	var stmtBuilder sq.InsertBuilder
@@ -194,5 +336,93 @@ func (store *RDbStore) InsertAllWithRDbHandle(rdbHandle *rdb.Handle, events []en
 		}
 	}
 
-	return nil
+	return store.insertDigestsWithRDbHandle(rdbHandle, heights)
+}
+
+// OutboxEvent is a committed event together with its insertion sequence id
+type OutboxEvent struct {
+	Id      int64
+	UUID    string
+	Height  int64
+	Name    string
+	Version int
+	// Payload is the event JSON as stored
+	Payload string
+	// Event is the decoded event, nil when the payload fails to be decoded
+	Event entity_event.Event
+	// DecodeErr is the error decoding the payload, such that an undecodable event does not block the events after it
+	DecodeErr error
+}
+
+// ListAfter returns at most limit committed events after the event of afterId at afterHeight, ordered by height then
+// insertion sequence id. The ids are allocated before the events are committed, so an event with a lower id may be
+// committed after one with a higher id. The events of a height are committed together and the heights are committed
+// in ascending order though, so no event committed later is ordered before the events already returned. Only the
+// events of the event names are returned unless the event names are empty.
+func (store *RDbStore) ListAfter(
+	afterHeight int64,
+	afterId int64,
+	eventNames []string,
+	limit uint64,
+) ([]OutboxEvent, error) {
+	conditions := sq.And{sq.Or{
+		sq.Gt{"height": afterHeight},
+		sq.And{sq.Eq{"height": afterHeight}, sq.Gt{"id": afterId}},
+	}}
+	if len(eventNames) > 0 {
+		conditions = append(conditions, sq.Eq{"name": eventNames})
+	}
+	sql, args, err := store.rdbHandle.StmtBuilder.Select(
+		"id", "uuid", "height", "name", "version", "payload",
+	).From(
+		store.table,
+	).Where(
+		conditions,
+	).OrderBy("height", "id").Limit(limit).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building events after cursor selection SQL: %v", err)
+	}
+
+	rows, err := store.rdbHandle.Query(sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error executing events after cursor selection SQL: %v", err)
+	}
+	defer rows.Close()
+
+	outboxEvents := make([]OutboxEvent, 0)
+	for rows.Next() {
+		var (
+			id      int64
+			uuid    string
+			height  int64
+			name    string
+			version int
+			payload string
+		)
+		if err := rows.Scan(&id, &uuid, &height, &name, &version, &payload); err != nil {
+			return nil, fmt.Errorf("error scanning event after cursor: %v", err)
+		}
+
+		outboxEvent := OutboxEvent{
+			Id:      id,
+			UUID:    uuid,
+			Height:  height,
+			Name:    name,
+			Version: version,
+			Payload: payload,
+		}
+		event, err := store.Registry.DecodeByType(name, version, []byte(payload))
+		if err != nil {
+			outboxEvent.DecodeErr = fmt.Errorf("error decoding the event string into type: %v", err)
+		} else {
+			outboxEvent.Event = event
+		}
+
+		outboxEvents = append(outboxEvents, outboxEvent)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating events after cursor: %v", err)
+	}
+
+	return outboxEvents, nil
 }
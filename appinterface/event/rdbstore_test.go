@@ -1,6 +1,10 @@
 package event_test
 
 import (
+	"context"
+	"errors"
+	"fmt"
+
 	"github.com/crypto-com/chain-indexing/appinterface/rdb"
 	"github.com/crypto-com/chain-indexing/entity/event/test"
 	"github.com/crypto-com/chain-indexing/external/primptr"
@@ -75,5 +79,139 @@ var _ = Describe("RdbEventStore", func() {
 				Expect(latestHeight).To(Equal(primptr.Int64(1)))
 			})
 		})
+
+		Describe("VerifyDigests", func() {
+			It("should return no inconsistent height when the digest chain is consistent", func() {
+				store := appinterface_event.NewRDbStore(pgxConn.ToHandle(), newArchivedEventRegistry())
+
+				Expect(store.InsertAll([]event.Event{newArchivedEvent(1), newArchivedEvent(2)})).To(Succeed())
+				Expect(store.InsertAll([]event.Event{newArchivedEvent(3)})).To(Succeed())
+
+				actual, err := store.VerifyDigests(context.Background())
+				Expect(err).To(BeNil())
+				Expect(actual).To(Equal(&appinterface_event.DigestVerification{}))
+			})
+
+			It("should reject events inserted below the digest tip", func() {
+				store := appinterface_event.NewRDbStore(pgxConn.ToHandle(), newArchivedEventRegistry())
+
+				Expect(store.InsertAll([]event.Event{newArchivedEvent(1), newArchivedEvent(3)})).To(Succeed())
+
+				err := store.InsertAll([]event.Event{newArchivedEvent(2)})
+				Expect(errors.Is(err, appinterface_event.ErrInsertBelowDigestTip)).To(BeTrue())
+				actual, err := store.GetAllByHeight(2)
+				Expect(err).To(BeNil())
+				Expect(actual).To(BeEmpty())
+			})
+
+			It("should return the first height with tampered events", func() {
+				store := appinterface_event.NewRDbStore(pgxConn.ToHandle(), newArchivedEventRegistry())
+
+				Expect(store.InsertAll([]event.Event{
+					newArchivedEvent(1), newArchivedEvent(2), newArchivedEvent(3),
+				})).To(Succeed())
+
+				_, err := pgxConn.Exec(
+					"UPDATE events SET payload = jsonb_set(payload, '{value}', '\"tampered\"') WHERE height >= 2",
+				)
+				Expect(err).To(BeNil())
+
+				actual, err := store.VerifyDigests(context.Background())
+				Expect(err).To(BeNil())
+				Expect(actual.MaybeInconsistentHeight).To(Equal(primptr.Int64(2)))
+			})
+
+			It("should not re-bless tampered events when more events are inserted", func() {
+				store := appinterface_event.NewRDbStore(pgxConn.ToHandle(), newArchivedEventRegistry())
+
+				Expect(store.InsertAll([]event.Event{newArchivedEvent(1), newArchivedEvent(2)})).To(Succeed())
+				_, err := pgxConn.Exec(
+					"UPDATE events SET payload = jsonb_set(payload, '{value}', '\"tampered\"') WHERE height = 1",
+				)
+				Expect(err).To(BeNil())
+				Expect(store.InsertAll([]event.Event{newArchivedEvent(3)})).To(Succeed())
+
+				actual, err := store.VerifyDigests(context.Background())
+				Expect(err).To(BeNil())
+				Expect(actual.MaybeInconsistentHeight).To(Equal(primptr.Int64(1)))
+			})
+
+			It("should report the heights stored before digests as unverified", func() {
+				store := appinterface_event.NewRDbStore(pgxConn.ToHandle(), newArchivedEventRegistry())
+
+				// Events stored before digests were introduced
+				Expect(store.InsertAll([]event.Event{newArchivedEvent(1), newArchivedEvent(2)})).To(Succeed())
+				_, err := pgxConn.Exec("UPDATE events SET digest = NULL")
+				Expect(err).To(BeNil())
+				Expect(store.InsertAll([]event.Event{newArchivedEvent(3)})).To(Succeed())
+
+				actual, err := store.VerifyDigests(context.Background())
+				Expect(err).To(BeNil())
+				Expect(actual).To(Equal(&appinterface_event.DigestVerification{
+					MaybeUnverifiedFromHeight: primptr.Int64(1),
+					MaybeUnverifiedToHeight:   primptr.Int64(2),
+				}))
+			})
+		})
+	})
+})
+
+type fakeListener struct {
+	err       error
+	handlerCh chan func(payload string)
+	doneCh    chan error
+}
+
+func (listener *fakeListener) Listen(
+	_ context.Context,
+	_ string,
+	handler func(payload string),
+) (<-chan error, error) {
+	if listener.err != nil {
+		return nil, listener.err
+	}
+	listener.handlerCh <- handler
+	return listener.doneCh, nil
+}
+
+var _ = Describe("RdbEventStore", func() {
+	Describe("SubscribeLatestHeight", func() {
+		It("should return ErrLatestHeightSubscriptionUnsupported when the connection cannot listen", func() {
+			store := appinterface_event.NewRDbStore(nil, event.NewRegistry()).WithListener(&fakeListener{
+				err: fmt.Errorf("error listening to channel: %w", rdb.ErrListenUnsupported),
+			})
+
+			_, err := store.SubscribeLatestHeight(context.Background())
+			Expect(errors.Is(err, event.ErrLatestHeightSubscriptionUnsupported)).To(BeTrue())
+		})
+
+		It("should return Error when it fails to listen", func() {
+			store := appinterface_event.NewRDbStore(nil, event.NewRegistry()).WithListener(&fakeListener{
+				err: errors.New("any error"),
+			})
+
+			_, err := store.SubscribeLatestHeight(context.Background())
+			Expect(err).NotTo(BeNil())
+			Expect(errors.Is(err, event.ErrLatestHeightSubscriptionUnsupported)).To(BeFalse())
+		})
+
+		It("should receive the notified heights until the listening ends", func() {
+			listener := &fakeListener{
+				handlerCh: make(chan func(payload string), 1),
+				doneCh:    make(chan error, 1),
+			}
+			store := appinterface_event.NewRDbStore(nil, event.NewRegistry()).WithListener(listener)
+
+			latestHeightCh, err := store.SubscribeLatestHeight(context.Background())
+			Expect(err).To(BeNil())
+
+			handler := <-listener.handlerCh
+			handler("1")
+			handler("2")
+			Expect(<-latestHeightCh).To(Equal(int64(2)))
+
+			listener.doneCh <- errors.New("connection lost")
+			Eventually(latestHeightCh).Should(BeClosed())
+		})
 	})
 })
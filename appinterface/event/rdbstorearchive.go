@@ -0,0 +1,397 @@
+package event
+
+import (
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/crypto-com/chain-indexing/appinterface/rdb"
+)
+
+// DEFAULT_ARCHIVE_CHUNK_SIZE is the number of heights exported to each archive chunk
+const DEFAULT_ARCHIVE_CHUNK_SIZE = 10000
+
+const ARCHIVE_CHUNK_FILE_SUFFIX = ".ndjson.gz"
+const ARCHIVE_MANIFEST_FILE_SUFFIX = ".manifest.json"
+
+// ARCHIVE_TEMP_FILE_SUFFIX is the suffix of the chunk and manifest files being exported
+const ARCHIVE_TEMP_FILE_SUFFIX = ".tmp"
+
+// ArchiveManifest describes an archive chunk of the events of a height range
+type ArchiveManifest struct {
+	// File is the name of the gzip-compressed NDJSON chunk file in the same directory
+	File       string `json:"file"`
+	FromHeight int64  `json:"fromHeight"`
+	ToHeight   int64  `json:"toHeight"`
+	EventCount int64  `json:"eventCount"`
+	// EventCountByName is the number of events of each event name
+	EventCountByName map[string]int64 `json:"eventCountByName"`
+	// Checksum is the hex-encoded SHA-256 digest of the chunk file
+	Checksum string `json:"checksum"`
+}
+
+// ArchivedEvent is a line of the NDJSON chunk. The payload is the raw stored JSON.
+type ArchivedEvent struct {
+	UUID    string          `json:"uuid"`
+	Height  int64           `json:"height"`
+	Name    string          `json:"name"`
+	Version int             `json:"version"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// Export streams the events from fromHeight to toHeight inclusively to gzip-compressed NDJSON chunk files of chunkSize
+// heights in the directory, each along with its manifest. Returns the manifests of the chunks written.
+func (store *RDbStore) Export(
+	directory string,
+	fromHeight int64,
+	toHeight int64,
+	chunkSize int64,
+) ([]ArchiveManifest, error) {
+	if chunkSize <= 0 {
+		chunkSize = DEFAULT_ARCHIVE_CHUNK_SIZE
+	}
+	if err := os.MkdirAll(directory, 0755); err != nil {
+		return nil, fmt.Errorf("error creating archive directory: %v", err)
+	}
+
+	manifests := make([]ArchiveManifest, 0)
+	for chunkFromHeight := fromHeight; chunkFromHeight <= toHeight; chunkFromHeight += chunkSize {
+		chunkToHeight := chunkFromHeight + chunkSize - 1
+		if chunkToHeight > toHeight {
+			chunkToHeight = toHeight
+		}
+
+		manifest, err := store.exportChunk(directory, chunkFromHeight, chunkToHeight)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"error exporting events from height %d to %d: %v", chunkFromHeight, chunkToHeight, err,
+			)
+		}
+		manifests = append(manifests, *manifest)
+	}
+
+	return manifests, nil
+}
+
+func (store *RDbStore) exportChunk(directory string, fromHeight int64, toHeight int64) (*ArchiveManifest, error) {
+	chunkName := fmt.Sprintf("events-%012d-%012d", fromHeight, toHeight)
+	manifest := ArchiveManifest{
+		File:             chunkName + ARCHIVE_CHUNK_FILE_SUFFIX,
+		FromHeight:       fromHeight,
+		ToHeight:         toHeight,
+		EventCountByName: make(map[string]int64),
+	}
+
+	// Chunk and manifest are written to temporary files first and only renamed once complete, so that an interrupted
+	// export never leaves a partial chunk behind
+	chunkFile, err := ioutil.TempFile(directory, manifest.File+".*"+ARCHIVE_TEMP_FILE_SUFFIX)
+	if err != nil {
+		return nil, fmt.Errorf("error creating chunk file: %v", err)
+	}
+	defer removeTempFile(chunkFile.Name())
+	defer chunkFile.Close()
+
+	hasher := sha256.New()
+	gzipWriter := gzip.NewWriter(io.MultiWriter(chunkFile, hasher))
+	jsonEncoder := json.NewEncoder(gzipWriter)
+
+	sql, args, err := store.rdbHandle.StmtBuilder.Select(
+		"uuid", "height", "name", "version", "payload",
+	).From(
+		store.table,
+	).Where(sq.And{
+		sq.GtOrEq{"height": fromHeight},
+		sq.LtOrEq{"height": toHeight},
+	}).OrderBy("height", "id").ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building events export selection SQL: %v", err)
+	}
+
+	rows, err := store.rdbHandle.Query(sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error executing events export selection SQL: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			archivedEvent ArchivedEvent
+			payload       string
+		)
+		if err := rows.Scan(
+			&archivedEvent.UUID, &archivedEvent.Height, &archivedEvent.Name, &archivedEvent.Version, &payload,
+		); err != nil {
+			return nil, fmt.Errorf("error scanning event to export: %v", err)
+		}
+		archivedEvent.Payload = json.RawMessage(payload)
+
+		if err := jsonEncoder.Encode(&archivedEvent); err != nil {
+			return nil, fmt.Errorf("error writing event to chunk file: %v", err)
+		}
+		manifest.EventCount += 1
+		manifest.EventCountByName[archivedEvent.Name] += 1
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating events to export: %v", err)
+	}
+
+	if err := gzipWriter.Close(); err != nil {
+		return nil, fmt.Errorf("error closing chunk file compression: %v", err)
+	}
+	if err := chunkFile.Close(); err != nil {
+		return nil, fmt.Errorf("error closing chunk file: %v", err)
+	}
+	manifest.Checksum = hex.EncodeToString(hasher.Sum(nil))
+
+	rawManifest, err := json.MarshalIndent(&manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error encoding chunk manifest: %v", err)
+	}
+	manifestFile, err := ioutil.TempFile(
+		directory, chunkName+ARCHIVE_MANIFEST_FILE_SUFFIX+".*"+ARCHIVE_TEMP_FILE_SUFFIX,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error creating chunk manifest: %v", err)
+	}
+	defer removeTempFile(manifestFile.Name())
+	defer manifestFile.Close()
+	if _, err := manifestFile.Write(rawManifest); err != nil {
+		return nil, fmt.Errorf("error writing chunk manifest: %v", err)
+	}
+	if err := manifestFile.Close(); err != nil {
+		return nil, fmt.Errorf("error closing chunk manifest: %v", err)
+	}
+
+	// Manifest is renamed last as the chunks are imported by their manifests
+	if err := renameTempFile(chunkFile.Name(), filepath.Join(directory, manifest.File)); err != nil {
+		return nil, fmt.Errorf("error renaming chunk file: %v", err)
+	}
+	if err := renameTempFile(
+		manifestFile.Name(), filepath.Join(directory, chunkName+ARCHIVE_MANIFEST_FILE_SUFFIX),
+	); err != nil {
+		return nil, fmt.Errorf("error renaming chunk manifest: %v", err)
+	}
+
+	return &manifest, nil
+}
+
+// renameTempFile moves the complete temporary file to its path, readable like the files created by os.Create
+func renameTempFile(tempPath string, path string) error {
+	if err := os.Chmod(tempPath, 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(tempPath, path)
+}
+
+// removeTempFile removes the temporary file unless it has been renamed
+func removeTempFile(tempPath string) {
+	_ = os.Remove(tempPath)
+}
+
+// Import inserts the events of all the archive chunks in the directory in height order. Each chunk is verified against
+// its manifest and every event is decoded with the Registry before any event of the chunk is inserted. The events of
+// each chunk are inserted in a single transaction of the connection, so that a failed chunk leaves no event behind.
+// Returns the number of events imported.
+func (store *RDbStore) Import(rdbConn rdb.Conn, directory string) (int64, error) {
+	manifests, err := ReadArchiveManifests(directory)
+	if err != nil {
+		return 0, err
+	}
+
+	importedCount := int64(0)
+	for _, manifest := range manifests {
+		archivedEvents, err := store.readChunk(directory, manifest)
+		if err != nil {
+			return importedCount, fmt.Errorf("error verifying chunk %s: %v", manifest.File, err)
+		}
+		if err := store.importChunk(rdbConn, archivedEvents); err != nil {
+			return importedCount, fmt.Errorf("error importing chunk %s: %v", manifest.File, err)
+		}
+		importedCount += int64(len(archivedEvents))
+	}
+
+	return importedCount, nil
+}
+
+func (store *RDbStore) importChunk(rdbConn rdb.Conn, archivedEvents []ArchivedEvent) error {
+	tx, err := rdbConn.Begin()
+	if err != nil {
+		return fmt.Errorf("error when beginning transaction: %v", err)
+	}
+
+	if err := store.insertArchivedEventsWithRDbHandle(tx.ToHandle(), archivedEvents); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing archived events insertion: %v", err)
+	}
+	return nil
+}
+
+// ReadArchiveManifests returns the manifests of all the archive chunks in the directory in height order
+func ReadArchiveManifests(directory string) ([]ArchiveManifest, error) {
+	files, err := ioutil.ReadDir(directory)
+	if err != nil {
+		return nil, fmt.Errorf("error reading archive directory: %v", err)
+	}
+
+	manifests := make([]ArchiveManifest, 0)
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ARCHIVE_MANIFEST_FILE_SUFFIX) {
+			continue
+		}
+
+		rawManifest, err := ioutil.ReadFile(filepath.Join(directory, file.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("error reading chunk manifest %s: %v", file.Name(), err)
+		}
+		var manifest ArchiveManifest
+		if err := json.Unmarshal(rawManifest, &manifest); err != nil {
+			return nil, fmt.Errorf("error decoding chunk manifest %s: %v", file.Name(), err)
+		}
+		manifests = append(manifests, manifest)
+	}
+	sort.Slice(manifests, func(i, j int) bool {
+		return manifests[i].FromHeight < manifests[j].FromHeight
+	})
+
+	return manifests, nil
+}
+
+// readChunk reads the events of the chunk after verifying the checksum, the heights, the event counts and decoding
+// every event with the Registry. The chunk file is decoded as it is read and hashed along the way.
+func (store *RDbStore) readChunk(directory string, manifest ArchiveManifest) ([]ArchivedEvent, error) {
+	chunkFile, err := os.Open(filepath.Join(directory, manifest.File))
+	if err != nil {
+		return nil, fmt.Errorf("error opening chunk file: %v", err)
+	}
+	defer chunkFile.Close()
+
+	hasher := sha256.New()
+	chunkReader := io.TeeReader(bufio.NewReader(chunkFile), hasher)
+	archivedEvents, decodeErr := store.decodeChunk(chunkReader, manifest)
+
+	// The rest of the file is hashed as well, so that a tampered file is reported as such even if it fails to decode
+	if _, err := io.Copy(ioutil.Discard, chunkReader); err != nil {
+		return nil, fmt.Errorf("error reading chunk file: %v", err)
+	}
+	if hex.EncodeToString(hasher.Sum(nil)) != manifest.Checksum {
+		return nil, errors.New("mismatched chunk checksum")
+	}
+	if decodeErr != nil {
+		return nil, decodeErr
+	}
+
+	return archivedEvents, nil
+}
+
+// decodeChunk decodes the gzip-compressed NDJSON events of the chunk and verifies them against the manifest
+func (store *RDbStore) decodeChunk(chunkReader io.Reader, manifest ArchiveManifest) ([]ArchivedEvent, error) {
+	gzipReader, err := gzip.NewReader(chunkReader)
+	if err != nil {
+		return nil, fmt.Errorf("error opening chunk file decompression: %v", err)
+	}
+	defer gzipReader.Close()
+
+	archivedEvents := make([]ArchivedEvent, 0, manifest.EventCount)
+	eventCountByName := make(map[string]int64)
+	jsonDecoder := json.NewDecoder(gzipReader)
+	for {
+		var archivedEvent ArchivedEvent
+		if err := jsonDecoder.Decode(&archivedEvent); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("error decoding archived event: %v", err)
+		}
+		if archivedEvent.Height < manifest.FromHeight || archivedEvent.Height > manifest.ToHeight {
+			return nil, fmt.Errorf("event %s at height %d out of chunk height range", archivedEvent.UUID, archivedEvent.Height)
+		}
+
+		event, err := store.Registry.DecodeByType(archivedEvent.Name, archivedEvent.Version, archivedEvent.Payload)
+		if err != nil {
+			return nil, fmt.Errorf("error verifying event %s: %v", archivedEvent.UUID, err)
+		}
+		if event.UUID() != archivedEvent.UUID || event.Height() != archivedEvent.Height {
+			return nil, fmt.Errorf("event %s mismatched with its payload", archivedEvent.UUID)
+		}
+
+		archivedEvents = append(archivedEvents, archivedEvent)
+		eventCountByName[archivedEvent.Name] += 1
+	}
+
+	if int64(len(archivedEvents)) != manifest.EventCount {
+		return nil, fmt.Errorf("mismatched event count: expected %d, got %d", manifest.EventCount, len(archivedEvents))
+	}
+	for name, count := range manifest.EventCountByName {
+		if eventCountByName[name] != count {
+			return nil, fmt.Errorf(
+				"mismatched count of event %s: expected %d, got %d", name, count, eventCountByName[name],
+			)
+		}
+	}
+
+	return archivedEvents, nil
+}
+
+// insertArchivedEventsWithRDbHandle inserts the archived events keeping their stored version and payload
+func (store *RDbStore) insertArchivedEventsWithRDbHandle(rdbHandle *rdb.Handle, archivedEvents []ArchivedEvent) error {
+	heights := make([]int64, 0, len(archivedEvents))
+	for _, archivedEvent := range archivedEvents {
+		heights = append(heights, archivedEvent.Height)
+	}
+	if err := store.checkDigestTipWithRDbHandle(rdbHandle, heights); err != nil {
+		return err
+	}
+
+	for start := 0; start < len(archivedEvents); start += 500 {
+		end := start + 500
+		if end > len(archivedEvents) {
+			end = len(archivedEvents)
+		}
+
+		stmtBuilder := rdbHandle.StmtBuilder.Insert(
+			store.table,
+		).Columns(
+			"uuid", "height", "name", "version", "payload",
+		)
+		for _, archivedEvent := range archivedEvents[start:end] {
+			stmtBuilder = stmtBuilder.Values(
+				archivedEvent.UUID,
+				archivedEvent.Height,
+				archivedEvent.Name,
+				archivedEvent.Version,
+				string(archivedEvent.Payload),
+			)
+		}
+		sql, args, err := stmtBuilder.ToSql()
+		if err != nil {
+			return fmt.Errorf("error building archived event insertion SQL: %v", err)
+		}
+
+		execResult, err := rdbHandle.Exec(sql, args...)
+		if err != nil {
+			return fmt.Errorf("error exectuing archived event insertion SQL: %v", err)
+		}
+		if execResult.RowsAffected() != int64(end-start) {
+			return errors.New("error executing archived event insertion SQL: mismatched number of rows inserted")
+		}
+	}
+
+	return store.insertDigestsWithRDbHandle(rdbHandle, heights)
+}
@@ -0,0 +1,279 @@
+package event_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	appinterface_event "github.com/crypto-com/chain-indexing/appinterface/event"
+	"github.com/crypto-com/chain-indexing/appinterface/rdb"
+	"github.com/crypto-com/chain-indexing/entity/event"
+	"github.com/crypto-com/chain-indexing/infrastructure/pg"
+	. "github.com/crypto-com/chain-indexing/test"
+)
+
+var _ = Describe("RdbEventStore archive", func() {
+	WithTestPgxConn(func(pgxConn *pg.PgxConn, migrate rdb.Migrate) {
+		var archiveDirectory string
+
+		BeforeEach(func() {
+			_ = migrate.Reset()
+			migrate.MustUp()
+
+			var err error
+			archiveDirectory, err = ioutil.TempDir("", "events-archive")
+			Expect(err).To(BeNil())
+		})
+
+		AfterEach(func() {
+			_ = migrate.Reset()
+			_ = os.RemoveAll(archiveDirectory)
+		})
+
+		It("should export events to chunks and import them back", func() {
+			registry := newArchivedEventRegistry()
+			store := appinterface_event.NewRDbStore(pgxConn.ToHandle(), registry)
+
+			events := []event.Event{
+				newArchivedEvent(1), newArchivedEvent(1), newArchivedEvent(2), newArchivedEvent(3),
+			}
+			Expect(store.InsertAll(events)).To(Succeed())
+
+			manifests, err := store.Export(archiveDirectory, 1, 3, 2)
+			Expect(err).To(BeNil())
+			Expect(manifests).To(HaveLen(2))
+			Expect(manifests[0].FromHeight).To(Equal(int64(1)))
+			Expect(manifests[0].ToHeight).To(Equal(int64(2)))
+			Expect(manifests[0].EventCount).To(Equal(int64(3)))
+			Expect(manifests[0].EventCountByName).To(Equal(map[string]int64{archivedEventName: 3}))
+			Expect(manifests[1].FromHeight).To(Equal(int64(3)))
+			Expect(manifests[1].EventCount).To(Equal(int64(1)))
+
+			_ = migrate.Reset()
+			migrate.MustUp()
+
+			importedCount, err := store.Import(pgxConn, archiveDirectory)
+			Expect(err).To(BeNil())
+			Expect(importedCount).To(Equal(int64(4)))
+
+			actual, err := store.GetAllByHeight(1)
+			Expect(err).To(BeNil())
+			Expect(actual).To(Equal(events[:2]))
+		})
+
+		It("should leave only the complete chunks and their manifests in the archive directory", func() {
+			registry := newArchivedEventRegistry()
+			store := appinterface_event.NewRDbStore(pgxConn.ToHandle(), registry)
+
+			Expect(store.InsertAll([]event.Event{newArchivedEvent(1), newArchivedEvent(2)})).To(Succeed())
+			manifests, err := store.Export(archiveDirectory, 1, 2, 1)
+			Expect(err).To(BeNil())
+
+			files, err := ioutil.ReadDir(archiveDirectory)
+			Expect(err).To(BeNil())
+			fileNames := make([]string, 0, len(files))
+			for _, file := range files {
+				fileNames = append(fileNames, file.Name())
+			}
+			Expect(fileNames).To(ConsistOf(
+				manifests[0].File,
+				strings.TrimSuffix(manifests[0].File, appinterface_event.ARCHIVE_CHUNK_FILE_SUFFIX)+
+					appinterface_event.ARCHIVE_MANIFEST_FILE_SUFFIX,
+				manifests[1].File,
+				strings.TrimSuffix(manifests[1].File, appinterface_event.ARCHIVE_CHUNK_FILE_SUFFIX)+
+					appinterface_event.ARCHIVE_MANIFEST_FILE_SUFFIX,
+			))
+		})
+
+		It("should not import any event of a chunk not matching its checksum", func() {
+			registry := newArchivedEventRegistry()
+			store := appinterface_event.NewRDbStore(pgxConn.ToHandle(), registry)
+
+			Expect(store.InsertAll([]event.Event{newArchivedEvent(1)})).To(Succeed())
+			manifests, err := store.Export(archiveDirectory, 1, 1, 1)
+			Expect(err).To(BeNil())
+
+			Expect(ioutil.WriteFile(
+				filepath.Join(archiveDirectory, manifests[0].File), []byte("tampered"), 0644,
+			)).To(Succeed())
+
+			_ = migrate.Reset()
+			migrate.MustUp()
+
+			_, err = store.Import(pgxConn, archiveDirectory)
+			Expect(err).NotTo(BeNil())
+
+			latestHeight, err := store.GetLatestHeight()
+			Expect(err).To(BeNil())
+			Expect(latestHeight).To(BeNil())
+		})
+
+		It("should not import any event of a chunk failing to be inserted", func() {
+			registry := newArchivedEventRegistry()
+			store := appinterface_event.NewRDbStore(pgxConn.ToHandle(), registry)
+
+			// More events than a single insertion batch
+			events := make([]event.Event, 0, 501)
+			for i := 0; i < 501; i += 1 {
+				events = append(events, newArchivedEvent(1))
+			}
+			Expect(store.InsertAll(events)).To(Succeed())
+			_, err := store.Export(archiveDirectory, 1, 1, 1)
+			Expect(err).To(BeNil())
+
+			_ = migrate.Reset()
+			migrate.MustUp()
+
+			// The last event of the chunk conflicts with the existing one
+			Expect(store.Insert(events[500])).To(Succeed())
+
+			_, err = store.Import(pgxConn, archiveDirectory)
+			Expect(err).NotTo(BeNil())
+
+			actual, err := store.GetAllByHeight(1)
+			Expect(err).To(BeNil())
+			Expect(actual).To(Equal(events[500:]))
+		})
+	})
+})
+
+var _ = Describe("RdbEventStore archive chunk verification", func() {
+	var archiveDirectory string
+
+	BeforeEach(func() {
+		var err error
+		archiveDirectory, err = ioutil.TempDir("", "events-archive")
+		Expect(err).To(BeNil())
+	})
+
+	AfterEach(func() {
+		_ = os.RemoveAll(archiveDirectory)
+	})
+
+	It("should not import a chunk of which the checksum does not match its manifest", func() {
+		writeArchiveChunk(
+			archiveDirectory,
+			[]event.Event{newArchivedEvent(1)},
+			func(manifest *appinterface_event.ArchiveManifest) {
+				manifest.Checksum = hex.EncodeToString(make([]byte, sha256.Size))
+			},
+		)
+
+		store := appinterface_event.NewRDbStore(nil, newArchivedEventRegistry())
+		_, err := store.Import(nil, archiveDirectory)
+
+		Expect(err).NotTo(BeNil())
+		Expect(err.Error()).To(ContainSubstring("mismatched chunk checksum"))
+	})
+
+	It("should not import a chunk of which the events decoded do not match its manifest", func() {
+		writeArchiveChunk(
+			archiveDirectory,
+			[]event.Event{newArchivedEvent(1), newArchivedEvent(1)},
+			func(manifest *appinterface_event.ArchiveManifest) {
+				manifest.EventCount = 3
+			},
+		)
+
+		store := appinterface_event.NewRDbStore(nil, newArchivedEventRegistry())
+		_, err := store.Import(nil, archiveDirectory)
+
+		Expect(err).NotTo(BeNil())
+		Expect(err.Error()).To(ContainSubstring("mismatched event count: expected 3, got 2"))
+	})
+})
+
+// writeArchiveChunk writes the events of height 1 as a chunk along with its manifest modified by the function
+func writeArchiveChunk(
+	directory string,
+	events []event.Event,
+	modifyManifest func(manifest *appinterface_event.ArchiveManifest),
+) appinterface_event.ArchiveManifest {
+	var rawChunk bytes.Buffer
+	gzipWriter := gzip.NewWriter(&rawChunk)
+	jsonEncoder := json.NewEncoder(gzipWriter)
+	for _, evt := range events {
+		payload, err := evt.ToJSON()
+		Expect(err).To(BeNil())
+		Expect(jsonEncoder.Encode(&appinterface_event.ArchivedEvent{
+			UUID:    evt.UUID(),
+			Height:  evt.Height(),
+			Name:    evt.Name(),
+			Version: evt.Version(),
+			Payload: json.RawMessage(payload),
+		})).To(Succeed())
+	}
+	Expect(gzipWriter.Close()).To(Succeed())
+
+	checksum := sha256.Sum256(rawChunk.Bytes())
+	manifest := appinterface_event.ArchiveManifest{
+		File:             "events-000000000001-000000000001" + appinterface_event.ARCHIVE_CHUNK_FILE_SUFFIX,
+		FromHeight:       1,
+		ToHeight:         1,
+		EventCount:       int64(len(events)),
+		EventCountByName: map[string]int64{archivedEventName: int64(len(events))},
+		Checksum:         hex.EncodeToString(checksum[:]),
+	}
+	modifyManifest(&manifest)
+
+	Expect(ioutil.WriteFile(filepath.Join(directory, manifest.File), rawChunk.Bytes(), 0644)).To(Succeed())
+	rawManifest, err := json.Marshal(&manifest)
+	Expect(err).To(BeNil())
+	Expect(ioutil.WriteFile(
+		filepath.Join(directory, "events-000000000001-000000000001"+appinterface_event.ARCHIVE_MANIFEST_FILE_SUFFIX),
+		rawManifest,
+		0644,
+	)).To(Succeed())
+
+	return manifest
+}
+
+const archivedEventName = "ArchivedEvent"
+
+type archivedEvent struct {
+	event.Base
+
+	Value string `json:"value"`
+}
+
+func newArchivedEvent(height int64) *archivedEvent {
+	return &archivedEvent{
+		Base: event.NewBase(event.BaseParams{
+			Name:        archivedEventName,
+			Version:     1,
+			BlockHeight: height,
+		}),
+		Value: "value",
+	}
+}
+
+func (evt *archivedEvent) ToJSON() (string, error) {
+	encoded, err := json.Marshal(evt)
+	return string(encoded), err
+}
+
+func (evt *archivedEvent) String() string {
+	return archivedEventName
+}
+
+func newArchivedEventRegistry() *event.Registry {
+	registry := event.NewRegistry()
+	registry.Register(archivedEventName, 1, func(encoded []byte) (event.Event, error) {
+		var evt *archivedEvent
+		if err := json.Unmarshal(encoded, &evt); err != nil {
+			return nil, err
+		}
+		return evt, nil
+	})
+
+	return registry
+}
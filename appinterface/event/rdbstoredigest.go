@@ -0,0 +1,365 @@
+package event
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/crypto-com/chain-indexing/appinterface/rdb"
+)
+
+// DIGEST_BATCH_SIZE is the number of heights verified per query when walking the digest chain
+const DIGEST_BATCH_SIZE = 1000
+
+// ErrInsertBelowDigestTip is returned when events are inserted below the highest digested height. Re-chaining the
+// digests above would bless any event altered meanwhile, so the digest chain only grows upward.
+var ErrInsertBelowDigestTip = errors.New("cannot insert events below the event digest tip")
+
+// The digest of a height is stored in the `digest` column of every event of the height. It is the first 8 bytes of the
+// SHA-256 of the digest of the previous digested height followed by the name, version and stored payload of each event
+// of the height in insertion order. Altering or removing any event below the latest height breaks the chain from that
+// height on. Events stored before digests were introduced have no digest and cannot be verified.
+
+type digestedEvent struct {
+	name        string
+	version     int
+	payload     string
+	maybeDigest *int64
+}
+
+// DigestVerification is the outcome of walking the event digest chain
+type DigestVerification struct {
+	// First height whose events do not match its digest, nil when the whole chain is consistent
+	MaybeInconsistentHeight *int64
+	// Range of the heights stored before digests were introduced, whose events cannot be verified. Nil when all the
+	// heights are digested.
+	MaybeUnverifiedFromHeight *int64
+	MaybeUnverifiedToHeight   *int64
+}
+
+// checkDigestTipWithRDbHandle returns ErrInsertBelowDigestTip when any of the heights is below the digest tip. It must be
+// called before the events are inserted.
+func (store *RDbStore) checkDigestTipWithRDbHandle(rdbHandle *rdb.Handle, heights []int64) error {
+	if len(heights) == 0 {
+		return nil
+	}
+
+	maybeTipHeight, err := store.digestTipHeightWithRDbHandle(rdbHandle)
+	if err != nil {
+		return err
+	}
+	if maybeTipHeight == nil {
+		return nil
+	}
+	for _, height := range heights {
+		if height < *maybeTipHeight {
+			return fmt.Errorf("%w: height %d is below %d", ErrInsertBelowDigestTip, height, *maybeTipHeight)
+		}
+	}
+
+	return nil
+}
+
+// insertDigestsWithRDbHandle computes and stores the digests of the heights, which must not be below the digest tip. It
+// must be called with the same handle the events are inserted so the digests are committed together with the events.
+func (store *RDbStore) insertDigestsWithRDbHandle(rdbHandle *rdb.Handle, heights []int64) error {
+	if len(heights) == 0 {
+		return nil
+	}
+
+	insertedHeights := make(map[int64]bool, len(heights))
+	sortedHeights := make([]int64, 0, len(heights))
+	for _, height := range heights {
+		if !insertedHeights[height] {
+			insertedHeights[height] = true
+			sortedHeights = append(sortedHeights, height)
+		}
+	}
+	sort.Slice(sortedHeights, func(i, j int) bool {
+		return sortedHeights[i] < sortedHeights[j]
+	})
+	fromHeight, toHeight := sortedHeights[0], sortedHeights[len(sortedHeights)-1]
+
+	prevDigest, err := store.prevDigestWithRDbHandle(rdbHandle, fromHeight)
+	if err != nil {
+		return err
+	}
+	eventsByHeight, err := store.digestedEventsWithRDbHandle(rdbHandle, fromHeight, toHeight)
+	if err != nil {
+		return err
+	}
+	for _, height := range sortedHeights {
+		prevDigest = computeDigest(prevDigest, height, eventsByHeight[height])
+
+		sql, args, err := rdbHandle.StmtBuilder.Update(
+			store.table,
+		).Set(
+			"digest", prevDigest,
+		).Where(
+			"height = ?", height,
+		).ToSql()
+		if err != nil {
+			return fmt.Errorf("error building event digest update SQL: %v", err)
+		}
+		if _, err := rdbHandle.Exec(sql, args...); err != nil {
+			return fmt.Errorf("error executing event digest update SQL: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// digestTipHeightWithRDbHandle returns the highest digested height, nil when there is no digest
+func (store *RDbStore) digestTipHeightWithRDbHandle(rdbHandle *rdb.Handle) (*int64, error) {
+	sql, args, err := rdbHandle.StmtBuilder.Select(
+		"height",
+	).From(
+		store.table,
+	).Where(
+		"digest IS NOT NULL",
+	).OrderBy("height DESC").Limit(1).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building event digest tip selection SQL: %v", err)
+	}
+
+	var tipHeight int64
+	if err := rdbHandle.QueryRow(sql, args...).Scan(&tipHeight); err != nil {
+		if errors.Is(err, rdb.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error executing event digest tip selection SQL: %v", err)
+	}
+
+	return &tipHeight, nil
+}
+
+// prevDigestWithRDbHandle returns the digest of the closest digested height below the height, 0 when there is none
+func (store *RDbStore) prevDigestWithRDbHandle(rdbHandle *rdb.Handle, height int64) (int64, error) {
+	sql, args, err := rdbHandle.StmtBuilder.Select(
+		"digest",
+	).From(
+		store.table,
+	).Where(
+		"digest IS NOT NULL AND height < ?", height,
+	).OrderBy("height DESC").Limit(1).ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("error building previous event digest selection SQL: %v", err)
+	}
+
+	var prevDigest int64
+	if err := rdbHandle.QueryRow(sql, args...).Scan(&prevDigest); err != nil {
+		if errors.Is(err, rdb.ErrNoRows) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("error executing previous event digest selection SQL: %v", err)
+	}
+
+	return prevDigest, nil
+}
+
+// digestedEventsWithRDbHandle returns the stored events from fromHeight to toHeight inclusively grouped by height
+func (store *RDbStore) digestedEventsWithRDbHandle(
+	rdbHandle *rdb.Handle,
+	fromHeight int64,
+	toHeight int64,
+) (map[int64][]digestedEvent, error) {
+	sql, args, err := rdbHandle.StmtBuilder.Select(
+		"height", "name", "version", "payload", "digest",
+	).From(
+		store.table,
+	).Where(
+		"height >= ? AND height <= ?", fromHeight, toHeight,
+	).OrderBy("height", "id").ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building digested events selection SQL: %v", err)
+	}
+
+	rows, err := rdbHandle.Query(sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error executing digested events selection SQL: %v", err)
+	}
+	defer rows.Close()
+
+	eventsByHeight := make(map[int64][]digestedEvent)
+	for rows.Next() {
+		var (
+			height int64
+			event  digestedEvent
+		)
+		if err := rows.Scan(&height, &event.name, &event.version, &event.payload, &event.maybeDigest); err != nil {
+			return nil, fmt.Errorf("error scanning digested event: %v", err)
+		}
+		eventsByHeight[height] = append(eventsByHeight[height], event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating digested events: %v", err)
+	}
+
+	return eventsByHeight, nil
+}
+
+func computeDigest(prevDigest int64, height int64, events []digestedEvent) int64 {
+	hash := sha256.New()
+	_ = binary.Write(hash, binary.BigEndian, prevDigest)
+	hash.Write([]byte("\n" + strconv.FormatInt(height, 10) + "\n"))
+	for _, event := range events {
+		hash.Write([]byte(event.name + "\n" + strconv.Itoa(event.version) + "\n" + event.payload + "\n"))
+	}
+
+	return int64(binary.BigEndian.Uint64(hash.Sum(nil)[:8]))
+}
+
+// VerifyDigests walks the digest chain in height order and recomputes each digest from the stored events. A height is
+// inconsistent when the digest of any of its events is missing or does not match its events and the digest of the
+// previous height. The heights below the first digested height, which were stored before digests were introduced, are
+// reported as unverified.
+func (store *RDbStore) VerifyDigests(ctx context.Context) (*DigestVerification, error) {
+	verification := &DigestVerification{}
+
+	maybeFirstDigestedHeight, err := store.firstDigestedHeight()
+	if err != nil {
+		return nil, err
+	}
+	verification.MaybeUnverifiedFromHeight, verification.MaybeUnverifiedToHeight, err = store.undigestedHeightRange(
+		maybeFirstDigestedHeight,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if maybeFirstDigestedHeight == nil {
+		return verification, nil
+	}
+
+	prevDigest := int64(0)
+	fromHeight := *maybeFirstDigestedHeight
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		heights, err := store.nextHeights(fromHeight, DIGEST_BATCH_SIZE)
+		if err != nil {
+			return nil, err
+		}
+		if len(heights) == 0 {
+			return verification, nil
+		}
+
+		toHeight := heights[len(heights)-1]
+		eventsByHeight, err := store.digestedEventsWithRDbHandle(store.rdbHandle, fromHeight, toHeight)
+		if err != nil {
+			return nil, err
+		}
+		for _, height := range heights {
+			height := height
+			events := eventsByHeight[height]
+			if len(events) == 0 || events[0].maybeDigest == nil {
+				verification.MaybeInconsistentHeight = &height
+				return verification, nil
+			}
+			digest := *events[0].maybeDigest
+			for _, event := range events {
+				if event.maybeDigest == nil || *event.maybeDigest != digest {
+					verification.MaybeInconsistentHeight = &height
+					return verification, nil
+				}
+			}
+			if computeDigest(prevDigest, height, events) != digest {
+				verification.MaybeInconsistentHeight = &height
+				return verification, nil
+			}
+			prevDigest = digest
+		}
+
+		fromHeight = toHeight + 1
+	}
+}
+
+// firstDigestedHeight returns the lowest digested height, nil when there is no digest
+func (store *RDbStore) firstDigestedHeight() (*int64, error) {
+	sql, args, err := store.rdbHandle.StmtBuilder.Select(
+		"height",
+	).From(
+		store.table,
+	).Where(
+		"digest IS NOT NULL",
+	).OrderBy("height").Limit(1).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building first digested height selection SQL: %v", err)
+	}
+
+	var firstDigestedHeight int64
+	if err := store.rdbHandle.QueryRow(sql, args...).Scan(&firstDigestedHeight); err != nil {
+		if errors.Is(err, rdb.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error executing first digested height selection SQL: %v", err)
+	}
+
+	return &firstDigestedHeight, nil
+}
+
+// undigestedHeightRange returns the range of the heights with events below the first digested height, or of all the
+// heights when there is no digest. Returns nil when there is no such height.
+func (store *RDbStore) undigestedHeightRange(maybeFirstDigestedHeight *int64) (*int64, *int64, error) {
+	selection := store.rdbHandle.StmtBuilder.Select(
+		"MIN(height)", "MAX(height)",
+	).From(
+		store.table,
+	)
+	if maybeFirstDigestedHeight != nil {
+		selection = selection.Where("height < ?", *maybeFirstDigestedHeight)
+	}
+	sql, args, err := selection.ToSql()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error building undigested height range selection SQL: %v", err)
+	}
+
+	var maybeFromHeight, maybeToHeight *int64
+	if err := store.rdbHandle.QueryRow(sql, args...).Scan(&maybeFromHeight, &maybeToHeight); err != nil {
+		if errors.Is(err, rdb.ErrNoRows) {
+			return nil, nil, nil
+		}
+		return nil, nil, fmt.Errorf("error executing undigested height range selection SQL: %v", err)
+	}
+
+	return maybeFromHeight, maybeToHeight, nil
+}
+
+// nextHeights returns up to limit distinct heights with events from the height on in ascending order
+func (store *RDbStore) nextHeights(fromHeight int64, limit uint64) ([]int64, error) {
+	sql, args, err := store.rdbHandle.StmtBuilder.Select(
+		"DISTINCT height",
+	).From(
+		store.table,
+	).Where(
+		"height >= ?", fromHeight,
+	).OrderBy("height").Limit(limit).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building event heights selection SQL: %v", err)
+	}
+
+	rows, err := store.rdbHandle.Query(sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error executing event heights selection SQL: %v", err)
+	}
+	defer rows.Close()
+
+	heights := make([]int64, 0, limit)
+	for rows.Next() {
+		var height int64
+		if err := rows.Scan(&height); err != nil {
+			return nil, fmt.Errorf("error scanning event height: %v", err)
+		}
+		heights = append(heights, height)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating event heights: %v", err)
+	}
+
+	return heights, nil
+}
@@ -0,0 +1,49 @@
+package event
+
+import (
+	"fmt"
+
+	entity_event "github.com/crypto-com/chain-indexing/entity/event"
+	"github.com/crypto-com/chain-indexing/external/jsonschema"
+)
+
+// EventSchema is the JSON Schema of the payload of an event name and version
+type EventSchema struct {
+	Name    string             `json:"name"`
+	Version int                `json:"version"`
+	Schema  *jsonschema.Schema `json:"schema"`
+}
+
+// GenerateSchemas returns the JSON Schema of every event name and version registered with a decoder, ordered by the
+// event name and version. The schema is generated from the Go event struct the decoder returns.
+func GenerateSchemas(registry *entity_event.Registry) ([]EventSchema, error) {
+	registeredTypes, err := registry.RegisteredTypes()
+	if err != nil {
+		return nil, fmt.Errorf("error getting registered event types: %v", err)
+	}
+
+	schemas := make([]EventSchema, 0, len(registeredTypes))
+	for _, registeredType := range registeredTypes {
+		schema := jsonschema.Reflect(registeredType.Type)
+		schema.Id = SchemaId(registeredType.Name, registeredType.Version)
+		schema.Title = registeredType.Name
+
+		schemas = append(schemas, EventSchema{
+			Name:    registeredType.Name,
+			Version: registeredType.Version,
+			Schema:  schema,
+		})
+	}
+
+	return schemas, nil
+}
+
+// SchemaId returns the identity of the schema of the event name and version
+func SchemaId(name string, version int) string {
+	return fmt.Sprintf("%s/v%d", name, version)
+}
+
+// SchemaFileName returns the file name of the schema of the event name and version in a schemas directory
+func SchemaFileName(name string, version int) string {
+	return fmt.Sprintf("%s.v%d.json", name, version)
+}
@@ -0,0 +1,668 @@
+package eventhandler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+
+	"github.com/crypto-com/chain-indexing/entity/event"
+	projection_entity "github.com/crypto-com/chain-indexing/entity/projection"
+	applogger "github.com/crypto-com/chain-indexing/external/logger"
+	"github.com/crypto-com/chain-indexing/infrastructure/metric/prometheus"
+)
+
+var _ Handler = &FanoutHandler{}
+var _ projection_entity.Controller = &FanoutHandler{}
+
+const DEFAULT_FANOUT_CACHE_SIZE = 1000
+const DEFAULT_FANOUT_MAX_RETRY_INTERVAL = 15 * time.Minute
+
+// FanoutHandler is an event handler which dispatches the events of each height to multiple handlers.
+// Blocks are fetched and parsed once, and each handler consumes the events at its own pace from a
+// bounded in-memory cache of recent heights. When the cache is full, HandleEvents blocks until the
+// slowest handler catches up. A handler depending on other handlers is handed a height only after
+// all its dependencies have handled it. The dependencies must not form a cycle. A handler failing to
+// handle a height is halted or skips the height according to its failure policy, and a halted handler
+// no longer holds back the others. Handlers can be paused, resumed and rewound individually. A paused
+// handler does not hold back the others either. A handler behind the cached heights, e.g. after being
+// resumed or rewound, catches up by getting the events of the heights no longer cached from the
+// catch-up source. Without a catch-up source, it catches up on the next restart.
+type FanoutHandler struct {
+	logger    applogger.Logger
+	cacheSize int
+
+	failurePolicies   projection_entity.FailurePolicies
+	maybeFailureStore projection_entity.FailureStore
+
+	maybeCatchUpSource CatchUpSource
+
+	consumers []*fanoutConsumer
+
+	// Serializes the pause, resume and rewind of the handlers
+	controlMutex sync.Mutex
+
+	mutex sync.Mutex
+	// Signaled on every cache update or handler progress
+	cond                    *sync.Cond
+	cache                   map[int64][]event.Event
+	minCachedHeight         int64
+	maybeLastProducedHeight *int64
+	isStarted               bool
+	isClosed                bool
+
+	// Cancelled on Close to stop retrying failing handlers
+	closeCtx           context.Context
+	cancelCloseCtx     context.CancelFunc
+	consumersWaitGroup sync.WaitGroup
+}
+
+// CatchUpSource provides the events of the heights no longer cached by the FanoutHandler
+type CatchUpSource interface {
+	GetAllByHeight(height int64) ([]event.Event, error)
+}
+
+// ErrNotCached is returned when the handler cannot be resumed or rewound to heights no longer cached and there is no
+// catch-up source
+var ErrNotCached = errors.New("heights are no longer cached and there is no catch-up source")
+
+type fanoutConsumer struct {
+	handler      Handler
+	dependencies []*fanoutConsumer
+
+	maybeLastHandledHeight *int64
+	isHalted               bool
+	isPaused               bool
+	// True when the next height of the handler is no longer cached
+	isBehind   bool
+	isHandling bool
+}
+
+func NewFanoutHandler(logger applogger.Logger, handlers []Handler, cacheSize int) *FanoutHandler {
+	if cacheSize <= 0 {
+		cacheSize = DEFAULT_FANOUT_CACHE_SIZE
+	}
+
+	logger = logger.WithFields(applogger.LogFields{
+		"module": "FanoutHandler",
+	})
+
+	consumers := make([]*fanoutConsumer, 0, len(handlers))
+	consumersById := make(map[string]*fanoutConsumer, len(handlers))
+	for _, handler := range handlers {
+		consumer := &fanoutConsumer{
+			handler: handler,
+		}
+		consumers = append(consumers, consumer)
+		consumersById[handler.Id()] = consumer
+	}
+	for _, consumer := range consumers {
+		dependentHandler, ok := consumer.handler.(DependentHandler)
+		if !ok {
+			continue
+		}
+		for _, dependencyId := range dependentHandler.GetDependencies() {
+			dependency, ok := consumersById[dependencyId]
+			if !ok {
+				logger.Infof(
+					"handler `%s` depends on `%s` which is not fanned out to, ignoring the dependency",
+					consumer.handler.Id(), dependencyId,
+				)
+				continue
+			}
+			consumer.dependencies = append(consumer.dependencies, dependency)
+		}
+	}
+
+	fanoutHandler := &FanoutHandler{
+		logger:    logger,
+		cacheSize: cacheSize,
+
+		consumers: consumers,
+
+		cache: make(map[int64][]event.Event),
+	}
+	fanoutHandler.cond = sync.NewCond(&fanoutHandler.mutex)
+	fanoutHandler.closeCtx, fanoutHandler.cancelCloseCtx = context.WithCancel(context.Background())
+
+	return fanoutHandler
+}
+
+// WithFailureHandling sets the failure policies of the handlers, by id, and the store recording their failures.
+// Without it, a handler retries a failed height forever.
+func (handler *FanoutHandler) WithFailureHandling(
+	failurePolicies projection_entity.FailurePolicies,
+	failureStore projection_entity.FailureStore,
+) *FanoutHandler {
+	handler.failurePolicies = failurePolicies
+	handler.maybeFailureStore = failureStore
+	return handler
+}
+
+// WithCatchUpSource sets the source of the events of the heights no longer cached, from which the handlers behind the
+// cached heights catch up
+func (handler *FanoutHandler) WithCatchUpSource(source CatchUpSource) *FanoutHandler {
+	handler.maybeCatchUpSource = source
+	return handler
+}
+
+// GetLastHandledEventHeight returns the last height dispatched to the handlers. Before any height is
+// dispatched, it returns the lowest last handled height among the handlers, such that the lagging
+// handlers are fed from there and handlers ahead of it skip the heights they have already handled.
+func (handler *FanoutHandler) GetLastHandledEventHeight() (*int64, error) {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	if !handler.isStarted {
+		if err := handler.start(); err != nil {
+			return nil, err
+		}
+	}
+
+	if handler.maybeLastProducedHeight != nil {
+		lastProducedHeight := *handler.maybeLastProducedHeight
+		return &lastProducedHeight, nil
+	}
+
+	return handler.lowestLastHandledHeight(), nil
+}
+
+// start loads the last handled height of each handler and starts consuming events. Must be called
+// with the mutex locked.
+func (handler *FanoutHandler) start() error {
+	for _, consumer := range handler.consumers {
+		maybeLastHandledHeight, err := consumer.handler.GetLastHandledEventHeight()
+		if err != nil {
+			return fmt.Errorf("error getting last handled event height of %s: %v", consumer.handler.Id(), err)
+		}
+		consumer.maybeLastHandledHeight = maybeLastHandledHeight
+
+		if handler.maybeFailureStore != nil {
+			if err := handler.maybeFailureStore.MarkRunning(consumer.handler.Id()); err != nil {
+				handler.logger.Errorf("error marking %s as running: %v", consumer.handler.Id(), err)
+			}
+		}
+	}
+
+	for _, consumer := range handler.consumers {
+		handler.startConsumer(consumer)
+	}
+	handler.isStarted = true
+
+	return nil
+}
+
+// startConsumer starts consuming events for the consumer. Must be called with the mutex locked.
+func (handler *FanoutHandler) startConsumer(consumer *fanoutConsumer) {
+	handler.consumersWaitGroup.Add(1)
+	go func() {
+		defer handler.consumersWaitGroup.Done()
+		handler.runConsumer(consumer)
+	}()
+}
+
+func (handler *FanoutHandler) HandleEvents(blockHeight int64, events []event.Event) error {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	if !handler.isStarted {
+		if err := handler.start(); err != nil {
+			return err
+		}
+	}
+
+	if handler.maybeLastProducedHeight != nil && blockHeight != *handler.maybeLastProducedHeight+1 {
+		return fmt.Errorf(
+			"error handling events of height %d: expected height %d",
+			blockHeight, *handler.maybeLastProducedHeight+1,
+		)
+	}
+
+	// Backpressure: wait for the slowest handler when the cache is full
+	for len(handler.cache) >= handler.cacheSize && !handler.isClosed {
+		handler.cond.Wait()
+	}
+	if handler.isClosed {
+		return errors.New("error handling events: handler is closed")
+	}
+
+	if len(handler.cache) == 0 {
+		handler.minCachedHeight = blockHeight
+	}
+	handler.cache[blockHeight] = events
+	handler.maybeLastProducedHeight = &blockHeight
+	handler.evictHandledHeights()
+	handler.cond.Broadcast()
+
+	return nil
+}
+
+func (handler *FanoutHandler) Id() string {
+	return "FanoutHandler"
+}
+
+// Close stops dispatching events to the handlers and blocks until the events being handled are handled. Events left
+// in the cache are dropped and will be synced again on next start.
+func (handler *FanoutHandler) Close() {
+	handler.mutex.Lock()
+	handler.isClosed = true
+	handler.cond.Broadcast()
+	handler.mutex.Unlock()
+
+	handler.cancelCloseCtx()
+	handler.consumersWaitGroup.Wait()
+}
+
+func (handler *FanoutHandler) runConsumer(consumer *fanoutConsumer) {
+	logger := handler.logger.WithFields(applogger.LogFields{
+		"handler": consumer.handler.Id(),
+	})
+	failurePolicy := handler.failurePolicyOf(consumer.handler.Id())
+
+	for {
+		blockHeight, maybeEvents, ok := handler.waitNextEvents(consumer)
+		if !ok {
+			return
+		}
+		events := maybeEvents
+		if maybeEvents == nil {
+			// The height is no longer cached
+			events, ok = handler.catchUpEvents(logger, blockHeight)
+			if !ok {
+				return
+			}
+		}
+
+		startTime := time.Now()
+		failureCount := 0
+		operation := func() error {
+			err := consumer.handler.HandleEvents(blockHeight, events)
+			if err != nil {
+				failureCount += 1
+				if failurePolicy.IsRetryExhausted(failureCount) {
+					return backoff.Permanent(err)
+				}
+			}
+			return err
+		}
+		notifyFn := func(opErr error, backoffDuration time.Duration) {
+			logger.Errorf(
+				"error handling events of height %d, retrying in %s: %v", blockHeight, backoffDuration.String(), opErr,
+			)
+		}
+		neverStopExponentialBackoff := backoff.NewExponentialBackOff()
+		neverStopExponentialBackoff.MaxElapsedTime = 0
+		neverStopExponentialBackoff.MaxInterval = DEFAULT_FANOUT_MAX_RETRY_INTERVAL
+		// Only returns error when the handler is closed or the retries of the failure policy are exhausted
+		if err := backoff.RetryNotify(
+			operation,
+			backoff.WithContext(neverStopExponentialBackoff, handler.closeCtx),
+			notifyFn,
+		); err != nil {
+			if handler.closeCtx.Err() != nil {
+				return
+			}
+
+			eventLogger := logger.WithFields(applogger.LogFields{
+				"height": blockHeight,
+			})
+			if failurePolicy.Action == projection_entity.FAILURE_ACTION_HALT {
+				if markErr := handler.maybeFailureStore.MarkFailed(
+					consumer.handler.Id(), blockHeight, err.Error(),
+				); markErr != nil {
+					eventLogger.Errorf("error marking handler as failed: %v", markErr)
+				}
+				eventLogger.Errorf("handler halted after %d failures", failureCount)
+
+				handler.mutex.Lock()
+				consumer.isHalted = true
+				consumer.isHandling = false
+				handler.evictHandledHeights()
+				handler.cond.Broadcast()
+				handler.mutex.Unlock()
+				return
+			}
+
+			if !handler.insertDeadLetter(eventLogger, &projection_entity.DeadLetter{
+				ProjectionId: consumer.handler.Id(),
+				Height:       blockHeight,
+				Events:       events,
+				Error:        err.Error(),
+			}) {
+				return
+			}
+			eventLogger.Errorf("skipped events after %d failures and recorded them as dead letter", failureCount)
+		}
+
+		prometheus.RecordProjectionExecTime(consumer.handler.Id(), time.Since(startTime).Milliseconds())
+		prometheus.RecordProjectionLatestHeight(consumer.handler.Id(), blockHeight)
+
+		handler.mutex.Lock()
+		consumer.maybeLastHandledHeight = &blockHeight
+		consumer.isHandling = false
+		handler.evictHandledHeights()
+		handler.cond.Broadcast()
+		handler.mutex.Unlock()
+	}
+}
+
+// failurePolicyOf returns the failure policy of the handler. Handlers retry forever without a failure store.
+func (handler *FanoutHandler) failurePolicyOf(handlerId string) projection_entity.FailurePolicy {
+	if handler.maybeFailureStore == nil {
+		return projection_entity.FailurePolicy{}
+	}
+
+	return handler.failurePolicies.Of(handlerId)
+}
+
+// insertDeadLetter records the dead letter, retrying until it succeeds. Returns false when the handler is closed
+// before that.
+func (handler *FanoutHandler) insertDeadLetter(logger applogger.Logger, deadLetter *projection_entity.DeadLetter) bool {
+	for {
+		err := handler.maybeFailureStore.InsertDeadLetter(deadLetter)
+		if err == nil {
+			return true
+		}
+		logger.Errorf("error inserting dead letter: %v", err)
+
+		select {
+		case <-handler.closeCtx.Done():
+			return false
+		case <-time.After(5 * time.Second):
+		}
+	}
+}
+
+// catchUpEvents gets the events of the height no longer cached from the catch-up source, retrying until it succeeds.
+// Returns false when the handler is closed before that.
+func (handler *FanoutHandler) catchUpEvents(logger applogger.Logger, height int64) ([]event.Event, bool) {
+	for {
+		events, err := handler.maybeCatchUpSource.GetAllByHeight(height)
+		if err == nil {
+			return events, true
+		}
+		logger.Errorf("error getting events of height %d from catch-up source: %v", height, err)
+
+		select {
+		case <-handler.closeCtx.Done():
+			return nil, false
+		case <-time.After(5 * time.Second):
+		}
+	}
+}
+
+// waitNextEvents blocks until the consumer is not paused, the events of the next height to be
+// handled by the consumer are available and all its dependencies have handled the height. The
+// events are nil when the height is no longer cached, in which case they have to be got from the
+// catch-up source. Returns false when the handler is closed.
+func (handler *FanoutHandler) waitNextEvents(consumer *fanoutConsumer) (int64, []event.Event, bool) {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	for {
+		if handler.isClosed {
+			return 0, nil, false
+		}
+
+		if !consumer.isPaused {
+			nextHeight := int64(0)
+			if consumer.maybeLastHandledHeight != nil {
+				nextHeight = *consumer.maybeLastHandledHeight + 1
+			}
+			events, ok := handler.cache[nextHeight]
+			if ok {
+				if consumer.isBehind {
+					handler.logger.Infof(
+						"handler `%s` caught up with the cached heights at height %d", consumer.handler.Id(), nextHeight,
+					)
+					consumer.isBehind = false
+				}
+				if consumer.isDependenciesHandled(nextHeight) {
+					consumer.isHandling = true
+					if events == nil {
+						events = []event.Event{}
+					}
+					return nextHeight, events, true
+				}
+			} else if handler.maybeLastProducedHeight != nil && nextHeight <= *handler.maybeLastProducedHeight {
+				if !consumer.isBehind {
+					if handler.maybeCatchUpSource != nil {
+						handler.logger.Infof(
+							"handler `%s` is behind the cached heights at height %d, catching up from source",
+							consumer.handler.Id(), nextHeight,
+						)
+					} else {
+						handler.logger.Errorf(
+							"handler `%s` is behind the cached heights at height %d, it catches up on next restart",
+							consumer.handler.Id(), nextHeight,
+						)
+					}
+					consumer.isBehind = true
+					handler.evictHandledHeights()
+					handler.cond.Broadcast()
+				}
+				if handler.maybeCatchUpSource != nil && consumer.isDependenciesHandled(nextHeight) {
+					consumer.isHandling = true
+					return nextHeight, nil, true
+				}
+			} else if consumer.isBehind {
+				handler.logger.Infof(
+					"handler `%s` caught up with the latest height at height %d", consumer.handler.Id(), nextHeight,
+				)
+				consumer.isBehind = false
+			}
+		}
+
+		handler.cond.Wait()
+	}
+}
+
+// PauseProjection implements projection.Controller.PauseProjection() for the handler with the id
+func (handler *FanoutHandler) PauseProjection(projectionId string) error {
+	handler.controlMutex.Lock()
+	defer handler.controlMutex.Unlock()
+
+	handler.mutex.Lock()
+	consumer := handler.consumerById(projectionId)
+	if consumer == nil {
+		handler.mutex.Unlock()
+		return projection_entity.ErrProjectionNotFound
+	}
+	consumer.isPaused = true
+	handler.evictHandledHeights()
+	handler.cond.Broadcast()
+	for consumer.isHandling {
+		handler.cond.Wait()
+	}
+	handler.mutex.Unlock()
+
+	if handler.maybeFailureStore != nil {
+		if err := handler.maybeFailureStore.MarkPaused(projectionId); err != nil {
+			handler.logger.Errorf("error marking %s as paused: %v", projectionId, err)
+		}
+	}
+	return nil
+}
+
+// ResumeProjection implements projection.Controller.ResumeProjection() for the handler with the id.
+// A halted handler is started again. Without a catch-up source, it returns ErrNotCached when the
+// next height of the handler is no longer cached.
+func (handler *FanoutHandler) ResumeProjection(projectionId string) error {
+	handler.controlMutex.Lock()
+	defer handler.controlMutex.Unlock()
+
+	handler.mutex.Lock()
+	consumer := handler.consumerById(projectionId)
+	if consumer == nil {
+		handler.mutex.Unlock()
+		return projection_entity.ErrProjectionNotFound
+	}
+	if !handler.isCachedFrom(consumer.maybeLastHandledHeight) {
+		handler.mutex.Unlock()
+		return fmt.Errorf("error resuming handler: %w", ErrNotCached)
+	}
+	if consumer.isHalted {
+		if handler.isClosed {
+			handler.mutex.Unlock()
+			return errors.New("error resuming handler: handler is closed")
+		}
+		consumer.isHalted = false
+		handler.startConsumer(consumer)
+	}
+	consumer.isPaused = false
+	consumer.isBehind = false
+	handler.cond.Broadcast()
+	handler.mutex.Unlock()
+
+	if handler.maybeFailureStore != nil {
+		if err := handler.maybeFailureStore.MarkRunning(projectionId); err != nil {
+			handler.logger.Errorf("error marking %s as running: %v", projectionId, err)
+		}
+	}
+	return nil
+}
+
+// RewindProjection implements projection.Controller.RewindProjection() for the paused handler with
+// the id. The rewound heights no longer cached are handled again from the catch-up source. Without a
+// catch-up source, it returns ErrNotCached when the heights above the height are no longer cached.
+func (handler *FanoutHandler) RewindProjection(projectionId string, height int64) error {
+	handler.controlMutex.Lock()
+	defer handler.controlMutex.Unlock()
+
+	handler.mutex.Lock()
+	consumer := handler.consumerById(projectionId)
+	if consumer == nil {
+		handler.mutex.Unlock()
+		return projection_entity.ErrProjectionNotFound
+	}
+	isPaused := consumer.isPaused
+	isCached := handler.isCachedFrom(&height)
+	handler.mutex.Unlock()
+	if !isPaused {
+		return projection_entity.ErrProjectionNotPaused
+	}
+	if !isCached {
+		return fmt.Errorf("error rewinding handler to height %d: %w", height, ErrNotCached)
+	}
+
+	rewindableHandler, ok := consumer.handler.(RewindableHandler)
+	if !ok {
+		return projection_entity.ErrRewindUnsupported
+	}
+	// The paused consumer cannot be resumed meanwhile as the controlMutex is held
+	if err := rewindableHandler.Rewind(height); err != nil {
+		return err
+	}
+
+	handler.mutex.Lock()
+	consumer.maybeLastHandledHeight = &height
+	handler.mutex.Unlock()
+	return nil
+}
+
+// isCachedFrom returns true when the heights above the last handled height can be handed to a
+// handler, either from the cache or from the catch-up source. Must be called with the mutex locked.
+func (handler *FanoutHandler) isCachedFrom(maybeLastHandledHeight *int64) bool {
+	if handler.maybeCatchUpSource != nil || handler.maybeLastProducedHeight == nil {
+		return true
+	}
+
+	nextHeight := int64(0)
+	if maybeLastHandledHeight != nil {
+		nextHeight = *maybeLastHandledHeight + 1
+	}
+	if nextHeight > *handler.maybeLastProducedHeight {
+		return true
+	}
+	_, ok := handler.cache[nextHeight]
+	return ok
+}
+
+// consumerById returns the consumer of the handler with the id, nil when there is none
+func (handler *FanoutHandler) consumerById(handlerId string) *fanoutConsumer {
+	for _, consumer := range handler.consumers {
+		if consumer.handler.Id() == handlerId {
+			return consumer
+		}
+	}
+
+	return nil
+}
+
+// isDependenciesHandled returns true when all the dependencies of the consumer have handled the
+// height. Must be called with the mutex locked.
+func (consumer *fanoutConsumer) isDependenciesHandled(height int64) bool {
+	for _, dependency := range consumer.dependencies {
+		if dependency.maybeLastHandledHeight == nil || *dependency.maybeLastHandledHeight < height {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isBlocked returns true when the consumer or any of its dependencies is halted, paused or behind the
+// cached heights, in which case it does not handle the cached heights. Must be called with the mutex
+// locked.
+func (consumer *fanoutConsumer) isBlocked() bool {
+	if consumer.isHalted || consumer.isPaused || consumer.isBehind {
+		return true
+	}
+	for _, dependency := range consumer.dependencies {
+		if dependency.isBlocked() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// evictHandledHeights removes the heights handled by all the handlers which are not blocked from the
+// cache. Must be called with the mutex locked.
+func (handler *FanoutHandler) evictHandledHeights() {
+	isAllBlocked := true
+	for _, consumer := range handler.consumers {
+		if !consumer.isBlocked() {
+			isAllBlocked = false
+			break
+		}
+	}
+	if isAllBlocked {
+		handler.cache = make(map[int64][]event.Event)
+		return
+	}
+
+	maybeLowestHeight := handler.lowestLastHandledHeight()
+	if maybeLowestHeight == nil {
+		return
+	}
+
+	for len(handler.cache) > 0 && handler.minCachedHeight <= *maybeLowestHeight {
+		delete(handler.cache, handler.minCachedHeight)
+		handler.minCachedHeight += 1
+	}
+}
+
+// lowestLastHandledHeight returns the lowest last handled height among the handlers which are not
+// blocked. Returns nil when any of them has not handled any height. Must be called with the mutex
+// locked.
+func (handler *FanoutHandler) lowestLastHandledHeight() *int64 {
+	var maybeLowestHeight *int64
+	for _, consumer := range handler.consumers {
+		if consumer.isBlocked() {
+			continue
+		}
+		if consumer.maybeLastHandledHeight == nil {
+			return nil
+		}
+		if maybeLowestHeight == nil || *consumer.maybeLastHandledHeight < *maybeLowestHeight {
+			lastHandledHeight := *consumer.maybeLastHandledHeight
+			maybeLowestHeight = &lastHandledHeight
+		}
+	}
+
+	return maybeLowestHeight
+}
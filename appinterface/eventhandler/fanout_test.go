@@ -0,0 +1,466 @@
+package eventhandler_test
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/crypto-com/chain-indexing/appinterface/eventhandler"
+	"github.com/crypto-com/chain-indexing/entity/event"
+	. "github.com/crypto-com/chain-indexing/entity/event/test"
+	"github.com/crypto-com/chain-indexing/entity/projection"
+	. "github.com/crypto-com/chain-indexing/entity/projection/test"
+	. "github.com/crypto-com/chain-indexing/external/logger/test"
+	"github.com/crypto-com/chain-indexing/external/primptr"
+)
+
+var _ = Describe("FanoutHandler", func() {
+	It("should return nil last handled height when any handler has not handled any height", func() {
+		fanoutHandler := eventhandler.NewFanoutHandler(NewFakeLogger(), []eventhandler.Handler{
+			newFakeHandler("A", primptr.Int64(10)),
+			newFakeHandler("B", nil),
+		}, 10)
+
+		maybeLastHandledHeight, err := fanoutHandler.GetLastHandledEventHeight()
+		Expect(err).To(BeNil())
+		Expect(maybeLastHandledHeight).To(BeNil())
+	})
+
+	It("should start from the lowest last handled height among handlers", func() {
+		fanoutHandler := eventhandler.NewFanoutHandler(NewFakeLogger(), []eventhandler.Handler{
+			newFakeHandler("A", primptr.Int64(10)),
+			newFakeHandler("B", primptr.Int64(5)),
+		}, 10)
+
+		maybeLastHandledHeight, err := fanoutHandler.GetLastHandledEventHeight()
+		Expect(err).To(BeNil())
+		Expect(*maybeLastHandledHeight).To(Equal(int64(5)))
+	})
+
+	It("should dispatch events of each height once to every handler which has not handled it", func() {
+		handlerA := newFakeHandler("A", primptr.Int64(3))
+		handlerB := newFakeHandler("B", primptr.Int64(1))
+		fanoutHandler := eventhandler.NewFanoutHandler(NewFakeLogger(), []eventhandler.Handler{
+			handlerA, handlerB,
+		}, 10)
+
+		_, err := fanoutHandler.GetLastHandledEventHeight()
+		Expect(err).To(BeNil())
+		for height := int64(2); height <= 5; height += 1 {
+			Expect(fanoutHandler.HandleEvents(height, []event.Event{NewFakeEvent()})).To(BeNil())
+		}
+
+		Eventually(handlerA.HandledHeights).Should(Equal([]int64{4, 5}))
+		Eventually(handlerB.HandledHeights).Should(Equal([]int64{2, 3, 4, 5}))
+
+		maybeLastHandledHeight, err := fanoutHandler.GetLastHandledEventHeight()
+		Expect(err).To(BeNil())
+		Expect(*maybeLastHandledHeight).To(Equal(int64(5)))
+	})
+
+	It("should retry handler on error without blocking other handlers", func() {
+		handlerA := newFakeHandler("A", nil)
+		handlerB := newFakeHandler("B", nil)
+		handlerB.failuresLeft = 1
+		fanoutHandler := eventhandler.NewFanoutHandler(NewFakeLogger(), []eventhandler.Handler{
+			handlerA, handlerB,
+		}, 10)
+
+		for height := int64(0); height <= 2; height += 1 {
+			Expect(fanoutHandler.HandleEvents(height, []event.Event{})).To(BeNil())
+		}
+
+		Eventually(handlerA.HandledHeights).Should(Equal([]int64{0, 1, 2}))
+		Eventually(handlerB.HandledHeights, 5*time.Second).Should(Equal([]int64{0, 1, 2}))
+	})
+
+	It("should block when the cache is full until the slowest handler catches up", func() {
+		slowHandler := newFakeHandler("Slow", nil)
+		slowHandler.blockCh = make(chan bool)
+		fanoutHandler := eventhandler.NewFanoutHandler(NewFakeLogger(), []eventhandler.Handler{
+			newFakeHandler("Fast", nil), slowHandler,
+		}, 2)
+
+		Expect(fanoutHandler.HandleEvents(0, []event.Event{})).To(BeNil())
+		Expect(fanoutHandler.HandleEvents(1, []event.Event{})).To(BeNil())
+
+		doneCh := make(chan bool)
+		go func() {
+			defer GinkgoRecover()
+			Expect(fanoutHandler.HandleEvents(2, []event.Event{})).To(BeNil())
+			close(doneCh)
+		}()
+		Consistently(doneCh, 100*time.Millisecond).ShouldNot(BeClosed())
+
+		slowHandler.blockCh <- true
+		Eventually(doneCh).Should(BeClosed())
+		close(slowHandler.blockCh)
+	})
+
+	It("should wait for the events being handled on Close", func() {
+		slowHandler := newFakeHandler("Slow", nil)
+		slowHandler.blockCh = make(chan bool)
+		fanoutHandler := eventhandler.NewFanoutHandler(NewFakeLogger(), []eventhandler.Handler{
+			slowHandler,
+		}, 10)
+
+		slowHandler.handlingCh = make(chan int64, 1)
+		Expect(fanoutHandler.HandleEvents(0, []event.Event{})).To(BeNil())
+		Expect(fanoutHandler.HandleEvents(1, []event.Event{})).To(BeNil())
+		Eventually(slowHandler.handlingCh).Should(Receive(Equal(int64(0))))
+
+		closedCh := make(chan bool)
+		go func() {
+			fanoutHandler.Close()
+			close(closedCh)
+		}()
+		Consistently(closedCh, 100*time.Millisecond).ShouldNot(BeClosed())
+
+		slowHandler.blockCh <- true
+		Eventually(closedCh).Should(BeClosed())
+		Expect(slowHandler.HandledHeights()).To(Equal([]int64{0}))
+		Expect(fanoutHandler.HandleEvents(2, []event.Event{})).NotTo(BeNil())
+	})
+
+	It("should dispatch a height to handler only after its dependencies have handled it", func() {
+		dependency := newFakeHandler("Dependency", nil)
+		dependency.blockCh = make(chan bool)
+		dependent := &fakeDependentHandler{
+			fakeHandler:  newFakeHandler("Dependent", nil),
+			dependencies: []string{"Dependency", "NotFannedOut"},
+		}
+		fanoutHandler := eventhandler.NewFanoutHandler(NewFakeLogger(), []eventhandler.Handler{
+			dependent, dependency,
+		}, 10)
+
+		Expect(fanoutHandler.HandleEvents(0, []event.Event{})).To(BeNil())
+		Expect(fanoutHandler.HandleEvents(1, []event.Event{})).To(BeNil())
+		Consistently(dependent.HandledHeights, 100*time.Millisecond).Should(BeEmpty())
+
+		dependency.blockCh <- true
+		Eventually(dependent.HandledHeights).Should(Equal([]int64{0}))
+		Consistently(dependent.HandledHeights, 100*time.Millisecond).Should(Equal([]int64{0}))
+
+		close(dependency.blockCh)
+		Eventually(dependent.HandledHeights).Should(Equal([]int64{0, 1}))
+	})
+
+	It("should halt handler and stop holding back other handlers when the retries of HALT policy are exhausted", func() {
+		failingHandler := newFakeHandler("Failing", nil)
+		failingHandler.failuresLeft = 1
+		handler := newFakeHandler("Handler", nil)
+		mockFailureStore := NewMockFailureStore()
+		mockFailureStore.On("MarkRunning", mock.Anything).Return(nil)
+		mockFailureStore.On("MarkFailed", "Failing", int64(0), "any error").Return(nil)
+		fanoutHandler := eventhandler.NewFanoutHandler(NewFakeLogger(), []eventhandler.Handler{
+			failingHandler, handler,
+		}, 2).WithFailureHandling(projection.FailurePolicies{
+			ByProjection: map[string]projection.FailurePolicy{
+				"Failing": {
+					MaxRetry: 0,
+					Action:   projection.FAILURE_ACTION_HALT,
+				},
+			},
+		}, mockFailureStore)
+
+		for height := int64(0); height <= 4; height += 1 {
+			Expect(fanoutHandler.HandleEvents(height, []event.Event{})).To(BeNil())
+		}
+
+		Eventually(handler.HandledHeights).Should(Equal([]int64{0, 1, 2, 3, 4}))
+		Expect(failingHandler.HandledHeights()).To(BeEmpty())
+		mockFailureStore.AssertExpectations(GinkgoT())
+	})
+
+	It("should catch up a handler behind the cached heights from the catch-up source", func() {
+		failingHandler := newFakeHandler("Failing", nil)
+		failingHandler.failuresLeft = 1
+		handler := newFakeHandler("Handler", nil)
+		mockFailureStore := NewMockFailureStore()
+		mockFailureStore.On("MarkRunning", mock.Anything).Return(nil)
+		mockFailureStore.On("MarkFailed", "Failing", int64(0), "any error").Return(nil)
+		catchUpSource := newFakeCatchUpSource()
+		fanoutHandler := eventhandler.NewFanoutHandler(NewFakeLogger(), []eventhandler.Handler{
+			failingHandler, handler,
+		}, 2).WithFailureHandling(projection.FailurePolicies{
+			ByProjection: map[string]projection.FailurePolicy{
+				"Failing": {
+					MaxRetry: 0,
+					Action:   projection.FAILURE_ACTION_HALT,
+				},
+			},
+		}, mockFailureStore).WithCatchUpSource(catchUpSource)
+
+		for height := int64(0); height <= 4; height += 1 {
+			Expect(fanoutHandler.HandleEvents(height, []event.Event{})).To(BeNil())
+		}
+		Eventually(handler.HandledHeights).Should(Equal([]int64{0, 1, 2, 3, 4}))
+		Expect(failingHandler.HandledHeights()).To(BeEmpty())
+
+		// The halted handler is behind the cached heights once started again
+		Expect(fanoutHandler.ResumeProjection("Failing")).To(Succeed())
+		Eventually(failingHandler.HandledHeights).Should(Equal([]int64{0, 1, 2, 3, 4}))
+		Expect(catchUpSource.RequestedHeights()).To(ContainElements(int64(0), int64(1), int64(2)))
+
+		// The caught up handler is handed the cached heights again
+		handler.blockCh = make(chan bool)
+		Expect(fanoutHandler.HandleEvents(5, []event.Event{})).To(BeNil())
+		Eventually(failingHandler.HandledHeights).Should(Equal([]int64{0, 1, 2, 3, 4, 5}))
+		Expect(catchUpSource.RequestedHeights()).NotTo(ContainElement(int64(5)))
+		close(handler.blockCh)
+		Eventually(handler.HandledHeights).Should(Equal([]int64{0, 1, 2, 3, 4, 5}))
+	})
+
+	It("should record dead letter and move on when the retries of SKIP policy are exhausted", func() {
+		failingHandler := newFakeHandler("Failing", nil)
+		failingHandler.failuresLeft = 2
+		mockFailureStore := NewMockFailureStore()
+		mockFailureStore.On("MarkRunning", "Failing").Return(nil)
+		mockFailureStore.On("InsertDeadLetter", &projection.DeadLetter{
+			ProjectionId: "Failing",
+			Height:       0,
+			Events:       []event.Event{},
+			Error:        "any error",
+		}).Return(nil)
+		fanoutHandler := eventhandler.NewFanoutHandler(NewFakeLogger(), []eventhandler.Handler{
+			failingHandler,
+		}, 10).WithFailureHandling(projection.FailurePolicies{
+			Default: projection.FailurePolicy{
+				MaxRetry: 1,
+				Action:   projection.FAILURE_ACTION_SKIP,
+			},
+		}, mockFailureStore)
+
+		for height := int64(0); height <= 2; height += 1 {
+			Expect(fanoutHandler.HandleEvents(height, []event.Event{})).To(BeNil())
+		}
+
+		Eventually(failingHandler.HandledHeights, 5*time.Second).Should(Equal([]int64{1, 2}))
+		mockFailureStore.AssertExpectations(GinkgoT())
+	})
+
+	It("should not hold back other handlers while a handler is paused", func() {
+		handlerA := newFakeHandler("A", nil)
+		handlerB := newFakeHandler("B", nil)
+		fanoutHandler := eventhandler.NewFanoutHandler(NewFakeLogger(), []eventhandler.Handler{
+			handlerA, handlerB,
+		}, 2)
+
+		_, err := fanoutHandler.GetLastHandledEventHeight()
+		Expect(err).To(BeNil())
+		Expect(fanoutHandler.PauseProjection("B")).To(Succeed())
+		for height := int64(0); height <= 4; height += 1 {
+			Expect(fanoutHandler.HandleEvents(height, []event.Event{})).To(BeNil())
+		}
+
+		Eventually(handlerA.HandledHeights).Should(Equal([]int64{0, 1, 2, 3, 4}))
+		Expect(handlerB.HandledHeights()).To(BeEmpty())
+
+		// Heights no longer cached cannot be handled without a catch-up source
+		Expect(errors.Is(fanoutHandler.ResumeProjection("B"), eventhandler.ErrNotCached)).To(BeTrue())
+		Expect(fanoutHandler.HandleEvents(5, []event.Event{})).To(BeNil())
+
+		Eventually(handlerA.HandledHeights).Should(Equal([]int64{0, 1, 2, 3, 4, 5}))
+		Consistently(handlerB.HandledHeights).Should(BeEmpty())
+	})
+
+	It("should resume a paused handler from the catch-up source once its heights are no longer cached", func() {
+		handlerA := newFakeHandler("A", nil)
+		handlerB := newFakeHandler("B", nil)
+		catchUpSource := newFakeCatchUpSource()
+		fanoutHandler := eventhandler.NewFanoutHandler(NewFakeLogger(), []eventhandler.Handler{
+			handlerA, handlerB,
+		}, 2).WithCatchUpSource(catchUpSource)
+
+		_, err := fanoutHandler.GetLastHandledEventHeight()
+		Expect(err).To(BeNil())
+		Expect(fanoutHandler.PauseProjection("B")).To(Succeed())
+		for height := int64(0); height <= 4; height += 1 {
+			Expect(fanoutHandler.HandleEvents(height, []event.Event{})).To(BeNil())
+		}
+		Eventually(handlerA.HandledHeights).Should(Equal([]int64{0, 1, 2, 3, 4}))
+
+		Expect(fanoutHandler.ResumeProjection("B")).To(Succeed())
+
+		Eventually(handlerB.HandledHeights).Should(Equal([]int64{0, 1, 2, 3, 4}))
+	})
+
+	It("should rewind a handler past the evicted heights and handle them again from the catch-up source", func() {
+		rewindableHandler := &fakeRewindableHandler{newFakeHandler("Rewindable", nil)}
+		catchUpSource := newFakeCatchUpSource()
+		fanoutHandler := eventhandler.NewFanoutHandler(NewFakeLogger(), []eventhandler.Handler{
+			rewindableHandler,
+		}, 2).WithCatchUpSource(catchUpSource)
+
+		for height := int64(0); height <= 4; height += 1 {
+			Expect(fanoutHandler.HandleEvents(height, []event.Event{})).To(BeNil())
+		}
+		Eventually(rewindableHandler.HandledHeights).Should(Equal([]int64{0, 1, 2, 3, 4}))
+
+		Expect(fanoutHandler.PauseProjection("Rewindable")).To(Succeed())
+		Expect(fanoutHandler.RewindProjection("Rewindable", 0)).To(Succeed())
+		Expect(fanoutHandler.ResumeProjection("Rewindable")).To(Succeed())
+
+		Eventually(rewindableHandler.HandledHeights).Should(Equal([]int64{0, 1, 2, 3, 4, 1, 2, 3, 4}))
+		Expect(catchUpSource.RequestedHeights()).To(Equal([]int64{1, 2, 3, 4}))
+
+		Expect(fanoutHandler.HandleEvents(5, []event.Event{})).To(BeNil())
+		Eventually(rewindableHandler.HandledHeights).Should(Equal([]int64{0, 1, 2, 3, 4, 1, 2, 3, 4, 5}))
+		Expect(catchUpSource.RequestedHeights()).To(Equal([]int64{1, 2, 3, 4}))
+	})
+
+	It("should reject rewinding past the evicted heights without catch-up source", func() {
+		rewindableHandler := &fakeRewindableHandler{newFakeHandler("Rewindable", nil)}
+		fanoutHandler := eventhandler.NewFanoutHandler(NewFakeLogger(), []eventhandler.Handler{
+			rewindableHandler,
+		}, 2)
+
+		for height := int64(0); height <= 4; height += 1 {
+			Expect(fanoutHandler.HandleEvents(height, []event.Event{})).To(BeNil())
+		}
+		Eventually(rewindableHandler.HandledHeights).Should(Equal([]int64{0, 1, 2, 3, 4}))
+
+		Expect(fanoutHandler.PauseProjection("Rewindable")).To(Succeed())
+		err := fanoutHandler.RewindProjection("Rewindable", 0)
+		Expect(errors.Is(err, eventhandler.ErrNotCached)).To(BeTrue())
+		Expect(rewindableHandler.maybeLastHandledHeight).To(BeNil())
+		Expect(fanoutHandler.ResumeProjection("Rewindable")).To(Succeed())
+	})
+
+	It("should handle the cached heights after the rewound height again after resumed", func() {
+		blockedHandler := newFakeHandler("Blocked", nil)
+		blockedHandler.blockCh = make(chan bool)
+		defer close(blockedHandler.blockCh)
+		rewindableHandler := &fakeRewindableHandler{newFakeHandler("Rewindable", nil)}
+		fanoutHandler := eventhandler.NewFanoutHandler(NewFakeLogger(), []eventhandler.Handler{
+			blockedHandler, rewindableHandler,
+		}, 10)
+
+		for height := int64(0); height <= 2; height += 1 {
+			Expect(fanoutHandler.HandleEvents(height, []event.Event{})).To(BeNil())
+		}
+		Eventually(rewindableHandler.HandledHeights).Should(Equal([]int64{0, 1, 2}))
+
+		Expect(fanoutHandler.RewindProjection("Rewindable", 0)).To(MatchError(projection.ErrProjectionNotPaused))
+		Expect(fanoutHandler.PauseProjection("Rewindable")).To(Succeed())
+		Expect(fanoutHandler.RewindProjection("Rewindable", 0)).To(Succeed())
+		Expect(fanoutHandler.ResumeProjection("Rewindable")).To(Succeed())
+
+		Eventually(rewindableHandler.HandledHeights).Should(Equal([]int64{0, 1, 2, 1, 2}))
+	})
+
+	It("should reject non-consecutive height", func() {
+		fanoutHandler := eventhandler.NewFanoutHandler(NewFakeLogger(), []eventhandler.Handler{
+			newFakeHandler("A", nil),
+		}, 10)
+
+		Expect(fanoutHandler.HandleEvents(0, []event.Event{})).To(BeNil())
+		Expect(fanoutHandler.HandleEvents(2, []event.Event{})).NotTo(BeNil())
+	})
+})
+
+type fakeHandler struct {
+	id                     string
+	maybeLastHandledHeight *int64
+	failuresLeft           int
+	blockCh                chan bool
+	handlingCh             chan int64
+
+	mutex          sync.Mutex
+	handledHeights []int64
+}
+
+func newFakeHandler(id string, maybeLastHandledHeight *int64) *fakeHandler {
+	return &fakeHandler{
+		id:                     id,
+		maybeLastHandledHeight: maybeLastHandledHeight,
+
+		handledHeights: make([]int64, 0),
+	}
+}
+
+func (handler *fakeHandler) GetLastHandledEventHeight() (*int64, error) {
+	return handler.maybeLastHandledHeight, nil
+}
+
+func (handler *fakeHandler) HandleEvents(blockHeight int64, _ []event.Event) error {
+	if handler.handlingCh != nil {
+		handler.handlingCh <- blockHeight
+	}
+	if handler.blockCh != nil {
+		<-handler.blockCh
+	}
+
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	if handler.failuresLeft > 0 {
+		handler.failuresLeft -= 1
+		return errors.New("any error")
+	}
+	handler.handledHeights = append(handler.handledHeights, blockHeight)
+	return nil
+}
+
+func (handler *fakeHandler) Id() string {
+	return handler.id
+}
+
+func (handler *fakeHandler) HandledHeights() []int64 {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	return append([]int64{}, handler.handledHeights...)
+}
+
+type fakeCatchUpSource struct {
+	mutex            sync.Mutex
+	requestedHeights []int64
+}
+
+func newFakeCatchUpSource() *fakeCatchUpSource {
+	return &fakeCatchUpSource{
+		requestedHeights: make([]int64, 0),
+	}
+}
+
+func (source *fakeCatchUpSource) GetAllByHeight(height int64) ([]event.Event, error) {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	source.requestedHeights = append(source.requestedHeights, height)
+	return []event.Event{}, nil
+}
+
+func (source *fakeCatchUpSource) RequestedHeights() []int64 {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	return append([]int64{}, source.requestedHeights...)
+}
+
+type fakeDependentHandler struct {
+	*fakeHandler
+
+	dependencies []string
+}
+
+func (handler *fakeDependentHandler) GetDependencies() []string {
+	return handler.dependencies
+}
+
+type fakeRewindableHandler struct {
+	*fakeHandler
+}
+
+func (handler *fakeRewindableHandler) Rewind(height int64) error {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	handler.maybeLastHandledHeight = &height
+	return nil
+}
@@ -9,3 +9,19 @@ type Handler interface {
 
 	Id() string
 }
+
+// DependentHandler is an optional interface of Handler to declare the handlers, by id, whose results it reads. A
+// height is handed to the handler only after all its dependencies have handled the height.
+type DependentHandler interface {
+	Handler
+
+	GetDependencies() []string
+}
+
+// RewindableHandler is an optional interface of Handler to rewind it to a height it has handled, such that the heights
+// after it are handled again
+type RewindableHandler interface {
+	Handler
+
+	Rewind(height int64) error
+}
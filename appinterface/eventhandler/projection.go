@@ -8,7 +8,8 @@ import (
 	applogger "github.com/crypto-com/chain-indexing/external/logger"
 )
 
-var _ Handler = &ProjectionHandler{}
+var _ DependentHandler = &ProjectionHandler{}
+var _ RewindableHandler = &ProjectionHandler{}
 
 type ProjectionHandler struct {
 	logger     applogger.Logger
@@ -57,6 +58,17 @@ func (handler *ProjectionHandler) Id() string {
 	return handler.projection.Id()
 }
 
+// Implements DependentHandler.GetDependencies() with the dependencies of the projection
+func (handler *ProjectionHandler) GetDependencies() []string {
+	return projection_entity.GetDependencies(handler.projection)
+}
+
+// Implements RewindableHandler.Rewind() by rewinding the projection. Returns projection.ErrRewindUnsupported when the
+// projection does not support rewind.
+func (handler *ProjectionHandler) Rewind(height int64) error {
+	return projection_entity.RewindProjection(handler.projection, height)
+}
+
 func isListeningEvent(event event.Event, eventsToListen []string) bool {
 	targetEventName := event.Name()
 	for _, eventName := range eventsToListen {
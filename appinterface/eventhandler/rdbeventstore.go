@@ -12,6 +12,20 @@ import (
 
 var _ Handler = &RDbEventStoreHandler{}
 
+// WriteFence fences the writes of a process which may have lost the right to write, e.g. a former leader
+type WriteFence interface {
+	// FenceWithRDbHandle returns error when the process is no longer allowed to write. Otherwise, the right to write is
+	// held until the transaction of the RDb handle ends.
+	FenceWithRDbHandle(rdbHandle *rdb.Handle) error
+}
+
+// WriteFenced is a component of which the writes are fenced by the write fence set, e.g. a projection or a cron job
+// which only the leader should write
+type WriteFenced interface {
+	// SetWriteFence sets the write fence to pass in every transaction the component writes
+	SetWriteFence(writeFence WriteFence)
+}
+
 // RDbEventStoreHandler is an event handler which persist the event to event store
 type RDbEventStoreHandler struct {
 	logger  applogger.Logger
@@ -19,6 +33,11 @@ type RDbEventStoreHandler struct {
 
 	eventStore  *event_interface.RDbStore
 	statusStore *rdbstatusstore.RDbStatusStore
+
+	// Event store other than the RDb one, e.g. the segment files, outside of the status transaction
+	maybeExternalEventStore event.Store
+
+	maybeWriteFence WriteFence
 }
 
 func NewRDbEventStoreHandler(
@@ -38,18 +57,41 @@ func NewRDbEventStoreHandler(
 	}
 }
 
+// WithEventStore persists the events to the event store instead of the RDb one. The event store must have the events
+// durable once InsertAll returns. The events are persisted before the last indexed block height is updated, so the
+// events of a height already stored are skipped when the height is handled again after a failure in between.
+func (handler *RDbEventStoreHandler) WithEventStore(eventStore event.Store) *RDbEventStoreHandler {
+	handler.maybeExternalEventStore = eventStore
+	return handler
+}
+
+// WithWriteFence persists the events of a height only when the write fence is passed in the same transaction
+func (handler *RDbEventStoreHandler) WithWriteFence(writeFence WriteFence) *RDbEventStoreHandler {
+	handler.maybeWriteFence = writeFence
+	return handler
+}
+
 func (handler *RDbEventStoreHandler) GetLastHandledEventHeight() (*int64, error) {
 	return handler.statusStore.GetLastIndexedBlockHeight()
 }
 
 func (handler *RDbEventStoreHandler) HandleEvents(blockHeight int64, events []event.Event) error {
 	handler.logger.Debug("start persisting blocks events")
+	if handler.maybeExternalEventStore != nil {
+		return handler.handleEventsWithExternalEventStore(blockHeight, events)
+	}
+
 	tx, err := handler.rdbConn.Begin()
 	if err != nil {
 		return fmt.Errorf("error when beginning transaction: %v", err)
 	}
 	txHandle := tx.ToHandle()
 
+	if err := handler.fenceWithRDbHandle(txHandle); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("error persisting events for height %d: %v", blockHeight, err)
+	}
+
 	if err := handler.eventStore.InsertAllWithRDbHandle(txHandle, events); err != nil {
 		return fmt.Errorf("error storing all events for height %d: %v", blockHeight, err)
 	}
@@ -58,12 +100,67 @@ func (handler *RDbEventStoreHandler) HandleEvents(blockHeight int64, events []ev
 		return fmt.Errorf("error updating last indexed block height to %d: %v", blockHeight, err)
 	}
 
+	if err := handler.eventStore.NotifyLatestHeightWithRDbHandle(txHandle, blockHeight); err != nil {
+		return fmt.Errorf("error notifying latest event height %d: %v", blockHeight, err)
+	}
+
 	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("error committing block synchronization outcomes: %v", err)
 	}
 	return nil
 }
 
+func (handler *RDbEventStoreHandler) handleEventsWithExternalEventStore(blockHeight int64, events []event.Event) error {
+	maybeLatestHeight, err := handler.maybeExternalEventStore.GetLatestHeight()
+	if err != nil {
+		return fmt.Errorf("error getting latest event height: %v", err)
+	}
+
+	tx, err := handler.rdbConn.Begin()
+	if err != nil {
+		return fmt.Errorf("error when beginning transaction: %v", err)
+	}
+	txHandle := tx.ToHandle()
+
+	if err := handler.fenceWithRDbHandle(txHandle); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("error persisting events for height %d: %v", blockHeight, err)
+	}
+
+	// The events cannot be written in the transaction. They are made durable before the last indexed block height is
+	// updated, so that the height is never committed ahead of the events stored.
+	if maybeLatestHeight == nil || *maybeLatestHeight < blockHeight {
+		if err := handler.maybeExternalEventStore.InsertAll(events); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("error storing all events for height %d: %v", blockHeight, err)
+		}
+	}
+
+	if err := handler.statusStore.UpdateLastIndexedBlockHeightWithRDbHandle(txHandle, blockHeight); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("error updating last indexed block height to %d: %v", blockHeight, err)
+	}
+
+	// Notifies the subscribers in the other processes, e.g. the projection workers, once the height is committed
+	if err := handler.eventStore.NotifyLatestHeightWithRDbHandle(txHandle, blockHeight); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("error notifying latest event height %d: %v", blockHeight, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing last indexed block height: %v", err)
+	}
+	return nil
+}
+
+func (handler *RDbEventStoreHandler) fenceWithRDbHandle(rdbHandle *rdb.Handle) error {
+	if handler.maybeWriteFence == nil {
+		return nil
+	}
+
+	return handler.maybeWriteFence.FenceWithRDbHandle(rdbHandle)
+}
+
 func (handler *RDbEventStoreHandler) Id() string {
 	return "RDbEventStoreHandler"
 }
@@ -0,0 +1,74 @@
+package eventhandler_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/crypto-com/chain-indexing/appinterface/eventhandler"
+	"github.com/crypto-com/chain-indexing/appinterface/rdb"
+	. "github.com/crypto-com/chain-indexing/appinterface/rdb/test"
+	"github.com/crypto-com/chain-indexing/entity/event"
+	. "github.com/crypto-com/chain-indexing/entity/event/test"
+	. "github.com/crypto-com/chain-indexing/external/logger/test"
+	"github.com/crypto-com/chain-indexing/infrastructure/pg"
+)
+
+func sqlContaining(substr string) interface{} {
+	return mock.MatchedBy(func(sql string) bool {
+		return strings.Contains(sql, substr)
+	})
+}
+
+var _ = Describe("RDbEventStoreHandler", func() {
+	It("should commit and notify the height only after the events are stored in the external event store", func() {
+		writes := make([]string, 0)
+
+		mockConn := NewMockRDbConn()
+		mockConn.On("ToHandle").Return(&rdb.Handle{
+			Runner:      mockConn,
+			TypeConv:    &pg.PgxTypeConv{},
+			StmtBuilder: pg.PostgresStmtBuilder,
+		})
+		mockStatusRowCount := &MockRDbRowResult{}
+		mockStatusRowCount.On("Scan", mock.Anything).Run(func(args mock.Arguments) {
+			*(args.Get(0).(*int64)) = 1
+		}).Return(nil)
+		mockConn.On("QueryRow", sqlContaining("COUNT(*)")).Return(mockStatusRowCount)
+
+		mockExecResult := &MockRDbExecResult{}
+		mockExecResult.On("RowsAffected").Return(int64(1))
+		mockTx := &MockRDbTx{}
+		mockTx.On("ToHandle").Return(&rdb.Handle{
+			Runner:      mockTx,
+			TypeConv:    &pg.PgxTypeConv{},
+			StmtBuilder: pg.PostgresStmtBuilder,
+		})
+		mockTx.On("Exec", sqlContaining("UPDATE service_status"), int64(10)).Run(func(_ mock.Arguments) {
+			writes = append(writes, "height")
+		}).Return(mockExecResult, nil)
+		mockTx.On("Exec", sqlContaining("pg_notify"), mock.Anything, "10").Run(func(_ mock.Arguments) {
+			writes = append(writes, "notify")
+		}).Return(mockExecResult, nil)
+		mockTx.On("Commit").Run(func(_ mock.Arguments) {
+			writes = append(writes, "commit")
+		}).Return(nil)
+		mockConn.On("Begin").Return(mockTx, nil)
+
+		events := []event.Event{NewFakeEvent()}
+		mockEventStore := NewMockEventStore()
+		mockEventStore.On("GetLatestHeight").Return((*int64)(nil), nil)
+		mockEventStore.On("InsertAll", events).Run(func(_ mock.Arguments) {
+			writes = append(writes, "events")
+		}).Return(nil)
+
+		handler := eventhandler.NewRDbEventStoreHandler(
+			NewFakeLogger(), mockConn, event.NewRegistry(),
+		).WithEventStore(mockEventStore)
+
+		Expect(handler.HandleEvents(10, events)).To(BeNil())
+		Expect(writes).To(Equal([]string{"events", "height", "notify", "commit"}))
+	})
+})
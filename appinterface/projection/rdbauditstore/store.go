@@ -0,0 +1,143 @@
+package rdbauditstore
+
+import (
+	"fmt"
+
+	"github.com/crypto-com/chain-indexing/appinterface/pagination"
+	"github.com/crypto-com/chain-indexing/appinterface/rdb"
+	"github.com/crypto-com/chain-indexing/external/utctime"
+)
+
+const TABLE_NAME = "projection_admin_audits"
+
+const ACTION_PAUSE = "PAUSE"
+const ACTION_RESUME = "RESUME"
+const ACTION_REWIND = "REWIND"
+const ACTION_REBUILD = "REBUILD"
+
+// Recorded once a triggered rebuild has finished, with the error when it has failed
+const ACTION_REBUILD_FINISHED = "REBUILD_FINISHED"
+
+// RDbAuditStore keeps the audit trail of the admin actions on the projections in relational database
+type RDbAuditStore struct {
+	rdb *rdb.Handle
+}
+
+func NewRDbAuditStore(rdbHandle *rdb.Handle) *RDbAuditStore {
+	return &RDbAuditStore{
+		rdbHandle,
+	}
+}
+
+func (store *RDbAuditStore) Insert(audit *AuditRow) error {
+	createdAt := utctime.Now()
+	sql, sqlArgs, err := store.rdb.StmtBuilder.Insert(
+		TABLE_NAME,
+	).Columns(
+		"projection_id",
+		"action",
+		"maybe_height",
+		"actor",
+		"remote_address",
+		"maybe_error",
+		"created_at",
+	).Values(
+		audit.ProjectionId,
+		audit.Action,
+		audit.MaybeHeight,
+		audit.Actor,
+		audit.RemoteAddress,
+		audit.MaybeError,
+		store.rdb.Tton(&createdAt),
+	).ToSql()
+	if err != nil {
+		return fmt.Errorf("error building projection admin audit insertion SQL: %v", err)
+	}
+
+	result, err := store.rdb.Exec(sql, sqlArgs...)
+	if err != nil {
+		return fmt.Errorf("error inserting projection admin audit: %v", err)
+	}
+	if result.RowsAffected() != 1 {
+		return fmt.Errorf("error inserting projection admin audit: %d rows inserted", result.RowsAffected())
+	}
+
+	return nil
+}
+
+// List returns the audits from the latest
+func (store *RDbAuditStore) List(
+	pagination *pagination.Pagination,
+) ([]AuditRow, *pagination.PaginationResult, error) {
+	stmtBuilder := store.rdb.StmtBuilder.Select(
+		"id",
+		"projection_id",
+		"action",
+		"maybe_height",
+		"actor",
+		"remote_address",
+		"maybe_error",
+		"created_at",
+	).From(
+		TABLE_NAME,
+	).OrderBy("id DESC")
+
+	rDbPagination := rdb.NewRDbPaginationBuilder(
+		pagination,
+		store.rdb,
+	).BuildStmt(stmtBuilder)
+	sql, sqlArgs, err := rDbPagination.ToStmtBuilder().ToSql()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error building projection admin audits selection SQL: %v: %w", err, rdb.ErrPrepare)
+	}
+
+	rowsResult, err := store.rdb.Query(sql, sqlArgs...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error executing projection admin audits selection SQL: %v: %w", err, rdb.ErrQuery)
+	}
+	defer rowsResult.Close()
+
+	rows := make([]AuditRow, 0)
+	for rowsResult.Next() {
+		var row AuditRow
+		createdAtReader := store.rdb.NtotReader()
+		if err := rowsResult.Scan(
+			&row.Id,
+			&row.ProjectionId,
+			&row.Action,
+			&row.MaybeHeight,
+			&row.Actor,
+			&row.RemoteAddress,
+			&row.MaybeError,
+			createdAtReader.ScannableArg(),
+		); err != nil {
+			return nil, nil, fmt.Errorf("error scanning projection admin audit row: %v: %w", err, rdb.ErrQuery)
+		}
+
+		createdAt, err := createdAtReader.Parse()
+		if err != nil {
+			return nil, nil, fmt.Errorf("error parsing projection admin audit created at: %v: %w", err, rdb.ErrQuery)
+		}
+		row.CreatedAt = *createdAt
+
+		rows = append(rows, row)
+	}
+
+	paginationResult, err := rDbPagination.Result()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error preparing pagination result: %v", err)
+	}
+
+	return rows, paginationResult, nil
+}
+
+type AuditRow struct {
+	Id            int64           `json:"id"`
+	ProjectionId  string          `json:"projectionId"`
+	Action        string          `json:"action"`
+	MaybeHeight   *int64          `json:"height"`
+	Actor         string          `json:"actor"`
+	RemoteAddress string          `json:"remoteAddress"`
+	MaybeError    *string         `json:"error"`
+	CreatedAt     utctime.UTCTime `json:"createdAt"`
+}
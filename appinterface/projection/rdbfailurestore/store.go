@@ -0,0 +1,315 @@
+package rdbfailurestore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/crypto-com/chain-indexing/appinterface/pagination"
+	"github.com/crypto-com/chain-indexing/appinterface/rdb"
+	projection_entity "github.com/crypto-com/chain-indexing/entity/projection"
+	"github.com/crypto-com/chain-indexing/external/utctime"
+)
+
+const STATUSES_TABLE_NAME = "projection_statuses"
+const DEAD_LETTERS_TABLE_NAME = "projection_dead_letters"
+
+var _ projection_entity.FailureStore = &RDbFailureStore{}
+
+// RDbFailureStore keeps the failure state of the projections and their dead letters in relational database
+type RDbFailureStore struct {
+	rdb *rdb.Handle
+}
+
+func NewRDbFailureStore(rdbHandle *rdb.Handle) *RDbFailureStore {
+	return &RDbFailureStore{
+		rdbHandle,
+	}
+}
+
+func (store *RDbFailureStore) MarkRunning(projectionId string) error {
+	return store.markState(projectionId, projection_entity.STATE_RUNNING)
+}
+
+func (store *RDbFailureStore) MarkPaused(projectionId string) error {
+	return store.markState(projectionId, projection_entity.STATE_PAUSED)
+}
+
+// markState upserts the state of the projection, keeping the details of its last failure
+func (store *RDbFailureStore) markState(projectionId string, state string) error {
+	updatedAt := utctime.Now()
+	sql, sqlArgs, err := store.rdb.StmtBuilder.Insert(
+		STATUSES_TABLE_NAME,
+	).Columns(
+		"id",
+		"state",
+		"updated_at",
+	).Values(
+		projectionId,
+		state,
+		store.rdb.Tton(&updatedAt),
+	).Suffix(
+		"ON CONFLICT (id) DO UPDATE SET state = EXCLUDED.state, updated_at = EXCLUDED.updated_at",
+	).ToSql()
+	if err != nil {
+		return fmt.Errorf("error building projection status upsert SQL: %v", err)
+	}
+
+	if _, err = store.rdb.Exec(sql, sqlArgs...); err != nil {
+		return fmt.Errorf("error upserting projection status: %v", err)
+	}
+
+	return nil
+}
+
+func (store *RDbFailureStore) MarkFailed(projectionId string, height int64, errMessage string) error {
+	updatedAt := utctime.Now()
+	sql, sqlArgs, err := store.rdb.StmtBuilder.Insert(
+		STATUSES_TABLE_NAME,
+	).Columns(
+		"id",
+		"state",
+		"maybe_failed_height",
+		"maybe_error",
+		"updated_at",
+	).Values(
+		projectionId,
+		projection_entity.STATE_FAILED,
+		height,
+		errMessage,
+		store.rdb.Tton(&updatedAt),
+	).Suffix(`ON CONFLICT (id) DO UPDATE SET
+		state = EXCLUDED.state,
+		maybe_failed_height = EXCLUDED.maybe_failed_height,
+		maybe_error = EXCLUDED.maybe_error,
+		updated_at = EXCLUDED.updated_at`,
+	).ToSql()
+	if err != nil {
+		return fmt.Errorf("error building projection status upsert SQL: %v", err)
+	}
+
+	if _, err = store.rdb.Exec(sql, sqlArgs...); err != nil {
+		return fmt.Errorf("error upserting projection status: %v", err)
+	}
+
+	return nil
+}
+
+// InsertDeadLetter implements FailureStore.InsertDeadLetter(). The dead letter replaces the existing one of the same
+// height.
+func (store *RDbFailureStore) InsertDeadLetter(deadLetter *projection_entity.DeadLetter) error {
+	encodedEvents := make([]string, 0, len(deadLetter.Events))
+	for _, event := range deadLetter.Events {
+		encodedEvent, err := event.ToJSON()
+		if err != nil {
+			return fmt.Errorf("error encoding event %s of dead letter: %v", event.UUID(), err)
+		}
+		encodedEvents = append(encodedEvents, encodedEvent)
+	}
+
+	createdAt := utctime.Now()
+	sql, sqlArgs, err := store.rdb.StmtBuilder.Insert(
+		DEAD_LETTERS_TABLE_NAME,
+	).Columns(
+		"projection_id",
+		"height",
+		"events",
+		"error",
+		"created_at",
+	).Values(
+		deadLetter.ProjectionId,
+		deadLetter.Height,
+		fmt.Sprintf("[%s]", strings.Join(encodedEvents, ",")),
+		deadLetter.Error,
+		store.rdb.Tton(&createdAt),
+	).Suffix(`ON CONFLICT (projection_id, height) DO UPDATE SET
+		events = EXCLUDED.events,
+		error = EXCLUDED.error,
+		created_at = EXCLUDED.created_at`,
+	).ToSql()
+	if err != nil {
+		return fmt.Errorf("error building projection dead letter insertion SQL: %v", err)
+	}
+
+	result, err := store.rdb.Exec(sql, sqlArgs...)
+	if err != nil {
+		return fmt.Errorf("error inserting projection dead letter: %v", err)
+	}
+	if result.RowsAffected() != 1 {
+		return fmt.Errorf("error inserting projection dead letter: %d rows inserted", result.RowsAffected())
+	}
+
+	return nil
+}
+
+// ListStatuses returns the status of every projection which has ever run with a failure store
+func (store *RDbFailureStore) ListStatuses() ([]StatusRow, error) {
+	sql, sqlArgs, err := store.statusSelectStmtBuilder().OrderBy("id").ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building projection statuses selection SQL: %v: %w", err, rdb.ErrPrepare)
+	}
+
+	rowsResult, err := store.rdb.Query(sql, sqlArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("error executing projection statuses selection SQL: %v: %w", err, rdb.ErrQuery)
+	}
+	defer rowsResult.Close()
+
+	rows := make([]StatusRow, 0)
+	for rowsResult.Next() {
+		row, err := store.scanStatusRow(rowsResult)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, *row)
+	}
+
+	return rows, nil
+}
+
+// FindStatusById returns the status of the projection. Returns rdb.ErrNoRows when the projection has no status.
+func (store *RDbFailureStore) FindStatusById(projectionId string) (*StatusRow, error) {
+	sql, sqlArgs, err := store.statusSelectStmtBuilder().Where("id = ?", projectionId).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building projection status selection SQL: %v: %w", err, rdb.ErrPrepare)
+	}
+
+	row, err := store.scanStatusRow(store.rdb.QueryRow(sql, sqlArgs...))
+	if err != nil {
+		if errors.Is(err, rdb.ErrNoRows) {
+			return nil, rdb.ErrNoRows
+		}
+		return nil, err
+	}
+
+	return row, nil
+}
+
+func (store *RDbFailureStore) statusSelectStmtBuilder() sq.SelectBuilder {
+	return store.rdb.StmtBuilder.Select(
+		"id",
+		"state",
+		"maybe_failed_height",
+		"maybe_error",
+		"updated_at",
+	).From(STATUSES_TABLE_NAME)
+}
+
+func (store *RDbFailureStore) scanStatusRow(scanner rowScanner) (*StatusRow, error) {
+	var row StatusRow
+	updatedAtReader := store.rdb.NtotReader()
+	if err := scanner.Scan(
+		&row.Id,
+		&row.State,
+		&row.MaybeFailedHeight,
+		&row.MaybeError,
+		updatedAtReader.ScannableArg(),
+	); err != nil {
+		if errors.Is(err, rdb.ErrNoRows) {
+			return nil, rdb.ErrNoRows
+		}
+		return nil, fmt.Errorf("error scanning projection status row: %v: %w", err, rdb.ErrQuery)
+	}
+
+	updatedAt, err := updatedAtReader.Parse()
+	if err != nil {
+		return nil, fmt.Errorf("error parsing projection status updated at: %v: %w", err, rdb.ErrQuery)
+	}
+	row.UpdatedAt = *updatedAt
+
+	return &row, nil
+}
+
+// ListDeadLettersByProjectionId returns the dead letters of the projection from the latest
+func (store *RDbFailureStore) ListDeadLettersByProjectionId(
+	projectionId string,
+	pagination *pagination.Pagination,
+) ([]DeadLetterRow, *pagination.PaginationResult, error) {
+	stmtBuilder := store.rdb.StmtBuilder.Select(
+		"id",
+		"projection_id",
+		"height",
+		"events",
+		"error",
+		"created_at",
+	).From(
+		DEAD_LETTERS_TABLE_NAME,
+	).Where(
+		"projection_id = ?", projectionId,
+	).OrderBy("id DESC")
+
+	rDbPagination := rdb.NewRDbPaginationBuilder(
+		pagination,
+		store.rdb,
+	).BuildStmt(stmtBuilder)
+	sql, sqlArgs, err := rDbPagination.ToStmtBuilder().ToSql()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error building projection dead letters selection SQL: %v: %w", err, rdb.ErrPrepare)
+	}
+
+	rowsResult, err := store.rdb.Query(sql, sqlArgs...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error executing projection dead letters selection SQL: %v: %w", err, rdb.ErrQuery)
+	}
+	defer rowsResult.Close()
+
+	rows := make([]DeadLetterRow, 0)
+	for rowsResult.Next() {
+		var row DeadLetterRow
+		var eventsJSON string
+		createdAtReader := store.rdb.NtotReader()
+		if err := rowsResult.Scan(
+			&row.Id,
+			&row.ProjectionId,
+			&row.Height,
+			&eventsJSON,
+			&row.Error,
+			createdAtReader.ScannableArg(),
+		); err != nil {
+			return nil, nil, fmt.Errorf("error scanning projection dead letter row: %v: %w", err, rdb.ErrQuery)
+		}
+
+		if err := json.Unmarshal([]byte(eventsJSON), &row.Events); err != nil {
+			return nil, nil, fmt.Errorf("error decoding projection dead letter events: %v: %w", err, rdb.ErrQuery)
+		}
+
+		createdAt, err := createdAtReader.Parse()
+		if err != nil {
+			return nil, nil, fmt.Errorf("error parsing projection dead letter created at: %v: %w", err, rdb.ErrQuery)
+		}
+		row.CreatedAt = *createdAt
+
+		rows = append(rows, row)
+	}
+
+	paginationResult, err := rDbPagination.Result()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error preparing pagination result: %v", err)
+	}
+
+	return rows, paginationResult, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+type StatusRow struct {
+	Id                string          `json:"id"`
+	State             string          `json:"state"`
+	MaybeFailedHeight *int64          `json:"failedHeight"`
+	MaybeError        *string         `json:"error"`
+	UpdatedAt         utctime.UTCTime `json:"updatedAt"`
+}
+
+type DeadLetterRow struct {
+	Id           int64             `json:"id"`
+	ProjectionId string            `json:"projectionId"`
+	Height       int64             `json:"height"`
+	Events       []json.RawMessage `json:"events"`
+	Error        string            `json:"error"`
+	CreatedAt    utctime.UTCTime   `json:"createdAt"`
+}
@@ -0,0 +1,259 @@
+package rdbleaser
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+
+	"github.com/crypto-com/chain-indexing/appinterface/rdb"
+	projection_entity "github.com/crypto-com/chain-indexing/entity/projection"
+	applogger "github.com/crypto-com/chain-indexing/external/logger"
+)
+
+const TABLE_NAME = "projection_leases"
+
+const DEFAULT_LEASE_DURATION = 30 * time.Second
+
+// NOW_SQL is the current time of the database in nanoseconds. The leases are compared against the clock of the
+// database such that the clock skew of the processes does not matter.
+const NOW_SQL = "(EXTRACT(EPOCH FROM clock_timestamp()) * 1000000000)::BIGINT"
+
+var _ projection_entity.Leaser = &RDbLeaser{}
+
+// RDbLeaser grants the leases of the projections in relational database. A lease expires after the lease duration
+// unless renewed by its holder, which is done every third of the lease duration. The expiries are set and compared with
+// the clock of the database.
+//
+// The lease of a projection can be acquired again by the same leaser, e.g. by the rebuilder of a projection paused by the
+// manager holding its lease. The lease is only given up once all of them are released.
+type RDbLeaser struct {
+	logger applogger.Logger
+	rdb    *rdb.Handle
+
+	holder        string
+	leaseDuration time.Duration
+
+	// Guards the acquisition and releasing of the leases such that the counts match the lease records
+	heldMutex sync.Mutex
+	// Number of leases held and neither released nor lost per projection
+	heldCounts map[string]int
+}
+
+func NewRDbLeaser(logger applogger.Logger, rdbHandle *rdb.Handle, leaseDuration time.Duration) *RDbLeaser {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+
+	return &RDbLeaser{
+		logger: logger.WithFields(applogger.LogFields{
+			"module": "RDbLeaser",
+		}),
+		rdb: rdbHandle,
+
+		holder:        fmt.Sprintf("%s:%d:%s", hostname, os.Getpid(), uuid.New().String()),
+		leaseDuration: leaseDuration,
+
+		heldCounts: make(map[string]int),
+	}
+}
+
+// TryAcquire implements Leaser.TryAcquire(). The lease is acquired when it is not held, already held by this leaser, or
+// expired.
+func (leaser *RDbLeaser) TryAcquire(projectionId string) (projection_entity.Lease, error) {
+	// Measured before the lease is acquired, such that the local estimate of the expiry is never after the actual one
+	expiresAt := time.Now().Add(leaser.leaseDuration)
+	sql, sqlArgs, err := leaser.rdb.StmtBuilder.Insert(
+		TABLE_NAME,
+	).Columns(
+		"projection_id",
+		"holder",
+		"expires_at",
+	).Values(
+		projectionId,
+		leaser.holder,
+		leaser.expiresAtExpr(),
+	).Suffix(`ON CONFLICT (projection_id) DO UPDATE SET
+		holder = EXCLUDED.holder,
+		expires_at = EXCLUDED.expires_at
+		WHERE projection_leases.holder = EXCLUDED.holder OR projection_leases.expires_at < ` + NOW_SQL,
+	).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building projection lease upsert SQL: %v", err)
+	}
+
+	leaser.heldMutex.Lock()
+	defer leaser.heldMutex.Unlock()
+
+	result, err := leaser.rdb.Exec(sql, sqlArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("error upserting projection lease: %v", err)
+	}
+	if result.RowsAffected() == 0 {
+		return nil, nil
+	}
+	leaser.heldCounts[projectionId] += 1
+
+	lease := &RDbLease{
+		leaser:       leaser,
+		projectionId: projectionId,
+		expiresAt:    expiresAt,
+
+		lostCh:    make(chan struct{}),
+		releaseCh: make(chan struct{}),
+	}
+	lease.renewWaitGroup.Add(1)
+	go lease.keepRenewed()
+
+	return lease, nil
+}
+
+// expiresAtExpr returns the SQL expression of the expiry of a lease acquired or renewed now
+func (leaser *RDbLeaser) expiresAtExpr() sq.Sqlizer {
+	return sq.Expr(NOW_SQL+" + ?", leaser.leaseDuration.Nanoseconds())
+}
+
+// renew extends the lease of the projection when it is still held by this leaser. Returns false when the lease is no
+// longer held.
+func (leaser *RDbLeaser) renew(projectionId string) (bool, error) {
+	sql, sqlArgs, err := leaser.rdb.StmtBuilder.Update(
+		TABLE_NAME,
+	).Set(
+		"expires_at", leaser.expiresAtExpr(),
+	).Where(
+		"projection_id = ? AND holder = ?", projectionId, leaser.holder,
+	).ToSql()
+	if err != nil {
+		return false, fmt.Errorf("error building projection lease renewal SQL: %v", err)
+	}
+
+	result, err := leaser.rdb.Exec(sql, sqlArgs...)
+	if err != nil {
+		return false, fmt.Errorf("error renewing projection lease: %v", err)
+	}
+
+	return result.RowsAffected() != 0, nil
+}
+
+// forget stops counting a lease of the projection as held. Must be called with the heldMutex locked. Returns true when
+// no other lease of the projection is held by this leaser.
+func (leaser *RDbLeaser) forget(projectionId string) bool {
+	leaser.heldCounts[projectionId] -= 1
+	if leaser.heldCounts[projectionId] > 0 {
+		return false
+	}
+	delete(leaser.heldCounts, projectionId)
+
+	return true
+}
+
+func (leaser *RDbLeaser) release(projectionId string) error {
+	sql, sqlArgs, err := leaser.rdb.StmtBuilder.Delete(
+		TABLE_NAME,
+	).Where(
+		"projection_id = ? AND holder = ?", projectionId, leaser.holder,
+	).ToSql()
+	if err != nil {
+		return fmt.Errorf("error building projection lease deletion SQL: %v", err)
+	}
+
+	if _, err = leaser.rdb.Exec(sql, sqlArgs...); err != nil {
+		return fmt.Errorf("error deleting projection lease: %v", err)
+	}
+
+	return nil
+}
+
+// RDbLease is a lease of a projection renewed in background until released or lost
+type RDbLease struct {
+	leaser       *RDbLeaser
+	projectionId string
+	// Local estimate of the expiry of the lease as last renewed, only accessed by the renewal goroutine
+	expiresAt time.Time
+
+	lostCh         chan struct{}
+	releaseCh      chan struct{}
+	releaseOnce    sync.Once
+	renewWaitGroup sync.WaitGroup
+	// Guarded by the heldMutex of the leaser
+	isForgotten bool
+}
+
+func (lease *RDbLease) Holder() string {
+	return lease.leaser.holder
+}
+
+func (lease *RDbLease) Lost() <-chan struct{} {
+	return lease.lostCh
+}
+
+func (lease *RDbLease) Release() error {
+	var err error
+	lease.releaseOnce.Do(func() {
+		close(lease.releaseCh)
+		lease.renewWaitGroup.Wait()
+
+		lease.leaser.heldMutex.Lock()
+		defer lease.leaser.heldMutex.Unlock()
+		// Lost lease has been forgotten already and is held by another process if any
+		if lease.forget() {
+			err = lease.leaser.release(lease.projectionId)
+		}
+	})
+
+	return err
+}
+
+// forget stops counting the lease as held by the leaser once. Must be called with the heldMutex of the leaser locked.
+// Returns true when no other lease of the projection is held by the leaser.
+func (lease *RDbLease) forget() bool {
+	if lease.isForgotten {
+		return false
+	}
+	lease.isForgotten = true
+
+	return lease.leaser.forget(lease.projectionId)
+}
+
+// keepRenewed renews the lease every third of the lease duration until it is released. The lease is lost when it has
+// been taken over, or has expired before it could be renewed.
+func (lease *RDbLease) keepRenewed() {
+	defer lease.renewWaitGroup.Done()
+
+	logger := lease.leaser.logger.WithFields(applogger.LogFields{
+		"projection": lease.projectionId,
+	})
+	ticker := time.NewTicker(lease.leaser.leaseDuration / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-lease.releaseCh:
+			return
+		case <-ticker.C:
+		}
+
+		now := time.Now()
+		expiresAt := now.Add(lease.leaser.leaseDuration)
+		isHeld, err := lease.leaser.renew(lease.projectionId)
+		if err != nil {
+			logger.Errorf("error renewing projection lease: %v", err)
+			if now.Before(lease.expiresAt) {
+				continue
+			}
+			isHeld = false
+		}
+		if !isHeld {
+			logger.Errorf("projection lease lost")
+			lease.leaser.heldMutex.Lock()
+			lease.forget()
+			lease.leaser.heldMutex.Unlock()
+			close(lease.lostCh)
+			return
+		}
+		lease.expiresAt = expiresAt
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/crypto-com/chain-indexing/external/json"
 	"github.com/crypto-com/chain-indexing/external/logger"
 	event_usecase "github.com/crypto-com/chain-indexing/usecase/event"
+	"github.com/crypto-com/chain-indexing/usecase/model"
 	"github.com/crypto-com/chain-indexing/usecase/model/genesis"
 )
 
@@ -36,6 +37,7 @@ func NewBase(tableName string, paramList []types.ParamAccessor) *Base {
 func (projection *Base) GetEventsToListen() []string {
 	return []string{
 		event_usecase.GENESIS_CREATED,
+		event_usecase.STATE_BOOTSTRAPPED,
 		// TODO: Listen to ParamChange proposal and proposal passed event
 	}
 }
@@ -50,6 +52,15 @@ func (projection *Base) HandleEvents(conn *rdb.Handle, _ logger.Logger, events [
 					return err
 				}
 			}
+		} else if stateBootstrappedEvent, ok := event.(*event_usecase.StateBootstrapped); ok {
+			view := NewParams(conn, projection.tableName)
+			for _, param := range projection.paramList {
+				if err := projection.persistBootstrapParam(
+					view, &stateBootstrappedEvent.GovParams, param,
+				); err != nil {
+					return err
+				}
+			}
 		}
 		// TODO: support ParamChange proposal
 	}
@@ -154,3 +165,34 @@ func (projection *Base) persistGenesisParam(
 
 	return nil
 }
+
+// persistBootstrapParam persists the param bootstrapped from chain state. Only gov params are bootstrapped, other
+// params are left unset.
+func (projection *Base) persistBootstrapParam(
+	view view.Params, govParams *model.BootstrapGovParams, param types.ParamAccessor,
+) error {
+	var value string
+	switch key := fmt.Sprintf("%s.%s", param.Module, param.Key); key {
+	case "gov.min_deposit":
+		value = json.MustMarshalToString(govParams.MinDeposit)
+	case "gov.max_deposit_period":
+		value = govParams.MaxDepositPeriod
+	case "gov.voting_period":
+		value = govParams.VotingPeriod
+	case "gov.quorum":
+		value = govParams.Quorum
+	case "gov.threshold":
+		value = govParams.Threshold
+	case "gov.veto_threshold":
+		value = govParams.VetoThreshold
+
+	default:
+		return nil
+	}
+
+	if err := view.Set(param, value); err != nil {
+		return fmt.Errorf("error persisting bootstrap param %s.%s: %v", param.Module, param.Key, err)
+	}
+
+	return nil
+}
@@ -1,7 +1,11 @@
 package rdbprojectionbase
 
 import (
+	"sync"
+
+	"github.com/crypto-com/chain-indexing/appinterface/eventhandler"
 	"github.com/crypto-com/chain-indexing/appinterface/rdb"
+	projection_entity "github.com/crypto-com/chain-indexing/entity/projection"
 	"github.com/crypto-com/chain-indexing/external/primptr"
 	projection_usecase "github.com/crypto-com/chain-indexing/usecase/projection"
 )
@@ -14,6 +18,8 @@ const DEFAULT_TABLE = "projections"
 // | id                        | VARCHAR   | PRIMARY KEY |
 // | last_handled_event_height | INT64     | NOT NULL    |
 
+var _ eventhandler.WriteFenced = &Base{}
+
 // Base is a bas for projection which keeps track of last handled event height using relational
 // database. It implements Id() and GetLastHandledEventHeight() of projection interface.
 type Base struct {
@@ -21,6 +27,11 @@ type Base struct {
 
 	rdbHandle *rdb.Handle
 	store     *Store
+
+	leaseMutex       sync.Mutex
+	maybeLeaseHolder *string
+
+	maybeWriteFence eventhandler.WriteFence
 }
 
 // Create a new Base using table name in the RDb to keep the projection handling records
@@ -57,7 +68,41 @@ type Options struct {
 	MaybeTable *string
 }
 
+// SetLease implements projection.FencedProjection.SetLease(). The last handled event height is only updated while the
+// lease is held.
+func (base *Base) SetLease(maybeLease projection_entity.Lease) {
+	base.leaseMutex.Lock()
+	defer base.leaseMutex.Unlock()
+
+	if maybeLease == nil {
+		base.maybeLeaseHolder = nil
+	} else {
+		base.maybeLeaseHolder = primptr.String(maybeLease.Holder())
+	}
+}
+
+// SetWriteFence implements eventhandler.WriteFenced.SetWriteFence(). The last handled event height is only updated when
+// the write fence is passed in the same transaction, e.g. while the process is still the leader.
+func (base *Base) SetWriteFence(writeFence eventhandler.WriteFence) {
+	base.maybeWriteFence = writeFence
+}
+
 func (base *Base) UpdateLastHandledEventHeight(rdbHandle *rdb.Handle, height int64) error {
+	if base.maybeWriteFence != nil {
+		if err := base.maybeWriteFence.FenceWithRDbHandle(rdbHandle); err != nil {
+			return err
+		}
+	}
+
+	base.leaseMutex.Lock()
+	maybeLeaseHolder := base.maybeLeaseHolder
+	base.leaseMutex.Unlock()
+	if maybeLeaseHolder != nil {
+		if err := base.store.LockLease(rdbHandle, base.Id(), *maybeLeaseHolder); err != nil {
+			return err
+		}
+	}
+
 	if err := base.store.UpdateLastHandledEventHeight(rdbHandle, base.Id(), height); err != nil {
 		return err
 	}
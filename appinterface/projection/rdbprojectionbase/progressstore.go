@@ -0,0 +1,26 @@
+package rdbprojectionbase
+
+import (
+	"github.com/crypto-com/chain-indexing/appinterface/rdb"
+	projection_entity "github.com/crypto-com/chain-indexing/entity/projection"
+)
+
+var _ projection_entity.ProgressStore = &ProgressStore{}
+
+// ProgressStore reads the last handled event height of any projection from the projection handling records table, such
+// that processes can follow the progress of the projections run by each other
+type ProgressStore struct {
+	rdbHandle *rdb.Handle
+	store     *Store
+}
+
+func NewProgressStore(rdbHandle *rdb.Handle, table string) *ProgressStore {
+	return &ProgressStore{
+		rdbHandle,
+		NewStore(table),
+	}
+}
+
+func (progressStore *ProgressStore) GetLastHandledEventHeight(projectionId string) (*int64, error) {
+	return progressStore.store.GetLastHandledEventHeight(progressStore.rdbHandle, projectionId)
+}
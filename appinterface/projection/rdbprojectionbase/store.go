@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/crypto-com/chain-indexing/appinterface/projection/rdbleaser"
 	"github.com/crypto-com/chain-indexing/appinterface/rdb"
 	"github.com/crypto-com/chain-indexing/external/primptr"
 )
@@ -73,6 +74,49 @@ func (impl *Store) UpdateLastHandledEventHeight(rdbHandle *rdb.Handle, projectio
 	return nil
 }
 
+// LockLease locks the unexpired lease of the projection held by the holder until the transaction of the RDb handle
+// ends, such that the lease cannot be taken over before the writes of the holder are committed. Returns error when the
+// lease is not held by the holder.
+func (impl *Store) LockLease(rdbHandle *rdb.Handle, projectionId string, holder string) error {
+	sql, args, err := rdbHandle.StmtBuilder.Select(
+		"holder",
+	).From(
+		rdbleaser.TABLE_NAME,
+	).Where(
+		"projection_id = ? AND holder = ? AND expires_at > "+rdbleaser.NOW_SQL, projectionId, holder,
+	).Suffix("FOR SHARE").ToSql()
+	if err != nil {
+		return fmt.Errorf("error building projection lease lock SQL: %v", err)
+	}
+
+	var lockedHolder string
+	if err := rdbHandle.QueryRow(sql, args...).Scan(&lockedHolder); err != nil {
+		if errors.Is(err, rdb.ErrNoRows) {
+			return fmt.Errorf("error locking projection lease: lease of %s is no longer held", projectionId)
+		}
+		return fmt.Errorf("error executing projection lease lock SQL: %v", err)
+	}
+
+	return nil
+}
+
+// ResetLastHandledEventHeight removes the projection record so that the projection is treated as having handled no
+// event
+func (impl *Store) ResetLastHandledEventHeight(rdbHandle *rdb.Handle, projectionId string) error {
+	sql, args, err := rdbHandle.StmtBuilder.Delete(
+		impl.table,
+	).Where("id = ?", projectionId).ToSql()
+	if err != nil {
+		return fmt.Errorf("error building last handled event height deletion SQL: %v", err)
+	}
+
+	if _, err := rdbHandle.Exec(sql, args...); err != nil {
+		return fmt.Errorf("error executing last handled event height deletion SQL: %v", err)
+	}
+
+	return nil
+}
+
 // GetLastHandledEventHeight returns the last handled event height, nil if no event has been
 // handled
 func (impl *Store) GetLastHandledEventHeight(rdbHandle *rdb.Handle, projectionId string) (*int64, error) {
@@ -2,6 +2,7 @@ package rdbprojectionbase_test
 
 import (
 	"fmt"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -52,6 +53,47 @@ var _ = Describe("RdbStoreImpl", func() {
 			})
 		})
 
+		Describe("ResetLastHandledEventHeight", func() {
+			It("should remove the projection record", func() {
+				store := rdbprojectionbase.NewStore(rdbprojectionbase.DEFAULT_TABLE)
+
+				anyProjectionId := "projection"
+				err := store.UpdateLastHandledEventHeight(pgxConn.ToHandle(), anyProjectionId, int64(100))
+				Expect(err).To(BeNil())
+
+				err = store.ResetLastHandledEventHeight(pgxConn.ToHandle(), anyProjectionId)
+				Expect(err).To(BeNil())
+
+				Expect(IsProjectionRowExist(pgxConn, anyProjectionId)).To(BeFalse())
+				actual, err := store.GetLastHandledEventHeight(pgxConn.ToHandle(), anyProjectionId)
+				Expect(err).To(BeNil())
+				Expect(actual).To(Equal(primptr.Int64Nil()))
+			})
+		})
+
+		Describe("LockLease", func() {
+			It("should lock the unexpired lease held by the holder", func() {
+				store := rdbprojectionbase.NewStore(rdbprojectionbase.DEFAULT_TABLE)
+				InsertProjectionLease(pgxConn, "projection", "holder", time.Minute)
+
+				Expect(store.LockLease(pgxConn.ToHandle(), "projection", "holder")).To(BeNil())
+			})
+
+			It("should return Error when the lease is held by another holder", func() {
+				store := rdbprojectionbase.NewStore(rdbprojectionbase.DEFAULT_TABLE)
+				InsertProjectionLease(pgxConn, "projection", "another_holder", time.Minute)
+
+				Expect(store.LockLease(pgxConn.ToHandle(), "projection", "holder")).NotTo(BeNil())
+			})
+
+			It("should return Error when the lease has expired", func() {
+				store := rdbprojectionbase.NewStore(rdbprojectionbase.DEFAULT_TABLE)
+				InsertProjectionLease(pgxConn, "projection", "holder", -time.Minute)
+
+				Expect(store.LockLease(pgxConn.ToHandle(), "projection", "holder")).NotTo(BeNil())
+			})
+		})
+
 		It("should update projection last handled height when record already exist", func() {
 			var err error
 
@@ -75,6 +117,15 @@ var _ = Describe("RdbStoreImpl", func() {
 	})
 })
 
+func InsertProjectionLease(pgxConn *pg.PgxConn, projectionId string, holder string, expiresIn time.Duration) {
+	if _, err := pgxConn.Exec(
+		"INSERT INTO projection_leases (projection_id, holder, expires_at) VALUES ($1, $2, $3)",
+		projectionId, holder, time.Now().Add(expiresIn).UnixNano(),
+	); err != nil {
+		panic(err)
+	}
+}
+
 func IsProjectionRowExist(pgxConn *pg.PgxConn, projectionId string) bool {
 	var rowCount int64
 	if err := pgxConn.QueryRow(
@@ -37,6 +37,7 @@ func (projection *Base) GetEventsToListen() []string {
 	return []string{
 		event_usecase.MSG_CREATE_VALIDATOR_CREATED,
 		event_usecase.GENESIS_VALIDATOR_CREATED,
+		event_usecase.BOOTSTRAP_VALIDATOR_CREATED,
 		event_usecase.MSG_EDIT_VALIDATOR_CREATED,
 	}
 }
@@ -16,4 +16,7 @@ var (
 
 	// when trying to scan a null row
 	ErrNoRows = errors.New("no rows in result set")
+
+	// when the connection cannot listen to notifications, e.g. a connection not backed by a pool
+	ErrListenUnsupported = errors.New("listening is not supported by the connection")
 )
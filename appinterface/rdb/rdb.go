@@ -1,6 +1,10 @@
 package rdb
 
-import sq "github.com/Masterminds/squirrel"
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+)
 
 // Relational database interface
 
@@ -12,6 +16,29 @@ type Conn interface {
 	ToHandle() *Handle
 }
 
+// Listener is an optional interface of Conn to listen to the notifications of a channel, e.g. Postgres LISTEN
+type Listener interface {
+	// Listen starts listening to the channel and calls the handler with the payload of each notification in background
+	// until the context is cancelled or the connection is lost. The returned channel receives the error ending the
+	// listening and is closed afterwards. Returns ErrListenUnsupported when the connection cannot listen.
+	Listen(ctx context.Context, channel string, handler func(payload string)) (<-chan error, error)
+}
+
+// AdvisoryLocker is an optional interface of Conn to hold a lock for as long as the session holding it is alive, e.g.
+// Postgres session advisory lock. The lock is released by the database when the session is lost.
+type AdvisoryLocker interface {
+	// TryAdvisoryLock tries to acquire the lock of the key on a dedicated session without waiting. Returns nil when the
+	// lock is held by another session.
+	TryAdvisoryLock(ctx context.Context, key int64) (AdvisoryLock, error)
+}
+
+type AdvisoryLock interface {
+	// Ping returns error when the session holding the lock is lost, in which case the lock is no longer held
+	Ping(ctx context.Context) error
+	// Release releases the lock and the session holding it
+	Release() error
+}
+
 type Tx interface {
 	Exec(sql string, args ...interface{}) (ExecResult, error)
 	Query(sql string, args ...interface{}) (RowsResult, error)
@@ -0,0 +1,44 @@
+package test
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/crypto-com/chain-indexing/appinterface/rdb"
+)
+
+type MockAdvisoryLocker struct {
+	mock.Mock
+}
+
+func NewMockAdvisoryLocker() *MockAdvisoryLocker {
+	return &MockAdvisoryLocker{}
+}
+
+func (locker *MockAdvisoryLocker) TryAdvisoryLock(ctx context.Context, key int64) (rdb.AdvisoryLock, error) {
+	mockArgs := locker.Called(ctx, key)
+
+	lock, _ := mockArgs.Get(0).(rdb.AdvisoryLock)
+	return lock, mockArgs.Error(1)
+}
+
+type MockAdvisoryLock struct {
+	mock.Mock
+}
+
+func NewMockAdvisoryLock() *MockAdvisoryLock {
+	return &MockAdvisoryLock{}
+}
+
+func (lock *MockAdvisoryLock) Ping(ctx context.Context) error {
+	mockArgs := lock.Called(ctx)
+
+	return mockArgs.Error(0)
+}
+
+func (lock *MockAdvisoryLock) Release() error {
+	mockArgs := lock.Called()
+
+	return mockArgs.Error(0)
+}
@@ -0,0 +1,13 @@
+package rdbleaderterm_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestRDbLeaderTerm(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "RDbLeaderTerm Suite")
+}
@@ -0,0 +1,78 @@
+package rdbleaderterm
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/crypto-com/chain-indexing/appinterface/rdb"
+)
+
+const TABLE_NAME = "leader_terms"
+
+// RDbLeaderTerms numbers the terms of the leaderships in relational database. A leader begins the next term once it
+// has acquired the leadership, and locks its term in every transaction it writes, such that the writes of a former
+// leader fail once the next leader has begun its term.
+type RDbLeaderTerms struct {
+	rdb *rdb.Handle
+}
+
+func NewRDbLeaderTerms(rdbHandle *rdb.Handle) *RDbLeaderTerms {
+	return &RDbLeaderTerms{
+		rdbHandle,
+	}
+}
+
+// Begin begins the next term of the leadership of the lock key and returns it. It waits for the transactions which
+// have locked the previous term to end.
+func (terms *RDbLeaderTerms) Begin(lockKey int64) (int64, error) {
+	sql, sqlArgs, err := terms.rdb.StmtBuilder.Insert(
+		TABLE_NAME,
+	).Columns(
+		"lock_key",
+		"term",
+	).Values(
+		lockKey,
+		1,
+	).Suffix(
+		"ON CONFLICT (lock_key) DO UPDATE SET term = leader_terms.term + 1 RETURNING term",
+	).ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("error building leader term upsert SQL: %v", err)
+	}
+
+	var term int64
+	if err = terms.rdb.QueryRow(sql, sqlArgs...).Scan(&term); err != nil {
+		return 0, fmt.Errorf("error upserting leader term: %v", err)
+	}
+
+	return term, nil
+}
+
+// LockWithRDbHandle locks the term of the leadership of the lock key until the transaction of the RDb handle ends,
+// such that the next term cannot begin before the writes of the transaction are committed. Returns error when the term
+// is no longer the current one.
+func (terms *RDbLeaderTerms) LockWithRDbHandle(rdbHandle *rdb.Handle, lockKey int64, term int64) error {
+	sql, sqlArgs, err := rdbHandle.StmtBuilder.Select(
+		"term",
+	).From(
+		TABLE_NAME,
+	).Where(
+		"lock_key = ?", lockKey,
+	).Suffix("FOR SHARE").ToSql()
+	if err != nil {
+		return fmt.Errorf("error building leader term lock SQL: %v", err)
+	}
+
+	var currentTerm int64
+	if err = rdbHandle.QueryRow(sql, sqlArgs...).Scan(&currentTerm); err != nil {
+		if errors.Is(err, rdb.ErrNoRows) {
+			return fmt.Errorf("error locking leader term: no term of lock key %d has begun", lockKey)
+		}
+		return fmt.Errorf("error executing leader term lock SQL: %v", err)
+	}
+	if currentTerm != term {
+		return fmt.Errorf("error locking leader term: term %d is superseded by term %d", term, currentTerm)
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package rdbleaderterm_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/crypto-com/chain-indexing/appinterface/rdb"
+	"github.com/crypto-com/chain-indexing/appinterface/rdbleaderterm"
+	"github.com/crypto-com/chain-indexing/infrastructure/pg"
+	. "github.com/crypto-com/chain-indexing/test"
+)
+
+var _ = Describe("RDbLeaderTerms", func() {
+	WithTestPgxConn(func(pgxConn *pg.PgxConn, migrate rdb.Migrate) {
+		BeforeEach(func() {
+			_ = migrate.Reset()
+			migrate.MustUp()
+		})
+
+		AfterEach(func() {
+			_ = migrate.Reset()
+		})
+
+		Describe("Begin", func() {
+			It("should begin the next term of the lock key", func() {
+				terms := rdbleaderterm.NewRDbLeaderTerms(pgxConn.ToHandle())
+
+				Expect(terms.Begin(1)).To(Equal(int64(1)))
+				Expect(terms.Begin(1)).To(Equal(int64(2)))
+				Expect(terms.Begin(2)).To(Equal(int64(1)))
+			})
+		})
+
+		Describe("LockWithRDbHandle", func() {
+			It("should lock the current term", func() {
+				terms := rdbleaderterm.NewRDbLeaderTerms(pgxConn.ToHandle())
+				term, err := terms.Begin(1)
+				Expect(err).To(BeNil())
+
+				Expect(terms.LockWithRDbHandle(pgxConn.ToHandle(), 1, term)).To(BeNil())
+			})
+
+			It("should return Error when the term is superseded by the next term", func() {
+				terms := rdbleaderterm.NewRDbLeaderTerms(pgxConn.ToHandle())
+				term, err := terms.Begin(1)
+				Expect(err).To(BeNil())
+				_, err = terms.Begin(1)
+				Expect(err).To(BeNil())
+
+				Expect(terms.LockWithRDbHandle(pgxConn.ToHandle(), 1, term)).NotTo(BeNil())
+			})
+
+			It("should return Error when no term has begun", func() {
+				terms := rdbleaderterm.NewRDbLeaderTerms(pgxConn.ToHandle())
+
+				Expect(terms.LockWithRDbHandle(pgxConn.ToHandle(), 1, 1)).NotTo(BeNil())
+			})
+		})
+	})
+})
@@ -1,8 +1,14 @@
 package bootstrap
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/crypto-com/chain-indexing/appinterface/rdb"
 	config "github.com/crypto-com/chain-indexing/bootstrap/config"
@@ -13,6 +19,9 @@ import (
 	"github.com/golang-migrate/migrate/v4"
 )
 
+// Deadline for the HTTP API server to finish in-flight requests on shutdown
+const HTTP_API_SERVER_SHUTDOWN_TIMEOUT = 10 * time.Second
+
 type app struct {
 	logger applogger.Logger
 	config *config.Config
@@ -20,6 +29,8 @@ type app struct {
 	rdbConn       rdb.Conn
 	httpAPIServer *HTTPAPIServer
 	indexService  *IndexService
+	// Elector of the replica running the index service, nil when every replica runs the index service
+	maybeLeaderElector *LeaderElector
 }
 
 func NewApp(logger applogger.Logger, config *config.Config) *app {
@@ -28,7 +39,7 @@ func NewApp(logger applogger.Logger, config *config.Config) *app {
 		logger.Panicf("error setting up RDb connection: %v", err)
 	}
 
-	if config.IndexService.Enable {
+	if isIndexServiceEnabled(config) {
 		ref := ""
 		if config.IndexService.GithubAPI.MigrationRepoRef != "" {
 			ref = "#" + config.IndexService.GithubAPI.MigrationRepoRef
@@ -68,24 +79,50 @@ func migrationDBConnString(conn rdb.Conn) string {
 	}
 }
 
+// isIndexServiceEnabled returns true when the index service is enabled and the process is not in `API_ONLY` role
+func isIndexServiceEnabled(appConfig *config.Config) bool {
+	return appConfig.IndexService.Enable && appConfig.IndexService.Role != config.PROCESS_ROLE_API_ONLY
+}
+
 func (a *app) GetRDbConn() rdb.Conn {
 	return a.rdbConn
 }
 
+// InitHTTPAPIServer creates the HTTP API server with the routes. The admin routes control the projections of the index
+// service, so InitIndexService must be called before it when the admin API is enabled.
 func (a *app) InitHTTPAPIServer(registry RouteRegistry) {
 	if a.config.HTTPService.Enable {
 		a.httpAPIServer = NewHTTPAPIServer(a.logger, a.config)
 		a.httpAPIServer.RegisterRoutes(registry)
+
+		if a.config.HTTPService.Admin.Enable {
+			if a.indexService == nil {
+				a.logger.Panicf("admin API requires index service to be enabled and initialized first")
+			}
+			a.httpAPIServer.RegisterAdminRoutes(a.rdbConn.ToHandle(), a.indexService)
+		}
 	}
 }
 
 func (a *app) InitIndexService(projections []projection_entity.Projection, cronJobs []projection_entity.CronJob) {
-	if a.config.IndexService.Enable {
+	if isIndexServiceEnabled(a.config) {
 		a.indexService = NewIndexService(a.logger, a.rdbConn, a.config, projections, cronJobs)
+		if a.config.IndexService.LeaderElection.Enable {
+			a.maybeLeaderElector = NewLeaderElector(a.logger, a.rdbConn, a.config.IndexService.LeaderElection)
+			a.indexService.WithWriteFence(a.maybeLeaderElector)
+		}
 	}
 }
 
+// Run starts all the enabled services and blocks until SIGINT or SIGTERM is received. With leader election enabled, the
+// index service only runs while this replica is the leader. On signal, the services stop taking new work, the in-flight
+// works are finished and the HTTP API server is shut down.
 func (a *app) Run() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var servicesWaitGroup sync.WaitGroup
+
 	if a.httpAPIServer != nil {
 		go func() {
 			if runErr := a.httpAPIServer.Run(); runErr != nil {
@@ -95,8 +132,16 @@ func (a *app) Run() {
 	}
 
 	if a.indexService != nil {
+		servicesWaitGroup.Add(1)
 		go func() {
-			if runErr := a.indexService.Run(); runErr != nil {
+			defer servicesWaitGroup.Done()
+			var runErr error
+			if a.maybeLeaderElector != nil {
+				runErr = a.maybeLeaderElector.Run(ctx, a.indexService.Run)
+			} else {
+				runErr = a.indexService.Run(ctx)
+			}
+			if runErr != nil {
 				a.logger.Panicf("%v", runErr)
 			}
 		}()
@@ -110,5 +155,17 @@ func (a *app) Run() {
 		}()
 	}
 
-	select {}
+	<-ctx.Done()
+	// Restore default signal handling so that a second signal terminates immediately
+	stop()
+	a.logger.Info("received shutdown signal, shutting down gracefully")
+
+	if a.httpAPIServer != nil {
+		if err := a.httpAPIServer.Shutdown(HTTP_API_SERVER_SHUTDOWN_TIMEOUT); err != nil {
+			a.logger.Errorf("%v", err)
+		}
+	}
+	servicesWaitGroup.Wait()
+
+	a.logger.Info("shutdown completed")
 }
@@ -0,0 +1,138 @@
+package bootstrap
+
+import (
+	"fmt"
+
+	cosmosapp_interface "github.com/crypto-com/chain-indexing/appinterface/cosmosapp"
+	tendermint_interface "github.com/crypto-com/chain-indexing/appinterface/tendermint"
+	"github.com/crypto-com/chain-indexing/bootstrap/config"
+	"github.com/crypto-com/chain-indexing/infrastructure/blockarchive"
+	cosmosapp_infrastructure "github.com/crypto-com/chain-indexing/infrastructure/cosmosapp"
+	"github.com/crypto-com/chain-indexing/infrastructure/tendermint"
+)
+
+// TendermintStatusClient is a Tendermint client which can also query the node status
+type TendermintStatusClient interface {
+	tendermint_interface.Client
+
+	Status() (*map[string]interface{}, error)
+}
+
+// NewTendermintClient creates a Tendermint client to the RPC URLs. When more than one URL is
+// provided, requests are routed between the endpoints with failover.
+func NewTendermintClient(rpcUrls []string, insecure bool, strictGenesisParsing bool) TendermintStatusClient {
+	return newTendermintClient(rpcUrls, insecure, strictGenesisParsing, "", nil, 0)
+}
+
+func newTendermintClient(
+	rpcUrls []string,
+	insecure bool,
+	strictGenesisParsing bool,
+	genesisFilePath string,
+	maybeArchiver tendermint.Archiver,
+	batchSize int,
+) TendermintStatusClient {
+	if len(rpcUrls) == 1 {
+		var client *tendermint.HTTPClient
+		if insecure {
+			client = tendermint.NewInsecureHTTPClient(rpcUrls[0], strictGenesisParsing)
+		} else {
+			client = tendermint.NewHTTPClient(rpcUrls[0], strictGenesisParsing)
+		}
+		if genesisFilePath != "" {
+			client = client.WithGenesisFile(genesisFilePath)
+		}
+		if maybeArchiver != nil {
+			client = client.WithArchiver(maybeArchiver)
+		}
+		if batchSize > 0 {
+			return tendermint.NewBatchHTTPClient(client, batchSize)
+		}
+		return client
+	}
+
+	var client *tendermint.MultiEndpointHTTPClient
+	if insecure {
+		client = tendermint.NewInsecureMultiEndpointHTTPClient(rpcUrls, strictGenesisParsing)
+	} else {
+		client = tendermint.NewMultiEndpointHTTPClient(rpcUrls, strictGenesisParsing)
+	}
+	if genesisFilePath != "" {
+		client = client.WithGenesisFile(genesisFilePath)
+	}
+	if maybeArchiver != nil {
+		client = client.WithArchiver(maybeArchiver)
+	}
+	if batchSize > 0 {
+		client = client.WithBatchSize(batchSize)
+	}
+	return client
+}
+
+// NewBlockSyncTendermintClient creates the Tendermint client used to sync blocks according to the archive mode.
+// In RECORD mode, the raw responses are archived to the archive directory. In REPLAY mode, blocks are served from the
+// archive directory without any network request. When genesis file path is provided, genesis is read from the local
+// file instead of requesting the node. When batch size is positive, block and block results of batch size
+// heights are requested in a single JSON-RPC batch request.
+func NewBlockSyncTendermintClient(
+	rpcUrls []string,
+	insecure bool,
+	strictGenesisParsing bool,
+	genesisFilePath string,
+	archiveMode string,
+	archiveDirectory string,
+	batchSize int,
+) (tendermint_interface.Client, error) {
+	switch archiveMode {
+	case config.ARCHIVE_MODE_NONE:
+		return newTendermintClient(rpcUrls, insecure, strictGenesisParsing, genesisFilePath, nil, batchSize), nil
+	case config.ARCHIVE_MODE_RECORD:
+		archive, err := blockarchive.NewArchive(archiveDirectory)
+		if err != nil {
+			return nil, fmt.Errorf("error opening block archive: %v", err)
+		}
+		return newTendermintClient(
+			rpcUrls, insecure, strictGenesisParsing, genesisFilePath, archive, batchSize,
+		), nil
+	case config.ARCHIVE_MODE_REPLAY:
+		archive, err := blockarchive.NewArchive(archiveDirectory)
+		if err != nil {
+			return nil, fmt.Errorf("error opening block archive: %v", err)
+		}
+		return blockarchive.NewClient(archive, strictGenesisParsing), nil
+	default:
+		return nil, fmt.Errorf("unsupported archive mode: %s", archiveMode)
+	}
+}
+
+// NewCosmosAppClient creates a Cosmos app client to the RPC URLs. When more than one URL is
+// provided, requests are routed between the endpoints with failover.
+func NewCosmosAppClient(rpcUrls []string, insecure bool, bondingDenom string) cosmosapp_interface.Client {
+	if len(rpcUrls) == 1 {
+		if insecure {
+			return cosmosapp_infrastructure.NewInsecureHTTPClient(rpcUrls[0], bondingDenom)
+		}
+		return cosmosapp_infrastructure.NewHTTPClient(rpcUrls[0], bondingDenom)
+	}
+
+	if insecure {
+		return cosmosapp_infrastructure.NewInsecureMultiEndpointHTTPClient(rpcUrls, bondingDenom)
+	}
+	return cosmosapp_infrastructure.NewMultiEndpointHTTPClient(rpcUrls, bondingDenom)
+}
+
+// NewBlockSyncCosmosAppClient creates the Cosmos app client used to parse blocks according to the archive mode. In
+// REPLAY mode, the Cosmos app responses are not archived, so the client is disabled and never makes any network
+// request. Parsing which requires the Cosmos app state, e.g. bootstrapping from the start height, fails in this mode.
+func NewBlockSyncCosmosAppClient(
+	rpcUrls []string,
+	insecure bool,
+	bondingDenom string,
+	archiveMode string,
+) cosmosapp_interface.Client {
+	if archiveMode == config.ARCHIVE_MODE_REPLAY {
+		return cosmosapp_infrastructure.NewDisabledClient()
+	}
+
+	return NewCosmosAppClient(rpcUrls, insecure, bondingDenom)
+}
@@ -0,0 +1,51 @@
+package bootstrap_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/crypto-com/chain-indexing/bootstrap"
+	"github.com/crypto-com/chain-indexing/bootstrap/config"
+	cosmosapp_infrastructure "github.com/crypto-com/chain-indexing/infrastructure/cosmosapp"
+)
+
+var _ = Describe("NewBlockSyncCosmosAppClient", func() {
+	It("should not make any network request in REPLAY mode", func() {
+		requestCount := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestCount += 1
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		client := bootstrap.NewBlockSyncCosmosAppClient(
+			[]string{server.URL}, false, "basetcro", config.ARCHIVE_MODE_REPLAY,
+		)
+
+		_, err := client.Account("tcro1fmprm0sjy6lz9llv7rltn0v2azzwcwzvk2lsyn")
+		Expect(err).To(Equal(cosmosapp_infrastructure.ErrClientDisabled))
+		_, err = client.ValidatorsAtHeight(100)
+		Expect(err).To(Equal(cosmosapp_infrastructure.ErrClientDisabled))
+		Expect(requestCount).To(Equal(0))
+	})
+
+	It("should request the Cosmos app when not in REPLAY mode", func() {
+		requestCount := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestCount += 1
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		client := bootstrap.NewBlockSyncCosmosAppClient(
+			[]string{server.URL}, false, "basetcro", config.ARCHIVE_MODE_RECORD,
+		)
+
+		_, err := client.Account("tcro1fmprm0sjy6lz9llv7rltn0v2azzwcwzvk2lsyn")
+		Expect(err).NotTo(BeNil())
+		Expect(requestCount).To(BeNumerically(">", 0))
+	})
+})
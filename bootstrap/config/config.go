@@ -3,6 +3,28 @@ package config
 const SYSTEM_MODE_EVENT_STORE = "EVENT_STORE"
 const SYSTEM_MODE_TENDERMINT_DIRECT = "TENDERMINT_DIRECT"
 
+const ARCHIVE_MODE_NONE = ""
+const ARCHIVE_MODE_RECORD = "RECORD"
+const ARCHIVE_MODE_REPLAY = "REPLAY"
+
+const SYNC_STRATEGY_WINDOW = "WINDOW"
+const SYNC_STRATEGY_ADAPTIVE = "ADAPTIVE"
+
+const EVENT_STORE_TYPE_RDB = "RDB"
+const EVENT_STORE_TYPE_SEGMENT = "SEGMENT"
+
+// Syncs blocks and runs all the projections and cron jobs
+const PROCESS_ROLE_ALL = "ALL"
+
+// Only syncs blocks into the event store
+const PROCESS_ROLE_SYNC_ONLY = "SYNC_ONLY"
+
+// Only runs the assigned projections and cron jobs from the event store
+const PROCESS_ROLE_PROJECTION_WORKER = "PROJECTION_WORKER"
+
+// Only serves the HTTP API
+const PROCESS_ROLE_API_ONLY = "API_ONLY"
+
 type Config struct {
 	Blockchain    Blockchain    `yaml:"blockchain" toml:"blockchain" xml:"blockchain" json:"blockchain"`
 	IndexService  IndexService  `yaml:"index_service" toml:"index_service" xml:"index_service" json:"index_service"`
@@ -18,20 +40,60 @@ type Config struct {
 type IndexService struct {
 	Enable                     bool                       `yaml:"enable" toml:"enable" xml:"enable" json:"enable,omitempty"`
 	Mode                       string                     `yaml:"mode" toml:"mode" xml:"mode" json:"mode,omitempty"`
+	Role                       string                     `yaml:"role" toml:"role" xml:"role" json:"role,omitempty"`
 	WindowSize                 int                        `yaml:"window_size" toml:"window_size" xml:"window_size" json:"window_size,omitempty"`
+	SyncStrategy               string                     `yaml:"sync_strategy" toml:"sync_strategy" xml:"sync_strategy" json:"sync_strategy,omitempty"`
+	AdaptiveSyncStrategy       AdaptiveSyncStrategy       `yaml:"adaptive_sync_strategy" toml:"adaptive_sync_strategy" xml:"adaptive_sync_strategy" json:"adaptive_sync_strategy"`
+	FanoutCacheSize            int                        `yaml:"fanout_cache_size" toml:"fanout_cache_size" xml:"fanout_cache_size" json:"fanout_cache_size,omitempty"`
+	StartHeight                int64                      `yaml:"start_height" toml:"start_height" xml:"start_height" json:"start_height,omitempty"`
 	Projection                 Projection                 `yaml:"projection" toml:"projection" xml:"projection" json:"projection"`
+	ProjectionWorker           ProjectionWorker           `yaml:"projection_worker" toml:"projection_worker" xml:"projection_worker" json:"projection_worker"`
 	CronJob                    CronJob                    `yaml:"cron_job" toml:"cron_job" xml:"cron_job" json:"cron_job"`
 	CosmosVersionEnabledHeight CosmosVersionEnabledHeight `yaml:"cosmos_version_enabled_height" toml:"cosmos_version_enabled_height" xml:"cosmos_version_enabled_height" json:"cosmos_version_enabled_height"`
+	Eras                       []Era                      `yaml:"eras" toml:"eras" xml:"eras" json:"eras,omitempty"`
+	EventStore                 EventStore                 `yaml:"event_store" toml:"event_store" xml:"event_store" json:"event_store"`
 	GithubAPI                  GithubAPI                  `yaml:"github_api" toml:"github_api" xml:"github_api" json:"github_api"`
+	LeaderElection             LeaderElection             `yaml:"leader_election" toml:"leader_election" xml:"leader_election" json:"leader_election"`
+}
+
+// LeaderElection runs the index service only on the replica holding the Postgres advisory lock of LockKey. Replicas
+// sharing the database must use the same LockKey. CheckInterval is how often the followers try to acquire the lock and
+// the leader checks it still holds the lock, defaults to 5s.
+type LeaderElection struct {
+	Enable        bool   `yaml:"enable" toml:"enable" xml:"enable" json:"enable,omitempty"`
+	LockKey       int64  `yaml:"lock_key" toml:"lock_key" xml:"lock_key" json:"lock_key,omitempty"`
+	CheckInterval string `yaml:"check_interval" toml:"check_interval" xml:"check_interval" json:"check_interval,omitempty"`
+}
+
+type AdaptiveSyncStrategy struct {
+	MinConcurrency int `yaml:"min_concurrency" toml:"min_concurrency" xml:"min_concurrency" json:"min_concurrency,omitempty"`
+	MaxConcurrency int `yaml:"max_concurrency" toml:"max_concurrency" xml:"max_concurrency" json:"max_concurrency,omitempty"`
 }
 
 type HTTPService struct {
-	Enable             bool     `yaml:"enable" toml:"enable" xml:"enable" json:"enable,omitempty"`
-	ListeningAddress   string   `yaml:"listening_address" toml:"listening_address" xml:"listening_address" json:"listening_address,omitempty"`
-	RoutePrefix        string   `yaml:"route_prefix" toml:"route_prefix" xml:"route_prefix" json:"route_prefix,omitempty"`
-	CorsAllowedOrigins []string `yaml:"cors_allowed_origins" toml:"cors_allowed_origins" xml:"cors_allowed_origins" json:"cors_allowed_origins,omitempty"`
-	CorsAllowedMethods []string `yaml:"cors_allowed_methods" toml:"cors_allowed_methods" xml:"cors_allowed_methods" json:"cors_allowed_methods,omitempty"`
-	CorsAllowedHeaders []string `yaml:"cors_allowed_headers" toml:"cors_allowed_headers" xml:"cors_allowed_headers" json:"cors_allowed_headers,omitempty"`
+	Enable             bool      `yaml:"enable" toml:"enable" xml:"enable" json:"enable,omitempty"`
+	ListeningAddress   string    `yaml:"listening_address" toml:"listening_address" xml:"listening_address" json:"listening_address,omitempty"`
+	RoutePrefix        string    `yaml:"route_prefix" toml:"route_prefix" xml:"route_prefix" json:"route_prefix,omitempty"`
+	CorsAllowedOrigins []string  `yaml:"cors_allowed_origins" toml:"cors_allowed_origins" xml:"cors_allowed_origins" json:"cors_allowed_origins,omitempty"`
+	CorsAllowedMethods []string  `yaml:"cors_allowed_methods" toml:"cors_allowed_methods" xml:"cors_allowed_methods" json:"cors_allowed_methods,omitempty"`
+	CorsAllowedHeaders []string  `yaml:"cors_allowed_headers" toml:"cors_allowed_headers" xml:"cors_allowed_headers" json:"cors_allowed_headers,omitempty"`
+	Readiness          Readiness `yaml:"readiness" toml:"readiness" xml:"readiness" json:"readiness"`
+	Admin              Admin     `yaml:"admin" toml:"admin" xml:"admin" json:"admin"`
+}
+
+// Admin is the authenticated API to pause, resume, rewind and rebuild the projections of the index service. Requests
+// must carry `Authorization: Bearer <token>` with one of the tokens, and are audited with the name of the token.
+type Admin struct {
+	Enable bool              `yaml:"enable" toml:"enable" xml:"enable" json:"enable,omitempty"`
+	Tokens map[string]string `yaml:"tokens" toml:"tokens" xml:"tokens" json:"tokens,omitempty"`
+}
+
+// Readiness decides when the projections are fresh enough to serve API traffic. All the enabled projections are required
+// when RequiredProjections is empty. Zero MaxLagBlocks and empty MaxLagDuration disable the respective check.
+type Readiness struct {
+	RequiredProjections []string `yaml:"required_projections" toml:"required_projections" xml:"required_projections" json:"required_projections,omitempty"`
+	MaxLagBlocks        int64    `yaml:"max_lag_blocks" toml:"max_lag_blocks" xml:"max_lag_blocks" json:"max_lag_blocks,omitempty"`
+	MaxLagDuration      string   `yaml:"max_lag_duration" toml:"max_lag_duration" xml:"max_lag_duration" json:"max_lag_duration,omitempty"`
 }
 
 type Blockchain struct {
@@ -50,14 +112,41 @@ type Debug struct {
 }
 
 type TendermintApp struct {
-	HTTPRPCUrl           string `yaml:"http_rpc_url" toml:"http_rpc_url" xml:"http_rpc_url" json:"http_rpc_url,omitempty"`
-	Insecure             bool   `yaml:"insecure" toml:"insecure" xml:"insecure" json:"insecure,omitempty"`
-	StrictGenesisParsing bool   `yaml:"strict_genesis_parsing" toml:"strict_genesis_parsing" xml:"strict_genesis_parsing" json:"strict_genesis_parsing,omitempty"`
+	HTTPRPCUrl           string   `yaml:"http_rpc_url" toml:"http_rpc_url" xml:"http_rpc_url" json:"http_rpc_url,omitempty"`
+	HTTPRPCUrls          []string `yaml:"http_rpc_urls" toml:"http_rpc_urls" xml:"http_rpc_urls" json:"http_rpc_urls,omitempty"`
+	Insecure             bool     `yaml:"insecure" toml:"insecure" xml:"insecure" json:"insecure,omitempty"`
+	StrictGenesisParsing bool     `yaml:"strict_genesis_parsing" toml:"strict_genesis_parsing" xml:"strict_genesis_parsing" json:"strict_genesis_parsing,omitempty"`
+	GenesisFilePath      string   `yaml:"genesis_file_path" toml:"genesis_file_path" xml:"genesis_file_path" json:"genesis_file_path,omitempty"`
+	WebSocketEnable      bool     `yaml:"websocket_enable" toml:"websocket_enable" xml:"websocket_enable" json:"websocket_enable,omitempty"`
+	WebSocketRPCUrl      string   `yaml:"websocket_rpc_url" toml:"websocket_rpc_url" xml:"websocket_rpc_url" json:"websocket_rpc_url,omitempty"`
+	ArchiveMode          string   `yaml:"archive_mode" toml:"archive_mode" xml:"archive_mode" json:"archive_mode,omitempty"`
+	ArchiveDirectory     string   `yaml:"archive_directory" toml:"archive_directory" xml:"archive_directory" json:"archive_directory,omitempty"`
+	BatchSize            int      `yaml:"batch_size" toml:"batch_size" xml:"batch_size" json:"batch_size,omitempty"`
+}
+
+// GetHTTPRPCUrls returns the list of HTTP RPC URLs. Falls back to the single HTTP RPC URL when the
+// list is not configured.
+func (tendermintApp *TendermintApp) GetHTTPRPCUrls() []string {
+	return rpcUrls(tendermintApp.HTTPRPCUrl, tendermintApp.HTTPRPCUrls)
 }
 
 type CosmosApp struct {
-	HTTPRPCUrl string `yaml:"http_rpc_url" toml:"http_rpc_url" xml:"http_rpc_url" json:"http_rpc_url,omitempty"`
-	Insecure   bool   `yaml:"insecure" toml:"insecure" xml:"insecure" json:"insecure,omitempty"`
+	HTTPRPCUrl  string   `yaml:"http_rpc_url" toml:"http_rpc_url" xml:"http_rpc_url" json:"http_rpc_url,omitempty"`
+	HTTPRPCUrls []string `yaml:"http_rpc_urls" toml:"http_rpc_urls" xml:"http_rpc_urls" json:"http_rpc_urls,omitempty"`
+	Insecure    bool     `yaml:"insecure" toml:"insecure" xml:"insecure" json:"insecure,omitempty"`
+}
+
+// GetHTTPRPCUrls returns the list of HTTP RPC URLs. Falls back to the single HTTP RPC URL when the
+// list is not configured.
+func (cosmosApp *CosmosApp) GetHTTPRPCUrls() []string {
+	return rpcUrls(cosmosApp.HTTPRPCUrl, cosmosApp.HTTPRPCUrls)
+}
+
+func rpcUrls(url string, urls []string) []string {
+	if len(urls) > 0 {
+		return urls
+	}
+	return []string{url}
 }
 
 type Postgres struct {
@@ -81,8 +170,26 @@ type Logger struct {
 }
 
 type Projection struct {
-	Enables      []string               `yaml:"enables" toml:"enables" xml:"enables" json:"enables,omitempty"`
-	ExtraConfigs map[string]interface{} `yaml:"extra_configs" toml:"extra_configs" xml:"extra_configs" json:"extra_configs,omitempty"`
+	Enables         []string                           `yaml:"enables" toml:"enables" xml:"enables" json:"enables,omitempty"`
+	ExtraConfigs    map[string]interface{}             `yaml:"extra_configs" toml:"extra_configs" xml:"extra_configs" json:"extra_configs,omitempty"`
+	FailurePolicy   ProjectionFailurePolicy            `yaml:"failure_policy" toml:"failure_policy" xml:"failure_policy" json:"failure_policy"`
+	FailurePolicies map[string]ProjectionFailurePolicy `yaml:"failure_policies" toml:"failure_policies" xml:"failure_policies" json:"failure_policies,omitempty"`
+}
+
+// ProjectionFailurePolicy decides what happens to a projection failing to handle a height after MaxRetry retries. The
+// action is one of `RETRY` (default, retries forever), `HALT` and `SKIP`.
+type ProjectionFailurePolicy struct {
+	MaxRetry int    `yaml:"max_retry" toml:"max_retry" xml:"max_retry" json:"max_retry,omitempty"`
+	Action   string `yaml:"action" toml:"action" xml:"action" json:"action,omitempty"`
+}
+
+// ProjectionWorker is the assignment of a process in `PROJECTION_WORKER` role. Empty Projections and CronJobs assign all
+// the enabled ones. A projection assigned to multiple workers is only run by the worker holding its lease, which expires
+// after LeaseDuration (defaults to 30s) when the worker crashes.
+type ProjectionWorker struct {
+	Projections   []string `yaml:"projections" toml:"projections" xml:"projections" json:"projections,omitempty"`
+	CronJobs      []string `yaml:"cron_jobs" toml:"cron_jobs" xml:"cron_jobs" json:"cron_jobs,omitempty"`
+	LeaseDuration string   `yaml:"lease_duration" toml:"lease_duration" xml:"lease_duration" json:"lease_duration,omitempty"`
 }
 
 type CronJob struct {
@@ -94,6 +201,21 @@ type CosmosVersionEnabledHeight struct {
 	V0_42_7 uint64 `yaml:"v_0_42_7" toml:"v_0_42_7" xml:"v_0_42_7" json:"v_0_42_7,omitempty"`
 }
 
+// Era is a block height range of the chain, e.g. between two chain upgrades, starting at StartHeight until the start
+// of the next era. Empty RPC URLs fall back to the ones of `tendermint_app` and `cosmos_app`.
+type Era struct {
+	StartHeight           int64    `yaml:"start_height" toml:"start_height" xml:"start_height" json:"start_height,omitempty"`
+	TendermintHTTPRPCUrls []string `yaml:"tendermint_http_rpc_urls" toml:"tendermint_http_rpc_urls" xml:"tendermint_http_rpc_urls" json:"tendermint_http_rpc_urls,omitempty"`
+	CosmosAppHTTPRPCUrls  []string `yaml:"cosmos_app_http_rpc_urls" toml:"cosmos_app_http_rpc_urls" xml:"cosmos_app_http_rpc_urls" json:"cosmos_app_http_rpc_urls,omitempty"`
+	ParserSet             string   `yaml:"parser_set" toml:"parser_set" xml:"parser_set" json:"parser_set,omitempty"`
+}
+
+type EventStore struct {
+	Type             string `yaml:"type" toml:"type" xml:"type" json:"type,omitempty"`
+	SegmentDirectory string `yaml:"segment_directory" toml:"segment_directory" xml:"segment_directory" json:"segment_directory,omitempty"`
+	MaxSegmentSize   int64  `yaml:"max_segment_size" toml:"max_segment_size" xml:"max_segment_size" json:"max_segment_size,omitempty"`
+}
+
 type GithubAPI struct {
 	Username         string `yaml:"username" toml:"username" xml:"username" json:"username,omitempty"`
 	Token            string `yaml:"token" toml:"token" xml:"token" json:"token,omitempty"`
@@ -1,11 +1,15 @@
 package bootstrap
 
 import (
+	"errors"
 	"fmt"
+	"time"
 
+	"github.com/crypto-com/chain-indexing/appinterface/rdb"
 	"github.com/crypto-com/chain-indexing/bootstrap/config"
 	applogger "github.com/crypto-com/chain-indexing/external/logger"
 	"github.com/crypto-com/chain-indexing/infrastructure/httpapi"
+	httpapi_handlers "github.com/crypto-com/chain-indexing/infrastructure/httpapi/handlers"
 	"github.com/lab259/cors"
 	"github.com/valyala/fasthttp"
 )
@@ -18,6 +22,8 @@ type HTTPAPIServer struct {
 
 	pprof config.Debug
 
+	adminTokens map[string]string
+
 	httpServer *httpapi.Server
 }
 
@@ -52,6 +58,8 @@ func NewHTTPAPIServer(logger applogger.Logger, config *config.Config) *HTTPAPISe
 
 		pprof: config.Debug,
 
+		adminTokens: config.HTTPService.Admin.Tokens,
+
 		httpServer: httpServer,
 	}
 }
@@ -65,6 +73,32 @@ func (server *HTTPAPIServer) RegisterRoutes(registry RouteRegistry) {
 	registry.Register(server.httpServer, server.routePrefix)
 }
 
+// RegisterAdminRoutes registers the admin route group controlling the projections through the administrator. Every
+// admin route requires one of the admin tokens as bearer token and every action is audited.
+func (server *HTTPAPIServer) RegisterAdminRoutes(
+	rdbHandle *rdb.Handle,
+	administrator httpapi_handlers.ProjectionAdministrator,
+) {
+	if len(server.adminTokens) == 0 {
+		server.logger.Panicf("admin API requires at least one token")
+	}
+
+	auth := httpapi.BearerAuth(server.adminTokens)
+	adminHandler := httpapi_handlers.NewProjectionAdmin(server.logger, rdbHandle, administrator)
+	adminPrefix := fmt.Sprintf("%s/api/v1/admin", server.routePrefix)
+	server.httpServer.POST(
+		fmt.Sprintf("%s/projections/{id}/pause", adminPrefix), auth(adminHandler.Pause),
+	).POST(
+		fmt.Sprintf("%s/projections/{id}/resume", adminPrefix), auth(adminHandler.Resume),
+	).POST(
+		fmt.Sprintf("%s/projections/{id}/rewind", adminPrefix), auth(adminHandler.Rewind),
+	).POST(
+		fmt.Sprintf("%s/projections/{id}/rebuild", adminPrefix), auth(adminHandler.Rebuild),
+	).GET(
+		fmt.Sprintf("%s/audits", adminPrefix), auth(adminHandler.ListAudits),
+	)
+}
+
 func (server *HTTPAPIServer) Run() error {
 	if server.pprof.PprofEnable {
 		pprofServer := httpapi.NewServer(
@@ -89,3 +123,21 @@ func (server *HTTPAPIServer) Run() error {
 
 	return nil
 }
+
+// Shutdown stops accepting new connections and waits for the in-flight requests to finish until the timeout
+func (server *HTTPAPIServer) Shutdown(timeout time.Duration) error {
+	shutdownErrCh := make(chan error, 1)
+	go func() {
+		shutdownErrCh <- server.httpServer.Shutdown()
+	}()
+
+	select {
+	case err := <-shutdownErrCh:
+		if err != nil {
+			return fmt.Errorf("error shutting down HTTP API server: %v", err)
+		}
+		return nil
+	case <-time.After(timeout):
+		return errors.New("timeout shutting down HTTP API server")
+	}
+}
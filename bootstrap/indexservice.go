@@ -1,16 +1,26 @@
 package bootstrap
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	event_interface "github.com/crypto-com/chain-indexing/appinterface/event"
 	eventhandler_interface "github.com/crypto-com/chain-indexing/appinterface/eventhandler"
+	"github.com/crypto-com/chain-indexing/appinterface/projection/rdbfailurestore"
+	"github.com/crypto-com/chain-indexing/appinterface/projection/rdbleaser"
+	"github.com/crypto-com/chain-indexing/appinterface/projection/rdbprojectionbase"
 	"github.com/crypto-com/chain-indexing/appinterface/rdb"
 	"github.com/crypto-com/chain-indexing/bootstrap/config"
 	"github.com/crypto-com/chain-indexing/entity/event"
 	projection_entity "github.com/crypto-com/chain-indexing/entity/projection"
 	applogger "github.com/crypto-com/chain-indexing/external/logger"
+	"github.com/crypto-com/chain-indexing/infrastructure/blockarchive"
+	chainfeed "github.com/crypto-com/chain-indexing/infrastructure/feed/chain"
+	"github.com/crypto-com/chain-indexing/infrastructure/pg"
+	"github.com/crypto-com/chain-indexing/infrastructure/segmentstore"
 	event_usecase "github.com/crypto-com/chain-indexing/usecase/event"
 	"github.com/crypto-com/chain-indexing/usecase/parser/utils"
 )
@@ -22,20 +32,49 @@ type IndexService struct {
 	cronJobs    []projection_entity.CronJob
 
 	mode                     string
+	role                     string
 	accountAddressPrefix     string
 	consNodeAddressPrefix    string
 	bondingDenom             string
 	windowSize               int
-	tendermintHTTPRPCURL     string
-	cosmosAppHTTPRPCURL      string
+	startHeight              int64
+	syncStrategy             string
+	adaptiveMinConcurrency   int
+	adaptiveMaxConcurrency   int
+	fanoutCacheSize          int
+	tendermintHTTPRPCURLs    []string
+	tendermintWebSocketURL   string
+	cosmosAppHTTPRPCURLs     []string
 	insecureTendermintClient bool
 	insecureCosmosAppClient  bool
 	strictGenesisParsing     bool
+	genesisFilePath          string
+	archiveMode              string
+	archiveDirectory         string
+	batchSize                int
+	eventStoreType           string
+	segmentDirectory         string
+	maxSegmentSize           int64
+	failurePolicies          projection_entity.FailurePolicies
+	leaseDuration            time.Duration
+	// Fence of the synced events when the index service runs as the leader, nil otherwise
+	maybeWriteFence eventhandler_interface.WriteFence
 
 	cosmosVersionBlockHeight utils.CosmosVersionBlockHeight
+	syncManagerEras          []SyncManagerEra
+	parserEras               []utils.CosmosParserEra
 
 	GithubAPIUser  string
 	GithubAPIToken string
+
+	controlMutex sync.Mutex
+	// Controller of the running projections, nil when the index service is not running
+	maybeProjectionController projection_entity.Controller
+	// Rebuilder of the running projections, nil when the index service is not running in event store mode
+	maybeProjectionRebuilder *ProjectionRebuilder
+	rebuildCtx               context.Context
+	rebuildingProjectionIds  map[string]bool
+	rebuildsWaitGroup        sync.WaitGroup
 }
 
 // NewIndexService creates a new server instance for polling and indexing
@@ -46,6 +85,47 @@ func NewIndexService(
 	projections []projection_entity.Projection,
 	cronJobs []projection_entity.CronJob,
 ) *IndexService {
+	tendermintWebSocketURL := ""
+	if config.TendermintApp.WebSocketEnable {
+		tendermintWebSocketURL = config.TendermintApp.WebSocketRPCUrl
+		if tendermintWebSocketURL == "" {
+			var err error
+			tendermintWebSocketURL, err = chainfeed.WebSocketURLFromHTTPRPCURL(
+				config.TendermintApp.GetHTTPRPCUrls()[0],
+			)
+			if err != nil {
+				logger.Panicf("error deriving Tendermint websocket URL from HTTP RPC URL: %v", err)
+			}
+		}
+	}
+
+	syncManagerEras := make([]SyncManagerEra, 0, len(config.IndexService.Eras))
+	parserEras := make([]utils.CosmosParserEra, 0, len(config.IndexService.Eras))
+	for _, era := range config.IndexService.Eras {
+		syncManagerEras = append(syncManagerEras, SyncManagerEra{
+			StartHeight:          era.StartHeight,
+			TendermintRPCUrls:    era.TendermintHTTPRPCUrls,
+			CosmosAppHTTPRPCURLs: era.CosmosAppHTTPRPCUrls,
+		})
+		// Era without parser set keeps parsing with the parsers of the previous era
+		if era.ParserSet != "" {
+			parserEras = append(parserEras, utils.CosmosParserEra{
+				StartHeight: utils.ParserBlockHeight(era.StartHeight),
+				ParserSet:   era.ParserSet,
+			})
+		}
+	}
+
+	role, projections, cronJobs, leaseDuration := newProcessRole(logger, config.IndexService, projections, cronJobs)
+
+	failurePolicies := projection_entity.FailurePolicies{
+		Default:      newFailurePolicy(logger, config.IndexService.Projection.FailurePolicy),
+		ByProjection: make(map[string]projection_entity.FailurePolicy),
+	}
+	for projectionId, failurePolicy := range config.IndexService.Projection.FailurePolicies {
+		failurePolicies.ByProjection[projectionId] = newFailurePolicy(logger, failurePolicy)
+	}
+
 	return &IndexService{
 		logger:      logger,
 		rdbConn:     rdbConn,
@@ -53,84 +133,392 @@ func NewIndexService(
 		cronJobs:    cronJobs,
 
 		mode:                     config.IndexService.Mode,
+		role:                     role,
 		consNodeAddressPrefix:    config.Blockchain.ConNodeAddressPrefix,
 		accountAddressPrefix:     config.Blockchain.AccountAddressPrefix,
 		bondingDenom:             config.Blockchain.BondingDenom,
 		windowSize:               config.IndexService.WindowSize,
-		tendermintHTTPRPCURL:     config.TendermintApp.HTTPRPCUrl,
-		cosmosAppHTTPRPCURL:      config.CosmosApp.HTTPRPCUrl,
+		startHeight:              config.IndexService.StartHeight,
+		syncStrategy:             config.IndexService.SyncStrategy,
+		adaptiveMinConcurrency:   config.IndexService.AdaptiveSyncStrategy.MinConcurrency,
+		adaptiveMaxConcurrency:   config.IndexService.AdaptiveSyncStrategy.MaxConcurrency,
+		fanoutCacheSize:          config.IndexService.FanoutCacheSize,
+		tendermintHTTPRPCURLs:    config.TendermintApp.GetHTTPRPCUrls(),
+		tendermintWebSocketURL:   tendermintWebSocketURL,
+		cosmosAppHTTPRPCURLs:     config.CosmosApp.GetHTTPRPCUrls(),
 		insecureTendermintClient: config.TendermintApp.Insecure,
 		insecureCosmosAppClient:  config.CosmosApp.Insecure,
 		strictGenesisParsing:     config.TendermintApp.StrictGenesisParsing,
+		genesisFilePath:          config.TendermintApp.GenesisFilePath,
+		archiveMode:              config.TendermintApp.ArchiveMode,
+		archiveDirectory:         config.TendermintApp.ArchiveDirectory,
+		batchSize:                config.TendermintApp.BatchSize,
+		eventStoreType:           config.IndexService.EventStore.Type,
+		segmentDirectory:         config.IndexService.EventStore.SegmentDirectory,
+		maxSegmentSize:           config.IndexService.EventStore.MaxSegmentSize,
+		failurePolicies:          failurePolicies,
+		leaseDuration:            leaseDuration,
 		cosmosVersionBlockHeight: utils.CosmosVersionBlockHeight{
 			V0_42_7: utils.ParserBlockHeight(config.IndexService.CosmosVersionEnabledHeight.V0_42_7),
 		},
+		syncManagerEras: syncManagerEras,
+		parserEras:      parserEras,
+
 		GithubAPIUser:  config.IndexService.GithubAPI.Username,
 		GithubAPIToken: config.IndexService.GithubAPI.Token,
+
+		rebuildingProjectionIds: make(map[string]bool),
+	}
+}
+
+// newProcessRole validates the process role of the index service, `ALL` by default, and returns it with the projections
+// and cron jobs to run and the lease duration of the projections
+func newProcessRole(
+	logger applogger.Logger,
+	indexServiceConfig config.IndexService,
+	projections []projection_entity.Projection,
+	cronJobs []projection_entity.CronJob,
+) (string, []projection_entity.Projection, []projection_entity.CronJob, time.Duration) {
+	role := indexServiceConfig.Role
+	if role == "" {
+		role = config.PROCESS_ROLE_ALL
+	}
+
+	leaseDuration := rdbleaser.DEFAULT_LEASE_DURATION
+	switch role {
+	case config.PROCESS_ROLE_ALL:
+	case config.PROCESS_ROLE_SYNC_ONLY, config.PROCESS_ROLE_PROJECTION_WORKER:
+		// The processes of the split roles share the event store through the database
+		if indexServiceConfig.Mode != config.SYSTEM_MODE_EVENT_STORE || (indexServiceConfig.EventStore.Type != "" &&
+			indexServiceConfig.EventStore.Type != config.EVENT_STORE_TYPE_RDB) {
+			logger.Panicf("process role %s requires %s mode with RDb event store", role, config.SYSTEM_MODE_EVENT_STORE)
+		}
+		if role == config.PROCESS_ROLE_PROJECTION_WORKER {
+			projections = assignedProjections(logger, projections, indexServiceConfig.ProjectionWorker.Projections)
+			cronJobs = assignedCronJobs(logger, cronJobs, indexServiceConfig.ProjectionWorker.CronJobs)
+			if indexServiceConfig.ProjectionWorker.LeaseDuration != "" {
+				var err error
+				leaseDuration, err = time.ParseDuration(indexServiceConfig.ProjectionWorker.LeaseDuration)
+				if err != nil {
+					logger.Panicf("error parsing projection worker lease duration: %v", err)
+				}
+			}
+		}
+	default:
+		logger.Panicf("unsupported process role of index service: %s", role)
+	}
+
+	return role, projections, cronJobs, leaseDuration
+}
+
+// assignedProjections returns the projections assigned to the projection worker, all of them when none is assigned
+func assignedProjections(
+	logger applogger.Logger,
+	projections []projection_entity.Projection,
+	projectionIds []string,
+) []projection_entity.Projection {
+	if len(projectionIds) == 0 {
+		return projections
+	}
+
+	assigned := make([]projection_entity.Projection, 0, len(projectionIds))
+	for _, projectionId := range projectionIds {
+		found := false
+		for _, projection := range projections {
+			if projection.Id() == projectionId {
+				assigned = append(assigned, projection)
+				found = true
+				break
+			}
+		}
+		if !found {
+			logger.Panicf("projection `%s` assigned to projection worker is not enabled", projectionId)
+		}
+	}
+
+	return assigned
+}
+
+// assignedCronJobs returns the cron jobs assigned to the projection worker, all of them when none is assigned
+func assignedCronJobs(
+	logger applogger.Logger,
+	cronJobs []projection_entity.CronJob,
+	cronJobIds []string,
+) []projection_entity.CronJob {
+	if len(cronJobIds) == 0 {
+		return cronJobs
+	}
+
+	assigned := make([]projection_entity.CronJob, 0, len(cronJobIds))
+	for _, cronJobId := range cronJobIds {
+		found := false
+		for _, cronJob := range cronJobs {
+			if cronJob.Id() == cronJobId {
+				assigned = append(assigned, cronJob)
+				found = true
+				break
+			}
+		}
+		if !found {
+			logger.Panicf("cron job `%s` assigned to projection worker is not enabled", cronJobId)
+		}
+	}
+
+	return assigned
+}
+
+func newFailurePolicy(
+	logger applogger.Logger,
+	failurePolicy config.ProjectionFailurePolicy,
+) projection_entity.FailurePolicy {
+	switch failurePolicy.Action {
+	case "",
+		projection_entity.FAILURE_ACTION_RETRY,
+		projection_entity.FAILURE_ACTION_HALT,
+		projection_entity.FAILURE_ACTION_SKIP:
+	default:
+		logger.Panicf("unsupported projection failure action: %s", failurePolicy.Action)
+	}
+
+	return projection_entity.FailurePolicy{
+		MaxRetry: failurePolicy.MaxRetry,
+		Action:   failurePolicy.Action,
+	}
+}
+
+// WithWriteFence persists the synced events, the projection heights and the writes of the cron jobs only when the
+// write fence is passed, e.g. while running as the leader
+func (service *IndexService) WithWriteFence(writeFence eventhandler_interface.WriteFence) *IndexService {
+	service.maybeWriteFence = writeFence
+	for _, projection := range service.projections {
+		if writeFenced, ok := projection.(eventhandler_interface.WriteFenced); ok {
+			writeFenced.SetWriteFence(writeFence)
+		}
+	}
+	for _, cronJob := range service.cronJobs {
+		if writeFenced, ok := cronJob.(eventhandler_interface.WriteFenced); ok {
+			writeFenced.SetWriteFence(writeFence)
+		}
 	}
+	return service
 }
 
-func (service *IndexService) Run() error {
+// Run starts the index service according to its process role. It returns when the context is cancelled and all the
+// components have stopped.
+func (service *IndexService) Run(ctx context.Context) error {
 	// run polling tendermint manager, update view tables directly
 	infoManager := NewInfoManager(
 		service.logger,
 		service.rdbConn,
-		service.tendermintHTTPRPCURL,
+		service.tendermintHTTPRPCURLs,
+		service.tendermintWebSocketURL,
 		service.insecureTendermintClient,
 		service.strictGenesisParsing,
 	)
+	if service.archiveMode == config.ARCHIVE_MODE_REPLAY {
+		archive, err := blockarchive.NewArchive(service.archiveDirectory)
+		if err != nil {
+			return fmt.Errorf("error opening block archive: %v", err)
+		}
+		infoManager = infoManager.WithArchiveClient(blockarchive.NewClient(archive, service.strictGenesisParsing))
+	}
+
+	var cronJobsWaitGroup sync.WaitGroup
+	defer cronJobsWaitGroup.Wait()
+	defer service.rebuildsWaitGroup.Wait()
+
+	switch service.role {
+	case config.PROCESS_ROLE_SYNC_ONLY:
+		infoManager.Run(ctx)
+		return service.RunSyncOnlyRole(ctx)
+	case config.PROCESS_ROLE_PROJECTION_WORKER:
+		service.runCronJobs(ctx, &cronJobsWaitGroup)
+		return service.RunProjectionWorkerRole(ctx)
+	}
 
 	switch service.mode {
 	case config.SYSTEM_MODE_EVENT_STORE:
-		infoManager.Run()
-		service.runCronJobs()
-		return service.RunEventStoreMode()
+		infoManager.Run(ctx)
+		service.runCronJobs(ctx, &cronJobsWaitGroup)
+		return service.RunEventStoreMode(ctx)
 	case config.SYSTEM_MODE_TENDERMINT_DIRECT:
-		infoManager.Run()
-		service.runCronJobs()
-		return service.RunTendermintDirectMode()
+		infoManager.Run(ctx)
+		service.runCronJobs(ctx, &cronJobsWaitGroup)
+		return service.RunTendermintDirectMode(ctx)
 	default:
 		return fmt.Errorf("unsupported system mode: %s", service.mode)
 	}
 }
 
-func (service *IndexService) runCronJobs() {
+func (service *IndexService) runCronJobs(ctx context.Context, waitGroup *sync.WaitGroup) {
 	for i := range service.cronJobs {
 		cronJobClosure := service.cronJobs[i]
+		waitGroup.Add(1)
 		go func() {
+			defer waitGroup.Done()
+
 			logger := service.logger.WithFields(applogger.LogFields{
 				"module": cronJobClosure.Id(),
 			})
 			for {
-				if cronJobErr := cronJobClosure.Exec(); cronJobErr != nil {
+				if cronJobErr := cronJobClosure.Exec(ctx); cronJobErr != nil {
+					if ctx.Err() != nil {
+						logger.Infof("cron job stopped")
+						return
+					}
 					logger.Errorf("error executing cron job: %v", cronJobErr)
 				}
 				logger.Infof("successfully executed cron job, going to execute again in %s", cronJobClosure.Interval())
-				<-time.After(cronJobClosure.Interval())
+				select {
+				case <-ctx.Done():
+					logger.Infof("cron job stopped")
+					return
+				case <-time.After(cronJobClosure.Interval()):
+				}
 			}
 		}()
 	}
 }
 
-func (service *IndexService) RunEventStoreMode() error {
+func (service *IndexService) RunEventStoreMode(ctx context.Context) error {
+	eventRegistry := newEventRegistry()
+	eventStore, maybeSegmentStore, err := service.openEventStore(eventRegistry)
+	if err != nil {
+		return err
+	}
+	if maybeSegmentStore != nil {
+		defer maybeSegmentStore.Close()
+	}
+
+	projectionManager := projection_entity.NewStoreBasedManager(
+		service.logger, eventStore,
+	).WithFailureHandling(
+		service.failurePolicies, rdbfailurestore.NewRDbFailureStore(service.rdbConn.ToHandle()),
+	)
+	waitProjections, err := service.runProjections(ctx, projectionManager, eventStore, nil)
+	if err != nil {
+		return err
+	}
+	defer waitProjections()
+
+	return service.runSync(ctx, eventRegistry, maybeSegmentStore)
+}
+
+// RunSyncOnlyRole syncs the blocks into the event store without running any projection
+func (service *IndexService) RunSyncOnlyRole(ctx context.Context) error {
+	return service.runSync(ctx, newEventRegistry(), nil)
+}
+
+// RunProjectionWorkerRole runs the assigned projections from the event store synced by other processes. The projections
+// follow the progress of their dependencies run by other workers in the projections table, and only handle events
+// while holding their leases such that the same projection can be assigned to multiple workers.
+func (service *IndexService) RunProjectionWorkerRole(ctx context.Context) error {
+	eventStore, _, err := service.openEventStore(newEventRegistry())
+	if err != nil {
+		return err
+	}
+
+	rdbHandle := service.rdbConn.ToHandle()
+	leaser := rdbleaser.NewRDbLeaser(service.logger, rdbHandle, service.leaseDuration)
+	projectionManager := projection_entity.NewStoreBasedManager(
+		service.logger, eventStore,
+	).WithFailureHandling(
+		service.failurePolicies, rdbfailurestore.NewRDbFailureStore(rdbHandle),
+	).WithProgressStore(
+		rdbprojectionbase.NewProgressStore(rdbHandle, rdbprojectionbase.DEFAULT_TABLE),
+	).WithLeaser(leaser)
+	waitProjections, err := service.runProjections(ctx, projectionManager, eventStore, leaser)
+	if err != nil {
+		return err
+	}
+	defer waitProjections()
+
+	<-ctx.Done()
+	return nil
+}
+
+func newEventRegistry() *event.Registry {
 	eventRegistry := event.NewRegistry()
 	event_usecase.RegisterEvents(eventRegistry)
-	eventStore := event_interface.NewRDbStore(service.rdbConn.ToHandle(), eventRegistry)
 
-	projectionManager := projection_entity.NewStoreBasedManager(service.logger, eventStore)
+	return eventRegistry
+}
 
+// openEventStore opens the configured event store. The segment store is returned as well when it is used, which must
+// be closed by the caller.
+func (service *IndexService) openEventStore(
+	eventRegistry *event.Registry,
+) (event.Store, *segmentstore.SegmentStore, error) {
+	switch service.eventStoreType {
+	case config.EVENT_STORE_TYPE_RDB, "":
+		rdbEventStore := event_interface.NewRDbStore(service.rdbConn.ToHandle(), eventRegistry)
+		if listener, ok := service.rdbConn.(rdb.Listener); ok {
+			// Projections are woken up as soon as events are stored instead of waiting for the next polling
+			rdbEventStore = rdbEventStore.WithListener(listener)
+		}
+		return rdbEventStore, nil, nil
+	case config.EVENT_STORE_TYPE_SEGMENT:
+		segmentStore, err := segmentstore.NewSegmentStore(service.segmentDirectory, eventRegistry)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error opening segment event store: %v", err)
+		}
+		if service.maxSegmentSize > 0 {
+			segmentStore = segmentStore.WithMaxSegmentSize(service.maxSegmentSize)
+		}
+		return segmentStore, segmentStore, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported event store type: %s", service.eventStoreType)
+	}
+}
+
+// runProjections runs the projections with the manager in background and makes them controllable. The leaser is the one
+// of the manager, nil when the manager does not lease the projections. Returns the function waiting for the projections
+// to stop after the context is cancelled.
+func (service *IndexService) runProjections(
+	ctx context.Context,
+	projectionManager *projection_entity.StoreBasedManager,
+	eventStore event.Store,
+	maybeLeaser projection_entity.Leaser,
+) (func(), error) {
 	for _, projection := range service.projections {
 		if err := projectionManager.RegisterProjection(projection); err != nil {
-			return fmt.Errorf("error registering projection `%s` to manager %v", projection.Id(), err)
+			return nil, fmt.Errorf("error registering projection `%s` to manager %v", projection.Id(), err)
+		}
+	}
+	projectionManager.RunInBackground(ctx)
+
+	var maybeProjectionRebuilder *ProjectionRebuilder
+	if pgxConn, ok := service.rdbConn.(*pg.PgxConn); ok {
+		maybeProjectionRebuilder = NewProjectionRebuilder(service.logger, pgxConn, eventStore)
+		if maybeLeaser != nil {
+			maybeProjectionRebuilder = maybeProjectionRebuilder.WithLeaser(maybeLeaser)
 		}
 	}
-	projectionManager.RunInBackground()
+	service.setProjectionController(ctx, projectionManager, maybeProjectionRebuilder)
+
+	return func() {
+		service.setProjectionController(ctx, nil, nil)
+		projectionManager.Wait()
+	}, nil
+}
 
+// runSync syncs the blocks into the event store until the context is cancelled
+func (service *IndexService) runSync(
+	ctx context.Context,
+	eventRegistry *event.Registry,
+	maybeSegmentStore *segmentstore.SegmentStore,
+) error {
 	eventStoreHandler := eventhandler_interface.NewRDbEventStoreHandler(
 		service.logger,
 		service.rdbConn,
 		eventRegistry,
 	)
+	if maybeSegmentStore != nil {
+		eventStoreHandler = eventStoreHandler.WithEventStore(maybeSegmentStore)
+	}
+	if service.maybeWriteFence != nil {
+		eventStoreHandler = eventStoreHandler.WithWriteFence(service.maybeWriteFence)
+	}
 	txDecoder := utils.NewTxDecoder()
 	syncManager := NewSyncManager(
 		SyncManagerParams{
@@ -139,13 +527,23 @@ func (service *IndexService) RunEventStoreMode() error {
 			TxDecoder: txDecoder,
 			Config: SyncManagerConfig{
 				WindowSize:               service.windowSize,
-				TendermintRPCUrl:         service.tendermintHTTPRPCURL,
-				CosmosAppHTTPRPCURL:      service.cosmosAppHTTPRPCURL,
+				StartHeight:              service.startHeight,
+				SyncStrategy:             service.syncStrategy,
+				AdaptiveMinConcurrency:   service.adaptiveMinConcurrency,
+				AdaptiveMaxConcurrency:   service.adaptiveMaxConcurrency,
+				TendermintRPCUrls:        service.tendermintHTTPRPCURLs,
+				TendermintWebSocketURL:   service.tendermintWebSocketURL,
+				CosmosAppHTTPRPCURLs:     service.cosmosAppHTTPRPCURLs,
 				InsecureTendermintClient: service.insecureTendermintClient,
 				InsecureCosmosAppClient:  service.insecureCosmosAppClient,
+				ArchiveMode:              service.archiveMode,
+				ArchiveDirectory:         service.archiveDirectory,
+				BatchSize:                service.batchSize,
 				StrictGenesisParsing:     service.strictGenesisParsing,
+				GenesisFilePath:          service.genesisFilePath,
 				AccountAddressPrefix:     service.accountAddressPrefix,
 				StakingDenom:             service.bondingDenom,
+				Eras:                     service.syncManagerEras,
 			},
 		},
 		utils.NewCosmosParserManager(
@@ -153,56 +551,215 @@ func (service *IndexService) RunEventStoreMode() error {
 				Logger: service.logger,
 				Config: utils.CosmosParserManagerConfig{
 					CosmosVersionBlockHeight: service.cosmosVersionBlockHeight,
+					Eras:                     service.parserEras,
 				},
 			},
 		),
 		eventStoreHandler,
 	)
-	if err := syncManager.Run(); err != nil {
+	if err := syncManager.Run(ctx); err != nil {
 		return fmt.Errorf("error running sync manager %v", err)
 	}
 
 	return nil
 }
 
-func (service *IndexService) RunTendermintDirectMode() error {
+func (service *IndexService) RunTendermintDirectMode(ctx context.Context) error {
+	if err := projection_entity.CheckProjectionDependencyCycle(service.projections); err != nil {
+		return fmt.Errorf("error checking projection dependencies: %v", err)
+	}
+
 	txDecoder := utils.NewTxDecoder()
 
-	for i := range service.projections {
-		go func(projection projection_entity.Projection) {
-			syncManager := NewSyncManager(
-				SyncManagerParams{
-					Logger: service.logger.WithFields(applogger.LogFields{
-						"projection": projection.Id(),
-					}),
-					RDbConn:   service.rdbConn,
-					TxDecoder: txDecoder,
-					Config: SyncManagerConfig{
-						WindowSize:               service.windowSize,
-						TendermintRPCUrl:         service.tendermintHTTPRPCURL,
-						CosmosAppHTTPRPCURL:      service.cosmosAppHTTPRPCURL,
-						InsecureTendermintClient: service.insecureTendermintClient,
-						InsecureCosmosAppClient:  service.insecureCosmosAppClient,
-						AccountAddressPrefix:     service.accountAddressPrefix,
-						StakingDenom:             service.bondingDenom,
-					},
+	// Blocks are fetched and parsed once and the events are fanned out to all projections
+	projectionHandlers := make([]eventhandler_interface.Handler, 0, len(service.projections))
+	for _, projection := range service.projections {
+		projectionHandlers = append(projectionHandlers, eventhandler_interface.NewProjectionHandler(
+			service.logger.WithFields(applogger.LogFields{
+				"projection": projection.Id(),
+			}),
+			projection,
+		))
+	}
+	fanoutHandler := eventhandler_interface.NewFanoutHandler(
+		service.logger, projectionHandlers, service.fanoutCacheSize,
+	).WithFailureHandling(
+		service.failurePolicies, rdbfailurestore.NewRDbFailureStore(service.rdbConn.ToHandle()),
+	)
+
+	syncManager := NewSyncManager(
+		SyncManagerParams{
+			Logger:    service.logger,
+			RDbConn:   service.rdbConn,
+			TxDecoder: txDecoder,
+			Config: SyncManagerConfig{
+				WindowSize:               service.windowSize,
+				StartHeight:              service.startHeight,
+				SyncStrategy:             service.syncStrategy,
+				AdaptiveMinConcurrency:   service.adaptiveMinConcurrency,
+				AdaptiveMaxConcurrency:   service.adaptiveMaxConcurrency,
+				TendermintRPCUrls:        service.tendermintHTTPRPCURLs,
+				TendermintWebSocketURL:   service.tendermintWebSocketURL,
+				CosmosAppHTTPRPCURLs:     service.cosmosAppHTTPRPCURLs,
+				InsecureTendermintClient: service.insecureTendermintClient,
+				InsecureCosmosAppClient:  service.insecureCosmosAppClient,
+				ArchiveMode:              service.archiveMode,
+				ArchiveDirectory:         service.archiveDirectory,
+				BatchSize:                service.batchSize,
+				GenesisFilePath:          service.genesisFilePath,
+				AccountAddressPrefix:     service.accountAddressPrefix,
+				StakingDenom:             service.bondingDenom,
+				Eras:                     service.syncManagerEras,
+			},
+		},
+		utils.NewCosmosParserManager(
+			utils.CosmosParserManagerParams{
+				Logger: service.logger,
+				Config: utils.CosmosParserManagerConfig{
+					CosmosVersionBlockHeight: service.cosmosVersionBlockHeight,
+					Eras:                     service.parserEras,
 				},
-				utils.NewCosmosParserManager(
-					utils.CosmosParserManagerParams{
-						Logger: service.logger.WithFields(applogger.LogFields{
-							"projection": projection.Id(),
-						}),
-						Config: utils.CosmosParserManagerConfig{
-							CosmosVersionBlockHeight: service.cosmosVersionBlockHeight,
-						},
-					},
-				),
-				eventhandler_interface.NewProjectionHandler(service.logger, projection),
-			)
-			if err := syncManager.Run(); err != nil {
-				panic(fmt.Sprintf("error running sync manager %v", err))
-			}
-		}(service.projections[i])
+			},
+		),
+		fanoutHandler,
+	)
+	// Handlers behind the cached heights, e.g. resumed or rewound ones, catch up by syncing the blocks again
+	fanoutHandler = fanoutHandler.WithCatchUpSource(syncManager)
+	defer fanoutHandler.Close()
+	service.setProjectionController(ctx, fanoutHandler, nil)
+	defer service.setProjectionController(ctx, nil, nil)
+	if err := syncManager.Run(ctx); err != nil {
+		return fmt.Errorf("error running sync manager %v", err)
+	}
+
+	return nil
+}
+
+func (service *IndexService) setProjectionController(
+	ctx context.Context,
+	maybeController projection_entity.Controller,
+	maybeRebuilder *ProjectionRebuilder,
+) {
+	service.controlMutex.Lock()
+	defer service.controlMutex.Unlock()
+
+	service.maybeProjectionController = maybeController
+	service.maybeProjectionRebuilder = maybeRebuilder
+	service.rebuildCtx = ctx
+}
+
+// projectionController returns the controller of the running projections, or error when the projection cannot be
+// controlled. Must be called with the controlMutex locked.
+func (service *IndexService) projectionController(projectionId string) (projection_entity.Controller, error) {
+	if service.maybeProjectionController == nil {
+		return nil, errors.New("index service is not running")
 	}
-	select {}
+	if service.rebuildingProjectionIds[projectionId] {
+		return nil, fmt.Errorf("projection `%s` is being rebuilt", projectionId)
+	}
+
+	return service.maybeProjectionController, nil
+}
+
+// PauseProjection pauses the running projection. It returns once the events being handled by the projection are
+// handled.
+func (service *IndexService) PauseProjection(projectionId string) error {
+	service.controlMutex.Lock()
+	controller, err := service.projectionController(projectionId)
+	service.controlMutex.Unlock()
+	if err != nil {
+		return err
+	}
+
+	// Waits for the runner without holding the controlMutex so that the other projections can be controlled meanwhile
+	return controller.PauseProjection(projectionId)
+}
+
+// ResumeProjection resumes the paused or halted projection from its last handled event height
+func (service *IndexService) ResumeProjection(projectionId string) error {
+	service.controlMutex.Lock()
+	defer service.controlMutex.Unlock()
+
+	controller, err := service.projectionController(projectionId)
+	if err != nil {
+		return err
+	}
+	return controller.ResumeProjection(projectionId)
+}
+
+// RewindProjection rewinds the paused projection to the height when the projection supports it
+func (service *IndexService) RewindProjection(projectionId string, height int64) error {
+	service.controlMutex.Lock()
+	defer service.controlMutex.Unlock()
+
+	controller, err := service.projectionController(projectionId)
+	if err != nil {
+		return err
+	}
+	return controller.RewindProjection(projectionId, height)
+}
+
+// RebuildProjection pauses the projection and rebuilds it in place in background, then resumes it. The projection is
+// kept paused when the rebuild fails. onFinished is called with the rebuild error, if any, once finished. Rebuild is
+// only supported in event store mode.
+func (service *IndexService) RebuildProjection(projectionId string, onFinished func(error)) error {
+	service.controlMutex.Lock()
+	controller, err := service.projectionController(projectionId)
+	if err != nil {
+		service.controlMutex.Unlock()
+		return err
+	}
+	if service.maybeProjectionRebuilder == nil {
+		service.controlMutex.Unlock()
+		return fmt.Errorf("rebuilding projection is not supported in %s mode", service.mode)
+	}
+	var projection projection_entity.Projection
+	for _, registeredProjection := range service.projections {
+		if registeredProjection.Id() == projectionId {
+			projection = registeredProjection
+			break
+		}
+	}
+	if projection == nil {
+		service.controlMutex.Unlock()
+		return projection_entity.ErrProjectionNotFound
+	}
+
+	// Marked as being rebuilt before pausing so that the projection cannot be resumed or rewound meanwhile
+	service.rebuildingProjectionIds[projectionId] = true
+	rebuilder := service.maybeProjectionRebuilder
+	ctx := service.rebuildCtx
+	service.rebuildsWaitGroup.Add(1)
+	service.controlMutex.Unlock()
+
+	// Waits for the runner without holding the controlMutex so that the other projections can be controlled meanwhile
+	if err := controller.PauseProjection(projectionId); err != nil {
+		service.controlMutex.Lock()
+		delete(service.rebuildingProjectionIds, projectionId)
+		service.controlMutex.Unlock()
+		service.rebuildsWaitGroup.Done()
+		return err
+	}
+
+	go func() {
+		defer service.rebuildsWaitGroup.Done()
+
+		err := rebuilder.Rebuild(ctx, projectionId, func(_ rdb.Conn) (projection_entity.Projection, error) {
+			return projection, nil
+		})
+		if err == nil {
+			err = controller.ResumeProjection(projectionId)
+		}
+		if err != nil {
+			service.logger.Errorf("error rebuilding projection `%s`, the projection is kept paused: %v", projectionId, err)
+		}
+
+		service.controlMutex.Lock()
+		delete(service.rebuildingProjectionIds, projectionId)
+		service.controlMutex.Unlock()
+
+		onFinished(err)
+	}()
+
+	return nil
 }
@@ -1,12 +1,16 @@
 package bootstrap
 
 import (
+	"context"
+	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/crypto-com/chain-indexing/appinterface/polling"
 	"github.com/crypto-com/chain-indexing/appinterface/rdb"
 	applogger "github.com/crypto-com/chain-indexing/external/logger"
-	"github.com/crypto-com/chain-indexing/infrastructure/tendermint"
+	"github.com/crypto-com/chain-indexing/infrastructure/blockarchive"
+	chainfeed "github.com/crypto-com/chain-indexing/infrastructure/feed/chain"
 )
 
 // TODO: Move InfoManager to CronJob
@@ -14,31 +18,24 @@ const INFO_DEFAULT_POLLING_INTERVAL = 5 * time.Second
 
 type InfoManager struct {
 	rdbConn         rdb.Conn
-	client          *tendermint.HTTPClient
+	client          TendermintStatusClient
+	websocketURL    string
 	pollingInterval time.Duration
 	viewStatus      *polling.Status
 	logger          applogger.Logger
+
+	maybeArchiveClient *blockarchive.Client
 }
 
 func NewInfoManager(
 	logger applogger.Logger,
 	rdbConn rdb.Conn,
-	tendermintRPCUrl string,
+	tendermintRPCUrls []string,
+	tendermintWebSocketURL string,
 	insecureTendermintClient bool,
 	strictGenesisParsing bool,
 ) *InfoManager {
-	var tendermintClient *tendermint.HTTPClient
-	if insecureTendermintClient {
-		tendermintClient = tendermint.NewInsecureHTTPClient(
-			tendermintRPCUrl,
-			strictGenesisParsing,
-		)
-	} else {
-		tendermintClient = tendermint.NewHTTPClient(
-			tendermintRPCUrl,
-			strictGenesisParsing,
-		)
-	}
+	tendermintClient := NewTendermintClient(tendermintRPCUrls, insecureTendermintClient, strictGenesisParsing)
 
 	viewStatus := polling.NewStatus(rdbConn.ToHandle())
 	return &InfoManager{
@@ -47,34 +44,97 @@ func NewInfoManager(
 		}),
 		rdbConn:         rdbConn,
 		client:          tendermintClient,
+		websocketURL:    tendermintWebSocketURL,
 		viewStatus:      viewStatus,
 		pollingInterval: INFO_DEFAULT_POLLING_INTERVAL,
 	}
 
 }
 
-func (manager *InfoManager) Run() {
+// WithArchiveClient reports the latest height archived instead of querying the Tendermint node, so that no network
+// request is made while blocks are replayed from the archive
+func (manager *InfoManager) WithArchiveClient(archiveClient *blockarchive.Client) *InfoManager {
+	manager.maybeArchiveClient = archiveClient
+	return manager
+}
+
+func (manager *InfoManager) Run(ctx context.Context) {
 	manager.logger.Infof("InfoManager started")
+	if manager.websocketURL != "" && manager.maybeArchiveClient == nil {
+		manager.runWithWebSocket(ctx)
+		return
+	}
+
 	go func() {
 		for {
-			status, err := manager.client.Status()
+			select {
+			case <-ctx.Done():
+				manager.logger.Infof("InfoManager stopped")
+				return
+			default:
+			}
+
+			latestHeight, err := manager.latestHeight()
 			if err != nil {
-				manager.logger.Errorf("error querying Tendermint status: %v", err)
-				time.Sleep(manager.pollingInterval)
+				manager.logger.Errorf("error querying latest height: %v", err)
+				manager.waitForPollingInterval(ctx)
 				continue
 			}
-			result := (*status)["result"]
-			syncInfo := result.(map[string]interface{})["sync_info"]
-			latestHeight := syncInfo.(map[string]interface{})["latest_block_height"].(string)
 
 			err = manager.viewStatus.Upsert("LatestHeight", latestHeight)
 			if err != nil {
 				manager.logger.Errorf("error upserting latest height: %v", err)
-				time.Sleep(manager.pollingInterval)
+				manager.waitForPollingInterval(ctx)
 				continue
 			}
 
-			time.Sleep(manager.pollingInterval)
+			manager.waitForPollingInterval(ctx)
+		}
+	}()
+}
+
+func (manager *InfoManager) latestHeight() (string, error) {
+	if manager.maybeArchiveClient != nil {
+		latestHeight, err := manager.maybeArchiveClient.LatestBlockHeight()
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatInt(latestHeight, 10), nil
+	}
+
+	status, err := manager.client.Status()
+	if err != nil {
+		return "", fmt.Errorf("error querying Tendermint status: %v", err)
+	}
+	result := (*status)["result"]
+	syncInfo := result.(map[string]interface{})["sync_info"]
+	return syncInfo.(map[string]interface{})["latest_block_height"].(string), nil
+}
+
+func (manager *InfoManager) waitForPollingInterval(ctx context.Context) {
+	select {
+	case <-ctx.Done():
+	case <-time.After(manager.pollingInterval):
+	}
+}
+
+// runWithWebSocket updates the latest height on every new block pushed from the Tendermint WebSocket
+func (manager *InfoManager) runWithWebSocket(ctx context.Context) {
+	tracker := chainfeed.NewWebSocketBlockHeightTracker(ctx, manager.logger, manager.client, manager.websocketURL)
+	blockHeightCh := make(chan int64, 1)
+	tracker.Subscribe(blockHeightCh)
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				manager.logger.Infof("InfoManager stopped")
+				return
+			case latestHeight := <-blockHeightCh:
+				if err := manager.viewStatus.Upsert("LatestHeight", strconv.FormatInt(latestHeight, 10)); err != nil {
+					manager.logger.Errorf("error upserting latest height: %v", err)
+				}
+			}
 		}
 	}()
 }
@@ -0,0 +1,196 @@
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/crypto-com/chain-indexing/appinterface/eventhandler"
+	"github.com/crypto-com/chain-indexing/appinterface/rdb"
+	"github.com/crypto-com/chain-indexing/appinterface/rdbleaderterm"
+	"github.com/crypto-com/chain-indexing/bootstrap/config"
+	applogger "github.com/crypto-com/chain-indexing/external/logger"
+	"github.com/crypto-com/chain-indexing/infrastructure/metric/prometheus"
+)
+
+const DEFAULT_LEADER_ELECTION_CHECK_INTERVAL = 5 * time.Second
+
+// LeaderTerms numbers the terms of the leaderships, such that the writes of a former leader can be fenced
+type LeaderTerms interface {
+	// Begin begins the next term of the leadership of the lock key and returns it
+	Begin(lockKey int64) (int64, error)
+	// LockWithRDbHandle locks the term until the transaction of the RDb handle ends. Returns error when the term is no
+	// longer the current one.
+	LockWithRDbHandle(rdbHandle *rdb.Handle, lockKey int64, term int64) error
+}
+
+var _ eventhandler.WriteFence = &LeaderElector{}
+
+// LeaderElector elects a single leader among the replicas sharing the database with a session advisory lock. The lock
+// is released by the database when the session of the leader is lost, e.g. on crash, so that one of the followers
+// takes over on its next attempt. Each leadership begins a new term, and the writes fenced by the elector fail once
+// the leader steps down or its term is superseded by the next leader.
+type LeaderElector struct {
+	logger applogger.Logger
+
+	locker        rdb.AdvisoryLocker
+	terms         LeaderTerms
+	lockKey       int64
+	checkInterval time.Duration
+
+	termMutex sync.Mutex
+	// Term of the current leadership, nil when not the leader
+	maybeTerm *int64
+}
+
+func NewLeaderElector(
+	logger applogger.Logger,
+	rdbConn rdb.Conn,
+	config config.LeaderElection,
+) *LeaderElector {
+	locker, ok := rdbConn.(rdb.AdvisoryLocker)
+	if !ok {
+		logger.Panicf("leader election requires RDb connection supporting advisory lock")
+	}
+
+	return NewLeaderElectorWithLocker(logger, locker, rdbleaderterm.NewRDbLeaderTerms(rdbConn.ToHandle()), config)
+}
+
+func NewLeaderElectorWithLocker(
+	logger applogger.Logger,
+	locker rdb.AdvisoryLocker,
+	terms LeaderTerms,
+	config config.LeaderElection,
+) *LeaderElector {
+	checkInterval := DEFAULT_LEADER_ELECTION_CHECK_INTERVAL
+	if config.CheckInterval != "" {
+		var err error
+		checkInterval, err = time.ParseDuration(config.CheckInterval)
+		if err != nil {
+			logger.Panicf("error parsing leader election check interval: %v", err)
+		}
+	}
+
+	return &LeaderElector{
+		logger: logger.WithFields(applogger.LogFields{
+			"module": "LeaderElector",
+		}),
+
+		locker:        locker,
+		terms:         terms,
+		lockKey:       config.LockKey,
+		checkInterval: checkInterval,
+	}
+}
+
+// Run campaigns for the leadership until the context is cancelled, and runs runAsLeader whenever it is the leader. The
+// context passed to runAsLeader is cancelled when the leadership is lost. Returns the error of runAsLeader.
+func (elector *LeaderElector) Run(ctx context.Context, runAsLeader func(ctx context.Context) error) error {
+	prometheus.RecordLeaderElectionIsLeader(false)
+
+	for {
+		lock, err := elector.locker.TryAdvisoryLock(ctx, elector.lockKey)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			elector.logger.Errorf("error campaigning for leadership: %v", err)
+		} else if lock != nil {
+			if leadErr := elector.lead(ctx, lock, runAsLeader); leadErr != nil {
+				return leadErr
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(elector.checkInterval):
+		}
+	}
+}
+
+func (elector *LeaderElector) lead(
+	ctx context.Context,
+	lock rdb.AdvisoryLock,
+	runAsLeader func(ctx context.Context) error,
+) error {
+	term, err := elector.terms.Begin(elector.lockKey)
+	if err != nil {
+		elector.logger.Errorf("error beginning leader term, giving up leadership: %v", err)
+		if releaseErr := lock.Release(); releaseErr != nil {
+			elector.logger.Errorf("error releasing leadership: %v", releaseErr)
+		}
+		return nil
+	}
+	elector.setTerm(&term)
+	elector.logger.Infof("acquired leadership of term %d", term)
+	prometheus.RecordLeaderElectionIsLeader(true)
+	prometheus.RecordLeaderElectionLeadershipAcquired()
+	defer prometheus.RecordLeaderElectionIsLeader(false)
+
+	leaderCtx, cancelLeaderCtx := context.WithCancel(ctx)
+	defer cancelLeaderCtx()
+
+	runErrCh := make(chan error, 1)
+	go func() {
+		runErrCh <- runAsLeader(leaderCtx)
+	}()
+
+	var runErr error
+	ticker := time.NewTicker(elector.checkInterval)
+	defer ticker.Stop()
+	for runErrCh != nil {
+		select {
+		case runErr = <-runErrCh:
+			runErrCh = nil
+		case <-ticker.C:
+			if pingErr := elector.ping(lock); pingErr != nil {
+				elector.logger.Errorf("lost leadership, stepping down: %v", pingErr)
+				// Fences the writes before waiting for the leader to stop, as the next leader may take over meanwhile
+				elector.setTerm(nil)
+				cancelLeaderCtx()
+				// Errors after the leadership is lost are caused by stepping down, the next leader takes over the works
+				if stepDownErr := <-runErrCh; stepDownErr != nil {
+					elector.logger.Errorf("error stepping down: %v", stepDownErr)
+				}
+				runErrCh = nil
+			}
+		}
+	}
+
+	elector.setTerm(nil)
+	if err := lock.Release(); err != nil {
+		elector.logger.Errorf("error releasing leadership: %v", err)
+	}
+	elector.logger.Infof("released leadership")
+
+	return runErr
+}
+
+func (elector *LeaderElector) ping(lock rdb.AdvisoryLock) error {
+	ctx, cancel := context.WithTimeout(context.Background(), elector.checkInterval)
+	defer cancel()
+
+	return lock.Ping(ctx)
+}
+
+// FenceWithRDbHandle implements eventhandler.WriteFence.FenceWithRDbHandle(). The writes are allowed only while this
+// replica is the leader of the current term.
+func (elector *LeaderElector) FenceWithRDbHandle(rdbHandle *rdb.Handle) error {
+	elector.termMutex.Lock()
+	maybeTerm := elector.maybeTerm
+	elector.termMutex.Unlock()
+	if maybeTerm == nil {
+		return errors.New("error fencing writes: not the leader")
+	}
+
+	return elector.terms.LockWithRDbHandle(rdbHandle, elector.lockKey, *maybeTerm)
+}
+
+func (elector *LeaderElector) setTerm(maybeTerm *int64) {
+	elector.termMutex.Lock()
+	defer elector.termMutex.Unlock()
+
+	elector.maybeTerm = maybeTerm
+}
@@ -0,0 +1,209 @@
+package bootstrap_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/crypto-com/chain-indexing/appinterface/projection/rdbprojectionbase"
+	"github.com/crypto-com/chain-indexing/appinterface/rdb"
+	. "github.com/crypto-com/chain-indexing/appinterface/rdb/test"
+	"github.com/crypto-com/chain-indexing/bootstrap"
+	"github.com/crypto-com/chain-indexing/bootstrap/config"
+	. "github.com/crypto-com/chain-indexing/external/logger/test"
+	"github.com/crypto-com/chain-indexing/infrastructure/pg"
+)
+
+type mockLeaderTerms struct {
+	mock.Mock
+}
+
+func (terms *mockLeaderTerms) Begin(lockKey int64) (int64, error) {
+	mockArgs := terms.Called(lockKey)
+
+	return mockArgs.Get(0).(int64), mockArgs.Error(1)
+}
+
+func (terms *mockLeaderTerms) LockWithRDbHandle(rdbHandle *rdb.Handle, lockKey int64, term int64) error {
+	mockArgs := terms.Called(rdbHandle, lockKey, term)
+
+	return mockArgs.Error(0)
+}
+
+const anyLockKey = int64(7001)
+
+var _ = Describe("LeaderElector", func() {
+	var (
+		mockLocker *MockAdvisoryLocker
+		mockLock   *MockAdvisoryLock
+		mockTerms  *mockLeaderTerms
+		elector    *bootstrap.LeaderElector
+	)
+
+	BeforeEach(func() {
+		mockLocker = NewMockAdvisoryLocker()
+		mockLock = NewMockAdvisoryLock()
+		mockTerms = &mockLeaderTerms{}
+		elector = bootstrap.NewLeaderElectorWithLocker(
+			NewFakeLogger(),
+			mockLocker,
+			mockTerms,
+			config.LeaderElection{
+				Enable:        true,
+				LockKey:       anyLockKey,
+				CheckInterval: "10ms",
+			},
+		)
+	})
+
+	It("should run as the leader of a new term with writes fenced by the term once the lock is acquired", func() {
+		mockLocker.On("TryAdvisoryLock", mock.Anything, anyLockKey).Return(mockLock, nil)
+		mockLock.On("Ping", mock.Anything).Return(nil)
+		mockLock.On("Release").Return(nil)
+		mockTerms.On("Begin", anyLockKey).Return(int64(1), nil)
+		mockTerms.On("LockWithRDbHandle", mock.Anything, anyLockKey, int64(1)).Return(nil)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		var fenceErrWhileLeading error
+		err := elector.Run(ctx, func(_ context.Context) error {
+			fenceErrWhileLeading = elector.FenceWithRDbHandle(nil)
+			cancel()
+			return nil
+		})
+
+		Expect(err).To(BeNil())
+		Expect(fenceErrWhileLeading).To(BeNil())
+		Expect(elector.FenceWithRDbHandle(nil)).NotTo(BeNil())
+		mockTerms.AssertCalled(GinkgoT(), "LockWithRDbHandle", mock.Anything, anyLockKey, int64(1))
+		mockLock.AssertCalled(GinkgoT(), "Release")
+	})
+
+	It("should return the error of the leader", func() {
+		mockLocker.On("TryAdvisoryLock", mock.Anything, anyLockKey).Return(mockLock, nil)
+		mockLock.On("Ping", mock.Anything).Return(nil)
+		mockLock.On("Release").Return(nil)
+		mockTerms.On("Begin", anyLockKey).Return(int64(1), nil)
+
+		err := elector.Run(context.Background(), func(_ context.Context) error {
+			return errors.New("any error")
+		})
+
+		Expect(err).To(MatchError("any error"))
+		mockLock.AssertCalled(GinkgoT(), "Release")
+	})
+
+	It("should fence the writes and stop the leader once the lock is lost", func() {
+		mockLocker.On("TryAdvisoryLock", mock.Anything, anyLockKey).Once().Return(mockLock, nil)
+		mockLocker.On("TryAdvisoryLock", mock.Anything, anyLockKey).Return(nil, nil)
+		releasedCh := make(chan bool)
+		mockLock.On("Ping", mock.Anything).Return(errors.New("connection lost"))
+		mockLock.On("Release").Once().Run(func(_ mock.Arguments) {
+			close(releasedCh)
+		}).Return(nil)
+		mockTerms.On("Begin", anyLockKey).Return(int64(1), nil)
+		mockTerms.On("LockWithRDbHandle", mock.Anything, anyLockKey, int64(1)).Return(nil)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		stepDownFenceErrCh := make(chan error, 1)
+		runErrCh := make(chan error, 1)
+		go func() {
+			runErrCh <- elector.Run(ctx, func(leaderCtx context.Context) error {
+				<-leaderCtx.Done()
+				stepDownFenceErrCh <- elector.FenceWithRDbHandle(nil)
+				// Errors after stepping down are ignored
+				return errors.New("any error while stepping down")
+			})
+		}()
+
+		var stepDownFenceErr error
+		Eventually(stepDownFenceErrCh).Should(Receive(&stepDownFenceErr))
+		Expect(stepDownFenceErr).NotTo(BeNil())
+
+		Eventually(releasedCh).Should(BeClosed())
+		Consistently(runErrCh).ShouldNot(Receive())
+
+		cancel()
+		Eventually(runErrCh).Should(Receive(BeNil()))
+	})
+
+	It("should fence the projection height commits of a stale leader of which the term is superseded", func() {
+		mockLocker.On("TryAdvisoryLock", mock.Anything, anyLockKey).Return(mockLock, nil)
+		mockLock.On("Ping", mock.Anything).Return(nil)
+		mockLock.On("Release").Return(nil)
+		mockTerms.On("Begin", anyLockKey).Return(int64(1), nil)
+		// The lock session is not yet found lost, but the next leader has begun term 2 meanwhile
+		mockTerms.On(
+			"LockWithRDbHandle", mock.Anything, anyLockKey, int64(1),
+		).Return(errors.New("term 1 is superseded by term 2"))
+
+		mockConn := NewMockRDbConn()
+		mockHandle := &rdb.Handle{
+			Runner:      mockConn,
+			TypeConv:    &pg.PgxTypeConv{},
+			StmtBuilder: pg.PostgresStmtBuilder,
+		}
+		projectionBase := rdbprojectionbase.NewRDbBase(mockHandle, "AnyProjection")
+		projectionBase.SetWriteFence(elector)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		var commitErr error
+		err := elector.Run(ctx, func(_ context.Context) error {
+			commitErr = projectionBase.UpdateLastHandledEventHeight(mockHandle, 10)
+			cancel()
+			return nil
+		})
+
+		Expect(err).To(BeNil())
+		Expect(commitErr).To(MatchError("term 1 is superseded by term 2"))
+		mockConn.AssertNotCalled(GinkgoT(), "Exec", mock.Anything, mock.Anything)
+	})
+
+	It("should take over the leadership once the lock held by the previous leader is released", func() {
+		mockLocker.On("TryAdvisoryLock", mock.Anything, anyLockKey).Twice().Return(nil, nil)
+		mockLocker.On("TryAdvisoryLock", mock.Anything, anyLockKey).Return(mockLock, nil)
+		mockLock.On("Ping", mock.Anything).Return(nil)
+		mockLock.On("Release").Return(nil)
+		mockTerms.On("Begin", anyLockKey).Return(int64(2), nil)
+		mockTerms.On("LockWithRDbHandle", mock.Anything, anyLockKey, int64(2)).Return(nil)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		var fenceErrWhileLeading error
+		err := elector.Run(ctx, func(_ context.Context) error {
+			fenceErrWhileLeading = elector.FenceWithRDbHandle(nil)
+			cancel()
+			return nil
+		})
+
+		Expect(err).To(BeNil())
+		Expect(fenceErrWhileLeading).To(BeNil())
+		mockLocker.AssertNumberOfCalls(GinkgoT(), "TryAdvisoryLock", 3)
+		mockTerms.AssertCalled(GinkgoT(), "LockWithRDbHandle", mock.Anything, anyLockKey, int64(2))
+	})
+
+	It("should not lead when the term fails to begin", func() {
+		mockLocker.On("TryAdvisoryLock", mock.Anything, anyLockKey).Return(mockLock, nil)
+		mockLock.On("Release").Return(nil)
+		mockTerms.On("Begin", anyLockKey).Return(int64(0), errors.New("any error"))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		isLeaderCh := make(chan bool, 1)
+		go func() {
+			_ = elector.Run(ctx, func(_ context.Context) error {
+				isLeaderCh <- true
+				return nil
+			})
+		}()
+
+		Consistently(isLeaderCh, "100ms").ShouldNot(Receive())
+		Expect(elector.FenceWithRDbHandle(nil)).NotTo(BeNil())
+		mockLock.AssertCalled(GinkgoT(), "Release")
+	})
+})
@@ -0,0 +1,515 @@
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ettle/strcase"
+	"github.com/jackc/pgx/v4"
+
+	"github.com/crypto-com/chain-indexing/appinterface/projection/rdbleaser"
+	"github.com/crypto-com/chain-indexing/appinterface/projection/rdbprojectionbase"
+	"github.com/crypto-com/chain-indexing/appinterface/rdb"
+	"github.com/crypto-com/chain-indexing/entity/event"
+	projection_entity "github.com/crypto-com/chain-indexing/entity/projection"
+	applogger "github.com/crypto-com/chain-indexing/external/logger"
+	"github.com/crypto-com/chain-indexing/infrastructure/pg"
+	"github.com/crypto-com/chain-indexing/infrastructure/pg/migrationhelper"
+)
+
+// Prefix of the schema a projection is built into during shadow rebuild
+const SHADOW_SCHEMA_PREFIX = "shadow_"
+
+// Number of attempts to catch up with the live projection and swap the shadow tables before giving up
+const MAX_SHADOW_SWAP_ATTEMPTS = 10
+
+// ErrProjectionLeaseHeld is returned when the projection to rebuild is run by another process holding its lease
+var ErrProjectionLeaseHeld = errors.New("projection lease is held by another process")
+
+// RebuildableProjection is a projection exposing the migration helper of its tables
+type RebuildableProjection interface {
+	projection_entity.Projection
+
+	MigrationHelper() migrationhelper.MigrationHelper
+}
+
+// ProjectionFactory creates the projection storing its tables and last handled event height through the connection
+type ProjectionFactory func(rdbConn rdb.Conn) (projection_entity.Projection, error)
+
+// ProjectionRebuilder resets a projection and replays all the events from the event store. The projection lease is held
+// while the live tables are changed, such that no projection worker handles the projection meanwhile.
+type ProjectionRebuilder struct {
+	logger     applogger.Logger
+	rdbConn    *pg.PgxConn
+	eventStore event.Store
+	leaser     projection_entity.Leaser
+
+	projectionStore *rdbprojectionbase.Store
+}
+
+func NewProjectionRebuilder(
+	logger applogger.Logger,
+	rdbConn *pg.PgxConn,
+	eventStore event.Store,
+) *ProjectionRebuilder {
+	return &ProjectionRebuilder{
+		logger: logger.WithFields(applogger.LogFields{
+			"module": "ProjectionRebuilder",
+		}),
+		rdbConn:    rdbConn,
+		eventStore: eventStore,
+		leaser:     rdbleaser.NewRDbLeaser(logger, rdbConn.ToHandle(), rdbleaser.DEFAULT_LEASE_DURATION),
+
+		projectionStore: rdbprojectionbase.NewStore(rdbprojectionbase.DEFAULT_TABLE),
+	}
+}
+
+// WithLeaser sets the leaser of the projection leases, which is the leaser of the projection manager when the rebuilder
+// runs in the same process, such that the lease held by the paused projection does not block the rebuild
+func (rebuilder *ProjectionRebuilder) WithLeaser(leaser projection_entity.Leaser) *ProjectionRebuilder {
+	rebuilder.leaser = leaser
+
+	return rebuilder
+}
+
+// Rebuild reverts the migrations of the projection, resets its last handled event height, migrates it again and
+// replays the events up to the latest event height. The projection tables are unavailable during the rebuild and the
+// projection must not be running in an index service meanwhile. Returns ErrProjectionLeaseHeld without any change when
+// the projection is run by a projection worker, and stops when the lease is lost during the rebuild.
+func (rebuilder *ProjectionRebuilder) Rebuild(
+	ctx context.Context,
+	projectionId string,
+	newProjection ProjectionFactory,
+) error {
+	logger := rebuilder.logger.WithFields(applogger.LogFields{
+		"projection": projectionId,
+	})
+
+	projection, err := newRebuildableProjection(rebuilder.rdbConn, projectionId, newProjection)
+	if err != nil {
+		return err
+	}
+	downMigrationHelper, ok := projection.MigrationHelper().(migrationhelper.DownMigrationHelper)
+	if !ok {
+		return fmt.Errorf("migration helper of projection `%s` does not support down migration", projectionId)
+	}
+
+	lease, err := rebuilder.acquireLease(projectionId)
+	if err != nil {
+		return err
+	}
+	defer rebuilder.releaseLease(logger, lease)
+	ctx, cancel := contextUntilLeaseLost(ctx, lease)
+	defer cancel()
+	if fencedProjection, ok := projection.(projection_entity.FencedProjection); ok {
+		// Replayed heights are only committed while the lease is held. The projection paused in the same process is set
+		// its own lease again by the manager once resumed.
+		fencedProjection.SetLease(lease)
+		defer fencedProjection.SetLease(nil)
+	}
+
+	logger.Infof("reverting projection migrations")
+	if err := recoverMigrationPanic(func() error {
+		downMigrationHelper.MigrateDown()
+		return nil
+	}); err != nil {
+		return fmt.Errorf("error reverting projection migrations: %v", err)
+	}
+	if err := rebuilder.projectionStore.ResetLastHandledEventHeight(
+		rebuilder.rdbConn.ToHandle(), projectionId,
+	); err != nil {
+		return fmt.Errorf("error resetting projection last handled event height: %v", err)
+	}
+
+	logger.Infof("initializing projection")
+	if err := recoverMigrationPanic(projection.OnInit); err != nil {
+		return fmt.Errorf("error initializing projection: %v", err)
+	}
+
+	maybeLatestHeight, err := rebuilder.eventStore.GetLatestHeight()
+	if err != nil {
+		return fmt.Errorf("error getting latest event height: %v", err)
+	}
+	if maybeLatestHeight == nil {
+		logger.Infof("no event in the system yet, projection rebuilt")
+		return nil
+	}
+
+	logger.Infof("replaying events up to height %d", *maybeLatestHeight)
+	manager := projection_entity.NewStoreBasedManager(rebuilder.logger, rebuilder.eventStore)
+	if err := manager.ReplayProjection(ctx, projection, *maybeLatestHeight); err != nil {
+		if isLeaseLost(lease) {
+			return fmt.Errorf("error replaying events: projection lease lost: %v", err)
+		}
+		return fmt.Errorf("error replaying events: %v", err)
+	}
+
+	logger.Infof("projection rebuilt")
+	return nil
+}
+
+// ShadowRebuild builds the projection into a separate schema while the live tables keep serving. Once the shadow
+// projection has caught up with the live one, the live tables are replaced by the shadow tables and the last handled
+// event height is carried over in a single transaction. The live projection can keep running in an index service,
+// it continues from the swapped tables after its in-flight handling is aborted. The projection lease is held during the
+// swap, which fails with ErrProjectionLeaseHeld when the projection is run by a projection worker.
+func (rebuilder *ProjectionRebuilder) ShadowRebuild(
+	ctx context.Context,
+	projectionId string,
+	newProjection ProjectionFactory,
+) error {
+	logger := rebuilder.logger.WithFields(applogger.LogFields{
+		"projection": projectionId,
+	})
+
+	var liveSchema string
+	if err := rebuilder.rdbConn.QueryRow("SELECT current_schema()").Scan(&liveSchema); err != nil {
+		return fmt.Errorf("error getting current schema: %v", err)
+	}
+	shadowSchema := SHADOW_SCHEMA_PREFIX + strcase.ToSnake(projectionId)
+	if shadowSchema == liveSchema {
+		return fmt.Errorf("shadow schema %s is the current schema", shadowSchema)
+	}
+
+	logger.Infof("creating shadow schema %s", shadowSchema)
+	if err := rebuilder.createShadowSchema(liveSchema, shadowSchema); err != nil {
+		return err
+	}
+	shadowRDbConn, err := rebuilder.rdbConn.WithSearchPath(shadowSchema)
+	if err != nil {
+		return fmt.Errorf("error connecting to shadow schema: %v", err)
+	}
+	defer shadowRDbConn.Close()
+
+	projection, err := newRebuildableProjection(shadowRDbConn, projectionId, newProjection)
+	if err != nil {
+		return err
+	}
+	logger.Infof("initializing shadow projection")
+	if err := recoverMigrationPanic(projection.OnInit); err != nil {
+		return fmt.Errorf("error initializing shadow projection: %v", err)
+	}
+
+	manager := projection_entity.NewStoreBasedManager(rebuilder.logger, rebuilder.eventStore)
+	liveProjectionTable := pgx.Identifier{liveSchema, rdbprojectionbase.DEFAULT_TABLE}.Sanitize()
+	for attempt := 1; ; attempt += 1 {
+		// Catch up with the live projection, or the latest event height when the live projection has not handled any
+		// event, so that the swapped tables continue from where the running live projection is
+		maybeTargetHeight, err := rdbprojectionbase.NewStore(liveProjectionTable).GetLastHandledEventHeight(
+			rebuilder.rdbConn.ToHandle(), projectionId,
+		)
+		if err != nil {
+			return fmt.Errorf("error getting live projection last handled event height: %v", err)
+		}
+		if maybeTargetHeight == nil {
+			if maybeTargetHeight, err = rebuilder.eventStore.GetLatestHeight(); err != nil {
+				return fmt.Errorf("error getting latest event height: %v", err)
+			}
+		}
+		if maybeTargetHeight != nil {
+			logger.Infof("replaying events to shadow projection up to height %d", *maybeTargetHeight)
+			if err := manager.ReplayProjection(ctx, projection, *maybeTargetHeight); err != nil {
+				return fmt.Errorf("error replaying events to shadow projection: %v", err)
+			}
+		}
+
+		swapped, err := rebuilder.swapShadowTablesWithLease(logger, projectionId, liveSchema, shadowSchema)
+		if err != nil {
+			return fmt.Errorf("error swapping shadow tables, shadow schema %s is kept: %w", shadowSchema, err)
+		}
+		if swapped {
+			break
+		}
+		if attempt == MAX_SHADOW_SWAP_ATTEMPTS {
+			return fmt.Errorf(
+				"live projection kept advancing after %d attempts to catch up, shadow schema %s is kept",
+				attempt, shadowSchema,
+			)
+		}
+		logger.Infof("live projection has advanced, catching up again")
+	}
+
+	if _, err := rebuilder.rdbConn.Exec(
+		"DROP SCHEMA " + pgx.Identifier{shadowSchema}.Sanitize() + " CASCADE",
+	); err != nil {
+		logger.Errorf("error dropping shadow schema %s: %v", shadowSchema, err)
+	}
+
+	logger.Infof("projection rebuilt and swapped")
+	return nil
+}
+
+// createShadowSchema recreates the shadow schema with an empty projections table
+func (rebuilder *ProjectionRebuilder) createShadowSchema(liveSchema string, shadowSchema string) error {
+	sanitizedShadowSchema := pgx.Identifier{shadowSchema}.Sanitize()
+	for _, sql := range []string{
+		"DROP SCHEMA IF EXISTS " + sanitizedShadowSchema + " CASCADE",
+		"CREATE SCHEMA " + sanitizedShadowSchema,
+		fmt.Sprintf(
+			"CREATE TABLE %s (LIKE %s INCLUDING ALL)",
+			pgx.Identifier{shadowSchema, rdbprojectionbase.DEFAULT_TABLE}.Sanitize(),
+			pgx.Identifier{liveSchema, rdbprojectionbase.DEFAULT_TABLE}.Sanitize(),
+		),
+	} {
+		if _, err := rebuilder.rdbConn.Exec(sql); err != nil {
+			return fmt.Errorf("error creating shadow schema: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// swapShadowTablesWithLease swaps the shadow tables while holding the projection lease
+func (rebuilder *ProjectionRebuilder) swapShadowTablesWithLease(
+	logger applogger.Logger,
+	projectionId string,
+	liveSchema string,
+	shadowSchema string,
+) (bool, error) {
+	lease, err := rebuilder.acquireLease(projectionId)
+	if err != nil {
+		return false, err
+	}
+	defer rebuilder.releaseLease(logger, lease)
+
+	return rebuilder.swapShadowTables(projectionId, liveSchema, shadowSchema, lease.Holder())
+}
+
+// swapShadowTables replaces the live tables by the tables of the shadow schema in a transaction. The live tables are
+// locked before comparing the last handled event heights so that the live projection cannot advance in between, and
+// the lease of the holder is locked so that it cannot be taken over before the swap is committed. Returns false
+// without any change when the live projection is not at the same height as the shadow one.
+func (rebuilder *ProjectionRebuilder) swapShadowTables(
+	projectionId string,
+	liveSchema string,
+	shadowSchema string,
+	leaseHolder string,
+) (bool, error) {
+	tx, err := rebuilder.rdbConn.Begin()
+	if err != nil {
+		return false, fmt.Errorf("error beginning transaction: %v", err)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			_ = tx.Rollback()
+		}
+	}()
+	handle := tx.ToHandle()
+
+	liveProjectionTable := pgx.Identifier{liveSchema, rdbprojectionbase.DEFAULT_TABLE}.Sanitize()
+	liveProjectionStore := rdbprojectionbase.NewStore(liveProjectionTable)
+	if err := liveProjectionStore.LockLease(handle, projectionId, leaseHolder); err != nil {
+		return false, err
+	}
+
+	shadowTables, err := listTables(handle, shadowSchema)
+	if err != nil {
+		return false, err
+	}
+	liveTables, err := listTables(handle, liveSchema)
+	if err != nil {
+		return false, err
+	}
+	tablesToSwap := make([]string, 0, len(shadowTables))
+	liveTablesToDrop := make([]string, 0, len(shadowTables))
+	for _, table := range shadowTables {
+		if table == rdbprojectionbase.DEFAULT_TABLE {
+			continue
+		}
+		tablesToSwap = append(tablesToSwap, table)
+		for _, liveTable := range liveTables {
+			if liveTable == table {
+				liveTablesToDrop = append(liveTablesToDrop, pgx.Identifier{liveSchema, table}.Sanitize())
+				break
+			}
+		}
+	}
+
+	if len(liveTablesToDrop) > 0 {
+		if _, err := handle.Exec(
+			"LOCK TABLE " + strings.Join(liveTablesToDrop, ", ") + " IN ACCESS EXCLUSIVE MODE",
+		); err != nil {
+			return false, fmt.Errorf("error locking live tables: %v", err)
+		}
+	}
+
+	maybeLiveHeight, err := lockLastHandledEventHeight(handle, liveProjectionTable, projectionId)
+	if err != nil {
+		return false, err
+	}
+	maybeShadowHeight, err := rdbprojectionbase.NewStore(
+		pgx.Identifier{shadowSchema, rdbprojectionbase.DEFAULT_TABLE}.Sanitize(),
+	).GetLastHandledEventHeight(handle, projectionId)
+	if err != nil {
+		return false, fmt.Errorf("error getting shadow projection last handled event height: %v", err)
+	}
+	if maybeLiveHeight != nil && (maybeShadowHeight == nil || *maybeLiveHeight != *maybeShadowHeight) {
+		return false, nil
+	}
+
+	if len(liveTablesToDrop) > 0 {
+		if _, err := handle.Exec("DROP TABLE " + strings.Join(liveTablesToDrop, ", ")); err != nil {
+			return false, fmt.Errorf("error dropping live tables: %v", err)
+		}
+	}
+	for _, table := range tablesToSwap {
+		if _, err := handle.Exec(fmt.Sprintf(
+			"ALTER TABLE %s SET SCHEMA %s",
+			pgx.Identifier{shadowSchema, table}.Sanitize(), pgx.Identifier{liveSchema}.Sanitize(),
+		)); err != nil {
+			return false, fmt.Errorf("error moving shadow table %s: %v", table, err)
+		}
+	}
+
+	if maybeShadowHeight == nil {
+		err = liveProjectionStore.ResetLastHandledEventHeight(handle, projectionId)
+	} else {
+		err = liveProjectionStore.UpdateLastHandledEventHeight(handle, projectionId, *maybeShadowHeight)
+	}
+	if err != nil {
+		return false, fmt.Errorf("error carrying over last handled event height: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("error committing swap: %v", err)
+	}
+	committed = true
+
+	return true, nil
+}
+
+// acquireLease acquires the lease of the projection, or returns ErrProjectionLeaseHeld when it is held by another
+// process
+func (rebuilder *ProjectionRebuilder) acquireLease(projectionId string) (projection_entity.Lease, error) {
+	lease, err := rebuilder.leaser.TryAcquire(projectionId)
+	if err != nil {
+		return nil, fmt.Errorf("error acquiring projection lease: %v", err)
+	}
+	if lease == nil {
+		return nil, fmt.Errorf("error acquiring lease of projection `%s`: %w", projectionId, ErrProjectionLeaseHeld)
+	}
+
+	return lease, nil
+}
+
+func (rebuilder *ProjectionRebuilder) releaseLease(logger applogger.Logger, lease projection_entity.Lease) {
+	if err := lease.Release(); err != nil {
+		logger.Errorf("error releasing projection lease: %v", err)
+	}
+}
+
+// contextUntilLeaseLost returns the context cancelled once the lease is lost
+func contextUntilLeaseLost(
+	ctx context.Context,
+	lease projection_entity.Lease,
+) (context.Context, context.CancelFunc) {
+	leaseCtx, cancel := context.WithCancel(ctx)
+	go func() {
+		select {
+		case <-lease.Lost():
+			cancel()
+		case <-leaseCtx.Done():
+		}
+	}()
+
+	return leaseCtx, cancel
+}
+
+func isLeaseLost(lease projection_entity.Lease) bool {
+	select {
+	case <-lease.Lost():
+		return true
+	default:
+		return false
+	}
+}
+
+func listTables(handle *rdb.Handle, schema string) ([]string, error) {
+	sql, args, err := handle.StmtBuilder.Select(
+		"table_name",
+	).From(
+		"information_schema.tables",
+	).Where(
+		"table_schema = ? AND table_type = 'BASE TABLE'", schema,
+	).OrderBy("table_name").ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building tables selection SQL: %v", err)
+	}
+
+	rowsResult, err := handle.Query(sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error executing tables selection SQL: %v", err)
+	}
+	defer rowsResult.Close()
+
+	tables := make([]string, 0)
+	for rowsResult.Next() {
+		var table string
+		if err := rowsResult.Scan(&table); err != nil {
+			return nil, fmt.Errorf("error scanning table row: %v", err)
+		}
+		tables = append(tables, table)
+	}
+	if err := rowsResult.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tables: %v", err)
+	}
+
+	return tables, nil
+}
+
+func lockLastHandledEventHeight(handle *rdb.Handle, table string, projectionId string) (*int64, error) {
+	sql, args, err := handle.StmtBuilder.Select(
+		"last_handled_event_height",
+	).From(
+		table,
+	).Where(
+		"id = ?", projectionId,
+	).Suffix("FOR UPDATE").ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building last handled event height locking SQL: %v", err)
+	}
+
+	var lastHandledEventHeight int64
+	if err := handle.QueryRow(sql, args...).Scan(&lastHandledEventHeight); err != nil {
+		if errors.Is(err, rdb.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error executing last handled event height locking SQL: %v", err)
+	}
+
+	return &lastHandledEventHeight, nil
+}
+
+func newRebuildableProjection(
+	rdbConn rdb.Conn,
+	projectionId string,
+	newProjection ProjectionFactory,
+) (RebuildableProjection, error) {
+	projection, err := newProjection(rdbConn)
+	if err != nil {
+		return nil, fmt.Errorf("error creating projection `%s`: %v", projectionId, err)
+	}
+	if projection.Id() != projectionId {
+		return nil, fmt.Errorf("created projection `%s` is not `%s`", projection.Id(), projectionId)
+	}
+	rebuildableProjection, ok := projection.(RebuildableProjection)
+	if !ok || rebuildableProjection.MigrationHelper() == nil {
+		return nil, fmt.Errorf("projection `%s` does not have migration helper to rebuild with", projectionId)
+	}
+
+	return rebuildableProjection, nil
+}
+
+// recoverMigrationPanic runs the function and returns the panic of migration helpers as error
+func recoverMigrationPanic(fn func() error) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("%v", recovered)
+		}
+	}()
+
+	return fn()
+}
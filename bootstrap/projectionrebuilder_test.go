@@ -0,0 +1,66 @@
+package bootstrap_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/crypto-com/chain-indexing/appinterface/rdb"
+	"github.com/crypto-com/chain-indexing/bootstrap"
+	. "github.com/crypto-com/chain-indexing/entity/event/test"
+	projection_entity "github.com/crypto-com/chain-indexing/entity/projection"
+	. "github.com/crypto-com/chain-indexing/entity/projection/test"
+	. "github.com/crypto-com/chain-indexing/external/logger/test"
+	"github.com/crypto-com/chain-indexing/infrastructure/pg/migrationhelper"
+)
+
+type mockDownMigrationHelper struct {
+	mock.Mock
+}
+
+func (helper *mockDownMigrationHelper) Migrate() {
+	helper.Called()
+}
+
+func (helper *mockDownMigrationHelper) MigrateDown() {
+	helper.Called()
+}
+
+type mockRebuildableProjection struct {
+	*MockProjection
+
+	migrationHelper migrationhelper.MigrationHelper
+}
+
+func (projection *mockRebuildableProjection) MigrationHelper() migrationhelper.MigrationHelper {
+	return projection.migrationHelper
+}
+
+var _ = Describe("ProjectionRebuilder", func() {
+	It("should not revert the migrations when the projection lease is held by another process", func() {
+		mockMigrationHelper := &mockDownMigrationHelper{}
+		mockProjection := NewMockProjection()
+		mockProjection.On("Id").Return("Account")
+		projection := &mockRebuildableProjection{mockProjection, mockMigrationHelper}
+
+		mockLeaser := NewMockLeaser()
+		mockLeaser.On("TryAcquire", "Account").Return(nil, nil)
+
+		rebuilder := bootstrap.NewProjectionRebuilder(
+			NewFakeLogger(), nil, NewMockEventStore(),
+		).WithLeaser(mockLeaser)
+
+		err := rebuilder.Rebuild(
+			context.Background(), "Account", func(_ rdb.Conn) (projection_entity.Projection, error) {
+				return projection, nil
+			},
+		)
+
+		Expect(errors.Is(err, bootstrap.ErrProjectionLeaseHeld)).To(BeTrue())
+		mockLeaser.AssertCalled(GinkgoT(), "TryAcquire", "Account")
+		mockMigrationHelper.AssertNotCalled(GinkgoT(), "MigrateDown")
+	})
+})
@@ -1,21 +1,23 @@
 package bootstrap
 
 import (
+	"context"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
 	cosmosapp_interface "github.com/crypto-com/chain-indexing/appinterface/cosmosapp"
 	eventhandler_interface "github.com/crypto-com/chain-indexing/appinterface/eventhandler"
-	cosmosapp_infrastructure "github.com/crypto-com/chain-indexing/infrastructure/cosmosapp"
+	tendermint_interface "github.com/crypto-com/chain-indexing/appinterface/tendermint"
 
 	"github.com/crypto-com/chain-indexing/appinterface/rdb"
+	"github.com/crypto-com/chain-indexing/bootstrap/config"
 	command_entity "github.com/crypto-com/chain-indexing/entity/command"
 	"github.com/crypto-com/chain-indexing/entity/event"
 	applogger "github.com/crypto-com/chain-indexing/external/logger"
 	chainfeed "github.com/crypto-com/chain-indexing/infrastructure/feed/chain"
 	"github.com/crypto-com/chain-indexing/infrastructure/metric/prometheus"
-	"github.com/crypto-com/chain-indexing/infrastructure/tendermint"
 	"github.com/crypto-com/chain-indexing/usecase/parser"
 	"github.com/crypto-com/chain-indexing/usecase/parser/utils"
 	"github.com/crypto-com/chain-indexing/usecase/syncstrategy"
@@ -27,20 +29,23 @@ const DEFAULT_MAX_RETRY_INTERVAL = 15 * time.Minute
 const DEFAULT_MAX_RETRY_TIME = MAX_RETRY_TIME_ALWAYS_RETRY
 
 type SyncManager struct {
-	rdbConn              rdb.Conn
-	tendermintClient     *tendermint.HTTPClient
-	cosmosClient         cosmosapp_interface.Client
-	logger               applogger.Logger
-	pollingInterval      time.Duration
-	maxRetryInterval     time.Duration
-	maxRetryTime         time.Duration
-	strictGenesisParsing bool
+	rdbConn                rdb.Conn
+	tendermintClient       tendermint_interface.Client
+	tendermintWebSocketURL string
+	cosmosClient           cosmosapp_interface.Client
+	eras                   []syncManagerEra
+	logger                 applogger.Logger
+	pollingInterval        time.Duration
+	maxRetryInterval       time.Duration
+	maxRetryTime           time.Duration
+	strictGenesisParsing   bool
+	startHeight            int64
 
 	accountAddressPrefix string
 	stakingDenom         string
 
-	txDecoder          *utils.TxDecoder
-	windowSyncStrategy *syncstrategy.Window
+	txDecoder    *utils.TxDecoder
+	syncStrategy syncstrategy.Strategy
 
 	eventHandler eventhandler_interface.Handler
 
@@ -61,14 +66,41 @@ type SyncManagerParams struct {
 
 type SyncManagerConfig struct {
 	WindowSize               int
+	StartHeight              int64
+	SyncStrategy             string
+	AdaptiveMinConcurrency   int
+	AdaptiveMaxConcurrency   int
 	TendermintRPCUrl         string
+	TendermintRPCUrls        []string
+	TendermintWebSocketURL   string
 	CosmosAppHTTPRPCURL      string
+	CosmosAppHTTPRPCURLs     []string
 	InsecureTendermintClient bool
 	InsecureCosmosAppClient  bool
 	StrictGenesisParsing     bool
+	GenesisFilePath          string
+	ArchiveMode              string
+	ArchiveDirectory         string
+	BatchSize                int
 
 	AccountAddressPrefix string
 	StakingDenom         string
+
+	Eras []SyncManagerEra
+}
+
+// SyncManagerEra is a block height range starting at StartHeight whose blocks are requested from its own endpoints.
+// Empty URLs fall back to the default endpoints.
+type SyncManagerEra struct {
+	StartHeight          int64
+	TendermintRPCUrls    []string
+	CosmosAppHTTPRPCURLs []string
+}
+
+type syncManagerEra struct {
+	startHeight      int64
+	tendermintClient tendermint_interface.Client
+	cosmosClient     cosmosapp_interface.Client
 }
 
 // NewSyncManager creates a new feed with polling for latest block starts at a specific height
@@ -77,34 +109,94 @@ func NewSyncManager(
 	pm *utils.CosmosParserManager,
 	eventHandler eventhandler_interface.Handler,
 ) *SyncManager {
-	var tendermintClient *tendermint.HTTPClient
-	if params.Config.InsecureTendermintClient {
-		tendermintClient = tendermint.NewInsecureHTTPClient(
-			params.Config.TendermintRPCUrl,
-			params.Config.StrictGenesisParsing,
-		)
-	} else {
-		tendermintClient = tendermint.NewHTTPClient(
-			params.Config.TendermintRPCUrl,
-			params.Config.StrictGenesisParsing,
-		)
+	tendermintRPCUrls := params.Config.TendermintRPCUrls
+	if len(tendermintRPCUrls) == 0 {
+		tendermintRPCUrls = []string{params.Config.TendermintRPCUrl}
+	}
+	tendermintClient, err := NewBlockSyncTendermintClient(
+		tendermintRPCUrls,
+		params.Config.InsecureTendermintClient,
+		params.Config.StrictGenesisParsing,
+		params.Config.GenesisFilePath,
+		params.Config.ArchiveMode,
+		params.Config.ArchiveDirectory,
+		params.Config.BatchSize,
+	)
+	if err != nil {
+		params.Logger.Panicf("error creating Tendermint client: %v", err)
+	}
+	tendermintWebSocketURL := params.Config.TendermintWebSocketURL
+	if params.Config.ArchiveMode == config.ARCHIVE_MODE_REPLAY {
+		// Blocks are replayed from archive, there is no new block to subscribe to
+		tendermintWebSocketURL = ""
 	}
 
-	var cosmosClient cosmosapp_interface.Client
-	if params.Config.InsecureCosmosAppClient {
-		cosmosClient = cosmosapp_infrastructure.NewInsecureHTTPClient(
-			params.Config.CosmosAppHTTPRPCURL, params.Config.StakingDenom,
-		)
-	} else {
-		cosmosClient = cosmosapp_infrastructure.NewHTTPClient(
-			params.Config.CosmosAppHTTPRPCURL, params.Config.StakingDenom,
+	cosmosAppHTTPRPCURLs := params.Config.CosmosAppHTTPRPCURLs
+	if len(cosmosAppHTTPRPCURLs) == 0 {
+		cosmosAppHTTPRPCURLs = []string{params.Config.CosmosAppHTTPRPCURL}
+	}
+	cosmosClient := NewBlockSyncCosmosAppClient(
+		cosmosAppHTTPRPCURLs,
+		params.Config.InsecureCosmosAppClient,
+		params.Config.StakingDenom,
+		params.Config.ArchiveMode,
+	)
+
+	eras := make([]syncManagerEra, 0, len(params.Config.Eras))
+	for _, eraConfig := range params.Config.Eras {
+		era := syncManagerEra{
+			startHeight:      eraConfig.StartHeight,
+			tendermintClient: tendermintClient,
+			cosmosClient:     cosmosClient,
+		}
+		if len(eraConfig.TendermintRPCUrls) > 0 {
+			era.tendermintClient, err = NewBlockSyncTendermintClient(
+				eraConfig.TendermintRPCUrls,
+				params.Config.InsecureTendermintClient,
+				params.Config.StrictGenesisParsing,
+				params.Config.GenesisFilePath,
+				params.Config.ArchiveMode,
+				params.Config.ArchiveDirectory,
+				params.Config.BatchSize,
+			)
+			if err != nil {
+				params.Logger.Panicf(
+					"error creating Tendermint client of era starting at height %d: %v", eraConfig.StartHeight, err,
+				)
+			}
+		}
+		if len(eraConfig.CosmosAppHTTPRPCURLs) > 0 {
+			era.cosmosClient = NewBlockSyncCosmosAppClient(
+				eraConfig.CosmosAppHTTPRPCURLs,
+				params.Config.InsecureCosmosAppClient,
+				params.Config.StakingDenom,
+				params.Config.ArchiveMode,
+			)
+		}
+		eras = append(eras, era)
+	}
+	sort.SliceStable(eras, func(i, j int) bool {
+		return eras[i].startHeight < eras[j].startHeight
+	})
+
+	var syncStrategy syncstrategy.Strategy
+	switch params.Config.SyncStrategy {
+	case "", config.SYNC_STRATEGY_WINDOW:
+		syncStrategy = syncstrategy.NewWindow(params.Logger, params.Config.WindowSize)
+	case config.SYNC_STRATEGY_ADAPTIVE:
+		syncStrategy = syncstrategy.NewAdaptive(
+			params.Logger, params.Config.AdaptiveMinConcurrency, params.Config.AdaptiveMaxConcurrency,
 		)
+	default:
+		params.Logger.Panicf("unsupported sync strategy: %s", params.Config.SyncStrategy)
 	}
 
 	return &SyncManager{
-		rdbConn:          params.RDbConn,
-		tendermintClient: tendermintClient,
-		cosmosClient:     cosmosClient,
+		rdbConn:                params.RDbConn,
+		tendermintClient:       tendermintClient,
+		tendermintWebSocketURL: tendermintWebSocketURL,
+		cosmosClient:           cosmosClient,
+		eras:                   eras,
 		logger: params.Logger.WithFields(applogger.LogFields{
 			"module": "SyncManager",
 		}),
@@ -112,14 +204,15 @@ func NewSyncManager(
 		maxRetryInterval:     DEFAULT_MAX_RETRY_INTERVAL,
 		maxRetryTime:         DEFAULT_MAX_RETRY_TIME,
 		strictGenesisParsing: params.Config.StrictGenesisParsing,
+		startHeight:          params.Config.StartHeight,
 
 		accountAddressPrefix: params.Config.AccountAddressPrefix,
 		stakingDenom:         params.Config.StakingDenom,
 
 		shouldSyncCh: make(chan bool, 1),
 
-		txDecoder:          params.TxDecoder,
-		windowSyncStrategy: syncstrategy.NewWindow(params.Logger, params.Config.WindowSize),
+		txDecoder:    params.TxDecoder,
+		syncStrategy: syncStrategy,
 
 		eventHandler: eventHandler,
 
@@ -127,15 +220,16 @@ func NewSyncManager(
 	}
 }
 
-// SyncBlocks makes request to tendermint, create and dispatch notifications
-func (manager *SyncManager) SyncBlocks(latestHeight int64, isRetry bool) error {
+// SyncBlocks makes request to tendermint, create and dispatch notifications. When the context is cancelled, it stops
+// after handling the events of the current block.
+func (manager *SyncManager) SyncBlocks(ctx context.Context, latestHeight int64, isRetry bool) error {
 	maybeLastIndexedHeight, err := manager.eventHandler.GetLastHandledEventHeight()
 	if err != nil {
 		return fmt.Errorf("error running GetLastIndexedBlockHeight %v", err)
 	}
 
-	// if none of the block has been indexed before, start with 0
-	currentIndexingHeight := int64(0)
+	// if none of the block has been indexed before, start with 0 or the configured start height
+	currentIndexingHeight := manager.startHeight
 	if maybeLastIndexedHeight != nil {
 		currentIndexingHeight = *maybeLastIndexedHeight + 1
 	}
@@ -150,11 +244,11 @@ func (manager *SyncManager) SyncBlocks(latestHeight int64, isRetry bool) error {
 	manager.logger.Infof("going to synchronized blocks from %d to %d", currentIndexingHeight, targetHeight)
 	for currentIndexingHeight <= targetHeight {
 		startTime := time.Now()
-		blocksCommands, syncedHeight, err := manager.windowSyncStrategy.Sync(
-			currentIndexingHeight, targetHeight, manager.syncBlockWorker,
+		blocksCommands, syncedHeight, err := manager.syncStrategy.Sync(
+			ctx, currentIndexingHeight, targetHeight, manager.syncBlockWorker,
 		)
 		if err != nil {
-			return fmt.Errorf("error when synchronizing block with window strategy: %v", err)
+			return fmt.Errorf("error when synchronizing block with sync strategy: %v", err)
 		}
 
 		if err != nil {
@@ -162,21 +256,16 @@ func (manager *SyncManager) SyncBlocks(latestHeight int64, isRetry bool) error {
 		}
 		for i, commands := range blocksCommands {
 			blockHeight := currentIndexingHeight + int64(i)
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
 
-			events := make([]event.Event, 0, len(commands))
-			for _, command := range commands {
-				event, err := command.Exec()
-				if err != nil {
-
-					return fmt.Errorf(
-						"error executing command %sV%d to produce events: %v",
-						command.Name(), command.Version(), err,
-					)
-				}
-				events = append(events, event)
+			events, err := execCommands(commands)
+			if err != nil {
+				return err
 			}
 
-			err := manager.eventHandler.HandleEvents(blockHeight, events)
+			err = manager.eventHandler.HandleEvents(blockHeight, events)
 			if err != nil {
 				return fmt.Errorf("error handling events: %v", err)
 			}
@@ -192,7 +281,38 @@ func (manager *SyncManager) SyncBlocks(latestHeight int64, isRetry bool) error {
 	return nil
 }
 
-func (manager *SyncManager) syncBlockWorker(blockHeight int64) ([]command_entity.Command, error) {
+// GetAllByHeight fetches and parses the block at the height again and returns its events. It feeds the handlers behind
+// the heights cached by the FanoutHandler.
+func (manager *SyncManager) GetAllByHeight(height int64) ([]event.Event, error) {
+	commands, err := manager.syncBlockWorker(context.Background(), height)
+	if err != nil {
+		return nil, fmt.Errorf("error synchronizing block at height %d: %v", height, err)
+	}
+
+	return execCommands(commands)
+}
+
+func execCommands(commands []command_entity.Command) ([]event.Event, error) {
+	events := make([]event.Event, 0, len(commands))
+	for _, command := range commands {
+		event, err := command.Exec()
+		if err != nil {
+			return nil, fmt.Errorf(
+				"error executing command %sV%d to produce events: %v",
+				command.Name(), command.Version(), err,
+			)
+		}
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+func (manager *SyncManager) syncBlockWorker(ctx context.Context, blockHeight int64) ([]command_entity.Command, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
 	logger := manager.logger.WithFields(applogger.LogFields{
 		"submodule":   "SyncBlockWorker",
 		"blockHeight": blockHeight,
@@ -200,8 +320,10 @@ func (manager *SyncManager) syncBlockWorker(blockHeight int64) ([]command_entity
 
 	logger.Info("synchronizing block")
 
+	tendermintClient, cosmosClient := manager.clientsAt(blockHeight)
+
 	if blockHeight == int64(0) {
-		genesis, err := manager.tendermintClient.Genesis()
+		genesis, err := tendermintClient.Genesis()
 		if err != nil {
 			return nil, fmt.Errorf("error requesting chain genesis: %v", err)
 		}
@@ -210,19 +332,19 @@ func (manager *SyncManager) syncBlockWorker(blockHeight int64) ([]command_entity
 	}
 
 	// Request tendermint RPC
-	block, rawBlock, err := manager.tendermintClient.Block(blockHeight)
+	block, rawBlock, err := tendermintClient.Block(blockHeight)
 	if err != nil {
 		return nil, fmt.Errorf("error requesting chain block at height %d: %v", blockHeight, err)
 	}
 
-	blockResults, err := manager.tendermintClient.BlockResults(blockHeight)
+	blockResults, err := tendermintClient.BlockResults(blockHeight)
 	if err != nil {
 		return nil, fmt.Errorf("error requesting chain block_results at height %d: %v", blockHeight, err)
 	}
 
 	commands, err := parser.ParseBlockToCommands(
 		manager.parserManager,
-		manager.cosmosClient,
+		cosmosClient,
 		manager.txDecoder,
 		block,
 		rawBlock,
@@ -234,34 +356,87 @@ func (manager *SyncManager) syncBlockWorker(blockHeight int64) ([]command_entity
 		return nil, fmt.Errorf("error parsing block data to commands %v", err)
 	}
 
+	if manager.startHeight > 0 && blockHeight == manager.startHeight {
+		// Bootstrap state in place of genesis when indexing starts from the start height
+		bootstrapCommands, bootstrapErr := parser.ParseBootstrapCommands(
+			cosmosClient, block, manager.accountAddressPrefix, manager.stakingDenom,
+		)
+		if bootstrapErr != nil {
+			return nil, fmt.Errorf("error bootstrapping state at height %d: %v", blockHeight, bootstrapErr)
+		}
+		commands = append(bootstrapCommands, commands...)
+	}
+
 	return commands, nil
 }
 
-// Run starts the polling service for blocks
-func (manager *SyncManager) Run() error {
-	tracker := chainfeed.NewBlockHeightTracker(manager.logger, manager.tendermintClient)
+// clientsAt returns the Tendermint and Cosmos app clients of the era the block height belongs to. Falls back to the
+// default clients when the height is before all the eras.
+func (manager *SyncManager) clientsAt(blockHeight int64) (tendermint_interface.Client, cosmosapp_interface.Client) {
+	tendermintClient, cosmosClient := manager.tendermintClient, manager.cosmosClient
+	for _, era := range manager.eras {
+		if era.startHeight > blockHeight {
+			break
+		}
+		tendermintClient, cosmosClient = era.tendermintClient, era.cosmosClient
+	}
+
+	return tendermintClient, cosmosClient
+}
+
+// latestTendermintClient returns the Tendermint client of the latest era, which is used to track the latest height
+func (manager *SyncManager) latestTendermintClient() tendermint_interface.Client {
+	if len(manager.eras) == 0 {
+		return manager.tendermintClient
+	}
+
+	return manager.eras[len(manager.eras)-1].tendermintClient
+}
+
+// Run starts the polling service for blocks. It returns when the context is cancelled and the events of the block
+// being handled are committed.
+func (manager *SyncManager) Run(ctx context.Context) error {
+	parser.InitParsers(manager.parserManager)
+	parser.RegisterBreakingVersionParsers(manager.parserManager)
+	parser.RegisterParserSets(manager.parserManager)
+	if err := manager.parserManager.EnableEraParserSets(); err != nil {
+		return fmt.Errorf("error enabling parser sets of eras: %v", err)
+	}
+
+	var tracker chainfeed.BlockHeightFeed
+	if manager.tendermintWebSocketURL != "" {
+		tracker = chainfeed.NewWebSocketBlockHeightTracker(
+			ctx, manager.logger, manager.latestTendermintClient(), manager.tendermintWebSocketURL,
+		)
+	} else {
+		tracker = chainfeed.NewBlockHeightTracker(manager.logger, manager.latestTendermintClient())
+	}
 	manager.latestBlockHeight = tracker.GetLatestBlockHeight()
 	blockHeightCh := make(chan int64, 1)
 	go func() {
 		for {
-			latestBlockHeight := <-blockHeightCh
-			manager.latestBlockHeight = &latestBlockHeight
-			manager.drainShouldSyncCh()
-			manager.shouldSyncCh <- true
+			select {
+			case <-ctx.Done():
+				return
+			case latestBlockHeight := <-blockHeightCh:
+				manager.latestBlockHeight = &latestBlockHeight
+				manager.drainShouldSyncCh()
+				manager.shouldSyncCh <- true
+			}
 		}
 	}()
 	tracker.Subscribe(blockHeightCh)
 
-	parser.InitParsers(manager.parserManager)
-	parser.RegisterBreakingVersionParsers(manager.parserManager)
-
-	for {
+	for ctx.Err() == nil {
 		isRetry := false
 		operation := func() error {
 			if manager.latestBlockHeight == nil {
 				manager.logger.Info("the chain has no block yet")
 			} else {
-				if syncErr := manager.SyncBlocks(*manager.latestBlockHeight, isRetry); syncErr != nil {
+				if syncErr := manager.SyncBlocks(ctx, *manager.latestBlockHeight, isRetry); syncErr != nil {
+					if ctx.Err() != nil {
+						return backoff.Permanent(ctx.Err())
+					}
 					return fmt.Errorf(
 						"error synchronizing blocks to latest height %d: %v", *manager.latestBlockHeight, syncErr,
 					)
@@ -269,6 +444,7 @@ func (manager *SyncManager) Run() error {
 			}
 
 			select {
+			case <-ctx.Done():
 			case <-manager.shouldSyncCh:
 			case <-time.After(manager.pollingInterval):
 			}
@@ -286,12 +462,15 @@ func (manager *SyncManager) Run() error {
 		neverStopExponentialBackoff.MaxInterval = manager.maxRetryInterval
 		if err := backoff.RetryNotify(
 			operation,
-			backoff.NewExponentialBackOff(),
+			backoff.WithContext(backoff.NewExponentialBackOff(), ctx),
 			notifyFn,
-		); err != nil {
-			manager.logger.Errorf("stopping retry after too many errors: %s: %v", err)
+		); err != nil && ctx.Err() == nil {
+			manager.logger.Errorf("stopping retry after too many errors: %v", err)
 		}
 	}
+
+	manager.logger.Info("stopped synchronizing blocks")
+	return nil
 }
 
 func (manager *SyncManager) drainShouldSyncCh() {
@@ -1,34 +1,69 @@
 package event
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
 )
 
 var ErrMismatchEvent = errors.New("mismatched event")
 
 type Registry struct {
-	decoders map[string]Decoder
+	decoders  map[string]registeredDecoder
+	upcasters map[string]Upcaster
+}
+
+type registeredDecoder struct {
+	name    string
+	version int
+	decoder Decoder
 }
 
 func NewRegistry() *Registry {
 	return &Registry{
-		decoders: make(map[string]Decoder),
+		decoders:  make(map[string]registeredDecoder),
+		upcasters: make(map[string]Upcaster),
 	}
 }
 
 // Register add a mapping of "event name ane version" to Decoder to the registry. It will overwrite
 // existing registration if any.
 func (registry *Registry) Register(eventName string, eventVersion int, decoder Decoder) {
-	registry.decoders[eventType(eventName, eventVersion)] = decoder
+	registry.decoders[eventType(eventName, eventVersion)] = registeredDecoder{
+		name:    eventName,
+		version: eventVersion,
+		decoder: decoder,
+	}
+}
+
+// RegisterUpcaster add an Upcaster converting the encoded event of the name from the version to the next version. It
+// will overwrite existing registration if any. Upcasters of consecutive versions are chained, so that an event stored
+// in any historical version is decoded as the latest version.
+func (registry *Registry) RegisterUpcaster(eventName string, fromVersion int, upcaster Upcaster) {
+	registry.upcasters[eventType(eventName, fromVersion)] = upcaster
 }
 
-// IsRegister returns true when the event to decoder mapping is already registered
+// IsRegister returns true when the event to decoder mapping is already registered, directly or through the upcasters
+// of the version
 func (registry *Registry) IsRegistered(eventName string, eventVersion int) bool {
+	for {
+		if _, exist := registry.upcasters[eventType(eventName, eventVersion)]; !exist {
+			break
+		}
+		eventVersion += 1
+	}
+
 	_, exist := registry.decoders[eventType(eventName, eventVersion)]
 	return exist
 }
 
+// DecodeByType decodes the encoded event of the name and version. The encoded event is upcasted through the chain of
+// upcasters registered from the version before being decoded. The `version` field of the encoded event is set to the
+// version it is upcasted to after each upcaster, such that the decoded event reports the latest version.
 func (registry *Registry) DecodeByType(eventName string, eventVersion int, encoded []byte) (Event, error) {
 	var err error
 
@@ -36,7 +71,21 @@ func (registry *Registry) DecodeByType(eventName string, eventVersion int, encod
 		return nil, fmt.Errorf("unrecognized event type `%s`", eventType(eventName, eventVersion))
 	}
 
-	decoder := registry.decoders[eventType(eventName, eventVersion)]
+	for {
+		upcaster, exist := registry.upcasters[eventType(eventName, eventVersion)]
+		if !exist {
+			break
+		}
+		if encoded, err = upcaster(encoded); err != nil {
+			return nil, fmt.Errorf("error upcasting event `%s`: %v", eventType(eventName, eventVersion), err)
+		}
+		if encoded, err = withEncodedVersion(encoded, eventVersion+1); err != nil {
+			return nil, fmt.Errorf("error upcasting event `%s`: %v", eventType(eventName, eventVersion), err)
+		}
+		eventVersion += 1
+	}
+
+	decoder := registry.decoders[eventType(eventName, eventVersion)].decoder
 	var event Event
 	if event, err = decoder(encoded); err != nil {
 		return nil, fmt.Errorf("error decoding event: %v", err)
@@ -45,8 +94,91 @@ func (registry *Registry) DecodeByType(eventName string, eventVersion int, encod
 	return event, nil
 }
 
+// withEncodedVersion sets the `version` field of the JSON encoded event to the version. Encoded events without the field
+// are returned as is.
+func withEncodedVersion(encoded []byte, version int) ([]byte, error) {
+	var payload map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &payload); err != nil {
+		return nil, fmt.Errorf("error decoding upcasted event payload: %v", err)
+	}
+	if _, ok := payload["version"]; !ok {
+		return encoded, nil
+	}
+
+	payload["version"] = json.RawMessage(strconv.Itoa(version))
+	return json.Marshal(payload)
+}
+
+// RegisteredType is the Go type decoded by the decoder registered for the event name and version
+type RegisteredType struct {
+	Name    string
+	Version int
+	Type    reflect.Type
+}
+
+// RegisteredTypes returns the Go type of each registered decoder ordered by the event name and version. The type is
+// found by decoding a JSON null, which decoders of pointer to struct handle without touching any field.
+func (registry *Registry) RegisteredTypes() ([]RegisteredType, error) {
+	registeredTypes := make([]RegisteredType, 0, len(registry.decoders))
+	for key, registered := range registry.decoders {
+		event, err := decodeNull(registered.decoder)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding null of event `%s`: %v", key, err)
+		}
+		if event == nil {
+			return nil, fmt.Errorf("error decoding null of event `%s`: untyped event returned", key)
+		}
+
+		registeredTypes = append(registeredTypes, RegisteredType{
+			Name:    registered.name,
+			Version: registered.version,
+			Type:    reflect.TypeOf(event),
+		})
+	}
+	sort.Slice(registeredTypes, func(i, j int) bool {
+		if registeredTypes[i].Name != registeredTypes[j].Name {
+			return registeredTypes[i].Name < registeredTypes[j].Name
+		}
+		return registeredTypes[i].Version < registeredTypes[j].Version
+	})
+
+	return registeredTypes, nil
+}
+
+func decodeNull(decoder Decoder) (event Event, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("decoder panicked: %v", recovered)
+		}
+	}()
+
+	return decoder([]byte("null"))
+}
+
 func eventType(name string, version int) string {
 	return fmt.Sprintf("%sV%d", name, version)
 }
 
 type Decoder = func([]byte) (Event, error)
+
+// Upcaster converts an encoded event of a version to the encoded event of the next version
+type Upcaster = func([]byte) ([]byte, error)
+
+// NewJSONUpcaster creates an Upcaster converting the JSON object of the encoded event in place, e.g. to add a new
+// field with its default value
+func NewJSONUpcaster(upcast func(payload map[string]interface{}) error) Upcaster {
+	return func(encoded []byte) ([]byte, error) {
+		var payload map[string]interface{}
+		jsonDecoder := json.NewDecoder(bytes.NewReader(encoded))
+		// Keep numbers as is, large integers would lose precision as float64
+		jsonDecoder.UseNumber()
+		if err := jsonDecoder.Decode(&payload); err != nil {
+			return nil, fmt.Errorf("error decoding event payload: %v", err)
+		}
+		if err := upcast(payload); err != nil {
+			return nil, err
+		}
+
+		return json.Marshal(payload)
+	}
+}
@@ -3,6 +3,9 @@ package event_test
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -62,6 +65,80 @@ var _ = Describe("eventRegistry", func() {
 			Expect(typedEvent).To(Equal(newSimpleJSONEvent()))
 		})
 	})
+
+	Describe("RegisterUpcaster", func() {
+		It("should register the event type of the version through the upcasters", func() {
+			registry := event.NewRegistry()
+			registry.Register(versionedJSONEventName, 2, decodeVersionedJSONEvent)
+
+			Expect(registry.IsRegistered(versionedJSONEventName, 0)).To(Equal(false))
+
+			registerVersionedJSONEventUpcasters(registry)
+
+			Expect(registry.IsRegistered(versionedJSONEventName, 0)).To(Equal(true))
+			Expect(registry.IsRegistered(versionedJSONEventName, 1)).To(Equal(true))
+			Expect(registry.IsRegistered(versionedJSONEventName, 2)).To(Equal(true))
+			Expect(registry.IsRegistered(versionedJSONEventName, 3)).To(Equal(false))
+		})
+
+		It("should decode all the historical versions as the latest version", func() {
+			registry := event.NewRegistry()
+			registry.Register(versionedJSONEventName, 2, decodeVersionedJSONEvent)
+			registerVersionedJSONEventUpcasters(registry)
+
+			historicalVersions := map[int]string{
+				0: `{"key":"value"}`,
+				1: `{"key":"value","count":0}`,
+				2: `{"name":"value","count":0}`,
+			}
+			for version, encoded := range historicalVersions {
+				actual, err := registry.DecodeByType(versionedJSONEventName, version, []byte(encoded))
+				Expect(err).To(BeNil())
+				Expect(actual).To(Equal(&versionedJSONEvent{Value: "value", Count: 0}))
+				Expect(actual.Version()).To(Equal(2))
+			}
+		})
+
+		It("should set the version of the upcasted event embedding event.Base to the latest version", func() {
+			registry := event.NewRegistry()
+			registry.Register(baseVersionedEventName, 2, decodeBaseVersionedEvent)
+			registerBaseVersionedEventUpcasters(registry)
+
+			for version := 0; version <= 1; version++ {
+				actual, err := registry.DecodeByType(baseVersionedEventName, version, []byte(fmt.Sprintf(
+					`{"name":"%s","version":%d,"height":1,"uuid":"id","key":"value"}`, baseVersionedEventName, version,
+				)))
+				Expect(err).To(BeNil())
+				Expect(actual.Version()).To(Equal(2))
+				Expect(actual.(*baseVersionedEvent).Value).To(Equal("value"))
+			}
+		})
+
+		It("should return error when the upcaster failed", func() {
+			registry := event.NewRegistry()
+			registry.Register(versionedJSONEventName, 2, decodeVersionedJSONEvent)
+			registerVersionedJSONEventUpcasters(registry)
+
+			_, err := registry.DecodeByType(versionedJSONEventName, 1, []byte(`{"count":0}`))
+			Expect(err).To(MatchError("error upcasting event `VersionedJSONEventV1`: missing key"))
+		})
+	})
+
+	Describe("RegisteredTypes", func() {
+		It("should return the decoded Go type of each registered name and version in order", func() {
+			registry := event.NewRegistry()
+			registry.Register(versionedJSONEventName, 2, decodeVersionedJSONEvent)
+			registry.Register(simpleJSONEventName, simpleJSONEventVersion, decodeSimpleJSONEvent)
+			registerVersionedJSONEventUpcasters(registry)
+
+			actual, err := registry.RegisteredTypes()
+			Expect(err).To(BeNil())
+			Expect(actual).To(Equal([]event.RegisteredType{
+				{Name: simpleJSONEventName, Version: simpleJSONEventVersion, Type: reflect.TypeOf(&simpleJSONEvent{})},
+				{Name: versionedJSONEventName, Version: 2, Type: reflect.TypeOf(&versionedJSONEvent{})},
+			}))
+		})
+	})
 })
 
 const simpleJSONEventName = "SimpleJSONEvent"
@@ -91,3 +168,84 @@ func decodeSimpleJSONEvent(eventBytes []byte) (event.Event, error) {
 	}
 	return event, nil
 }
+
+const versionedJSONEventName = "VersionedJSONEvent"
+
+// versionedJSONEvent is the version 2 of the event. Version 0 has only `key`, version 1 adds `count` and version 2
+// renames `key` to `name`.
+type versionedJSONEvent struct {
+	Value string `json:"name"`
+	Count int    `json:"count"`
+}
+
+func (event *versionedJSONEvent) Height() int64 { return 1 }
+func (event *versionedJSONEvent) Name() string  { return versionedJSONEventName }
+func (event *versionedJSONEvent) Version() int  { return 2 }
+func (event *versionedJSONEvent) UUID() string  { return "versioned-json-event-id" }
+func (event *versionedJSONEvent) ToJSON() (string, error) {
+	return event.UUID(), nil
+}
+func (event *versionedJSONEvent) String() string { return versionedJSONEventName }
+func decodeVersionedJSONEvent(eventBytes []byte) (event.Event, error) {
+	var event *versionedJSONEvent
+	jsonDecoder := json.NewDecoder(bytes.NewReader(eventBytes))
+	jsonDecoder.DisallowUnknownFields()
+	if err := jsonDecoder.Decode(&event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+func registerVersionedJSONEventUpcasters(registry *event.Registry) {
+	registry.RegisterUpcaster(versionedJSONEventName, 0, event.NewJSONUpcaster(func(payload map[string]interface{}) error {
+		payload["count"] = 0
+		return nil
+	}))
+	registry.RegisterUpcaster(versionedJSONEventName, 1, event.NewJSONUpcaster(func(payload map[string]interface{}) error {
+		key, ok := payload["key"]
+		if !ok {
+			return errors.New("missing key")
+		}
+		payload["name"] = key
+		delete(payload, "key")
+		return nil
+	}))
+}
+
+const baseVersionedEventName = "BaseVersionedEvent"
+
+// baseVersionedEvent is the version 2 of the event embedding event.Base. Version 0 has only `key`, version 1 adds
+// `count` and version 2 renames `key` to `value`.
+type baseVersionedEvent struct {
+	event.Base
+
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+func (event *baseVersionedEvent) ToJSON() (string, error) {
+	encoded, err := json.Marshal(event)
+	return string(encoded), err
+}
+func (event *baseVersionedEvent) String() string { return baseVersionedEventName }
+func decodeBaseVersionedEvent(eventBytes []byte) (event.Event, error) {
+	var event *baseVersionedEvent
+	jsonDecoder := json.NewDecoder(bytes.NewReader(eventBytes))
+	jsonDecoder.DisallowUnknownFields()
+	if err := jsonDecoder.Decode(&event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+func registerBaseVersionedEventUpcasters(registry *event.Registry) {
+	registry.RegisterUpcaster(baseVersionedEventName, 0, event.NewJSONUpcaster(func(payload map[string]interface{}) error {
+		payload["count"] = 0
+		return nil
+	}))
+	registry.RegisterUpcaster(baseVersionedEventName, 1, event.NewJSONUpcaster(func(payload map[string]interface{}) error {
+		payload["value"] = payload["key"]
+		delete(payload, "key")
+		return nil
+	}))
+}
@@ -1,5 +1,13 @@
 package event
 
+import (
+	"context"
+	"errors"
+)
+
+// ErrLatestHeightSubscriptionUnsupported is returned when the store is not able to notify the latest height
+var ErrLatestHeightSubscriptionUnsupported = errors.New("latest height subscription is not supported by the store")
+
 type Store interface {
 	// GetLatestEventHeight returns latest event height, nil if no event is stored
 	GetLatestHeight() (*int64, error)
@@ -11,3 +19,20 @@ type Store interface {
 	// InsertAll insert all events into store. It will rollback when the insert fails at any point.
 	InsertAll(evt []Event) error
 }
+
+// LatestHeightSubscriber is an optional interface of Store to be notified when events of a new height are stored. A
+// store may only be notified of the events stored by the same process, e.g. a file based one; only the RDb store is
+// notified of the events stored by the other processes.
+type LatestHeightSubscriber interface {
+	// SubscribeLatestHeight returns a channel receiving the latest event height whenever events are stored. The
+	// channel is closed when the subscription ends, e.g. the context is cancelled or the connection is lost.
+	// Returns ErrLatestHeightSubscriptionUnsupported when the store cannot notify the latest height.
+	SubscribeLatestHeight(ctx context.Context) (<-chan int64, error)
+}
+
+// RangeStore is an optional interface of Store to replay the events of a height range in a single query
+type RangeStore interface {
+	// StreamByHeightRange calls the handler with each event of the event names in the heights from fromHeight to
+	// toHeight inclusively, in height order. Events of other names are not decoded. Stops at the first handler error.
+	StreamByHeightRange(fromHeight int64, toHeight int64, eventNames []string, handler func(event Event) error) error
+}
@@ -1,6 +1,8 @@
 package test
 
 import (
+	"context"
+
 	entity_event "github.com/crypto-com/chain-indexing/entity/event"
 	"github.com/stretchr/testify/mock"
 )
@@ -36,3 +38,44 @@ func (manager *MockEventStore) InsertAll(evts []entity_event.Event) error {
 
 	return mockArgs.Error(0)
 }
+
+// MockSubscribableEventStore is a MockEventStore notifying the latest height sent to LatestHeightCh
+type MockSubscribableEventStore struct {
+	MockEventStore
+
+	LatestHeightCh chan int64
+}
+
+func NewMockSubscribableEventStore() *MockSubscribableEventStore {
+	return &MockSubscribableEventStore{
+		LatestHeightCh: make(chan int64, 1),
+	}
+}
+
+func (manager *MockSubscribableEventStore) SubscribeLatestHeight(ctx context.Context) (<-chan int64, error) {
+	return manager.LatestHeightCh, nil
+}
+
+type MockRangeEventStore struct {
+	MockEventStore
+}
+
+func NewMockRangeEventStore() *MockRangeEventStore {
+	return &MockRangeEventStore{}
+}
+
+func (manager *MockRangeEventStore) StreamByHeightRange(
+	fromHeight int64,
+	toHeight int64,
+	eventNames []string,
+	handler func(event entity_event.Event) error,
+) error {
+	mockArgs := manager.Called(fromHeight, toHeight, eventNames)
+
+	for _, event := range mockArgs.Get(0).([]entity_event.Event) {
+		if err := handler(event); err != nil {
+			return err
+		}
+	}
+	return mockArgs.Error(1)
+}
@@ -0,0 +1,58 @@
+package projection
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrProjectionNotFound = errors.New("projection not found")
+var ErrProjectionNotPaused = errors.New("projection is not paused")
+var ErrRewindUnsupported = errors.New("projection does not support rewind")
+
+// RewindableProjection is a projection able to be rewound to a height it has handled, such that the heights after it
+// are handled again
+type RewindableProjection interface {
+	Projection
+
+	// Rewind reverts the changes made by the heights after the height and sets the last handled event height to it
+	Rewind(height int64) error
+}
+
+// Controller pauses, resumes and rewinds the projections it runs individually
+type Controller interface {
+	// PauseProjection stops the projection from handling more heights. It returns once the events being handled by
+	// the projection are handled.
+	PauseProjection(projectionId string) error
+	// ResumeProjection continues the paused or halted projection from its last handled event height
+	ResumeProjection(projectionId string) error
+	// RewindProjection rewinds the paused projection to the height
+	RewindProjection(projectionId string, height int64) error
+}
+
+// RewindProjection rewinds the projection to the height when it supports rewind. The height must not be after the last
+// handled event height of the projection.
+func RewindProjection(projection Projection, height int64) error {
+	rewindableProjection, ok := projection.(RewindableProjection)
+	if !ok {
+		return ErrRewindUnsupported
+	}
+	if height < 0 {
+		return fmt.Errorf("error rewinding projection `%s`: invalid height %d", projection.Id(), height)
+	}
+
+	maybeLastHandledEventHeight, err := projection.GetLastHandledEventHeight()
+	if err != nil {
+		return fmt.Errorf("error getting last handled event height of projection `%s`: %v", projection.Id(), err)
+	}
+	if maybeLastHandledEventHeight == nil || height > *maybeLastHandledEventHeight {
+		return fmt.Errorf(
+			"error rewinding projection `%s`: height %d is after its last handled event height", projection.Id(), height,
+		)
+	}
+
+	if err := rewindableProjection.Rewind(height); err != nil {
+		return fmt.Errorf("error rewinding projection `%s`: %v", projection.Id(), err)
+	}
+
+	return nil
+}
@@ -1,6 +1,9 @@
 package projection
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 // A Cron Job is a special type of projection
 type CronJob interface {
@@ -15,6 +18,7 @@ type CronJob interface {
 	// Cron Job execution interval in duration
 	Interval() time.Duration
 
-	// Execute Cron Job logic on regular interval
-	Exec() error
+	// Execute Cron Job logic on regular interval. The context is cancelled when the system is shutting down, the cron
+	// job should stop any new work and return as soon as possible.
+	Exec(ctx context.Context) error
 }
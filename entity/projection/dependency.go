@@ -0,0 +1,94 @@
+package projection
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DependentProjection is an optional interface of Projection to declare the projections whose tables it reads. A
+// height is handed to the projection only after all its dependencies have handled the height.
+type DependentProjection interface {
+	Projection
+
+	// Returns the ids of the projections it depends on. Dependencies which are not running are ignored.
+	GetDependencies() []string
+}
+
+// GetDependencies returns the ids of the projections the projection depends on, empty when it does not declare any
+func GetDependencies(projection Projection) []string {
+	if dependentProjection, ok := projection.(DependentProjection); ok {
+		return dependentProjection.GetDependencies()
+	}
+	return []string{}
+}
+
+// CheckDependencyCycle returns an error naming the cycle when the dependencies, keyed by the dependent id, form a
+// cycle. Dependencies on ids which are not keys are ignored.
+func CheckDependencyCycle(dependencies map[string][]string) error {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	states := make(map[string]int, len(dependencies))
+	path := make([]string, 0)
+
+	var visit func(id string) error
+	visit = func(id string) error {
+		switch states[id] {
+		case visiting:
+			cycleStart := 0
+			for i, pathId := range path {
+				if pathId == id {
+					cycleStart = i
+					break
+				}
+			}
+			cycle := append(append([]string{}, path[cycleStart:]...), id)
+			return fmt.Errorf("projection dependency cycle: %s", strings.Join(cycle, " -> "))
+		case visited:
+			return nil
+		}
+
+		states[id] = visiting
+		path = append(path, id)
+		for _, dependencyId := range dependencies[id] {
+			if _, ok := dependencies[dependencyId]; !ok {
+				continue
+			}
+			if err := visit(dependencyId); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		states[id] = visited
+
+		return nil
+	}
+
+	// Visit in a deterministic order for a stable error message
+	ids := make([]string, 0, len(dependencies))
+	for id := range dependencies {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if err := visit(id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CheckProjectionDependencyCycle returns an error naming the cycle when the dependencies among the projections form a
+// cycle
+func CheckProjectionDependencyCycle(projections []Projection) error {
+	dependencies := make(map[string][]string, len(projections))
+	for _, projection := range projections {
+		dependencies[projection.Id()] = GetDependencies(projection)
+	}
+
+	return CheckDependencyCycle(dependencies)
+}
@@ -0,0 +1,38 @@
+package projection_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/crypto-com/chain-indexing/entity/projection"
+)
+
+var _ = Describe("CheckDependencyCycle", func() {
+	It("should return nil when the dependencies form no cycle", func() {
+		Expect(projection.CheckDependencyCycle(map[string][]string{
+			"Block":     {},
+			"Validator": {"Block"},
+			"Proposal":  {"Validator", "Block"},
+		})).To(Succeed())
+	})
+
+	It("should ignore the dependencies on unknown ids", func() {
+		Expect(projection.CheckDependencyCycle(map[string][]string{
+			"Proposal": {"Validator"},
+		})).To(Succeed())
+	})
+
+	It("should return Error naming the cycle when the dependencies form a cycle", func() {
+		Expect(projection.CheckDependencyCycle(map[string][]string{
+			"Block":     {"Proposal"},
+			"Validator": {"Block"},
+			"Proposal":  {"Validator"},
+		})).To(MatchError("projection dependency cycle: Block -> Proposal -> Validator -> Block"))
+	})
+
+	It("should return Error when a projection depends on itself", func() {
+		Expect(projection.CheckDependencyCycle(map[string][]string{
+			"Block": {"Block"},
+		})).To(MatchError("projection dependency cycle: Block -> Block"))
+	})
+})
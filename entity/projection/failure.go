@@ -0,0 +1,71 @@
+package projection
+
+import (
+	entity_event "github.com/crypto-com/chain-indexing/entity/event"
+)
+
+// Retries the failed height forever
+const FAILURE_ACTION_RETRY = "RETRY"
+
+// Stops the projection and marks it as failed once the retries are exhausted
+const FAILURE_ACTION_HALT = "HALT"
+
+// Records the events of the failed height as a dead letter and moves on to the next height once the retries are
+// exhausted
+const FAILURE_ACTION_SKIP = "SKIP"
+
+const STATE_RUNNING = "RUNNING"
+const STATE_FAILED = "FAILED"
+const STATE_PAUSED = "PAUSED"
+
+// FailurePolicy decides what happens to a projection failing to handle the events of a height. The height is retried
+// MaxRetry times before the action is taken. Any action other than halt and skip retries forever.
+type FailurePolicy struct {
+	MaxRetry int
+	Action   string
+}
+
+// IsRetryExhausted returns true when the action of the policy should be taken after the number of failures
+func (policy FailurePolicy) IsRetryExhausted(failureCount int) bool {
+	if policy.Action != FAILURE_ACTION_HALT && policy.Action != FAILURE_ACTION_SKIP {
+		return false
+	}
+
+	return failureCount > policy.MaxRetry
+}
+
+// FailurePolicies are the failure policies of the projections. Projections without their own policy use the default
+// one.
+type FailurePolicies struct {
+	Default      FailurePolicy
+	ByProjection map[string]FailurePolicy
+}
+
+func (policies FailurePolicies) Of(projectionId string) FailurePolicy {
+	if policy, ok := policies.ByProjection[projectionId]; ok {
+		return policy
+	}
+
+	return policies.Default
+}
+
+// DeadLetter is the events of a height skipped by a projection after failing to handle them
+type DeadLetter struct {
+	ProjectionId string
+	Height       int64
+	Events       []entity_event.Event
+	Error        string
+}
+
+// FailureStore keeps the failure state of the projections and their dead letters
+type FailureStore interface {
+	// MarkRunning marks the projection as running, keeping the details of its last failure
+	MarkRunning(projectionId string) error
+	// MarkPaused marks the projection as paused by an operator, keeping the details of its last failure
+	MarkPaused(projectionId string) error
+	// MarkFailed marks the projection as halted because of failing to handle the events of the height
+	MarkFailed(projectionId string, height int64, errMessage string) error
+	// InsertDeadLetter records the skipped events of the height. The skipped height is only persisted once the next
+	// height is handled, so a height skipped again after a restart must replace its dead letter instead of duplicating it.
+	InsertDeadLetter(deadLetter *DeadLetter) error
+}
@@ -1,7 +1,10 @@
 package projection
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	entity_event "github.com/crypto-com/chain-indexing/entity/event"
@@ -9,50 +12,442 @@ import (
 	"github.com/crypto-com/chain-indexing/infrastructure/metric/prometheus"
 )
 
+// DEFAULT_REPLAY_RANGE_SIZE is the number of heights replayed at once when the event store supports range replay
+const DEFAULT_REPLAY_RANGE_SIZE = 1000
+
+var _ Controller = &StoreBasedManager{}
+
 // StoreBasedManager is a projection manager relies on replaying events from EventStore
 type StoreBasedManager struct {
-	logger     applogger.Logger
-	eventStore entity_event.Store
+	logger          applogger.Logger
+	eventStore      entity_event.Store
+	replayRangeSize int64
+
+	failurePolicies   FailurePolicies
+	maybeFailureStore FailureStore
+
+	// Progress of the dependencies run by other processes, nil when the dependencies not registered are ignored
+	maybeProgressStore ProgressStore
+	// Leases of the projections run by multiple processes, nil when the projections are only run by this manager
+	maybeLeaser Leaser
 
 	projections []Projection
+
+	// Context the runners run with, used to restart the halted runners on resume
+	maybeRunCtx      context.Context
+	runnersWaitGroup sync.WaitGroup
+
+	// Serializes the pause, resume and rewind of the projections
+	controlMutex sync.Mutex
+
+	progressMutex sync.Mutex
+	// Last handled event height of each running projection, absent until known
+	lastHandledHeights map[string]int64
+	// Wake up channel of each running projection, used to notify the dependents of a projection progress
+	wakeChs map[string]chan bool
+	// Pause state of the runner of each projection
+	runnerControls map[string]*runnerControl
+	// Signaled on every runner parking or stopping. Uses progressMutex.
+	runnerControlCond *sync.Cond
+}
+
+// runnerControl is the pause state of a projection runner. It is guarded by the progressMutex of the manager.
+type runnerControl struct {
+	isPaused  bool
+	isRunning bool
+	// True when the runner is waiting to be resumed and is not handling any event
+	isParked bool
 }
 
 func NewStoreBasedManager(logger applogger.Logger, eventStore entity_event.Store) *StoreBasedManager {
-	return &StoreBasedManager{
+	manager := &StoreBasedManager{
 		logger: logger.WithFields(applogger.LogFields{
 			"module": "projectionManager",
 		}),
-		eventStore: eventStore,
+		eventStore:      eventStore,
+		replayRangeSize: DEFAULT_REPLAY_RANGE_SIZE,
 
 		projections: make([]Projection, 0),
+
+		lastHandledHeights: make(map[string]int64),
+		wakeChs:            make(map[string]chan bool),
+		runnerControls:     make(map[string]*runnerControl),
 	}
+	manager.runnerControlCond = sync.NewCond(&manager.progressMutex)
+
+	return manager
+}
+
+// WithReplayRangeSize sets the number of heights replayed at once when the event store supports range replay
+func (manager *StoreBasedManager) WithReplayRangeSize(replayRangeSize int64) *StoreBasedManager {
+	manager.replayRangeSize = replayRangeSize
+	return manager
+}
+
+// WithFailureHandling sets the failure policies of the projections and the store recording their failures. Without
+// it, a projection retries a failed height forever.
+func (manager *StoreBasedManager) WithFailureHandling(
+	failurePolicies FailurePolicies,
+	failureStore FailureStore,
+) *StoreBasedManager {
+	manager.failurePolicies = failurePolicies
+	manager.maybeFailureStore = failureStore
+	return manager
+}
+
+// WithProgressStore makes the projections depending on projections not registered, e.g. run by other processes, wait
+// for the progress of those dependencies in the store
+func (manager *StoreBasedManager) WithProgressStore(progressStore ProgressStore) *StoreBasedManager {
+	manager.maybeProgressStore = progressStore
+	return manager
+}
+
+// WithLeaser makes the projections handle events only while holding their leases, such that the same projection can be
+// run by multiple processes with only one of them handling its events at a time. The leases are set on the fenced
+// projections such that they cannot commit once the lease is lost.
+func (manager *StoreBasedManager) WithLeaser(leaser Leaser) *StoreBasedManager {
+	manager.maybeLeaser = leaser
+	return manager
 }
 
 func (manager *StoreBasedManager) RegisterProjection(projection Projection) error {
 	if manager.IsProjectionRegistered(projection) {
 		return fmt.Errorf("projection `%s` already registered", projection.Id())
 	}
+	projections := append(append(make([]Projection, 0, len(manager.projections)+1), manager.projections...), projection)
+	if err := CheckProjectionDependencyCycle(projections); err != nil {
+		return fmt.Errorf("error registering projection `%s`: %v", projection.Id(), err)
+	}
 	manager.projections = append(manager.projections, projection)
+	manager.progressMutex.Lock()
+	manager.runnerControls[projection.Id()] = &runnerControl{}
+	manager.progressMutex.Unlock()
 	return nil
 }
 
 func (manager *StoreBasedManager) IsProjectionRegistered(projection Projection) bool {
+	return manager.isProjectionIdRegistered(projection.Id())
+}
+
+func (manager *StoreBasedManager) isProjectionIdRegistered(projectionId string) bool {
 	for _, registeredProjection := range manager.projections {
-		if projection.Id() == registeredProjection.Id() {
+		if projectionId == registeredProjection.Id() {
 			return true
 		}
 	}
 	return false
 }
 
-// Starts projectionManager by running all registered projection.
-func (manager *StoreBasedManager) RunInBackground() {
+func (manager *StoreBasedManager) projectionById(projectionId string) (Projection, bool) {
+	for _, registeredProjection := range manager.projections {
+		if projectionId == registeredProjection.Id() {
+			return registeredProjection, true
+		}
+	}
+	return nil, false
+}
+
+// Starts projectionManager by running all registered projection. The projections stop after finishing the events of
+// the current height when the context is cancelled. When the event store supports latest height subscription, the
+// projections are woken up as soon as new events are stored instead of waiting for the next polling. A projection
+// depending on other projections handles a height only after all its dependencies have handled it. A projection failing
+// to handle a height is halted or skips the height according to its failure policy. The projections can be paused,
+// resumed and rewound individually while running.
+func (manager *StoreBasedManager) RunInBackground(ctx context.Context) {
+	manager.controlMutex.Lock()
+	manager.maybeRunCtx = ctx
+	manager.controlMutex.Unlock()
+
+	wakeChs := make([]chan bool, 0, len(manager.projections))
+	for _, projection := range manager.projections {
+		wakeCh := make(chan bool, 1)
+		wakeChs = append(wakeChs, wakeCh)
+		manager.progressMutex.Lock()
+		manager.wakeChs[projection.Id()] = wakeCh
+		manager.progressMutex.Unlock()
+
+		for _, dependencyId := range GetDependencies(projection) {
+			if manager.isProjectionIdRegistered(dependencyId) {
+				continue
+			}
+			if manager.maybeProgressStore != nil {
+				manager.logger.Infof(
+					"projection `%s` depends on `%s` which is not registered, following its progress in the store",
+					projection.Id(), dependencyId,
+				)
+			} else {
+				manager.logger.Infof(
+					"projection `%s` depends on `%s` which is not registered, ignoring the dependency",
+					projection.Id(), dependencyId,
+				)
+			}
+		}
+	}
+	manager.progressMutex.Lock()
 	for _, projection := range manager.projections {
-		go manager.projectionRunner(projection)
+		manager.startRunner(ctx, projection)
+	}
+	manager.progressMutex.Unlock()
+
+	if subscriber, ok := manager.eventStore.(entity_event.LatestHeightSubscriber); ok {
+		manager.runnersWaitGroup.Add(1)
+		go func() {
+			defer manager.runnersWaitGroup.Done()
+			manager.latestHeightNotifier(ctx, subscriber, wakeChs)
+		}()
+	}
+}
+
+// latestHeightNotifier wakes up all the projections whenever new events are stored. It resubscribes when the
+// subscription ends until the context is cancelled.
+func (manager *StoreBasedManager) latestHeightNotifier(
+	ctx context.Context,
+	subscriber entity_event.LatestHeightSubscriber,
+	wakeChs []chan bool,
+) {
+	for {
+		latestHeightCh, err := subscriber.SubscribeLatestHeight(ctx)
+		if err != nil {
+			if errors.Is(err, entity_event.ErrLatestHeightSubscriptionUnsupported) {
+				manager.logger.Infof("event store does not support latest height subscription, polling instead")
+				return
+			}
+			manager.logger.Errorf("error subscribing to latest event height: %v", err)
+		} else {
+			for latestHeight := range latestHeightCh {
+				manager.logger.Debugf("notified of latest event height %d", latestHeight)
+				for _, wakeCh := range wakeChs {
+					select {
+					case wakeCh <- true:
+					default:
+					}
+				}
+			}
+			if ctx.Err() == nil {
+				manager.logger.Errorf("latest event height subscription ended, resubscribing")
+			}
+		}
+
+		if !waitFor(ctx, 5*time.Second) {
+			return
+		}
+	}
+}
+
+// Wait blocks until all the projections have stopped
+func (manager *StoreBasedManager) Wait() {
+	manager.runnersWaitGroup.Wait()
+}
+
+// startRunner runs the projection in background. Must be called with the progressMutex locked.
+func (manager *StoreBasedManager) startRunner(ctx context.Context, projection Projection) {
+	control := manager.runnerControls[projection.Id()]
+	control.isRunning = true
+	wakeCh := manager.wakeChs[projection.Id()]
+
+	manager.runnersWaitGroup.Add(1)
+	go func() {
+		defer manager.runnersWaitGroup.Done()
+		defer func() {
+			manager.progressMutex.Lock()
+			control.isRunning = false
+			control.isParked = false
+			manager.runnerControlCond.Broadcast()
+			manager.progressMutex.Unlock()
+		}()
+		manager.projectionRunner(ctx, projection, wakeCh)
+	}()
+}
+
+// PauseProjection implements Controller.PauseProjection(). The runner finishes the height, or the height range, being
+// handled before being paused.
+func (manager *StoreBasedManager) PauseProjection(projectionId string) error {
+	manager.controlMutex.Lock()
+	defer manager.controlMutex.Unlock()
+	manager.progressMutex.Lock()
+	defer manager.progressMutex.Unlock()
+
+	control, ok := manager.runnerControls[projectionId]
+	if !ok {
+		return ErrProjectionNotFound
+	}
+	control.isPaused = true
+	manager.wakeRunner(projectionId)
+	for control.isRunning && !control.isParked {
+		manager.runnerControlCond.Wait()
+	}
+
+	if manager.maybeFailureStore != nil {
+		if err := manager.maybeFailureStore.MarkPaused(projectionId); err != nil {
+			manager.logger.Errorf("error marking projection `%s` as paused: %v", projectionId, err)
+		}
+	}
+	return nil
+}
+
+// ResumeProjection implements Controller.ResumeProjection(). The runner reloads the last handled event height from the
+// projection as it may have been rewound or rebuilt while paused. A halted runner is started again.
+func (manager *StoreBasedManager) ResumeProjection(projectionId string) error {
+	manager.controlMutex.Lock()
+	defer manager.controlMutex.Unlock()
+	manager.progressMutex.Lock()
+	defer manager.progressMutex.Unlock()
+
+	projection, ok := manager.projectionById(projectionId)
+	if !ok {
+		return ErrProjectionNotFound
+	}
+	control := manager.runnerControls[projectionId]
+	if control.isRunning {
+		control.isPaused = false
+		manager.wakeRunner(projectionId)
+		if manager.maybeFailureStore != nil {
+			if err := manager.maybeFailureStore.MarkRunning(projectionId); err != nil {
+				manager.logger.Errorf("error marking projection `%s` as running: %v", projectionId, err)
+			}
+		}
+		return nil
+	}
+
+	if manager.maybeRunCtx == nil || manager.maybeRunCtx.Err() != nil {
+		return errors.New("error resuming projection: projection manager is not running")
+	}
+	control.isPaused = false
+	manager.startRunner(manager.maybeRunCtx, projection)
+	return nil
+}
+
+// RewindProjection implements Controller.RewindProjection()
+func (manager *StoreBasedManager) RewindProjection(projectionId string, height int64) error {
+	manager.controlMutex.Lock()
+	defer manager.controlMutex.Unlock()
+
+	projection, ok := manager.projectionById(projectionId)
+	if !ok {
+		return ErrProjectionNotFound
+	}
+	manager.progressMutex.Lock()
+	isPaused := manager.runnerControls[projectionId].isPaused
+	manager.progressMutex.Unlock()
+	if !isPaused {
+		return ErrProjectionNotPaused
+	}
+
+	// The paused runner cannot be resumed meanwhile as the controlMutex is held
+	if err := RewindProjection(projection, height); err != nil {
+		return err
+	}
+
+	manager.progressMutex.Lock()
+	manager.lastHandledHeights[projectionId] = height
+	manager.progressMutex.Unlock()
+	return nil
+}
+
+// waitWhilePaused parks the runner of the projection until the projection is resumed. Returns true when the runner
+// has been parked, and false for ok when the context is cancelled.
+func (manager *StoreBasedManager) waitWhilePaused(
+	ctx context.Context,
+	logger applogger.Logger,
+	projectionId string,
+	wakeCh <-chan bool,
+) (parked bool, ok bool) {
+	manager.progressMutex.Lock()
+	defer manager.progressMutex.Unlock()
+
+	control := manager.runnerControls[projectionId]
+	if !control.isPaused {
+		return false, true
+	}
+
+	logger.Infof("projection paused")
+	control.isParked = true
+	manager.runnerControlCond.Broadcast()
+	for control.isPaused {
+		manager.progressMutex.Unlock()
+		isWoken := waitForWake(ctx, wakeCh, 5*time.Second)
+		manager.progressMutex.Lock()
+		if !isWoken {
+			return true, false
+		}
+	}
+	control.isParked = false
+	logger.Infof("projection resumed")
+
+	return true, true
+}
+
+// holdLease makes sure the runner holds the lease of the projection when a leaser is set, waiting until the lease is
+// acquired. The lost lease is acquired again. Returns true for acquired when the lease has been newly acquired, and
+// false for ok when the context is cancelled. Returns without the lease when the projection is paused meanwhile.
+func (manager *StoreBasedManager) holdLease(
+	ctx context.Context,
+	logger applogger.Logger,
+	projectionId string,
+	wakeCh <-chan bool,
+	maybeLease *Lease,
+) (acquired bool, ok bool) {
+	if manager.maybeLeaser == nil {
+		return false, true
+	}
+	if *maybeLease != nil {
+		select {
+		case <-(*maybeLease).Lost():
+			logger.Errorf("lost projection lease, waiting to acquire it again")
+			*maybeLease = nil
+		default:
+			return false, true
+		}
+	}
+
+	logger.Infof("waiting for projection lease")
+	for {
+		manager.progressMutex.Lock()
+		isPaused := manager.runnerControls[projectionId].isPaused
+		manager.progressMutex.Unlock()
+		if isPaused {
+			return false, true
+		}
+
+		lease, err := manager.maybeLeaser.TryAcquire(projectionId)
+		if err != nil {
+			logger.Errorf("error acquiring projection lease: %v", err)
+		} else if lease != nil {
+			logger.Infof("acquired projection lease")
+			*maybeLease = lease
+			return true, true
+		}
+		if !waitForWake(ctx, wakeCh, 5*time.Second) {
+			return false, false
+		}
+	}
+}
+
+// isLeaseLost returns true when the lease is set and has been lost
+func isLeaseLost(maybeLease Lease) bool {
+	if maybeLease == nil {
+		return false
+	}
+
+	select {
+	case <-maybeLease.Lost():
+		return true
+	default:
+		return false
+	}
+}
+
+// wakeRunner wakes up the runner of the projection if it is waiting. Must be called with the progressMutex locked.
+func (manager *StoreBasedManager) wakeRunner(projectionId string) {
+	if wakeCh, ok := manager.wakeChs[projectionId]; ok {
+		select {
+		case wakeCh <- true:
+		default:
+		}
 	}
 }
 
-func (manager *StoreBasedManager) projectionRunner(projection Projection) {
+func (manager *StoreBasedManager) projectionRunner(ctx context.Context, projection Projection, wakeCh <-chan bool) {
 	eventsToListen := projection.GetEventsToListen()
 	logger := manager.logger.WithFields(applogger.LogFields{
 		"projection": projection.Id(),
@@ -62,36 +457,142 @@ func (manager *StoreBasedManager) projectionRunner(projection Projection) {
 		"eventsToListen": eventsToListen,
 	}).Infof("projection start running")
 
-	var lastHandledEventHeight *int64
-	for {
-		var err error
-		lastHandledEventHeight, err = projection.GetLastHandledEventHeight()
-		if err == nil {
-			break
-		}
+	if _, ok := manager.waitWhilePaused(ctx, logger, projection.Id(), wakeCh); !ok {
+		return
+	}
+	nextEventHeight, ok := manager.loadNextEventHeight(ctx, logger, projection)
+	if !ok {
+		return
+	}
 
-		logger.Infof("error getting last handled event height from projection")
-		<-waitFor(5 * time.Second)
+	rangeStore, isRangeStore := manager.eventStore.(entity_event.RangeStore)
+
+	failurePolicy := manager.failurePolicyOf(projection.Id())
+	if manager.maybeFailureStore != nil {
+		if err := manager.maybeFailureStore.MarkRunning(projection.Id()); err != nil {
+			logger.Errorf("error marking projection as running: %v", err)
+		}
 	}
+	// Height failed to be handled and the number of failures so far. The failed height is handled alone instead of
+	// as part of a height range such that the failure policy applies to that height only.
+	failedHeight := int64(-1)
+	failureCount := 0
 
-	var nextEventHeight int64
-	if lastHandledEventHeight == nil {
-		nextEventHeight = 0
-	} else {
-		nextEventHeight = *lastHandledEventHeight + 1
+	var maybeLease Lease
+	fencedProjection, isFencedProjection := projection.(FencedProjection)
+	defer func() {
+		if maybeLease != nil {
+			if isFencedProjection {
+				fencedProjection.SetLease(nil)
+			}
+			if err := maybeLease.Release(); err != nil {
+				logger.Errorf("error releasing projection lease: %v", err)
+			}
+		}
+	}()
+
+	// Parks the runner while the projection is paused, and waits for the lease of the projection when a leaser is set.
+	// The next event height is reloaded after the projection is resumed or the lease is acquired, as it may have been
+	// rewound, rebuilt or progressed by another process meanwhile. Returns false for ok when the context is cancelled.
+	waitUntilRunnable := func() (reloaded bool, ok bool) {
+		for {
+			parked, ok := manager.waitWhilePaused(ctx, logger, projection.Id(), wakeCh)
+			if !ok {
+				return false, false
+			}
+			acquired, ok := manager.holdLease(ctx, logger, projection.Id(), wakeCh, &maybeLease)
+			if !ok {
+				return false, false
+			}
+			if manager.maybeLeaser != nil && isFencedProjection {
+				fencedProjection.SetLease(maybeLease)
+			}
+			if manager.maybeLeaser != nil && maybeLease == nil {
+				// Paused while waiting for the lease
+				continue
+			}
+			if !parked && !acquired {
+				return false, true
+			}
+
+			if nextEventHeight, ok = manager.loadNextEventHeight(ctx, logger, projection); !ok {
+				return false, false
+			}
+			failedHeight = -1
+			failureCount = 0
+			return true, true
+		}
 	}
 
 	for {
+		if _, ok := waitUntilRunnable(); !ok {
+			logger.Infof("projection stopped")
+			return
+		}
+
 		latestEventHeight, _ := manager.eventStore.GetLatestHeight()
 		if latestEventHeight == nil {
 			logger.Debugf("no event in in the system yet")
-			<-waitFor(5 * time.Second)
+			if !waitForWake(ctx, wakeCh, 5*time.Second) {
+				return
+			}
 			continue
 		}
 		for nextEventHeight <= *latestEventHeight {
+			if ctx.Err() != nil {
+				logger.Infof("projection stopped")
+				return
+			}
+			if reloaded, ok := waitUntilRunnable(); !ok {
+				logger.Infof("projection stopped")
+				return
+			} else if reloaded {
+				continue
+			}
+
+			handleableHeight := *latestEventHeight
+			if maybeDependenciesHeight := manager.dependenciesHandledHeight(
+				projection,
+			); maybeDependenciesHeight != nil && *maybeDependenciesHeight < handleableHeight {
+				handleableHeight = *maybeDependenciesHeight
+			}
+			if nextEventHeight > handleableHeight {
+				logger.Debugf("waiting for dependencies to handle height %d", nextEventHeight)
+				break
+			}
+
 			startTime := time.Now()
 			var err error
 
+			if isRangeStore && nextEventHeight != failedHeight {
+				toHeight := nextEventHeight + manager.replayRangeSize - 1
+				if toHeight > handleableHeight {
+					toHeight = handleableHeight
+				}
+				rangeLogger := logger.WithFields(applogger.LogFields{
+					"fromHeight": nextEventHeight,
+					"toHeight":   toHeight,
+				})
+
+				nextEventHeight, err = manager.handleHeightRange(
+					ctx, projection, rangeStore, eventsToListen, nextEventHeight, toHeight, maybeLease,
+				)
+				if err != nil {
+					if ctx.Err() == nil {
+						rangeLogger.Errorf("error replaying events of height range: %v", err)
+						failedHeight = nextEventHeight
+						failureCount = 1
+						waitFor(ctx, 5*time.Second)
+					}
+					continue
+				}
+
+				rangeLogger.Infof("successfully handled events of height range")
+				prometheus.RecordProjectionExecTime(projection.Id(), time.Since(startTime).Milliseconds())
+				manager.recordLastHandledHeight(projection.Id(), toHeight)
+				continue
+			}
+
 			eventLogger := logger.WithFields(applogger.LogFields{
 				"height": nextEventHeight,
 			})
@@ -99,7 +600,7 @@ func (manager *StoreBasedManager) projectionRunner(projection Projection) {
 			var eventsAtHeight []entity_event.Event
 			if eventsAtHeight, err = manager.eventStore.GetAllByHeight(nextEventHeight); err != nil {
 				eventLogger.Errorf("error getting all events by height: %v", err)
-				<-waitFor(time.Second)
+				waitFor(ctx, time.Second)
 				continue
 			}
 
@@ -121,19 +622,289 @@ func (manager *StoreBasedManager) projectionRunner(projection Projection) {
 				eventLogger.WithFields(applogger.LogFields{
 					"events": events,
 				}).Errorf("error handling events: %v", err)
-				<-waitFor(5 * time.Second)
-				continue
+
+				if nextEventHeight != failedHeight {
+					failedHeight = nextEventHeight
+					failureCount = 0
+				}
+				failureCount += 1
+				if !failurePolicy.IsRetryExhausted(failureCount) {
+					waitFor(ctx, 5*time.Second)
+					continue
+				}
+
+				switch failurePolicy.Action {
+				case FAILURE_ACTION_HALT:
+					if markErr := manager.maybeFailureStore.MarkFailed(
+						projection.Id(), nextEventHeight, err.Error(),
+					); markErr != nil {
+						eventLogger.Errorf("error marking projection as failed: %v", markErr)
+					}
+					eventLogger.Errorf("projection halted after %d failures", failureCount)
+					return
+				case FAILURE_ACTION_SKIP:
+					if deadLetterErr := manager.maybeFailureStore.InsertDeadLetter(&DeadLetter{
+						ProjectionId: projection.Id(),
+						Height:       nextEventHeight,
+						Events:       events,
+						Error:        err.Error(),
+					}); deadLetterErr != nil {
+						eventLogger.Errorf("error inserting dead letter: %v", deadLetterErr)
+						waitFor(ctx, 5*time.Second)
+						continue
+					}
+					eventLogger.Errorf("skipped events after %d failures and recorded them as dead letter", failureCount)
+					manager.recordLastHandledHeight(projection.Id(), nextEventHeight)
+					nextEventHeight += 1
+					continue
+				}
 			}
 
 			eventLogger.Infof("successfully handled events")
 			prometheus.RecordProjectionExecTime(projection.Id(), time.Since(startTime).Milliseconds())
+			manager.recordLastHandledHeight(projection.Id(), nextEventHeight)
 			nextEventHeight += 1
 		}
 		prometheus.RecordProjectionLatestHeight(projection.Id(), nextEventHeight)
-		<-waitFor(5 * time.Second)
+		if !waitForWake(ctx, wakeCh, 5*time.Second) {
+			logger.Infof("projection stopped")
+			return
+		}
+	}
+}
+
+// loadNextEventHeight returns the height after the last handled event height of the projection, retrying until it is
+// loaded. Returns false when the context is cancelled before that.
+func (manager *StoreBasedManager) loadNextEventHeight(
+	ctx context.Context,
+	logger applogger.Logger,
+	projection Projection,
+) (int64, bool) {
+	for {
+		lastHandledEventHeight, err := projection.GetLastHandledEventHeight()
+		if err == nil {
+			if lastHandledEventHeight == nil {
+				return 0, true
+			}
+			manager.recordLastHandledHeight(projection.Id(), *lastHandledEventHeight)
+			return *lastHandledEventHeight + 1, true
+		}
+
+		logger.Infof("error getting last handled event height from projection")
+		if !waitFor(ctx, 5*time.Second) {
+			return 0, false
+		}
+	}
+}
+
+// failurePolicyOf returns the failure policy of the projection. Projections retry forever without a failure store.
+func (manager *StoreBasedManager) failurePolicyOf(projectionId string) FailurePolicy {
+	if manager.maybeFailureStore == nil {
+		return FailurePolicy{}
+	}
+
+	return manager.failurePolicies.Of(projectionId)
+}
+
+// recordLastHandledHeight records the progress of the projection and wakes up the projections depending on it
+func (manager *StoreBasedManager) recordLastHandledHeight(projectionId string, height int64) {
+	manager.progressMutex.Lock()
+	defer manager.progressMutex.Unlock()
+
+	manager.lastHandledHeights[projectionId] = height
+	for _, projection := range manager.projections {
+		for _, dependencyId := range GetDependencies(projection) {
+			if dependencyId != projectionId {
+				continue
+			}
+			manager.wakeRunner(projection.Id())
+		}
 	}
 }
 
+// dependenciesHandledHeight returns the lowest last handled height among the dependencies of the projection, -1 when any
+// of them has not handled any height yet. The dependencies not registered are ignored unless a progress store is set.
+// Returns nil when the projection has no dependency to wait for.
+func (manager *StoreBasedManager) dependenciesHandledHeight(projection Projection) *int64 {
+	var maybeLowestHeight *int64
+	lowerTo := func(height int64) {
+		if maybeLowestHeight == nil || height < *maybeLowestHeight {
+			maybeLowestHeight = &height
+		}
+	}
+
+	externalDependencyIds := make([]string, 0)
+	manager.progressMutex.Lock()
+	for _, dependencyId := range GetDependencies(projection) {
+		if !manager.isProjectionIdRegistered(dependencyId) {
+			if manager.maybeProgressStore != nil {
+				externalDependencyIds = append(externalDependencyIds, dependencyId)
+			}
+			continue
+		}
+		lastHandledHeight, ok := manager.lastHandledHeights[dependencyId]
+		if !ok {
+			lastHandledHeight = -1
+		}
+		lowerTo(lastHandledHeight)
+	}
+	manager.progressMutex.Unlock()
+
+	for _, dependencyId := range externalDependencyIds {
+		lastHandledHeight := int64(-1)
+		maybeHeight, err := manager.maybeProgressStore.GetLastHandledEventHeight(dependencyId)
+		if err != nil {
+			manager.logger.Errorf("error getting last handled event height of projection `%s`: %v", dependencyId, err)
+		} else if maybeHeight != nil {
+			lastHandledHeight = *maybeHeight
+		}
+		lowerTo(lastHandledHeight)
+	}
+
+	return maybeLowestHeight
+}
+
+// handleHeightRange replays the events of the projection from fromHeight to toHeight inclusively with a single query.
+// Batch projections handle the whole range at once, others handle each height as soon as its events are streamed.
+// Returns the next event height to handle, which is the failed height when an error is returned. Stops before the next
+// height once the lease, if any, is lost.
+func (manager *StoreBasedManager) handleHeightRange(
+	ctx context.Context,
+	projection Projection,
+	rangeStore entity_event.RangeStore,
+	eventsToListen []string,
+	fromHeight int64,
+	toHeight int64,
+	maybeLease Lease,
+) (int64, error) {
+	if batchProjection, ok := projection.(BatchProjection); ok {
+		events := make([]entity_event.Event, 0)
+		if err := rangeStore.StreamByHeightRange(
+			fromHeight, toHeight, eventsToListen, func(event entity_event.Event) error {
+				events = append(events, event)
+				return nil
+			},
+		); err != nil {
+			return fromHeight, fmt.Errorf("error getting events by height range: %v", err)
+		}
+
+		if isLeaseLost(maybeLease) {
+			return fromHeight, errors.New("error handling events batch: projection lease lost")
+		}
+		if err := batchProjection.HandleEventsBatch(fromHeight, toHeight, events); err != nil {
+			return fromHeight, fmt.Errorf("error handling events batch: %v", err)
+		}
+		return toHeight + 1, nil
+	}
+
+	nextHeight := fromHeight
+	// Events streamed of the next height so far
+	eventsAtHeight := make([]entity_event.Event, 0)
+	// handleHeightsBefore handles the heights up to but excluding the height, which are complete once an event of the
+	// height is streamed
+	handleHeightsBefore := func(height int64) error {
+		for ; nextHeight < height; nextHeight += 1 {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if isLeaseLost(maybeLease) {
+				return errors.New("error handling events of height range: projection lease lost")
+			}
+			if err := projection.HandleEvents(nextHeight, eventsAtHeight); err != nil {
+				return fmt.Errorf("error handling events of height %d: %v", nextHeight, err)
+			}
+			eventsAtHeight = make([]entity_event.Event, 0)
+		}
+		return nil
+	}
+
+	var handleErr error
+	if err := rangeStore.StreamByHeightRange(
+		fromHeight, toHeight, eventsToListen, func(event entity_event.Event) error {
+			if handleErr = handleHeightsBefore(event.Height()); handleErr != nil {
+				return handleErr
+			}
+			eventsAtHeight = append(eventsAtHeight, event)
+			return nil
+		},
+	); err != nil {
+		if handleErr != nil {
+			return nextHeight, handleErr
+		}
+		return nextHeight, fmt.Errorf("error getting events by height range: %v", err)
+	}
+	if err := handleHeightsBefore(toHeight + 1); err != nil {
+		return nextHeight, err
+	}
+
+	return toHeight + 1, nil
+}
+
+// ReplayProjection replays the events to the projection from its next event height to toHeight inclusively and returns
+// once done. It is meant for rebuilding a projection that is not run by any manager at the same time.
+func (manager *StoreBasedManager) ReplayProjection(ctx context.Context, projection Projection, toHeight int64) error {
+	eventsToListen := projection.GetEventsToListen()
+	logger := manager.logger.WithFields(applogger.LogFields{
+		"projection": projection.Id(),
+	})
+
+	lastHandledEventHeight, err := projection.GetLastHandledEventHeight()
+	if err != nil {
+		return fmt.Errorf("error getting last handled event height from projection: %v", err)
+	}
+	var nextEventHeight int64
+	if lastHandledEventHeight == nil {
+		nextEventHeight = 0
+	} else {
+		nextEventHeight = *lastHandledEventHeight + 1
+	}
+
+	rangeStore, isRangeStore := manager.eventStore.(entity_event.RangeStore)
+	for nextEventHeight <= toHeight {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if isRangeStore {
+			rangeToHeight := nextEventHeight + manager.replayRangeSize - 1
+			if rangeToHeight > toHeight {
+				rangeToHeight = toHeight
+			}
+			if nextEventHeight, err = manager.handleHeightRange(
+				ctx, projection, rangeStore, eventsToListen, nextEventHeight, rangeToHeight, nil,
+			); err != nil {
+				return err
+			}
+
+			logger.WithFields(applogger.LogFields{
+				"toHeight": rangeToHeight,
+			}).Infof("successfully replayed events of height range")
+			continue
+		}
+
+		eventsAtHeight, err := manager.eventStore.GetAllByHeight(nextEventHeight)
+		if err != nil {
+			return fmt.Errorf("error getting all events by height %d: %v", nextEventHeight, err)
+		}
+		events := make([]entity_event.Event, 0)
+		for _, event := range eventsAtHeight {
+			if isListeningEvent(event, eventsToListen) {
+				events = append(events, event)
+			}
+		}
+		if err := projection.HandleEvents(nextEventHeight, events); err != nil {
+			return fmt.Errorf("error handling events of height %d: %v", nextEventHeight, err)
+		}
+
+		logger.WithFields(applogger.LogFields{
+			"height": nextEventHeight,
+		}).Debugf("successfully replayed events")
+		nextEventHeight += 1
+	}
+
+	return nil
+}
+
 func isListeningEvent(event entity_event.Event, eventsToListen []string) bool {
 	targetEventName := event.Name()
 	for _, eventName := range eventsToListen {
@@ -145,6 +916,24 @@ func isListeningEvent(event entity_event.Event, eventsToListen []string) bool {
 	return false
 }
 
-func waitFor(wait time.Duration) <-chan time.Time {
-	return time.After(wait)
+// waitFor waits for the duration. Returns false when the context is cancelled before that.
+func waitFor(ctx context.Context, wait time.Duration) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(wait):
+		return true
+	}
+}
+
+// waitForWake waits for the duration or until woken up. Returns false when the context is cancelled before that.
+func waitForWake(ctx context.Context, wakeCh <-chan bool, wait time.Duration) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-wakeCh:
+		return true
+	case <-time.After(wait):
+		return true
+	}
 }
@@ -1,6 +1,9 @@
 package projection_test
 
 import (
+	"context"
+	"errors"
+	"sync"
 	"time"
 
 	. "github.com/crypto-com/chain-indexing/entity/event/test"
@@ -25,6 +28,23 @@ var _ = Describe("StoreBasedManager", func() {
 			Expect(manager.RegisterProjection(anyProjection)).To(MatchError("projection `FakeProjection` already registered"))
 		})
 
+		It("should return Error when the projection dependencies form a cycle", func() {
+			manager := projection.NewStoreBasedManager(NewFakeLogger(), NewFakeEventStore())
+
+			anyProjection := NewMockDependentProjection()
+			anyProjection.On("Id").Return("A")
+			anyProjection.On("GetDependencies").Return([]string{"B"})
+			anyOtherProjection := NewMockDependentProjection()
+			anyOtherProjection.On("Id").Return("B")
+			anyOtherProjection.On("GetDependencies").Return([]string{"A"})
+
+			Expect(manager.RegisterProjection(anyProjection)).To(BeNil())
+			Expect(manager.RegisterProjection(anyOtherProjection)).To(
+				MatchError("error registering projection `B`: projection dependency cycle: A -> B -> A"),
+			)
+			Expect(manager.IsProjectionRegistered(anyOtherProjection)).To(BeFalse())
+		})
+
 		It("should register projection to the manager", func() {
 			manager := projection.NewStoreBasedManager(NewFakeLogger(), NewFakeEventStore())
 
@@ -74,7 +94,7 @@ var _ = Describe("StoreBasedManager", func() {
 			})).Once().Return(nil)
 
 			// RunInBackground the manager
-			manager.RunInBackground()
+			manager.RunInBackground(context.Background())
 			// Since manager create goroutines for Projection, we have to give up the CPU for
 			// events channel to happen
 			<-time.After(time.Second)
@@ -127,7 +147,7 @@ var _ = Describe("StoreBasedManager", func() {
 			})).Once().Return(nil)
 
 			// RunInBackground the manager
-			manager.RunInBackground()
+			manager.RunInBackground(context.Background())
 			// Since manager create goroutines for Projection, we have to give up the CPU for
 			// events channel to happen
 			<-time.After(time.Second)
@@ -188,7 +208,7 @@ var _ = Describe("StoreBasedManager", func() {
 			})).Once().Return(nil)
 
 			// RunInBackground the manager
-			manager.RunInBackground()
+			manager.RunInBackground(context.Background())
 			// Since manager create goroutines for Projection, we have to give up the CPU for
 			// events channel to happen
 			<-time.After(time.Second)
@@ -233,7 +253,7 @@ var _ = Describe("StoreBasedManager", func() {
 			})).Once().Return(nil)
 
 			// RunInBackground the manager
-			manager.RunInBackground()
+			manager.RunInBackground(context.Background())
 			// Since manager create goroutines for Projection, we have to give up the CPU for
 			// events channel to happen
 			<-time.After(time.Second)
@@ -241,6 +261,649 @@ var _ = Describe("StoreBasedManager", func() {
 			// Assert the projection expectations. i.e. events are handled
 			mockProjection.AssertExpectations(GinkgoT())
 		})
+
+		It("should wake projection up when the event store notifies the latest height", func() {
+			// Setup
+			mockEventStore := NewMockSubscribableEventStore()
+			manager := projection.NewStoreBasedManager(NewFakeLogger(), mockEventStore)
+			mockProjection := NewMockProjection()
+
+			// BlockEvent setup
+			anyEvent := newAnyEvent()
+
+			// Projection setup
+			anyProjectionId := "ANY_PROJECTION_ID"
+			mockProjection.On("Id").Return(anyProjectionId)
+			mockProjection.On("GetEventsToListen").Return([]string{anyEvent.Name()})
+			mockProjection.On("GetLastHandledEventHeight").Return(
+				primptr.Int64(0), nil,
+			)
+
+			// Register the Projection
+			err := manager.RegisterProjection(mockProjection)
+			Expect(err).To(BeNil())
+
+			// Event store has nothing new at first
+			nextHeight := int64(1)
+			mockEventStore.On("GetLatestHeight").Return(primptr.Int64(int64(0)), nil).Once()
+			mockEventStore.On("GetLatestHeight").Return(primptr.Int64(nextHeight), nil)
+			mockEventStore.On("GetAllByHeight", nextHeight).Return(
+				[]entity_event.Event{anyEvent}, nil,
+			)
+
+			// Define the assertion expectations
+			mockProjection.On("HandleEvents", nextHeight, mock.MatchedBy(func(events interface{}) bool {
+				typedEvents, _ := events.([]entity_event.Event)
+				return len(typedEvents) == 1 && typedEvents[0].Name() == anyEvent.Name()
+			})).Once().Return(nil)
+
+			// RunInBackground the manager
+			manager.RunInBackground(context.Background())
+			<-time.After(100 * time.Millisecond)
+
+			// Notify the new height, the projection should not wait for the next polling
+			mockEventStore.LatestHeightCh <- nextHeight
+			<-time.After(time.Second)
+
+			// Assert the projection expectations. i.e. events are handled
+			mockProjection.AssertExpectations(GinkgoT())
+		})
+
+		It("should replay a height range with a single query when the event store supports range replay", func() {
+			// Setup
+			mockEventStore := NewMockRangeEventStore()
+			manager := projection.NewStoreBasedManager(NewFakeLogger(), mockEventStore)
+			mockProjection := NewMockProjection()
+
+			// BlockEvent setup
+			anyEvent := newAnyEventAtHeight(int64(2))
+
+			// Projection setup
+			anyProjectionId := "ANY_PROJECTION_ID"
+			mockProjection.On("Id").Return(anyProjectionId)
+			mockProjection.On("GetEventsToListen").Return([]string{anyEvent.Name()})
+			mockProjection.On("GetLastHandledEventHeight").Return(
+				primptr.Int64(0), nil,
+			)
+
+			// Register the Projection
+			err := manager.RegisterProjection(mockProjection)
+			Expect(err).To(BeNil())
+
+			// Produce event to the event store
+			mockEventStore.On("GetLatestHeight").Return(primptr.Int64(int64(3)), nil)
+			mockEventStore.On("StreamByHeightRange", int64(1), int64(3), []string{anyEvent.Name()}).Return(
+				[]entity_event.Event{anyEvent}, nil,
+			).Once()
+
+			// Define the assertion expectations
+			mockProjection.On("HandleEvents", int64(1), []entity_event.Event{}).Once().Return(nil)
+			mockProjection.On("HandleEvents", int64(2), []entity_event.Event{anyEvent}).Once().Return(nil)
+			mockProjection.On("HandleEvents", int64(3), []entity_event.Event{}).Once().Return(nil)
+
+			// RunInBackground the manager
+			manager.RunInBackground(context.Background())
+			<-time.After(time.Second)
+
+			// Assert the projection and event store expectations
+			mockProjection.AssertExpectations(GinkgoT())
+			mockEventStore.AssertExpectations(GinkgoT())
+			mockEventStore.AssertNotCalled(GinkgoT(), "GetAllByHeight", mock.Anything)
+		})
+
+		It("should pass the events of the whole height range to batch projection", func() {
+			// Setup
+			mockEventStore := NewMockRangeEventStore()
+			manager := projection.NewStoreBasedManager(
+				NewFakeLogger(), mockEventStore,
+			).WithReplayRangeSize(2)
+			mockProjection := NewMockBatchProjection()
+
+			// BlockEvent setup
+			anyEvent := newAnyEventAtHeight(int64(2))
+
+			// Projection setup
+			anyProjectionId := "ANY_PROJECTION_ID"
+			mockProjection.On("Id").Return(anyProjectionId)
+			mockProjection.On("GetEventsToListen").Return([]string{anyEvent.Name()})
+			mockProjection.On("GetLastHandledEventHeight").Return(
+				primptr.Int64(0), nil,
+			)
+
+			// Register the Projection
+			err := manager.RegisterProjection(mockProjection)
+			Expect(err).To(BeNil())
+
+			// Produce event to the event store
+			mockEventStore.On("GetLatestHeight").Return(primptr.Int64(int64(3)), nil)
+			mockEventStore.On("StreamByHeightRange", int64(1), int64(2), []string{anyEvent.Name()}).Return(
+				[]entity_event.Event{anyEvent}, nil,
+			).Once()
+			mockEventStore.On("StreamByHeightRange", int64(3), int64(3), []string{anyEvent.Name()}).Return(
+				[]entity_event.Event{}, nil,
+			).Once()
+
+			// Define the assertion expectations
+			mockProjection.On("HandleEventsBatch", int64(1), int64(2), []entity_event.Event{anyEvent}).Once().Return(nil)
+			mockProjection.On("HandleEventsBatch", int64(3), int64(3), []entity_event.Event{}).Once().Return(nil)
+
+			// RunInBackground the manager
+			manager.RunInBackground(context.Background())
+			<-time.After(time.Second)
+
+			// Assert the projection expectations. i.e. events are handled
+			mockProjection.AssertExpectations(GinkgoT())
+			mockProjection.AssertNotCalled(GinkgoT(), "HandleEvents", mock.Anything, mock.Anything)
+		})
+
+		It("should pass a height to projection only after its dependencies have handled it", func() {
+			// Setup
+			mockEventStore := NewMockEventStore()
+			manager := projection.NewStoreBasedManager(NewFakeLogger(), mockEventStore)
+			anyEvent := newAnyEvent()
+
+			var handledMutex sync.Mutex
+			handled := make([]string, 0)
+			recordHandled := func(handledHeight string) func(mock.Arguments) {
+				return func(_ mock.Arguments) {
+					handledMutex.Lock()
+					defer handledMutex.Unlock()
+					handled = append(handled, handledHeight)
+				}
+			}
+
+			// Dependency handles height 2 slowly
+			dependency := NewMockProjection()
+			dependency.On("Id").Return("DEPENDENCY")
+			dependency.On("GetEventsToListen").Return([]string{anyEvent.Name()})
+			dependency.On("GetLastHandledEventHeight").Return(primptr.Int64(1), nil)
+			dependency.On("HandleEvents", int64(2), mock.Anything).Once().WaitUntil(
+				time.After(500 * time.Millisecond),
+			).Run(recordHandled("DEPENDENCY:2")).Return(nil)
+
+			dependent := NewMockDependentProjection()
+			dependent.On("Id").Return("DEPENDENT")
+			dependent.On("GetDependencies").Return([]string{"DEPENDENCY"})
+			dependent.On("GetEventsToListen").Return([]string{anyEvent.Name()})
+			dependent.On("GetLastHandledEventHeight").Return(primptr.Int64(0), nil)
+			dependent.On("HandleEvents", int64(1), mock.Anything).Once().Run(
+				recordHandled("DEPENDENT:1"),
+			).Return(nil)
+			dependent.On("HandleEvents", int64(2), mock.Anything).Once().Run(
+				recordHandled("DEPENDENT:2"),
+			).Return(nil)
+
+			Expect(manager.RegisterProjection(dependent)).To(BeNil())
+			Expect(manager.RegisterProjection(dependency)).To(BeNil())
+
+			mockEventStore.On("GetAllByHeight", mock.Anything).Return([]entity_event.Event{anyEvent}, nil)
+			mockEventStore.On("GetLatestHeight").Return(primptr.Int64(int64(2)), nil)
+
+			manager.RunInBackground(context.Background())
+			<-time.After(2 * time.Second)
+
+			dependency.AssertExpectations(GinkgoT())
+			dependent.AssertExpectations(GinkgoT())
+			handledMutex.Lock()
+			defer handledMutex.Unlock()
+			Expect(handled).To(Equal([]string{"DEPENDENT:1", "DEPENDENCY:2", "DEPENDENT:2"}))
+		})
+
+		It("should follow the progress of the dependencies not registered in the progress store", func() {
+			// Setup
+			mockEventStore := NewMockEventStore()
+			mockProgressStore := NewMockProgressStore()
+			manager := projection.NewStoreBasedManager(
+				NewFakeLogger(), mockEventStore,
+			).WithProgressStore(mockProgressStore)
+			anyEvent := newAnyEvent()
+
+			dependent := NewMockDependentProjection()
+			dependent.On("Id").Return("DEPENDENT")
+			dependent.On("GetDependencies").Return([]string{"EXTERNAL_DEPENDENCY"})
+			dependent.On("GetEventsToListen").Return([]string{anyEvent.Name()})
+			dependent.On("GetLastHandledEventHeight").Return(primptr.Int64(0), nil)
+			dependent.On("HandleEvents", int64(1), mock.Anything).Once().Return(nil)
+
+			Expect(manager.RegisterProjection(dependent)).To(BeNil())
+
+			mockProgressStore.On("GetLastHandledEventHeight", "EXTERNAL_DEPENDENCY").Return(primptr.Int64(1), nil)
+			mockEventStore.On("GetAllByHeight", mock.Anything).Return([]entity_event.Event{anyEvent}, nil)
+			mockEventStore.On("GetLatestHeight").Return(primptr.Int64(int64(2)), nil)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			manager.RunInBackground(ctx)
+			<-time.After(500 * time.Millisecond)
+
+			dependent.AssertExpectations(GinkgoT())
+			dependent.AssertNotCalled(GinkgoT(), "HandleEvents", int64(2), mock.Anything)
+			mockProgressStore.AssertExpectations(GinkgoT())
+		})
+
+		It("should not handle any height without the lease of the projection", func() {
+			// Setup
+			mockEventStore := NewMockEventStore()
+			mockLeaser := NewMockLeaser()
+			manager := projection.NewStoreBasedManager(NewFakeLogger(), mockEventStore).WithLeaser(mockLeaser)
+			mockProjection := NewMockProjection()
+			anyEvent := newAnyEvent()
+
+			mockProjection.On("Id").Return("ANY_PROJECTION_ID")
+			mockProjection.On("GetEventsToListen").Return([]string{anyEvent.Name()})
+			mockProjection.On("GetLastHandledEventHeight").Return(primptr.Int64(0), nil)
+
+			mockLeaser.On("TryAcquire", "ANY_PROJECTION_ID").Return(nil, nil)
+			mockEventStore.On("GetAllByHeight", int64(1)).Return([]entity_event.Event{anyEvent}, nil)
+			mockEventStore.On("GetLatestHeight").Return(primptr.Int64(int64(1)), nil)
+
+			Expect(manager.RegisterProjection(mockProjection)).To(BeNil())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			manager.RunInBackground(ctx)
+			<-time.After(500 * time.Millisecond)
+
+			mockLeaser.AssertExpectations(GinkgoT())
+			mockProjection.AssertNotCalled(GinkgoT(), "HandleEvents", mock.Anything, mock.Anything)
+		})
+
+		It("should handle heights while holding the lease and release it once stopped", func() {
+			// Setup
+			mockEventStore := NewMockEventStore()
+			mockLeaser := NewMockLeaser()
+			mockLease := NewMockLease()
+			manager := projection.NewStoreBasedManager(NewFakeLogger(), mockEventStore).WithLeaser(mockLeaser)
+			mockProjection := NewMockProjection()
+			anyEvent := newAnyEvent()
+
+			mockProjection.On("Id").Return("ANY_PROJECTION_ID")
+			mockProjection.On("GetEventsToListen").Return([]string{anyEvent.Name()})
+			mockProjection.On("GetLastHandledEventHeight").Return(primptr.Int64(0), nil)
+			mockProjection.On("HandleEvents", int64(1), mock.Anything).Once().Return(nil)
+
+			mockLeaser.On("TryAcquire", "ANY_PROJECTION_ID").Once().Return(mockLease, nil)
+			mockLease.On("Release").Once().Return(nil)
+			mockEventStore.On("GetAllByHeight", int64(1)).Return([]entity_event.Event{anyEvent}, nil)
+			mockEventStore.On("GetLatestHeight").Return(primptr.Int64(int64(1)), nil)
+
+			Expect(manager.RegisterProjection(mockProjection)).To(BeNil())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			manager.RunInBackground(ctx)
+			<-time.After(500 * time.Millisecond)
+
+			mockProjection.AssertExpectations(GinkgoT())
+
+			cancel()
+			manager.Wait()
+
+			mockLeaser.AssertExpectations(GinkgoT())
+			mockLease.AssertExpectations(GinkgoT())
+		})
+
+		It("should fence the fenced projection with the lease held and unset it once stopped", func() {
+			// Setup
+			mockEventStore := NewMockEventStore()
+			mockLeaser := NewMockLeaser()
+			mockLease := NewMockLease()
+			manager := projection.NewStoreBasedManager(NewFakeLogger(), mockEventStore).WithLeaser(mockLeaser)
+			mockProjection := NewMockFencedProjection()
+			anyEvent := newAnyEvent()
+
+			mockProjection.On("Id").Return("ANY_PROJECTION_ID")
+			mockProjection.On("GetEventsToListen").Return([]string{anyEvent.Name()})
+			mockProjection.On("GetLastHandledEventHeight").Return(primptr.Int64(0), nil)
+			mockProjection.On("HandleEvents", int64(1), mock.Anything).Once().Return(nil)
+			mockProjection.On("SetLease", mockLease).Return()
+			mockProjection.On("SetLease", nil).Once().Return()
+
+			mockLeaser.On("TryAcquire", "ANY_PROJECTION_ID").Once().Return(mockLease, nil)
+			mockLease.On("Release").Once().Return(nil)
+			mockEventStore.On("GetAllByHeight", int64(1)).Return([]entity_event.Event{anyEvent}, nil)
+			mockEventStore.On("GetLatestHeight").Return(primptr.Int64(int64(1)), nil)
+
+			Expect(manager.RegisterProjection(mockProjection)).To(BeNil())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			manager.RunInBackground(ctx)
+			<-time.After(500 * time.Millisecond)
+
+			mockProjection.AssertCalled(GinkgoT(), "SetLease", mockLease)
+			mockProjection.AssertNotCalled(GinkgoT(), "SetLease", nil)
+
+			cancel()
+			manager.Wait()
+
+			mockProjection.AssertExpectations(GinkgoT())
+			mockLease.AssertExpectations(GinkgoT())
+		})
+
+		It("should stop handling the height range once the lease is lost", func() {
+			// Setup
+			mockEventStore := NewMockRangeEventStore()
+			mockLeaser := NewMockLeaser()
+			mockLease := NewMockLease()
+			manager := projection.NewStoreBasedManager(NewFakeLogger(), mockEventStore).WithLeaser(mockLeaser)
+			mockProjection := NewMockProjection()
+			anyEvent := newAnyEvent()
+
+			mockProjection.On("Id").Return("ANY_PROJECTION_ID")
+			mockProjection.On("GetEventsToListen").Return([]string{anyEvent.Name()})
+			mockProjection.On("GetLastHandledEventHeight").Return(primptr.Int64(0), nil)
+			mockProjection.On("HandleEvents", int64(1), mock.Anything).Once().Run(func(_ mock.Arguments) {
+				close(mockLease.LostCh)
+			}).Return(nil)
+
+			mockLeaser.On("TryAcquire", "ANY_PROJECTION_ID").Once().Return(mockLease, nil)
+			mockLeaser.On("TryAcquire", "ANY_PROJECTION_ID").Return(nil, nil)
+			mockLease.On("Release").Return(nil)
+			mockEventStore.On("StreamByHeightRange", int64(1), int64(3), mock.Anything).Return(
+				[]entity_event.Event{}, nil,
+			)
+			mockEventStore.On("GetLatestHeight").Return(primptr.Int64(int64(3)), nil)
+
+			Expect(manager.RegisterProjection(mockProjection)).To(BeNil())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			manager.RunInBackground(ctx)
+			<-time.After(500 * time.Millisecond)
+
+			mockProjection.AssertNumberOfCalls(GinkgoT(), "HandleEvents", 1)
+			mockProjection.AssertNotCalled(GinkgoT(), "HandleEvents", int64(2), mock.Anything)
+		})
+
+		It("should halt projection and mark it as failed when the retries of HALT policy are exhausted", func() {
+			// Setup
+			mockEventStore := NewMockEventStore()
+			mockFailureStore := NewMockFailureStore()
+			manager := projection.NewStoreBasedManager(NewFakeLogger(), mockEventStore).WithFailureHandling(
+				projection.FailurePolicies{
+					Default: projection.FailurePolicy{
+						MaxRetry: 0,
+						Action:   projection.FAILURE_ACTION_HALT,
+					},
+				},
+				mockFailureStore,
+			)
+			mockProjection := NewMockProjection()
+			anyEvent := newAnyEvent()
+
+			mockProjection.On("Id").Return("ANY_PROJECTION_ID")
+			mockProjection.On("GetEventsToListen").Return([]string{anyEvent.Name()})
+			mockProjection.On("GetLastHandledEventHeight").Return(primptr.Int64(0), nil)
+			mockProjection.On("HandleEvents", int64(1), mock.Anything).Return(errors.New("any error"))
+
+			mockEventStore.On("GetAllByHeight", mock.Anything).Return([]entity_event.Event{anyEvent}, nil)
+			mockEventStore.On("GetLatestHeight").Return(primptr.Int64(int64(2)), nil)
+
+			mockFailureStore.On("MarkRunning", "ANY_PROJECTION_ID").Return(nil)
+			mockFailureStore.On("MarkFailed", "ANY_PROJECTION_ID", int64(1), "any error").Return(nil)
+
+			Expect(manager.RegisterProjection(mockProjection)).To(BeNil())
+
+			manager.RunInBackground(context.Background())
+
+			stoppedCh := make(chan bool)
+			go func() {
+				manager.Wait()
+				close(stoppedCh)
+			}()
+			Eventually(stoppedCh).Should(BeClosed())
+
+			mockFailureStore.AssertExpectations(GinkgoT())
+			mockProjection.AssertNumberOfCalls(GinkgoT(), "HandleEvents", 1)
+			mockProjection.AssertNotCalled(GinkgoT(), "HandleEvents", int64(2), mock.Anything)
+		})
+
+		It("should record dead letter and move on when the retries of SKIP policy are exhausted", func() {
+			// Setup
+			mockEventStore := NewMockEventStore()
+			mockFailureStore := NewMockFailureStore()
+			manager := projection.NewStoreBasedManager(NewFakeLogger(), mockEventStore).WithFailureHandling(
+				projection.FailurePolicies{
+					ByProjection: map[string]projection.FailurePolicy{
+						"ANY_PROJECTION_ID": {
+							MaxRetry: 0,
+							Action:   projection.FAILURE_ACTION_SKIP,
+						},
+					},
+				},
+				mockFailureStore,
+			)
+			mockProjection := NewMockProjection()
+			anyEvent := newAnyEvent()
+
+			mockProjection.On("Id").Return("ANY_PROJECTION_ID")
+			mockProjection.On("GetEventsToListen").Return([]string{anyEvent.Name()})
+			mockProjection.On("GetLastHandledEventHeight").Return(primptr.Int64(0), nil)
+			mockProjection.On("HandleEvents", int64(1), mock.Anything).Return(errors.New("any error"))
+			mockProjection.On("HandleEvents", int64(2), mock.Anything).Return(nil)
+
+			mockEventStore.On("GetAllByHeight", mock.Anything).Return([]entity_event.Event{anyEvent}, nil)
+			mockEventStore.On("GetLatestHeight").Return(primptr.Int64(int64(2)), nil)
+
+			mockFailureStore.On("MarkRunning", "ANY_PROJECTION_ID").Return(nil)
+			mockFailureStore.On("InsertDeadLetter", &projection.DeadLetter{
+				ProjectionId: "ANY_PROJECTION_ID",
+				Height:       1,
+				Events:       []entity_event.Event{anyEvent},
+				Error:        "any error",
+			}).Return(nil)
+
+			Expect(manager.RegisterProjection(mockProjection)).To(BeNil())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			manager.RunInBackground(ctx)
+			<-time.After(500 * time.Millisecond)
+
+			mockFailureStore.AssertExpectations(GinkgoT())
+			mockProjection.AssertNumberOfCalls(GinkgoT(), "HandleEvents", 2)
+		})
+
+		It("should stop projections when the context is cancelled", func() {
+			// Setup
+			mockEventStore := NewMockEventStore()
+			manager := projection.NewStoreBasedManager(NewFakeLogger(), mockEventStore)
+			mockProjection := NewMockProjection()
+
+			// Projection setup
+			anyProjectionId := "ANY_PROJECTION_ID"
+			mockProjection.On("Id").Return(anyProjectionId)
+			mockProjection.On("GetEventsToListen").Return([]string{})
+			mockProjection.On("GetLastHandledEventHeight").Return(
+				primptr.Int64(0), nil,
+			)
+			mockEventStore.On("GetLatestHeight").Return(primptr.Int64Nil(), nil)
+
+			// Register the Projection
+			err := manager.RegisterProjection(mockProjection)
+			Expect(err).To(BeNil())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			manager.RunInBackground(ctx)
+			cancel()
+
+			stoppedCh := make(chan bool)
+			go func() {
+				manager.Wait()
+				close(stoppedCh)
+			}()
+			Eventually(stoppedCh).Should(BeClosed())
+		})
+	})
+
+	Describe("PauseProjection", func() {
+		It("should return ErrProjectionNotFound when the projection is not registered", func() {
+			manager := projection.NewStoreBasedManager(NewFakeLogger(), NewFakeEventStore())
+
+			Expect(manager.PauseProjection("ANY_PROJECTION_ID")).To(MatchError(projection.ErrProjectionNotFound))
+		})
+
+		It("should not handle any height while paused and continue after resumed", func() {
+			// Setup
+			mockEventStore := NewMockEventStore()
+			manager := projection.NewStoreBasedManager(NewFakeLogger(), mockEventStore)
+			mockProjection := NewMockProjection()
+			anyEvent := newAnyEvent()
+
+			mockProjection.On("Id").Return("ANY_PROJECTION_ID")
+			mockProjection.On("GetEventsToListen").Return([]string{anyEvent.Name()})
+			mockProjection.On("GetLastHandledEventHeight").Return(primptr.Int64(0), nil)
+			mockProjection.On("HandleEvents", int64(1), mock.Anything).Return(nil)
+
+			mockEventStore.On("GetAllByHeight", int64(1)).Return([]entity_event.Event{anyEvent}, nil)
+			mockEventStore.On("GetLatestHeight").Return(primptr.Int64(int64(1)), nil)
+
+			Expect(manager.RegisterProjection(mockProjection)).To(BeNil())
+			Expect(manager.PauseProjection("ANY_PROJECTION_ID")).To(Succeed())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			manager.RunInBackground(ctx)
+			<-time.After(500 * time.Millisecond)
+
+			mockProjection.AssertNotCalled(GinkgoT(), "HandleEvents", int64(1), mock.Anything)
+
+			Expect(manager.ResumeProjection("ANY_PROJECTION_ID")).To(Succeed())
+			<-time.After(500 * time.Millisecond)
+
+			mockProjection.AssertNumberOfCalls(GinkgoT(), "HandleEvents", 1)
+		})
+	})
+
+	Describe("RewindProjection", func() {
+		It("should return ErrProjectionNotPaused when the projection is not paused", func() {
+			manager := projection.NewStoreBasedManager(NewFakeLogger(), NewFakeEventStore())
+
+			Expect(manager.RegisterProjection(NewFakeProjection())).To(BeNil())
+
+			Expect(manager.RewindProjection("FakeProjection", 0)).To(MatchError(projection.ErrProjectionNotPaused))
+		})
+
+		It("should return ErrRewindUnsupported when the projection does not support rewind", func() {
+			manager := projection.NewStoreBasedManager(NewFakeLogger(), NewFakeEventStore())
+
+			Expect(manager.RegisterProjection(NewFakeProjection())).To(BeNil())
+			Expect(manager.PauseProjection("FakeProjection")).To(Succeed())
+
+			Expect(manager.RewindProjection("FakeProjection", 0)).To(MatchError(projection.ErrRewindUnsupported))
+		})
+
+		It("should handle the heights after the rewound height again after resumed", func() {
+			// Setup
+			mockEventStore := NewMockEventStore()
+			manager := projection.NewStoreBasedManager(NewFakeLogger(), mockEventStore)
+			mockProjection := NewMockRewindableProjection()
+			anyEvent := newAnyEvent()
+
+			mockProjection.On("Id").Return("ANY_PROJECTION_ID")
+			mockProjection.On("GetEventsToListen").Return([]string{anyEvent.Name()})
+			mockProjection.On("GetLastHandledEventHeight").Once().Return(primptr.Int64(3), nil)
+			mockProjection.On("Rewind", int64(1)).Once().Return(nil)
+			mockProjection.On("GetLastHandledEventHeight").Return(primptr.Int64(1), nil)
+			mockProjection.On("HandleEvents", mock.Anything, mock.Anything).Return(nil)
+
+			mockEventStore.On("GetAllByHeight", mock.Anything).Return([]entity_event.Event{anyEvent}, nil)
+			mockEventStore.On("GetLatestHeight").Return(primptr.Int64(int64(3)), nil)
+
+			Expect(manager.RegisterProjection(mockProjection)).To(BeNil())
+			Expect(manager.PauseProjection("ANY_PROJECTION_ID")).To(Succeed())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			manager.RunInBackground(ctx)
+
+			Expect(manager.RewindProjection("ANY_PROJECTION_ID", 1)).To(Succeed())
+			Expect(manager.ResumeProjection("ANY_PROJECTION_ID")).To(Succeed())
+			<-time.After(500 * time.Millisecond)
+
+			mockProjection.AssertExpectations(GinkgoT())
+			mockProjection.AssertCalled(GinkgoT(), "HandleEvents", int64(2), mock.Anything)
+			mockProjection.AssertCalled(GinkgoT(), "HandleEvents", int64(3), mock.Anything)
+			mockProjection.AssertNotCalled(GinkgoT(), "HandleEvents", int64(1), mock.Anything)
+		})
+	})
+
+	Describe("ReplayProjection", func() {
+		It("should replay the events from the next event height to the target height", func() {
+			mockEventStore := NewMockEventStore()
+			manager := projection.NewStoreBasedManager(NewFakeLogger(), mockEventStore)
+			mockProjection := NewMockProjection()
+
+			anyEvent := newAnyEvent()
+			anyOtherEvent := newAnyOtherEvent()
+
+			mockProjection.On("Id").Return("ANY_PROJECTION_ID")
+			mockProjection.On("GetEventsToListen").Return([]string{anyEvent.Name()})
+			mockProjection.On("GetLastHandledEventHeight").Return(primptr.Int64Nil(), nil)
+
+			mockEventStore.On("GetAllByHeight", int64(0)).Return([]entity_event.Event{}, nil)
+			mockEventStore.On("GetAllByHeight", int64(1)).Return(
+				[]entity_event.Event{anyEvent, anyOtherEvent}, nil,
+			)
+
+			mockProjection.On("HandleEvents", int64(0), []entity_event.Event{}).Once().Return(nil)
+			mockProjection.On("HandleEvents", int64(1), []entity_event.Event{anyEvent}).Once().Return(nil)
+
+			Expect(manager.ReplayProjection(context.Background(), mockProjection, int64(1))).To(Succeed())
+
+			mockProjection.AssertExpectations(GinkgoT())
+			mockEventStore.AssertNotCalled(GinkgoT(), "GetAllByHeight", int64(2))
+		})
+
+		It("should return the error of the failed height", func() {
+			mockEventStore := NewMockRangeEventStore()
+			manager := projection.NewStoreBasedManager(NewFakeLogger(), mockEventStore)
+			mockProjection := NewMockProjection()
+
+			anyEvent := newAnyEventAtHeight(int64(2))
+
+			mockProjection.On("Id").Return("ANY_PROJECTION_ID")
+			mockProjection.On("GetEventsToListen").Return([]string{anyEvent.Name()})
+			mockProjection.On("GetLastHandledEventHeight").Return(primptr.Int64(0), nil)
+
+			mockEventStore.On("StreamByHeightRange", int64(1), int64(2), []string{anyEvent.Name()}).Return(
+				[]entity_event.Event{anyEvent}, nil,
+			)
+
+			mockProjection.On("HandleEvents", int64(1), []entity_event.Event{}).Once().Return(nil)
+			mockProjection.On("HandleEvents", int64(2), []entity_event.Event{anyEvent}).Once().Return(
+				errors.New("any error"),
+			)
+
+			Expect(manager.ReplayProjection(context.Background(), mockProjection, int64(2))).To(
+				MatchError("error handling events of height 2: any error"),
+			)
+		})
+
+		It("should handle each height as soon as its events are streamed", func() {
+			mockEventStore := NewMockRangeEventStore()
+			manager := projection.NewStoreBasedManager(NewFakeLogger(), mockEventStore)
+			mockProjection := NewMockProjection()
+
+			anyEventAtHeight1 := newAnyEventAtHeight(int64(1))
+			anyEventAtHeight2 := newAnyEventAtHeight(int64(2))
+
+			mockProjection.On("Id").Return("ANY_PROJECTION_ID")
+			mockProjection.On("GetEventsToListen").Return([]string{anyEventAtHeight1.Name()})
+			mockProjection.On("GetLastHandledEventHeight").Return(primptr.Int64(0), nil)
+
+			// The stream breaks after an event of height 2, such that height 1 is known to be complete
+			mockEventStore.On("StreamByHeightRange", int64(1), int64(3), []string{anyEventAtHeight1.Name()}).Return(
+				[]entity_event.Event{anyEventAtHeight1, anyEventAtHeight2}, errors.New("any stream error"),
+			)
+
+			mockProjection.On("HandleEvents", int64(1), []entity_event.Event{anyEventAtHeight1}).Once().Return(nil)
+
+			Expect(manager.ReplayProjection(context.Background(), mockProjection, int64(3))).To(
+				MatchError("error getting events by height range: any stream error"),
+			)
+			mockProjection.AssertExpectations(GinkgoT())
+			mockProjection.AssertNotCalled(GinkgoT(), "HandleEvents", int64(2), mock.Anything)
+		})
 	})
 })
 
@@ -259,3 +922,12 @@ func newAnyOtherEvent() entity_event.Event {
 
 	return anyOtherEvent
 }
+
+func newAnyEventAtHeight(height int64) entity_event.Event {
+	anyEventName := "ANY_EVENT"
+	anyEvent := NewMockEvent()
+	anyEvent.On("Name").Return(anyEventName)
+	anyEvent.On("Height").Return(height)
+
+	return anyEvent
+}
@@ -26,3 +26,14 @@ type Projection interface {
 	// projection. It is also responsible to update the last handled event height.
 	HandleEvents(height int64, events []entity_event.Event) error
 }
+
+// BatchProjection is an optional interface of Projection to handle the events of a range of heights at once when
+// replaying from an event store supporting range replay
+type BatchProjection interface {
+	Projection
+
+	// Handle all events from `fromHeight` to `toHeight` inclusively that matches `GetEventsToListen()` in a single
+	// commit. Events are in height order and heights without events are omitted. It is also responsible to update the
+	// last handled event height to `toHeight`.
+	HandleEventsBatch(fromHeight int64, toHeight int64, events []entity_event.Event) error
+}
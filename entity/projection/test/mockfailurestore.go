@@ -0,0 +1,39 @@
+package test
+
+import (
+	"github.com/stretchr/testify/mock"
+
+	"github.com/crypto-com/chain-indexing/entity/projection"
+)
+
+type MockFailureStore struct {
+	mock.Mock
+}
+
+func NewMockFailureStore() *MockFailureStore {
+	return &MockFailureStore{}
+}
+
+func (store *MockFailureStore) MarkRunning(projectionId string) error {
+	mockArgs := store.Called(projectionId)
+
+	return mockArgs.Error(0)
+}
+
+func (store *MockFailureStore) MarkPaused(projectionId string) error {
+	mockArgs := store.Called(projectionId)
+
+	return mockArgs.Error(0)
+}
+
+func (store *MockFailureStore) MarkFailed(projectionId string, height int64, errMessage string) error {
+	mockArgs := store.Called(projectionId, height, errMessage)
+
+	return mockArgs.Error(0)
+}
+
+func (store *MockFailureStore) InsertDeadLetter(deadLetter *projection.DeadLetter) error {
+	mockArgs := store.Called(deadLetter)
+
+	return mockArgs.Error(0)
+}
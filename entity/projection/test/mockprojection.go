@@ -43,3 +43,49 @@ func (projection *MockProjection) HandleEvents(height int64, events []entity_eve
 
 	return mockArgs.Error(0)
 }
+
+type MockBatchProjection struct {
+	MockProjection
+}
+
+func NewMockBatchProjection() *MockBatchProjection {
+	return &MockBatchProjection{}
+}
+
+func (projection *MockBatchProjection) HandleEventsBatch(
+	fromHeight int64,
+	toHeight int64,
+	events []entity_event.Event,
+) error {
+	mockArgs := projection.Called(fromHeight, toHeight, events)
+
+	return mockArgs.Error(0)
+}
+
+type MockDependentProjection struct {
+	MockProjection
+}
+
+func NewMockDependentProjection() *MockDependentProjection {
+	return &MockDependentProjection{}
+}
+
+func (projection *MockDependentProjection) GetDependencies() []string {
+	mockArgs := projection.Called()
+
+	return mockArgs.Get(0).([]string)
+}
+
+type MockRewindableProjection struct {
+	MockProjection
+}
+
+func NewMockRewindableProjection() *MockRewindableProjection {
+	return &MockRewindableProjection{}
+}
+
+func (projection *MockRewindableProjection) Rewind(height int64) error {
+	mockArgs := projection.Called(height)
+
+	return mockArgs.Error(0)
+}
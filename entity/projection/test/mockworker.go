@@ -0,0 +1,76 @@
+package test
+
+import (
+	"github.com/stretchr/testify/mock"
+
+	"github.com/crypto-com/chain-indexing/entity/projection"
+)
+
+type MockProgressStore struct {
+	mock.Mock
+}
+
+func NewMockProgressStore() *MockProgressStore {
+	return &MockProgressStore{}
+}
+
+func (store *MockProgressStore) GetLastHandledEventHeight(projectionId string) (*int64, error) {
+	mockArgs := store.Called(projectionId)
+
+	return mockArgs.Get(0).(*int64), mockArgs.Error(1)
+}
+
+type MockLeaser struct {
+	mock.Mock
+}
+
+func NewMockLeaser() *MockLeaser {
+	return &MockLeaser{}
+}
+
+func (leaser *MockLeaser) TryAcquire(projectionId string) (projection.Lease, error) {
+	mockArgs := leaser.Called(projectionId)
+
+	lease, _ := mockArgs.Get(0).(projection.Lease)
+	return lease, mockArgs.Error(1)
+}
+
+type MockLease struct {
+	mock.Mock
+
+	LostCh chan struct{}
+}
+
+func NewMockLease() *MockLease {
+	return &MockLease{
+		LostCh: make(chan struct{}),
+	}
+}
+
+func (lease *MockLease) Holder() string {
+	return "MockLease"
+}
+
+func (lease *MockLease) Lost() <-chan struct{} {
+	return lease.LostCh
+}
+
+func (lease *MockLease) Release() error {
+	mockArgs := lease.Called()
+
+	return mockArgs.Error(0)
+}
+
+type MockFencedProjection struct {
+	*MockProjection
+}
+
+func NewMockFencedProjection() *MockFencedProjection {
+	return &MockFencedProjection{
+		NewMockProjection(),
+	}
+}
+
+func (projection *MockFencedProjection) SetLease(maybeLease projection.Lease) {
+	projection.Called(maybeLease)
+}
@@ -0,0 +1,31 @@
+package projection
+
+// ProgressStore reads the last handled event height of the projections run by other processes, such that a projection
+// can depend on a projection it does not run
+type ProgressStore interface {
+	GetLastHandledEventHeight(projectionId string) (*int64, error)
+}
+
+// Leaser grants one of the processes running the same projection the exclusive right to handle its events. The lease
+// of a crashed process expires such that another process takes over the projection.
+type Leaser interface {
+	// TryAcquire acquires the lease of the projection without waiting. Returns nil when the lease is held by another
+	// process. The acquired lease is kept renewed until it is released.
+	TryAcquire(projectionId string) (Lease, error)
+}
+
+type Lease interface {
+	// Holder identifies the process holding the lease
+	Holder() string
+	// Lost is closed when the lease has failed to be renewed before it expires
+	Lost() <-chan struct{}
+	// Release gives up the lease such that another process can take over the projection immediately
+	Release() error
+}
+
+// FencedProjection is a projection committing the handled heights only while the lease set is still held, such that
+// a process which has lost the lease without noticing yet cannot commit over the process taking over the projection
+type FencedProjection interface {
+	// SetLease sets the lease of the projection held by the process, nil when the lease is not held
+	SetLease(maybeLease Lease)
+}
@@ -6,12 +6,16 @@ import (
 
 	"github.com/crypto-com/chain-indexing/appinterface/rdb"
 	configuration "github.com/crypto-com/chain-indexing/bootstrap/config"
+	event_entity "github.com/crypto-com/chain-indexing/entity/event"
 	projection_entity "github.com/crypto-com/chain-indexing/entity/projection"
 	applogger "github.com/crypto-com/chain-indexing/external/logger"
 	"github.com/crypto-com/chain-indexing/infrastructure/pg"
 	"github.com/crypto-com/chain-indexing/infrastructure/pg/migrationhelper"
 	github_migrationhelper "github.com/crypto-com/chain-indexing/infrastructure/pg/migrationhelper/github"
 	"github.com/crypto-com/chain-indexing/projection/bridge_activity/bridge_activity_matcher"
+	"github.com/crypto-com/chain-indexing/projection/event_digest_verifier"
+	"github.com/crypto-com/chain-indexing/projection/webhook_publisher"
+	event_usecase "github.com/crypto-com/chain-indexing/usecase/event"
 )
 
 func initCronJobs(
@@ -66,6 +70,33 @@ func InitCronJob(name string, params InitCronJobParams) projection_entity.CronJo
 		}
 
 		return bridge_activity_matcher.New(params.Logger, params.RdbConn, migrationHelper, config)
+	case "EventDigestVerifier":
+		config, err := event_digest_verifier.ConfigFromInterface(params.ExtraConfigs[name])
+		if err != nil {
+			params.Logger.Panicf(err.Error())
+		}
+
+		return event_digest_verifier.New(config, params.Logger, params.RdbConn)
+	case "WebhookPublisher":
+		sourceURL := github_migrationhelper.GenerateSourceURL(
+			github_migrationhelper.MIGRATION_GITHUB_URL_FORMAT,
+			params.GithubAPIUser,
+			params.GithubAPIToken,
+			webhook_publisher.MIGRATION_DIRECOTRY,
+			params.MigrationRepoRef,
+		)
+		databaseURL := migrationhelper.GenerateDefaultDatabaseURL(name, connString)
+		migrationHelper := github_migrationhelper.NewGithubMigrationHelper(sourceURL, databaseURL)
+
+		config, err := webhook_publisher.ConfigFromInterface(params.ExtraConfigs[name])
+		if err != nil {
+			params.Logger.Panicf(err.Error())
+		}
+
+		eventRegistry := event_entity.NewRegistry()
+		event_usecase.RegisterEvents(eventRegistry)
+
+		return webhook_publisher.New(config, params.Logger, params.RdbConn, eventRegistry, migrationHelper)
 	// register more cronjobs here
 	default:
 		panic(fmt.Sprintf("Unrecognized cron job: %s", name))
@@ -38,38 +38,12 @@ func initProjections(
 	config *configuration.Config,
 	customConfig *CustomConfig,
 ) []projection_entity.Projection {
-	if !config.IndexService.Enable {
+	if !config.IndexService.Enable || config.IndexService.Role == configuration.PROCESS_ROLE_API_ONLY {
 		return []projection_entity.Projection{}
 	}
 
-	var cosmosAppClient cosmosapp.Client
-	if config.CosmosApp.Insecure {
-		cosmosAppClient = cosmosapp_infrastructure.NewInsecureHTTPClient(
-			config.CosmosApp.HTTPRPCUrl, config.Blockchain.BondingDenom,
-		)
-	} else {
-		cosmosAppClient = cosmosapp_infrastructure.NewHTTPClient(
-			config.CosmosApp.HTTPRPCUrl, config.Blockchain.BondingDenom,
-		)
-	}
-
 	projections := make([]projection_entity.Projection, 0, len(config.IndexService.Projection.Enables))
-	initParams := InitProjectionParams{
-		Logger:  logger,
-		RdbConn: rdbConn,
-
-		ExtraConfigs: config.IndexService.Projection.ExtraConfigs,
-
-		CosmosAppClient:       cosmosAppClient,
-		AccountAddressPrefix:  config.Blockchain.AccountAddressPrefix,
-		ConsNodeAddressPrefix: config.Blockchain.ConNodeAddressPrefix,
-
-		GithubAPIUser:    config.IndexService.GithubAPI.Username,
-		GithubAPIToken:   config.IndexService.GithubAPI.Token,
-		MigrationRepoRef: config.IndexService.GithubAPI.MigrationRepoRef,
-
-		ServerMigrationRepoRef: customConfig.ServerGithubAPI.MigrationRepoRef,
-	}
+	initParams := newInitProjectionParams(logger, rdbConn, config, customConfig)
 	for _, projectionName := range config.IndexService.Projection.Enables {
 		projection := InitProjection(
 			projectionName, initParams,
@@ -112,6 +86,41 @@ func initProjections(
 	return projections
 }
 
+func newInitProjectionParams(
+	logger applogger.Logger,
+	rdbConn rdb.Conn,
+	config *configuration.Config,
+	customConfig *CustomConfig,
+) InitProjectionParams {
+	var cosmosAppClient cosmosapp.Client
+	if config.CosmosApp.Insecure {
+		cosmosAppClient = cosmosapp_infrastructure.NewInsecureHTTPClient(
+			config.CosmosApp.HTTPRPCUrl, config.Blockchain.BondingDenom,
+		)
+	} else {
+		cosmosAppClient = cosmosapp_infrastructure.NewHTTPClient(
+			config.CosmosApp.HTTPRPCUrl, config.Blockchain.BondingDenom,
+		)
+	}
+
+	return InitProjectionParams{
+		Logger:  logger,
+		RdbConn: rdbConn,
+
+		ExtraConfigs: config.IndexService.Projection.ExtraConfigs,
+
+		CosmosAppClient:       cosmosAppClient,
+		AccountAddressPrefix:  config.Blockchain.AccountAddressPrefix,
+		ConsNodeAddressPrefix: config.Blockchain.ConNodeAddressPrefix,
+
+		GithubAPIUser:    config.IndexService.GithubAPI.Username,
+		GithubAPIToken:   config.IndexService.GithubAPI.Token,
+		MigrationRepoRef: config.IndexService.GithubAPI.MigrationRepoRef,
+
+		ServerMigrationRepoRef: customConfig.ServerGithubAPI.MigrationRepoRef,
+	}
+}
+
 func InitAdditionalProjection(name string, params InitProjectionParams) projection_entity.Projection {
 	connString := params.RdbConn.(*pg.PgxConn).ConnString()
 
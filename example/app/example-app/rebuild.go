@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/urfave/cli/v2"
+
+	event_interface "github.com/crypto-com/chain-indexing/appinterface/event"
+	"github.com/crypto-com/chain-indexing/appinterface/rdb"
+	"github.com/crypto-com/chain-indexing/bootstrap"
+	configuration "github.com/crypto-com/chain-indexing/bootstrap/config"
+	"github.com/crypto-com/chain-indexing/entity/event"
+	projection_entity "github.com/crypto-com/chain-indexing/entity/projection"
+	"github.com/crypto-com/chain-indexing/infrastructure"
+	"github.com/crypto-com/chain-indexing/infrastructure/pg"
+	event_usecase "github.com/crypto-com/chain-indexing/usecase/event"
+)
+
+func rebuildProjection(ctx *cli.Context) error {
+	config, customConfig, err := loadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	if config.IndexService.EventStore.Type == configuration.EVENT_STORE_TYPE_SEGMENT {
+		return fmt.Errorf("rebuilding projection from %s event store is not supported", config.IndexService.EventStore.Type)
+	}
+
+	logger := infrastructure.NewZerologLogger(os.Stdout)
+	logger.SetLogLevel(parseLogLevel(config.Logger.Level))
+
+	rdbConn, err := bootstrap.SetupRDbConn(config, logger)
+	if err != nil {
+		return fmt.Errorf("error setting up RDb connection: %v", err)
+	}
+	pgxConn := rdbConn.(*pg.PgxConn)
+	defer pgxConn.Close()
+
+	eventRegistry := event.NewRegistry()
+	event_usecase.RegisterEvents(eventRegistry)
+	eventStore := event_interface.NewRDbStore(rdbConn.ToHandle(), eventRegistry)
+
+	projectionId := ctx.String("id")
+	newProjection := func(projectionRDbConn rdb.Conn) (projection_entity.Projection, error) {
+		initParams := newInitProjectionParams(logger, projectionRDbConn, config, customConfig)
+		if projection := InitProjection(projectionId, initParams); projection != nil {
+			return projection, nil
+		}
+		if projection := InitAdditionalProjection(projectionId, initParams); projection != nil {
+			return projection, nil
+		}
+		return nil, fmt.Errorf("unknown projection `%s`", projectionId)
+	}
+
+	signalCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	rebuilder := bootstrap.NewProjectionRebuilder(logger, pgxConn, eventStore)
+	if ctx.Bool("shadow") {
+		return rebuilder.ShadowRebuild(signalCtx, projectionId, newProjection)
+	}
+	return rebuilder.Rebuild(signalCtx, projectionId, newProjection)
+}
@@ -1,16 +1,21 @@
 package routes
 
 import (
+	"time"
+
 	"github.com/crypto-com/chain-indexing/appinterface/cosmosapp"
+	"github.com/crypto-com/chain-indexing/appinterface/projection/rdbprojectionbase"
 	"github.com/crypto-com/chain-indexing/appinterface/rdb"
 	"github.com/crypto-com/chain-indexing/appinterface/tendermint"
 	"github.com/crypto-com/chain-indexing/bootstrap"
 	"github.com/crypto-com/chain-indexing/bootstrap/config"
+	event_entity "github.com/crypto-com/chain-indexing/entity/event"
 	custom_httpapi_handlers "github.com/crypto-com/chain-indexing/example/httpapi/handlers"
 	applogger "github.com/crypto-com/chain-indexing/external/logger"
 	cosmosapp_infrastructure "github.com/crypto-com/chain-indexing/infrastructure/cosmosapp"
 	httpapi_handlers "github.com/crypto-com/chain-indexing/infrastructure/httpapi/handlers"
 	tendermint_infrastructure "github.com/crypto-com/chain-indexing/infrastructure/tendermint"
+	event_usecase "github.com/crypto-com/chain-indexing/usecase/event"
 )
 
 func InitRouteRegistry(
@@ -354,6 +359,66 @@ func InitRouteRegistry(
 		},
 	)
 
+	eventRegistry := event_entity.NewRegistry()
+	event_usecase.RegisterEvents(eventRegistry)
+	eventSchemasHandler, err := httpapi_handlers.NewEventSchemas(logger, eventRegistry)
+	if err != nil {
+		logger.Panicf("error creating event schemas handler: %v", err)
+	}
+	routes = append(routes,
+		Route{
+			Method:  GET,
+			path:    "api/v1/event_schemas",
+			handler: eventSchemasHandler.List,
+		},
+		Route{
+			Method:  GET,
+			path:    "api/v1/event_schemas/{name}/{version}",
+			handler: eventSchemasHandler.FindBy,
+		},
+	)
+
+	var maxLagDuration time.Duration
+	if config.HTTPService.Readiness.MaxLagDuration != "" {
+		maxLagDuration, err = time.ParseDuration(config.HTTPService.Readiness.MaxLagDuration)
+		if err != nil {
+			logger.Panicf("error parsing readiness max lag duration: %v", err)
+		}
+	}
+	projectionStatusesHandler := httpapi_handlers.NewProjectionStatuses(
+		logger,
+		rdbConn.ToHandle(),
+		config.IndexService.Projection.Enables,
+		httpapi_handlers.ProjectionStatusesConfig{
+			ProgressTable:       rdbprojectionbase.DEFAULT_TABLE,
+			RequiredProjections: config.HTTPService.Readiness.RequiredProjections,
+			MaxLagBlocks:        config.HTTPService.Readiness.MaxLagBlocks,
+			MaxLagDuration:      maxLagDuration,
+		},
+	)
+	routes = append(routes,
+		Route{
+			Method:  GET,
+			path:    "api/v1/projection_statuses",
+			handler: projectionStatusesHandler.List,
+		},
+		Route{
+			Method:  GET,
+			path:    "api/v1/projection_statuses/{id}",
+			handler: projectionStatusesHandler.FindById,
+		},
+		Route{
+			Method:  GET,
+			path:    "api/v1/projection_statuses/{id}/dead_letters",
+			handler: projectionStatusesHandler.ListDeadLettersById,
+		},
+		Route{
+			Method:  GET,
+			path:    "api/v1/readiness",
+			handler: projectionStatusesHandler.Readiness,
+		},
+	)
+
 	exampleHandler := custom_httpapi_handlers.NewExample(
 		logger,
 		rdbConn.ToHandle(),
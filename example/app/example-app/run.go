@@ -87,64 +87,46 @@ func run(args []string) error {
 				EnvVars: []string{"COSMOSAPP_URL"},
 			},
 		},
+		Commands: []*cli.Command{
+			{
+				Name:  "rebuild-projection",
+				Usage: "Reset a projection and replay all the events from the event store",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "id",
+						Usage:    "Id of the projection to rebuild",
+						Required: true,
+					},
+					&cli.BoolFlag{
+						Name:  "shadow",
+						Usage: "Build into a temporary schema while the live tables keep serving, then swap atomically",
+					},
+				},
+				Action: rebuildProjection,
+			},
+		},
 		Action: func(ctx *cli.Context) error {
 			if args := ctx.Args(); args.Len() > 0 {
 				return fmt.Errorf("Unexpected arguments: %q", args.Get(0))
 			}
 
-			// Prepare FileConfig
-			configPath := ctx.String("config")
-			var config configuration.Config
-			err := yaml.FromYAMLFile(configPath, &config)
-			if err != nil {
-				return fmt.Errorf("error config from yaml: %v", err)
-			}
-
-			var customConfig CustomConfig
-			err = yaml.FromYAMLFile(configPath, &customConfig)
+			config, customConfig, err := loadConfig(ctx)
 			if err != nil {
-				return fmt.Errorf("error custom config from yaml: %v", err)
-			}
-
-			cliConfig := CLIConfig{
-				LogLevel: ctx.String("logLevel"),
-
-				DatabaseHost:     ctx.String("dbHost"),
-				DatabaseUsername: ctx.String("dbUsername"),
-				DatabasePassword: ctx.String("dbPassword"),
-				DatabaseName:     ctx.String("dbName"),
-				DatabaseSchema:   ctx.String("dbSchema"),
-
-				TendermintHTTPRPCUrl: ctx.String("tendermintURL"),
-				CosmosHTTPRPCUrl:     ctx.String("cosmosAppURL"),
-
-				GithubAPIUsername: ctx.String("githubAPIUsername"),
-				GithubAPIToken:    ctx.String("githubAPIToken"),
-			}
-			if ctx.IsSet("color") {
-				cliConfig.LoggerColor = primptr.Bool(ctx.Bool("color"))
-			}
-			if ctx.IsSet("dbSSL") {
-				cliConfig.DatabaseSSL = primptr.Bool(ctx.Bool("dbSSL"))
+				return err
 			}
-			if ctx.IsSet("dbPort") {
-				cliConfig.DatabasePort = primptr.Int32(int32(ctx.Int("dbPort")))
-			}
-
-			OverrideByCLIConfig(&config, &cliConfig)
 
 			// Create logger
 			logLevel := parseLogLevel(config.Logger.Level)
 			logger := infrastructure.NewZerologLogger(os.Stdout)
 			logger.SetLogLevel(logLevel)
 
-			app := bootstrap.NewApp(logger, &config)
+			app := bootstrap.NewApp(logger, config)
 
 			app.InitIndexService(
-				initProjections(logger, app.GetRDbConn(), &config, &customConfig),
-				initCronJobs(logger, app.GetRDbConn(), &config),
+				initProjections(logger, app.GetRDbConn(), config, customConfig),
+				initCronJobs(logger, app.GetRDbConn(), config),
 			)
-			app.InitHTTPAPIServer(routes.InitRouteRegistry(logger, app.GetRDbConn(), &config))
+			app.InitHTTPAPIServer(routes.InitRouteRegistry(logger, app.GetRDbConn(), config))
 
 			app.Run()
 
@@ -160,6 +142,52 @@ func run(args []string) error {
 	return nil
 }
 
+// loadConfig loads the configuration from the YAML file overridden by the CLI flags
+func loadConfig(ctx *cli.Context) (*configuration.Config, *CustomConfig, error) {
+	// Prepare FileConfig
+	configPath := ctx.String("config")
+	var config configuration.Config
+	err := yaml.FromYAMLFile(configPath, &config)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error config from yaml: %v", err)
+	}
+
+	var customConfig CustomConfig
+	err = yaml.FromYAMLFile(configPath, &customConfig)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error custom config from yaml: %v", err)
+	}
+
+	cliConfig := CLIConfig{
+		LogLevel: ctx.String("logLevel"),
+
+		DatabaseHost:     ctx.String("dbHost"),
+		DatabaseUsername: ctx.String("dbUsername"),
+		DatabasePassword: ctx.String("dbPassword"),
+		DatabaseName:     ctx.String("dbName"),
+		DatabaseSchema:   ctx.String("dbSchema"),
+
+		TendermintHTTPRPCUrl: ctx.String("tendermintURL"),
+		CosmosHTTPRPCUrl:     ctx.String("cosmosAppURL"),
+
+		GithubAPIUsername: ctx.String("githubAPIUsername"),
+		GithubAPIToken:    ctx.String("githubAPIToken"),
+	}
+	if ctx.IsSet("color") {
+		cliConfig.LoggerColor = primptr.Bool(ctx.Bool("color"))
+	}
+	if ctx.IsSet("dbSSL") {
+		cliConfig.DatabaseSSL = primptr.Bool(ctx.Bool("dbSSL"))
+	}
+	if ctx.IsSet("dbPort") {
+		cliConfig.DatabasePort = primptr.Int32(int32(ctx.Int("dbPort")))
+	}
+
+	OverrideByCLIConfig(&config, &cliConfig)
+
+	return &config, &customConfig, nil
+}
+
 func parseLogLevel(level string) applogger.LogLevel {
 	switch level {
 	case "panic":
@@ -46,6 +46,10 @@ func (_ *AdditionalExampleProjection) GetEventsToListen() []string {
 	return event_usecase.MSG_EVENTS
 }
 
+func (projection *AdditionalExampleProjection) MigrationHelper() migrationhelper.MigrationHelper {
+	return projection.migrationHelper
+}
+
 func (projection *AdditionalExampleProjection) OnInit() error {
 	if projection.migrationHelper != nil {
 		projection.migrationHelper.Migrate()
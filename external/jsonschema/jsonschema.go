@@ -0,0 +1,267 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const DRAFT_07 = "http://json-schema.org/draft-07/schema#"
+
+// Schema is a JSON Schema (draft-07) of the JSON encoding of a Go type
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Id                   string             `json:"$id,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Type                 interface{}        `json:"type,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties interface{}        `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// Reflect returns the JSON Schema of the JSON encoding of the Go type following the rules of encoding/json: embedded
+// structs are flattened, fields tagged with `omitempty` are optional and pointers, slices and maps are nullable except
+// the root pointer. Types implementing json.Marshaler are described by the JSON type of their encoded zero value.
+func Reflect(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	schema := newReflector().reflect(t)
+	schema.Schema = DRAFT_07
+
+	return schema
+}
+
+type reflector struct {
+	// Types being reflected, which are described as any value when referenced recursively
+	visiting map[reflect.Type]bool
+}
+
+func newReflector() *reflector {
+	return &reflector{
+		visiting: make(map[reflect.Type]bool),
+	}
+}
+
+func (r *reflector) reflect(t reflect.Type) *Schema {
+	if t.Kind() == reflect.Ptr {
+		return nullable(r.reflect(t.Elem()))
+	}
+
+	if t.Implements(jsonMarshalerType) || reflect.PtrTo(t).Implements(jsonMarshalerType) {
+		return &Schema{Type: marshaledJSONType(t)}
+	}
+	if t.Implements(textMarshalerType) || reflect.PtrTo(t).Implements(textMarshalerType) {
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// Base64 encoded
+			return nullable(&Schema{Type: "string"})
+		}
+		return nullable(&Schema{
+			Type:  "array",
+			Items: r.reflect(t.Elem()),
+		})
+	case reflect.Array:
+		return &Schema{
+			Type:  "array",
+			Items: r.reflect(t.Elem()),
+		}
+	case reflect.Map:
+		return nullable(&Schema{
+			Type:                 "object",
+			AdditionalProperties: r.reflect(t.Elem()),
+		})
+	case reflect.Struct:
+		return r.reflectStruct(t)
+	default:
+		// Interfaces can be of any value
+		return &Schema{}
+	}
+}
+
+func (r *reflector) reflectStruct(t reflect.Type) *Schema {
+	if r.visiting[t] {
+		return &Schema{}
+	}
+	r.visiting[t] = true
+	defer delete(r.visiting, t)
+
+	fields := make([]structField, 0)
+	r.collectFields(t, 0, &fields)
+
+	schema := &Schema{
+		Type:                 "object",
+		Properties:           make(map[string]*Schema),
+		AdditionalProperties: false,
+	}
+	for _, field := range fields {
+		schema.Properties[field.name] = field.schema
+		if field.required {
+			schema.Required = append(schema.Required, field.name)
+		}
+	}
+
+	return schema
+}
+
+type structField struct {
+	name     string
+	depth    int
+	required bool
+	schema   *Schema
+}
+
+// collectFields collects the encoded fields of the struct in declaration order. As encoding/json, a field of an
+// embedded struct is hidden by the field of the same name at a shallower depth.
+func (r *reflector) collectFields(t reflect.Type, depth int, fields *[]structField) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options := parseTag(tag)
+
+		if field.Anonymous && name == "" {
+			embeddedType := field.Type
+			if embeddedType.Kind() == reflect.Ptr {
+				embeddedType = embeddedType.Elem()
+			}
+			if embeddedType.Kind() == reflect.Struct {
+				r.collectFields(embeddedType, depth+1, fields)
+				continue
+			}
+		}
+		if field.PkgPath != "" {
+			// Unexported
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		var fieldSchema *Schema
+		if options.Contains("string") {
+			fieldSchema = &Schema{Type: "string"}
+		} else {
+			fieldSchema = r.reflect(field.Type)
+		}
+		collected := structField{
+			name:     name,
+			depth:    depth,
+			required: !options.Contains("omitempty"),
+			schema:   fieldSchema,
+		}
+
+		hidden := false
+		for j, existing := range *fields {
+			if existing.name != name {
+				continue
+			}
+			if depth < existing.depth {
+				(*fields)[j] = collected
+			}
+			hidden = true
+			break
+		}
+		if !hidden {
+			*fields = append(*fields, collected)
+		}
+	}
+}
+
+func nullable(schema *Schema) *Schema {
+	switch schemaType := schema.Type.(type) {
+	case string:
+		schema.Type = []string{schemaType, "null"}
+	case nil:
+		// Any value includes null
+	}
+
+	return schema
+}
+
+// marshaledJSONType returns the JSON type of the zero value encoded with its MarshalJSON, any value when it cannot be
+// encoded
+func marshaledJSONType(t reflect.Type) (jsonType interface{}) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			jsonType = nil
+		}
+	}()
+
+	encoded, err := json.Marshal(reflect.New(t).Interface())
+	if err != nil {
+		return nil
+	}
+	encoded = bytes.TrimSpace(encoded)
+	if len(encoded) == 0 {
+		return nil
+	}
+
+	switch encoded[0] {
+	case '"':
+		return "string"
+	case '{':
+		return "object"
+	case '[':
+		return "array"
+	case 't', 'f':
+		return "boolean"
+	case 'n':
+		return nil
+	default:
+		return "number"
+	}
+}
+
+type tagOptions string
+
+func parseTag(tag string) (string, tagOptions) {
+	if i := strings.Index(tag, ","); i != -1 {
+		return tag[:i], tagOptions(tag[i+1:])
+	}
+	return tag, ""
+}
+
+func (options tagOptions) Contains(name string) bool {
+	for _, option := range strings.Split(string(options), ",") {
+		if option == name {
+			return true
+		}
+	}
+	return false
+}
+
+// MustMarshal returns the indented JSON of the schema with the properties sorted by name
+func (schema *Schema) MustMarshal() []byte {
+	encoded, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		panic(fmt.Sprintf("error encoding schema: %v", err))
+	}
+
+	return append(encoded, '\n')
+}
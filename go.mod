@@ -18,6 +18,7 @@ require (
 	github.com/golang-migrate/migrate/v4 v4.15.1
 	github.com/google/go-querystring v1.0.0
 	github.com/google/uuid v1.3.0
+	github.com/gorilla/websocket v1.4.2
 	github.com/gravity-devs/liquidity v1.4.5
 	github.com/jackc/pgconn v1.8.0
 	github.com/jackc/pgtype v1.6.2
@@ -80,7 +81,6 @@ require (
 	github.com/google/btree v1.0.1 // indirect
 	github.com/google/go-github/v35 v35.2.0 // indirect
 	github.com/gorilla/mux v1.8.0 // indirect
-	github.com/gorilla/websocket v1.4.2 // indirect
 	github.com/grpc-ecosystem/go-grpc-middleware v1.3.0 // indirect
 	github.com/grpc-ecosystem/grpc-gateway v1.16.0 // indirect
 	github.com/grpc-ecosystem/grpc-gateway/v2 v2.0.1 // indirect
@@ -0,0 +1,202 @@
+package blockarchive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/crypto-com/chain-indexing/infrastructure/tendermint"
+)
+
+var _ tendermint.Archiver = &Archive{}
+
+// ErrNotFound is returned when the requested raw response is not archived
+var ErrNotFound = errors.New("not found in archive")
+
+const OBJECTS_DIR = "objects"
+const REFS_DIR = "refs"
+const LATEST_REF = "LATEST"
+
+// Number of heights grouped under the same refs sub-directory
+const REFS_BUCKET_SIZE = 10000
+
+// Archive is a compressed, content-addressed archive of raw RPC responses on local file system.
+//
+// Raw responses are gzip compressed and stored once under `objects/<sha256[:2]>/<sha256>.gz`, where sha256 is the
+// digest of the uncompressed response. Each method and height references its response in
+// `refs/<method>/<height bucket>/<height>`. The digest is verified on every read.
+type Archive struct {
+	dir string
+
+	latestRefMutex sync.Mutex
+}
+
+func NewArchive(dir string) (*Archive, error) {
+	for _, subDir := range []string{OBJECTS_DIR, REFS_DIR} {
+		if err := os.MkdirAll(filepath.Join(dir, subDir), 0o755); err != nil {
+			return nil, fmt.Errorf("error creating archive directory: %v", err)
+		}
+	}
+
+	return &Archive{
+		dir: dir,
+	}, nil
+}
+
+// Put archives the raw response of the method at the height. Height is 0 for genesis.
+func (archive *Archive) Put(method string, height int64, rawResp []byte) error {
+	digest := sha256.Sum256(rawResp)
+	hash := hex.EncodeToString(digest[:])
+
+	objectPath := archive.objectPath(hash)
+	if _, err := os.Stat(objectPath); os.IsNotExist(err) {
+		var compressed bytes.Buffer
+		gzipWriter := gzip.NewWriter(&compressed)
+		if _, err = gzipWriter.Write(rawResp); err != nil {
+			return fmt.Errorf("error compressing raw response: %v", err)
+		}
+		if err = gzipWriter.Close(); err != nil {
+			return fmt.Errorf("error compressing raw response: %v", err)
+		}
+
+		if err = writeFileAtomically(objectPath, compressed.Bytes()); err != nil {
+			return fmt.Errorf("error writing archive object: %v", err)
+		}
+	} else if err != nil {
+		return fmt.Errorf("error checking archive object: %v", err)
+	}
+
+	if err := writeFileAtomically(archive.refPath(method, height), []byte(hash)); err != nil {
+		return fmt.Errorf("error writing archive ref: %v", err)
+	}
+
+	return archive.updateLatestHeight(method, height)
+}
+
+// Get returns the archived raw response of the method at the height. Returns ErrNotFound when it is not archived.
+func (archive *Archive) Get(method string, height int64) ([]byte, error) {
+	rawHash, err := ioutil.ReadFile(archive.refPath(method, height))
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("error reading archive ref: %v", err)
+	}
+	hash := strings.TrimSpace(string(rawHash))
+
+	compressed, err := ioutil.ReadFile(archive.objectPath(hash))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("error reading archive object %s referenced by %s at height %d: %w", hash, method, height, ErrNotFound)
+	} else if err != nil {
+		return nil, fmt.Errorf("error reading archive object: %v", err)
+	}
+
+	gzipReader, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing archive object %s: %v", hash, err)
+	}
+	defer gzipReader.Close()
+	rawResp, err := ioutil.ReadAll(gzipReader)
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing archive object %s: %v", hash, err)
+	}
+
+	digest := sha256.Sum256(rawResp)
+	if hex.EncodeToString(digest[:]) != hash {
+		return nil, fmt.Errorf("corrupted archive object %s: digest mismatch", hash)
+	}
+
+	return rawResp, nil
+}
+
+// LatestHeight returns the highest archived height of the method. Returns ErrNotFound when nothing is archived.
+func (archive *Archive) LatestHeight(method string) (int64, error) {
+	archive.latestRefMutex.Lock()
+	defer archive.latestRefMutex.Unlock()
+
+	return archive.readLatestHeight(method)
+}
+
+func (archive *Archive) updateLatestHeight(method string, height int64) error {
+	archive.latestRefMutex.Lock()
+	defer archive.latestRefMutex.Unlock()
+
+	latestHeight, err := archive.readLatestHeight(method)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	if err == nil && latestHeight >= height {
+		return nil
+	}
+
+	if err = writeFileAtomically(
+		filepath.Join(archive.dir, REFS_DIR, method, LATEST_REF),
+		[]byte(strconv.FormatInt(height, 10)),
+	); err != nil {
+		return fmt.Errorf("error writing archive latest height: %v", err)
+	}
+
+	return nil
+}
+
+func (archive *Archive) readLatestHeight(method string) (int64, error) {
+	rawLatestHeight, err := ioutil.ReadFile(filepath.Join(archive.dir, REFS_DIR, method, LATEST_REF))
+	if os.IsNotExist(err) {
+		return 0, ErrNotFound
+	} else if err != nil {
+		return 0, fmt.Errorf("error reading archive latest height: %v", err)
+	}
+
+	latestHeight, err := strconv.ParseInt(strings.TrimSpace(string(rawLatestHeight)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("error parsing archive latest height: %v", err)
+	}
+
+	return latestHeight, nil
+}
+
+func (archive *Archive) objectPath(hash string) string {
+	return filepath.Join(archive.dir, OBJECTS_DIR, hash[:2], hash+".gz")
+}
+
+func (archive *Archive) refPath(method string, height int64) string {
+	return filepath.Join(
+		archive.dir,
+		REFS_DIR,
+		method,
+		strconv.FormatInt(height/REFS_BUCKET_SIZE, 10),
+		strconv.FormatInt(height, 10),
+	)
+}
+
+// writeFileAtomically writes to a temporary file and renames it to the target path, so that readers never see a
+// partially written file
+func writeFileAtomically(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmpFile, err := ioutil.TempFile(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err = tmpFile.Write(data); err != nil {
+		tmpFile.Close()
+		return err
+	}
+	if err = tmpFile.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpFile.Name(), path)
+}
@@ -0,0 +1,154 @@
+package blockarchive_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+
+	"github.com/crypto-com/chain-indexing/infrastructure/blockarchive"
+	"github.com/crypto-com/chain-indexing/infrastructure/tendermint"
+	tendermint_test "github.com/crypto-com/chain-indexing/infrastructure/tendermint/test"
+)
+
+var _ = Describe("Archive", func() {
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "blockarchive")
+		Expect(err).To(BeNil())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("should return the archived raw response", func() {
+		archive, err := blockarchive.NewArchive(dir)
+		Expect(err).To(BeNil())
+
+		Expect(archive.Put("block", 10, []byte(`{"height":"10"}`))).To(BeNil())
+
+		rawResp, err := archive.Get("block", 10)
+		Expect(err).To(BeNil())
+		Expect(string(rawResp)).To(Equal(`{"height":"10"}`))
+	})
+
+	It("should return ErrNotFound when the height is not archived", func() {
+		archive, err := blockarchive.NewArchive(dir)
+		Expect(err).To(BeNil())
+
+		_, err = archive.Get("block", 10)
+		Expect(errors.Is(err, blockarchive.ErrNotFound)).To(BeTrue())
+		_, err = archive.LatestHeight("block")
+		Expect(errors.Is(err, blockarchive.ErrNotFound)).To(BeTrue())
+	})
+
+	It("should store identical responses once", func() {
+		archive, err := blockarchive.NewArchive(dir)
+		Expect(err).To(BeNil())
+
+		Expect(archive.Put("block_results", 10, []byte(`{}`))).To(BeNil())
+		Expect(archive.Put("block_results", 11, []byte(`{}`))).To(BeNil())
+
+		objects, err := filepath.Glob(filepath.Join(dir, "objects", "*", "*.gz"))
+		Expect(err).To(BeNil())
+		Expect(objects).To(HaveLen(1))
+	})
+
+	It("should reject corrupted object", func() {
+		archive, err := blockarchive.NewArchive(dir)
+		Expect(err).To(BeNil())
+
+		Expect(archive.Put("block", 10, []byte(`{"height":"10"}`))).To(BeNil())
+		Expect(archive.Put("block", 11, []byte(`{"height":"11"}`))).To(BeNil())
+		objects, err := filepath.Glob(filepath.Join(dir, "objects", "*", "*.gz"))
+		Expect(err).To(BeNil())
+		Expect(objects).To(HaveLen(2))
+		// Swap the contents of the two objects
+		first, _ := ioutil.ReadFile(objects[0])
+		second, _ := ioutil.ReadFile(objects[1])
+		Expect(ioutil.WriteFile(objects[0], second, 0o644)).To(BeNil())
+		Expect(ioutil.WriteFile(objects[1], first, 0o644)).To(BeNil())
+
+		_, err = archive.Get("block", 10)
+		Expect(err).To(MatchError(ContainSubstring("digest mismatch")))
+	})
+
+	It("should keep track of the latest archived height", func() {
+		archive, err := blockarchive.NewArchive(dir)
+		Expect(err).To(BeNil())
+
+		Expect(archive.Put("block", 12, []byte(`{}`))).To(BeNil())
+		Expect(archive.Put("block", 11, []byte(`{}`))).To(BeNil())
+
+		latestHeight, err := archive.LatestHeight("block")
+		Expect(err).To(BeNil())
+		Expect(latestHeight).To(Equal(int64(12)))
+	})
+})
+
+var _ = Describe("Client", func() {
+	var dir string
+	var server *ghttp.Server
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "blockarchive")
+		Expect(err).To(BeNil())
+
+		server = ghttp.NewServer()
+	})
+
+	AfterEach(func() {
+		server.Close()
+		os.RemoveAll(dir)
+	})
+
+	It("should serve the responses archived by HTTPClient", func() {
+		anyHeight := int64(3813)
+		server.AppendHandlers(
+			ghttp.RespondWith(http.StatusOK, tendermint_test.BLOCK_JSON),
+			ghttp.RespondWith(http.StatusOK, tendermint_test.BLOCK_RESULTS_JSON),
+		)
+
+		archive, err := blockarchive.NewArchive(dir)
+		Expect(err).To(BeNil())
+		httpClient := tendermint.NewHTTPClient(server.URL(), true).WithArchiver(archive)
+
+		expectedBlock, expectedRawBlock, err := httpClient.Block(anyHeight)
+		Expect(err).To(BeNil())
+		expectedBlockResults, err := httpClient.BlockResults(anyHeight)
+		Expect(err).To(BeNil())
+
+		client := blockarchive.NewClient(archive, true)
+
+		block, rawBlock, err := client.Block(anyHeight)
+		Expect(err).To(BeNil())
+		Expect(block).To(Equal(expectedBlock))
+		Expect(rawBlock).To(Equal(expectedRawBlock))
+
+		blockResults, err := client.BlockResults(anyHeight)
+		Expect(err).To(BeNil())
+		Expect(blockResults).To(Equal(expectedBlockResults))
+
+		latestBlockHeight, err := client.LatestBlockHeight()
+		Expect(err).To(BeNil())
+		Expect(latestBlockHeight).To(Equal(anyHeight))
+	})
+
+	It("should return error when the block is not archived", func() {
+		archive, err := blockarchive.NewArchive(dir)
+		Expect(err).To(BeNil())
+		client := blockarchive.NewClient(archive, true)
+
+		_, _, err = client.Block(1)
+		Expect(errors.Is(err, blockarchive.ErrNotFound)).To(BeTrue())
+	})
+})
@@ -0,0 +1,71 @@
+package blockarchive
+
+import (
+	"bytes"
+	"fmt"
+
+	tendermint_interface "github.com/crypto-com/chain-indexing/appinterface/tendermint"
+	"github.com/crypto-com/chain-indexing/infrastructure/tendermint"
+	usecase_model "github.com/crypto-com/chain-indexing/usecase/model"
+	"github.com/crypto-com/chain-indexing/usecase/model/genesis"
+)
+
+var _ tendermint_interface.Client = &Client{}
+
+// Client is a Tendermint client serving raw responses from the archive. It never makes any network request.
+type Client struct {
+	archive              *Archive
+	strictGenesisParsing bool
+}
+
+func NewClient(archive *Archive, strictGenesisParsing bool) *Client {
+	return &Client{
+		archive,
+		strictGenesisParsing,
+	}
+}
+
+func (client *Client) Genesis() (*genesis.Genesis, error) {
+	rawResp, err := client.archive.Get(tendermint.ARCHIVE_METHOD_GENESIS, 0)
+	if err != nil {
+		return nil, fmt.Errorf("error getting genesis from archive: %w", err)
+	}
+
+	return tendermint.ParseGenesisResp(bytes.NewReader(rawResp), client.strictGenesisParsing)
+}
+
+func (client *Client) Block(height int64) (*usecase_model.Block, *usecase_model.RawBlock, error) {
+	rawResp, err := client.archive.Get(tendermint.ARCHIVE_METHOD_BLOCK, height)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error getting block at height %d from archive: %w", height, err)
+	}
+
+	return tendermint.ParseBlockResp(bytes.NewReader(rawResp))
+}
+
+func (client *Client) BlockResults(height int64) (*usecase_model.BlockResults, error) {
+	rawResp, err := client.archive.Get(tendermint.ARCHIVE_METHOD_BLOCK_RESULTS, height)
+	if err != nil {
+		return nil, fmt.Errorf("error getting block_results at height %d from archive: %w", height, err)
+	}
+
+	return tendermint.ParseBlockResultsResp(bytes.NewReader(rawResp))
+}
+
+// LatestBlockHeight returns the highest height with both block and block results archived. Lower heights may be
+// missing when the archive was recorded out of order.
+func (client *Client) LatestBlockHeight() (int64, error) {
+	latestBlockHeight, err := client.archive.LatestHeight(tendermint.ARCHIVE_METHOD_BLOCK)
+	if err != nil {
+		return 0, fmt.Errorf("error getting latest block height from archive: %w", err)
+	}
+	latestBlockResultsHeight, err := client.archive.LatestHeight(tendermint.ARCHIVE_METHOD_BLOCK_RESULTS)
+	if err != nil {
+		return 0, fmt.Errorf("error getting latest block_results height from archive: %w", err)
+	}
+
+	if latestBlockResultsHeight < latestBlockHeight {
+		return latestBlockResultsHeight, nil
+	}
+	return latestBlockHeight, nil
+}
@@ -0,0 +1,102 @@
+package cosmosapp
+
+import (
+	"errors"
+
+	cosmosapp_interface "github.com/crypto-com/chain-indexing/appinterface/cosmosapp"
+	"github.com/crypto-com/chain-indexing/usecase/coin"
+)
+
+var _ cosmosapp_interface.Client = &DisabledClient{}
+
+var ErrClientDisabled = errors.New("cosmos app client is disabled")
+
+// DisabledClient is a Cosmos app client which never makes any network request. Every query returns
+// ErrClientDisabled. It is used when blocks are replayed from the archive.
+type DisabledClient struct{}
+
+func NewDisabledClient() *DisabledClient {
+	return &DisabledClient{}
+}
+
+func (client *DisabledClient) Account(_ string) (*cosmosapp_interface.Account, error) {
+	return nil, ErrClientDisabled
+}
+
+func (client *DisabledClient) Balances(_ string) (coin.Coins, error) {
+	return nil, ErrClientDisabled
+}
+
+func (client *DisabledClient) BondedBalance(_ string) (coin.Coins, error) {
+	return nil, ErrClientDisabled
+}
+
+func (client *DisabledClient) RedelegatingBalance(_ string) (coin.Coins, error) {
+	return nil, ErrClientDisabled
+}
+
+func (client *DisabledClient) UnbondingBalance(_ string) (coin.Coins, error) {
+	return nil, ErrClientDisabled
+}
+
+func (client *DisabledClient) TotalRewards(_ string) (coin.DecCoins, error) {
+	return nil, ErrClientDisabled
+}
+
+func (client *DisabledClient) Commission(_ string) (coin.DecCoins, error) {
+	return nil, ErrClientDisabled
+}
+
+func (client *DisabledClient) Validator(_ string) (*cosmosapp_interface.Validator, error) {
+	return nil, ErrClientDisabled
+}
+
+func (client *DisabledClient) Delegation(_ string, _ string) (*cosmosapp_interface.DelegationResponse, error) {
+	return nil, ErrClientDisabled
+}
+
+func (client *DisabledClient) TotalBondedBalance() (coin.Coin, error) {
+	return coin.Coin{}, ErrClientDisabled
+}
+
+func (client *DisabledClient) AnnualProvisions() (coin.DecCoin, error) {
+	return coin.DecCoin{}, ErrClientDisabled
+}
+
+func (client *DisabledClient) Proposals() ([]cosmosapp_interface.Proposal, error) {
+	return nil, ErrClientDisabled
+}
+
+func (client *DisabledClient) ProposalById(_ string) (cosmosapp_interface.Proposal, error) {
+	return cosmosapp_interface.Proposal{}, ErrClientDisabled
+}
+
+func (client *DisabledClient) ProposalTally(_ string) (cosmosapp_interface.Tally, error) {
+	return cosmosapp_interface.Tally{}, ErrClientDisabled
+}
+
+func (client *DisabledClient) ValidatorsAtHeight(_ int64) ([]cosmosapp_interface.Validator, error) {
+	return nil, ErrClientDisabled
+}
+
+func (client *DisabledClient) ValidatorDelegationsAtHeight(
+	_ string, _ int64,
+) ([]cosmosapp_interface.DelegationResponse, error) {
+	return nil, ErrClientDisabled
+}
+
+func (client *DisabledClient) AccountAtHeight(_ string, _ int64) (*cosmosapp_interface.Account, error) {
+	return nil, ErrClientDisabled
+}
+
+func (client *DisabledClient) BalancesAtHeight(_ string, _ int64) (coin.Coins, error) {
+	return nil, ErrClientDisabled
+}
+
+func (client *DisabledClient) GovParamsAtHeight(_ int64) (*cosmosapp_interface.GovParams, error) {
+	return nil, ErrClientDisabled
+}
+
+func (client *DisabledClient) ProposalsAtHeight(_ int64) ([]cosmosapp_interface.Proposal, error) {
+	return nil, ErrClientDisabled
+}
@@ -0,0 +1,9 @@
+package cosmosapp
+
+import cosmosapp_interface "github.com/crypto-com/chain-indexing/appinterface/cosmosapp"
+
+type GovParamsResp struct {
+	DepositParams cosmosapp_interface.GovDepositParams `json:"deposit_params"`
+	VotingParams  cosmosapp_interface.GovVotingParams  `json:"voting_params"`
+	TallyParams   cosmosapp_interface.GovTallyParams   `json:"tally_params"`
+}
@@ -3,10 +3,12 @@ package cosmosapp
 import (
 	"context"
 	"crypto/tls"
+	"encoding/base64"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -15,6 +17,7 @@ import (
 	jsoniter "github.com/json-iterator/go"
 
 	cosmosapp_interface "github.com/crypto-com/chain-indexing/appinterface/cosmosapp"
+	"github.com/crypto-com/chain-indexing/infrastructure/multiendpoint"
 )
 
 var _ cosmosapp_interface.Client = &HTTPClient{}
@@ -22,6 +25,9 @@ var _ cosmosapp_interface.Client = &HTTPClient{}
 const ERR_CODE_ACCOUNT_NOT_FOUND = 2
 const ERR_CODE_ACCOUNT_NO_DELEGATION = 5
 
+// BLOCK_HEIGHT_HEADER is the request header to query the state at a specific height
+const BLOCK_HEIGHT_HEADER = "x-cosmos-block-height"
+
 type HTTPClient struct {
 	httpClient *http.Client
 	rpcUrl     string
@@ -70,6 +76,22 @@ func (client *HTTPClient) Account(accountAddress string) (*cosmosapp_interface.A
 	}
 	defer rawRespBody.Close()
 
+	return parseAccountResp(rawRespBody)
+}
+
+func (client *HTTPClient) AccountAtHeight(accountAddress string, height int64) (*cosmosapp_interface.Account, error) {
+	rawRespBody, err := client.requestAtHeight(
+		fmt.Sprintf("%s/%s", client.getUrl("auth", "accounts"), accountAddress), height,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rawRespBody.Close()
+
+	return parseAccountResp(rawRespBody)
+}
+
+func parseAccountResp(rawRespBody io.Reader) (*cosmosapp_interface.Account, error) {
 	var accountResp AccountResp
 	if err := jsoniter.NewDecoder(rawRespBody).Decode(&accountResp); err != nil {
 		return nil, err
@@ -248,7 +270,10 @@ func (client *HTTPClient) BondedBalance(accountAddress string) (coin.Coins, erro
 			}
 		}
 		if statusCode != 200 {
-			return nil, fmt.Errorf("error requesting Cosmos %s endpoint: status code %d", queryUrl, statusCode)
+			return nil, fmt.Errorf(
+				"error requesting Cosmos %s endpoint: %w",
+				queryUrl, multiendpoint.NewHTTPStatusError(statusCode, ""),
+			)
 		}
 		for _, delegation := range resp.MaybeDelegationResponses {
 			delegatedCoin, coinErr := coin.NewCoinFromString(delegation.Balance.Denom, delegation.Balance.Amount)
@@ -466,7 +491,10 @@ func (client *HTTPClient) Delegation(
 			return nil, cosmosapp_interface.ErrAccountNotFound
 		}
 		if statusCode != 200 {
-			return nil, fmt.Errorf("error requesting Cosmos %s endpoint: status code %d", queryUrl, statusCode)
+			return nil, fmt.Errorf(
+				"error requesting Cosmos %s endpoint: %w",
+				queryUrl, multiendpoint.NewHTTPStatusError(statusCode, ""),
+			)
 		}
 
 		for _, delegation := range resp.MaybeDelegationResponses {
@@ -538,7 +566,10 @@ func (client *HTTPClient) TotalBondedBalance() (coin.Coin, error) {
 			return coin.Coin{}, cosmosapp_interface.ErrAccountNotFound
 		}
 		if statusCode != 200 {
-			return coin.Coin{}, fmt.Errorf("error requesting Cosmos %s endpoint: status code %d", queryUrl, statusCode)
+			return coin.Coin{}, fmt.Errorf(
+				"error requesting Cosmos %s endpoint: %w",
+				queryUrl, multiendpoint.NewHTTPStatusError(statusCode, ""),
+			)
 		}
 
 		for _, validator := range resp.MaybeValidatorResponse {
@@ -587,7 +618,10 @@ func (client *HTTPClient) Proposals() ([]cosmosapp_interface.Proposal, error) {
 			return nil, decodeErr
 		}
 		if statusCode != 200 {
-			return nil, fmt.Errorf("error requesting Cosmos %s endpoint: status code %d", queryUrl, statusCode)
+			return nil, fmt.Errorf(
+				"error requesting Cosmos %s endpoint: %w",
+				queryUrl, multiendpoint.NewHTTPStatusError(statusCode, ""),
+			)
 		}
 
 		proposals = append(proposals, resp.MaybeProposalsResponse...)
@@ -656,6 +690,217 @@ func (client *HTTPClient) ProposalTally(id string) (cosmosapp_interface.Tally, e
 	return tallyResp.Tally, nil
 }
 
+func (client *HTTPClient) ValidatorsAtHeight(height int64) ([]cosmosapp_interface.Validator, error) {
+	resp := &ValidatorsResp{
+		MaybePagination: &Pagination{
+			MaybeNextKey: nil,
+			Total:        "",
+		},
+	}
+	validators := make([]cosmosapp_interface.Validator, 0)
+	for {
+		queryUrl := client.getUrl("staking", "validators")
+		if resp.MaybePagination.MaybeNextKey != nil {
+			queryUrl = fmt.Sprintf(
+				"%s?pagination.key=%s",
+				queryUrl, url.QueryEscape(*resp.MaybePagination.MaybeNextKey),
+			)
+		}
+
+		rawRespBody, err := client.requestAtHeight(queryUrl, height)
+		if err != nil {
+			return nil, err
+		}
+		defer rawRespBody.Close()
+
+		resp.MaybePagination = nil
+		if decodeErr := jsoniter.NewDecoder(rawRespBody).Decode(&resp); decodeErr != nil {
+			return nil, decodeErr
+		}
+		validators = append(validators, resp.MaybeValidatorResponse...)
+
+		if resp.MaybePagination == nil || resp.MaybePagination.MaybeNextKey == nil {
+			break
+		}
+	}
+
+	return validators, nil
+}
+
+func (client *HTTPClient) ValidatorDelegationsAtHeight(
+	validatorAddress string, height int64,
+) ([]cosmosapp_interface.DelegationResponse, error) {
+	resp := &DelegationsResp{
+		MaybePagination: &Pagination{
+			MaybeNextKey: nil,
+			Total:        "",
+		},
+	}
+	delegations := make([]cosmosapp_interface.DelegationResponse, 0)
+	for {
+		queryUrl := fmt.Sprintf("%s/%s/delegations", client.getUrl("staking", "validators"), validatorAddress)
+		if resp.MaybePagination.MaybeNextKey != nil {
+			queryUrl = fmt.Sprintf(
+				"%s?pagination.key=%s",
+				queryUrl, url.QueryEscape(*resp.MaybePagination.MaybeNextKey),
+			)
+		}
+
+		rawRespBody, err := client.requestAtHeight(queryUrl, height)
+		if err != nil {
+			return nil, err
+		}
+		defer rawRespBody.Close()
+
+		resp.MaybePagination = nil
+		if decodeErr := jsoniter.NewDecoder(rawRespBody).Decode(&resp); decodeErr != nil {
+			return nil, decodeErr
+		}
+		delegations = append(delegations, resp.MaybeDelegationResponses...)
+
+		if resp.MaybePagination == nil || resp.MaybePagination.MaybeNextKey == nil {
+			break
+		}
+	}
+
+	return delegations, nil
+}
+
+func (client *HTTPClient) BalancesAtHeight(accountAddress string, height int64) (coin.Coins, error) {
+	resp := &BankBalancesResp{
+		Pagination: Pagination{
+			MaybeNextKey: nil,
+			Total:        "",
+		},
+	}
+	balances := coin.NewEmptyCoins()
+	for {
+		queryUrl := fmt.Sprintf("%s/%s", client.getUrl("bank", "balances"), accountAddress)
+		if resp.Pagination.MaybeNextKey != nil {
+			queryUrl = fmt.Sprintf(
+				"%s?pagination.key=%s",
+				queryUrl, url.QueryEscape(*resp.Pagination.MaybeNextKey),
+			)
+		}
+
+		rawRespBody, err := client.requestAtHeight(queryUrl, height)
+		if err != nil {
+			return nil, err
+		}
+		defer rawRespBody.Close()
+
+		resp.Pagination.MaybeNextKey = nil
+		resp.BankBalanceResponses = nil
+		if err := jsoniter.NewDecoder(rawRespBody).Decode(&resp); err != nil {
+			return nil, err
+		}
+		for _, balanceKVPair := range resp.BankBalanceResponses {
+			balance, coinErr := coin.NewCoinFromString(balanceKVPair.Denom, balanceKVPair.Amount)
+			if coinErr != nil {
+				return nil, coinErr
+			}
+			balances = balances.Add(balance)
+		}
+
+		if resp.Pagination.MaybeNextKey == nil {
+			break
+		}
+	}
+
+	return balances, nil
+}
+
+func (client *HTTPClient) ProposalsAtHeight(height int64) ([]cosmosapp_interface.Proposal, error) {
+	resp := &ProposalsResp{
+		MaybePagination: &Pagination{
+			MaybeNextKey: nil,
+			Total:        "",
+		},
+	}
+
+	proposals := make([]cosmosapp_interface.Proposal, 0)
+	for {
+		queryUrl := client.getUrl("gov", "proposals")
+		if resp.MaybePagination.MaybeNextKey != nil {
+			queryUrl = fmt.Sprintf(
+				"%s?pagination.key=%s",
+				queryUrl, url.QueryEscape(*resp.MaybePagination.MaybeNextKey),
+			)
+		}
+
+		rawRespBody, err := client.requestAtHeight(queryUrl, height)
+		if err != nil {
+			return nil, err
+		}
+		defer rawRespBody.Close()
+
+		resp.MaybePagination = nil
+		resp.MaybeProposalsResponse = nil
+		if decodeErr := jsoniter.NewDecoder(rawRespBody).Decode(&resp); decodeErr != nil {
+			return nil, decodeErr
+		}
+		proposals = append(proposals, resp.MaybeProposalsResponse...)
+
+		if resp.MaybePagination == nil || resp.MaybePagination.MaybeNextKey == nil {
+			break
+		}
+	}
+
+	return proposals, nil
+}
+
+// GovParamsAtHeight returns the deposit, voting and tally params of gov module. Tally params returned as base64
+// encoded bytes by some versions of Cosmos SDK are decoded into decimal strings.
+func (client *HTTPClient) GovParamsAtHeight(height int64) (*cosmosapp_interface.GovParams, error) {
+	var params cosmosapp_interface.GovParams
+	for _, paramsType := range []string{"deposit", "voting", "tallying"} {
+		rawRespBody, err := client.requestAtHeight(
+			fmt.Sprintf("%s/%s", client.getUrl("gov", "params"), paramsType), height,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		var govParamsResp GovParamsResp
+		decodeErr := jsoniter.NewDecoder(rawRespBody).Decode(&govParamsResp)
+		rawRespBody.Close()
+		if decodeErr != nil {
+			return nil, fmt.Errorf("error decoding gov %s params response: %v", paramsType, decodeErr)
+		}
+
+		switch paramsType {
+		case "deposit":
+			params.DepositParams = govParamsResp.DepositParams
+		case "voting":
+			params.VotingParams = govParamsResp.VotingParams
+		case "tallying":
+			params.TallyParams = cosmosapp_interface.GovTallyParams{
+				Quorum:        decodeTallyParam(govParamsResp.TallyParams.Quorum),
+				Threshold:     decodeTallyParam(govParamsResp.TallyParams.Threshold),
+				VetoThreshold: decodeTallyParam(govParamsResp.TallyParams.VetoThreshold),
+			}
+		}
+	}
+
+	return &params, nil
+}
+
+// decodeTallyParam returns the decimal string of the tally param, which is either a decimal string or a base64
+// encoded decimal string
+func decodeTallyParam(value string) string {
+	if _, err := coin.NewDecFromStr(value); err == nil {
+		return value
+	}
+	decoded, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return value
+	}
+	if _, err := coin.NewDecFromStr(string(decoded)); err != nil {
+		return value
+	}
+	return string(decoded)
+}
+
 func (client *HTTPClient) getUrl(module string, method string) string {
 	return fmt.Sprintf("cosmos/%s/v1beta1/%s", module, method)
 }
@@ -676,12 +921,40 @@ func (client *HTTPClient) request(method string, queryString ...string) (io.Read
 	}
 	rawResp, err := client.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("error requesting Cosmos %s endpoint: %v", queryUrl, err)
+		return nil, fmt.Errorf("error requesting Cosmos %s endpoint: %w", queryUrl, err)
 	}
 
 	if rawResp.StatusCode != 200 {
 		rawResp.Body.Close()
-		return nil, fmt.Errorf("error requesting Cosmos %s endpoint: %s", method, rawResp.Status)
+		return nil, fmt.Errorf(
+			"error requesting Cosmos %s endpoint: %w",
+			method, multiendpoint.NewHTTPStatusError(rawResp.StatusCode, rawResp.Status),
+		)
+	}
+
+	return rawResp.Body, nil
+}
+
+// requestAtHeight issues an HTTP request querying the state at the height and returns the success http Body
+func (client *HTTPClient) requestAtHeight(method string, height int64) (io.ReadCloser, error) {
+	queryUrl := client.rpcUrl + "/" + method
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, queryUrl, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating HTTP request with context: %v", err)
+	}
+	req.Header.Set(BLOCK_HEIGHT_HEADER, strconv.FormatInt(height, 10))
+	rawResp, err := client.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error requesting Cosmos %s endpoint at height %d: %w", queryUrl, height, err)
+	}
+
+	if rawResp.StatusCode != 200 {
+		rawResp.Body.Close()
+		return nil, fmt.Errorf(
+			"error requesting Cosmos %s endpoint at height %d: %w",
+			method, height, multiendpoint.NewHTTPStatusError(rawResp.StatusCode, rawResp.Status),
+		)
 	}
 
 	return rawResp.Body, nil
@@ -704,7 +977,7 @@ func (client *HTTPClient) rawRequest(method string, queryString ...string) (io.R
 	// nolint:bodyclose
 	rawResp, err := client.httpClient.Do(req)
 	if err != nil {
-		return nil, 0, fmt.Errorf("error requesting Cosmos %s endpoint: %v", queryUrl, err)
+		return nil, 0, fmt.Errorf("error requesting Cosmos %s endpoint: %w", queryUrl, err)
 	}
 
 	return rawResp.Body, rawResp.StatusCode, nil
@@ -0,0 +1,274 @@
+package cosmosapp
+
+import (
+	cosmosapp_interface "github.com/crypto-com/chain-indexing/appinterface/cosmosapp"
+	"github.com/crypto-com/chain-indexing/infrastructure/multiendpoint"
+	"github.com/crypto-com/chain-indexing/usecase/coin"
+)
+
+var _ cosmosapp_interface.Client = &MultiEndpointHTTPClient{}
+
+const MULTI_ENDPOINT_POOL_NAME = "cosmosapp"
+
+// MultiEndpointHTTPClient is a Cosmos app client over multiple RPC endpoints. Requests are routed by
+// endpoint health score and latency and fail over on server errors, timeouts and unavailable heights.
+type MultiEndpointHTTPClient struct {
+	pool    *multiendpoint.Pool
+	clients map[string]*HTTPClient
+}
+
+// NewMultiEndpointHTTPClient returns a new MultiEndpointHTTPClient for Cosmos app requests
+func NewMultiEndpointHTTPClient(rpcUrls []string, bondingDenom string) *MultiEndpointHTTPClient {
+	clients := make(map[string]*HTTPClient, len(rpcUrls))
+	for _, url := range rpcUrls {
+		clients[url] = NewHTTPClient(url, bondingDenom)
+	}
+
+	return newMultiEndpointHTTPClient(rpcUrls, clients)
+}
+
+// NewInsecureMultiEndpointHTTPClient returns a new MultiEndpointHTTPClient which skips TLS
+// certificate verification
+func NewInsecureMultiEndpointHTTPClient(rpcUrls []string, bondingDenom string) *MultiEndpointHTTPClient {
+	clients := make(map[string]*HTTPClient, len(rpcUrls))
+	for _, url := range rpcUrls {
+		clients[url] = NewInsecureHTTPClient(url, bondingDenom)
+	}
+
+	return newMultiEndpointHTTPClient(rpcUrls, clients)
+}
+
+func newMultiEndpointHTTPClient(rpcUrls []string, clients map[string]*HTTPClient) *MultiEndpointHTTPClient {
+	return &MultiEndpointHTTPClient{
+		pool: multiendpoint.NewPool(MULTI_ENDPOINT_POOL_NAME, rpcUrls, func(err error) bool {
+			return multiendpoint.IsFailoverError(err) || multiendpoint.IsHeightNotAvailableError(err)
+		}),
+		clients: clients,
+	}
+}
+
+func (client *MultiEndpointHTTPClient) Account(accountAddress string) (*cosmosapp_interface.Account, error) {
+	var result *cosmosapp_interface.Account
+	err := client.pool.Do(func(url string) error {
+		var err error
+		result, err = client.clients[url].Account(accountAddress)
+		return err
+	})
+
+	return result, err
+}
+
+func (client *MultiEndpointHTTPClient) Balances(accountAddress string) (coin.Coins, error) {
+	var result coin.Coins
+	err := client.pool.Do(func(url string) error {
+		var err error
+		result, err = client.clients[url].Balances(accountAddress)
+		return err
+	})
+
+	return result, err
+}
+
+func (client *MultiEndpointHTTPClient) BondedBalance(accountAddress string) (coin.Coins, error) {
+	var result coin.Coins
+	err := client.pool.Do(func(url string) error {
+		var err error
+		result, err = client.clients[url].BondedBalance(accountAddress)
+		return err
+	})
+
+	return result, err
+}
+
+func (client *MultiEndpointHTTPClient) RedelegatingBalance(accountAddress string) (coin.Coins, error) {
+	var result coin.Coins
+	err := client.pool.Do(func(url string) error {
+		var err error
+		result, err = client.clients[url].RedelegatingBalance(accountAddress)
+		return err
+	})
+
+	return result, err
+}
+
+func (client *MultiEndpointHTTPClient) UnbondingBalance(accountAddress string) (coin.Coins, error) {
+	var result coin.Coins
+	err := client.pool.Do(func(url string) error {
+		var err error
+		result, err = client.clients[url].UnbondingBalance(accountAddress)
+		return err
+	})
+
+	return result, err
+}
+
+func (client *MultiEndpointHTTPClient) TotalRewards(accountAddress string) (coin.DecCoins, error) {
+	var result coin.DecCoins
+	err := client.pool.Do(func(url string) error {
+		var err error
+		result, err = client.clients[url].TotalRewards(accountAddress)
+		return err
+	})
+
+	return result, err
+}
+
+func (client *MultiEndpointHTTPClient) Commission(validatorAddress string) (coin.DecCoins, error) {
+	var result coin.DecCoins
+	err := client.pool.Do(func(url string) error {
+		var err error
+		result, err = client.clients[url].Commission(validatorAddress)
+		return err
+	})
+
+	return result, err
+}
+
+func (client *MultiEndpointHTTPClient) Validator(validatorAddress string) (*cosmosapp_interface.Validator, error) {
+	var result *cosmosapp_interface.Validator
+	err := client.pool.Do(func(url string) error {
+		var err error
+		result, err = client.clients[url].Validator(validatorAddress)
+		return err
+	})
+
+	return result, err
+}
+
+func (client *MultiEndpointHTTPClient) Delegation(
+	delegator string, validator string,
+) (*cosmosapp_interface.DelegationResponse, error) {
+	var result *cosmosapp_interface.DelegationResponse
+	err := client.pool.Do(func(url string) error {
+		var err error
+		result, err = client.clients[url].Delegation(delegator, validator)
+		return err
+	})
+
+	return result, err
+}
+
+func (client *MultiEndpointHTTPClient) TotalBondedBalance() (coin.Coin, error) {
+	var result coin.Coin
+	err := client.pool.Do(func(url string) error {
+		var err error
+		result, err = client.clients[url].TotalBondedBalance()
+		return err
+	})
+
+	return result, err
+}
+
+func (client *MultiEndpointHTTPClient) AnnualProvisions() (coin.DecCoin, error) {
+	var result coin.DecCoin
+	err := client.pool.Do(func(url string) error {
+		var err error
+		result, err = client.clients[url].AnnualProvisions()
+		return err
+	})
+
+	return result, err
+}
+
+func (client *MultiEndpointHTTPClient) Proposals() ([]cosmosapp_interface.Proposal, error) {
+	var result []cosmosapp_interface.Proposal
+	err := client.pool.Do(func(url string) error {
+		var err error
+		result, err = client.clients[url].Proposals()
+		return err
+	})
+
+	return result, err
+}
+
+func (client *MultiEndpointHTTPClient) ProposalById(id string) (cosmosapp_interface.Proposal, error) {
+	var result cosmosapp_interface.Proposal
+	err := client.pool.Do(func(url string) error {
+		var err error
+		result, err = client.clients[url].ProposalById(id)
+		return err
+	})
+
+	return result, err
+}
+
+func (client *MultiEndpointHTTPClient) ValidatorsAtHeight(height int64) ([]cosmosapp_interface.Validator, error) {
+	var result []cosmosapp_interface.Validator
+	err := client.pool.Do(func(url string) error {
+		var err error
+		result, err = client.clients[url].ValidatorsAtHeight(height)
+		return err
+	})
+
+	return result, err
+}
+
+func (client *MultiEndpointHTTPClient) ValidatorDelegationsAtHeight(
+	validatorAddress string, height int64,
+) ([]cosmosapp_interface.DelegationResponse, error) {
+	var result []cosmosapp_interface.DelegationResponse
+	err := client.pool.Do(func(url string) error {
+		var err error
+		result, err = client.clients[url].ValidatorDelegationsAtHeight(validatorAddress, height)
+		return err
+	})
+
+	return result, err
+}
+
+func (client *MultiEndpointHTTPClient) AccountAtHeight(
+	accountAddress string, height int64,
+) (*cosmosapp_interface.Account, error) {
+	var result *cosmosapp_interface.Account
+	err := client.pool.Do(func(url string) error {
+		var err error
+		result, err = client.clients[url].AccountAtHeight(accountAddress, height)
+		return err
+	})
+
+	return result, err
+}
+
+func (client *MultiEndpointHTTPClient) BalancesAtHeight(accountAddress string, height int64) (coin.Coins, error) {
+	var result coin.Coins
+	err := client.pool.Do(func(url string) error {
+		var err error
+		result, err = client.clients[url].BalancesAtHeight(accountAddress, height)
+		return err
+	})
+
+	return result, err
+}
+
+func (client *MultiEndpointHTTPClient) GovParamsAtHeight(height int64) (*cosmosapp_interface.GovParams, error) {
+	var result *cosmosapp_interface.GovParams
+	err := client.pool.Do(func(url string) error {
+		var err error
+		result, err = client.clients[url].GovParamsAtHeight(height)
+		return err
+	})
+
+	return result, err
+}
+
+func (client *MultiEndpointHTTPClient) ProposalsAtHeight(height int64) ([]cosmosapp_interface.Proposal, error) {
+	var result []cosmosapp_interface.Proposal
+	err := client.pool.Do(func(url string) error {
+		var err error
+		result, err = client.clients[url].ProposalsAtHeight(height)
+		return err
+	})
+
+	return result, err
+}
+
+func (client *MultiEndpointHTTPClient) ProposalTally(id string) (cosmosapp_interface.Tally, error) {
+	var result cosmosapp_interface.Tally
+	err := client.pool.Do(func(url string) error {
+		var err error
+		result, err = client.clients[url].ProposalTally(id)
+		return err
+	})
+
+	return result, err
+}
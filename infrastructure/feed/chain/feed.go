@@ -0,0 +1,12 @@
+package chain
+
+// BlockHeightFeed publishes the chain latest block height to its subscribers
+type BlockHeightFeed interface {
+	// Subscribe registers a channel to receive latest block height updates
+	Subscribe(ch chan<- int64)
+	// GetLatestBlockHeight returns the last seen chain block height, nil if it is not known yet
+	GetLatestBlockHeight() *int64
+}
+
+var _ BlockHeightFeed = &BlockHeightTracker{}
+var _ BlockHeightFeed = &WebSocketBlockHeightTracker{}
@@ -0,0 +1,399 @@
+package chain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/gorilla/websocket"
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/crypto-com/chain-indexing/appinterface/tendermint"
+	applogger "github.com/crypto-com/chain-indexing/external/logger"
+	"github.com/crypto-com/chain-indexing/external/primptr"
+)
+
+const DEFAULT_MIN_RECONNECT_INTERVAL = 1 * time.Second
+const DEFAULT_MAX_RECONNECT_INTERVAL = 1 * time.Minute
+const DEFAULT_WEBSOCKET_READ_TIMEOUT = 1 * time.Minute
+
+const NEW_BLOCK_SUBSCRIPTION_QUERY = "tm.event='NewBlock'"
+
+// WebSocketBlockHeightTracker tracks the chain latest block height by subscribing to Tendermint
+// `NewBlock` events over WebSocket. When the WebSocket connection is down, it falls back to polling
+// the latest block height until the connection is re-established. It stops when the context passed to
+// its constructor is cancelled.
+type WebSocketBlockHeightTracker struct {
+	logger       applogger.Logger
+	client       tendermint.Client
+	websocketUrl string
+
+	pollingInterval      time.Duration
+	minReconnectInterval time.Duration
+	maxReconnectInterval time.Duration
+	readTimeout          time.Duration
+
+	subscriptions      []*heightSubscription
+	subscriptionsMutex sync.RWMutex
+	// Closed when the tracker stops
+	done chan struct{}
+
+	latestBlockHeight *int64
+	rwMutex           sync.RWMutex
+}
+
+type WebSocketBlockHeightTrackerOptions struct {
+	// Polling interval when WebSocket is down
+	MaybePollingInterval *time.Duration
+	// Minimum and maximum backoff interval between reconnection attempts
+	MaybeMinReconnectInterval *time.Duration
+	MaybeMaxReconnectInterval *time.Duration
+	// Connection is considered broken when nothing (including pings) is received within the timeout
+	MaybeReadTimeout *time.Duration
+}
+
+func NewWebSocketBlockHeightTracker(
+	ctx context.Context,
+	logger applogger.Logger,
+	client tendermint.Client,
+	websocketUrl string,
+) *WebSocketBlockHeightTracker {
+	return NewWebSocketBlockHeightTrackerWithOptions(
+		ctx, logger, client, websocketUrl, WebSocketBlockHeightTrackerOptions{},
+	)
+}
+
+func NewWebSocketBlockHeightTrackerWithOptions(
+	ctx context.Context,
+	logger applogger.Logger,
+	client tendermint.Client,
+	websocketUrl string,
+	options WebSocketBlockHeightTrackerOptions,
+) *WebSocketBlockHeightTracker {
+	tracker := &WebSocketBlockHeightTracker{
+		logger: logger.WithFields(applogger.LogFields{
+			"module": "WebSocketBlockHeightTracker",
+		}),
+		client:       client,
+		websocketUrl: websocketUrl,
+
+		pollingInterval:      DEFAULT_POLLING_INTERVAL,
+		minReconnectInterval: DEFAULT_MIN_RECONNECT_INTERVAL,
+		maxReconnectInterval: DEFAULT_MAX_RECONNECT_INTERVAL,
+		readTimeout:          DEFAULT_WEBSOCKET_READ_TIMEOUT,
+
+		subscriptions: make([]*heightSubscription, 0),
+		done:          make(chan struct{}),
+
+		latestBlockHeight: primptr.Int64Nil(),
+	}
+	if options.MaybePollingInterval != nil {
+		tracker.pollingInterval = *options.MaybePollingInterval
+	}
+	if options.MaybeMinReconnectInterval != nil {
+		tracker.minReconnectInterval = *options.MaybeMinReconnectInterval
+	}
+	if options.MaybeMaxReconnectInterval != nil {
+		tracker.maxReconnectInterval = *options.MaybeMaxReconnectInterval
+	}
+	if options.MaybeReadTimeout != nil {
+		tracker.readTimeout = *options.MaybeReadTimeout
+	}
+
+	go tracker.Run(ctx)
+
+	return tracker
+}
+
+// Run subscribes to NewBlock events and reconnects on disconnection until the context is cancelled
+func (tracker *WebSocketBlockHeightTracker) Run(ctx context.Context) {
+	defer close(tracker.done)
+
+	reconnectBackoff := backoff.NewExponentialBackOff()
+	reconnectBackoff.InitialInterval = tracker.minReconnectInterval
+	reconnectBackoff.MaxInterval = tracker.maxReconnectInterval
+	reconnectBackoff.MaxElapsedTime = MAX_RETRY_TIME_ALWAYS_RETRY
+
+	for {
+		err := tracker.subscribeNewBlock(ctx, func() {
+			reconnectBackoff.Reset()
+		})
+		if ctx.Err() != nil {
+			tracker.logger.Info("stopped tracking latest block height")
+			return
+		}
+
+		reconnectInterval := reconnectBackoff.NextBackOff()
+		tracker.logger.Errorf(
+			"websocket subscription is down, polling latest block height and reconnecting in %s: %v",
+			reconnectInterval.String(), err,
+		)
+		if !tracker.pollUntil(ctx, time.After(reconnectInterval)) {
+			tracker.logger.Info("stopped tracking latest block height")
+			return
+		}
+	}
+}
+
+// subscribeNewBlock connects to the Tendermint WebSocket endpoint and subscribes to NewBlock events.
+// It blocks until the connection is broken or the context is cancelled and always returns the error causing the
+// disconnection.
+func (tracker *WebSocketBlockHeightTracker) subscribeNewBlock(ctx context.Context, onSubscribed func()) error {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, tracker.websocketUrl, nil)
+	if err != nil {
+		return fmt.Errorf("error dialing Tendermint websocket %s: %v", tracker.websocketUrl, err)
+	}
+	defer conn.Close()
+
+	// Closing the connection unblocks the pending read on cancellation
+	subscriptionDone := make(chan struct{})
+	defer close(subscriptionDone)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = conn.Close()
+		case <-subscriptionDone:
+		}
+	}()
+
+	if err = conn.SetReadDeadline(time.Now().Add(tracker.readTimeout)); err != nil {
+		return fmt.Errorf("error setting websocket read deadline: %v", err)
+	}
+	conn.SetPingHandler(func(appData string) error {
+		if deadlineErr := conn.SetReadDeadline(time.Now().Add(tracker.readTimeout)); deadlineErr != nil {
+			return deadlineErr
+		}
+		return conn.WriteControl(websocket.PongMessage, []byte(appData), time.Now().Add(time.Second))
+	})
+
+	if err = conn.WriteJSON(subscribeRequest{
+		Jsonrpc: "2.0",
+		Method:  "subscribe",
+		ID:      0,
+		Params: subscribeRequestParams{
+			Query: NEW_BLOCK_SUBSCRIPTION_QUERY,
+		},
+	}); err != nil {
+		return fmt.Errorf("error sending websocket subscribe request: %v", err)
+	}
+
+	// The subscription may have missed blocks produced while it was disconnected
+	tracker.poll()
+
+	subscribed := false
+	for {
+		_, message, readErr := conn.ReadMessage()
+		if readErr != nil {
+			return fmt.Errorf("error reading websocket message: %v", readErr)
+		}
+		if err = conn.SetReadDeadline(time.Now().Add(tracker.readTimeout)); err != nil {
+			return fmt.Errorf("error setting websocket read deadline: %v", err)
+		}
+
+		maybeHeight, parseErr := ParseNewBlockEventHeight(message)
+		if parseErr != nil {
+			return parseErr
+		}
+		if !subscribed {
+			subscribed = true
+			tracker.logger.Infof("subscribed to Tendermint NewBlock events on %s", tracker.websocketUrl)
+			onSubscribed()
+		}
+		if maybeHeight == nil {
+			continue
+		}
+
+		tracker.updateLatestBlockHeight(*maybeHeight)
+	}
+}
+
+// pollUntil polls the latest block height on regular interval until the provided channel fires. Returns false when
+// the context is cancelled.
+func (tracker *WebSocketBlockHeightTracker) pollUntil(ctx context.Context, untilCh <-chan time.Time) bool {
+	for {
+		tracker.poll()
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-untilCh:
+			return true
+		case <-time.After(tracker.pollingInterval):
+		}
+	}
+}
+
+func (tracker *WebSocketBlockHeightTracker) poll() {
+	height, err := tracker.client.LatestBlockHeight()
+	if err != nil {
+		tracker.logger.Errorf("error getting chain latest block height: %v", err)
+		return
+	}
+
+	tracker.updateLatestBlockHeight(height)
+}
+
+func (tracker *WebSocketBlockHeightTracker) updateLatestBlockHeight(height int64) {
+	tracker.rwMutex.Lock()
+	if tracker.latestBlockHeight != nil && *tracker.latestBlockHeight >= height {
+		tracker.rwMutex.Unlock()
+		return
+	}
+	tracker.latestBlockHeight = &height
+	tracker.rwMutex.Unlock()
+
+	tracker.subscriptionsMutex.RLock()
+	for _, subscription := range tracker.subscriptions {
+		subscription.publish(height)
+	}
+	tracker.subscriptionsMutex.RUnlock()
+
+	tracker.logger.Debugf("updated chain latest block height: %d", height)
+}
+
+// Subscribe registers a channel to receive latest block height. Unlike BlockHeightTracker, a busy
+// channel does not miss the update: it receives the latest height once it is ready again.
+func (tracker *WebSocketBlockHeightTracker) Subscribe(ch chan<- int64) {
+	subscription := newHeightSubscription(ch)
+
+	tracker.subscriptionsMutex.Lock()
+	tracker.subscriptions = append(tracker.subscriptions, subscription)
+	tracker.subscriptionsMutex.Unlock()
+
+	go subscription.run(tracker.done)
+}
+
+func (tracker *WebSocketBlockHeightTracker) GetLatestBlockHeight() *int64 {
+	tracker.rwMutex.RLock()
+	defer tracker.rwMutex.RUnlock()
+
+	return tracker.latestBlockHeight
+}
+
+// ParseNewBlockEventHeight parses a Tendermint WebSocket message and returns the block height when
+// it is a NewBlock event. Returns nil height for other messages such as subscription acknowledgement.
+func ParseNewBlockEventHeight(message []byte) (*int64, error) {
+	var resp newBlockEventResp
+	if err := jsoniter.Unmarshal(message, &resp); err != nil {
+		return nil, fmt.Errorf("error decoding websocket message: %v", err)
+	}
+
+	if resp.MaybeError != nil {
+		return nil, fmt.Errorf(
+			"error response from websocket: %s (%d): %s",
+			resp.MaybeError.Message, resp.MaybeError.Code, resp.MaybeError.Data,
+		)
+	}
+
+	rawHeight := resp.Result.Data.Value.Block.Header.Height
+	if rawHeight == "" {
+		return nil, nil
+	}
+	height, err := strconv.ParseInt(rawHeight, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing NewBlock event height: %v", err)
+	}
+
+	return &height, nil
+}
+
+// WebSocketURLFromHTTPRPCURL derives Tendermint WebSocket endpoint from its HTTP RPC URL
+func WebSocketURLFromHTTPRPCURL(httpRPCUrl string) (string, error) {
+	url := strings.TrimSuffix(httpRPCUrl, "/")
+	if strings.HasPrefix(url, "https://") {
+		return "wss://" + strings.TrimPrefix(url, "https://") + "/websocket", nil
+	} else if strings.HasPrefix(url, "http://") {
+		return "ws://" + strings.TrimPrefix(url, "http://") + "/websocket", nil
+	}
+
+	return "", errors.New("unsupported Tendermint HTTP RPC URL scheme")
+}
+
+// heightSubscription delivers heights to a subscriber channel. When the subscriber is busy, only
+// the latest pending height is kept.
+type heightSubscription struct {
+	ch      chan<- int64
+	pending chan int64
+
+	publishMutex sync.Mutex
+}
+
+func newHeightSubscription(ch chan<- int64) *heightSubscription {
+	return &heightSubscription{
+		ch:      ch,
+		pending: make(chan int64, 1),
+	}
+}
+
+func (subscription *heightSubscription) publish(height int64) {
+	subscription.publishMutex.Lock()
+	defer subscription.publishMutex.Unlock()
+
+	for {
+		select {
+		case subscription.pending <- height:
+			return
+		default:
+		}
+		// Replace the stale pending height
+		select {
+		case <-subscription.pending:
+		default:
+		}
+	}
+}
+
+// run delivers the pending heights to the subscriber channel until done is closed
+func (subscription *heightSubscription) run(done <-chan struct{}) {
+	for {
+		select {
+		case <-done:
+			return
+		case height := <-subscription.pending:
+			select {
+			case <-done:
+				return
+			case subscription.ch <- height:
+			}
+		}
+	}
+}
+
+type subscribeRequest struct {
+	Jsonrpc string                 `json:"jsonrpc"`
+	Method  string                 `json:"method"`
+	ID      int                    `json:"id"`
+	Params  subscribeRequestParams `json:"params"`
+}
+
+type subscribeRequestParams struct {
+	Query string `json:"query"`
+}
+
+type newBlockEventResp struct {
+	Result     newBlockEventRespResult `json:"result"`
+	MaybeError *websocketRespError     `json:"error"`
+}
+
+type newBlockEventRespResult struct {
+	Data struct {
+		Value struct {
+			Block struct {
+				Header struct {
+					Height string `json:"height"`
+				} `json:"header"`
+			} `json:"block"`
+		} `json:"value"`
+	} `json:"data"`
+}
+
+type websocketRespError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    string `json:"data"`
+}
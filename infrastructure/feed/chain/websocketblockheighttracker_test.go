@@ -0,0 +1,313 @@
+package chain_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/crypto-com/chain-indexing/external/logger/test"
+	"github.com/crypto-com/chain-indexing/external/primptr"
+	"github.com/crypto-com/chain-indexing/infrastructure/feed/chain"
+	usecase_model "github.com/crypto-com/chain-indexing/usecase/model"
+	"github.com/crypto-com/chain-indexing/usecase/model/genesis"
+)
+
+var _ = Describe("WebSocketBlockHeightTracker", func() {
+	var anyPollingInterval = 50 * time.Millisecond
+	var anyReconnectInterval = 100 * time.Millisecond
+
+	It("should publish heights pushed from the websocket NewBlock subscription", func() {
+		server := newFakeWebSocketServer()
+		defer server.Close()
+
+		client := &fakeLatestHeightClient{}
+		client.SetLatestBlockHeight(1)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		tracker := chain.NewWebSocketBlockHeightTrackerWithOptions(
+			ctx, NewFakeLogger(), client, server.URL(), chain.WebSocketBlockHeightTrackerOptions{
+				MaybePollingInterval:      &anyPollingInterval,
+				MaybeMinReconnectInterval: &anyReconnectInterval,
+				MaybeMaxReconnectInterval: &anyReconnectInterval,
+			},
+		)
+		heightCh := make(chan int64, 1)
+		tracker.Subscribe(heightCh)
+
+		Eventually(heightCh).Should(Receive(Equal(int64(1))))
+		Eventually(server.SubscribedQuery).Should(Equal(chain.NEW_BLOCK_SUBSCRIPTION_QUERY))
+
+		server.PushNewBlock(2)
+		Eventually(heightCh).Should(Receive(Equal(int64(2))))
+		server.PushNewBlock(3)
+		Eventually(heightCh).Should(Receive(Equal(int64(3))))
+		Expect(*tracker.GetLatestBlockHeight()).To(Equal(int64(3)))
+	})
+
+	It("should deliver the latest height to a busy subscriber once it is ready", func() {
+		server := newFakeWebSocketServer()
+		defer server.Close()
+
+		client := &fakeLatestHeightClient{}
+		client.SetLatestBlockHeight(1)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		tracker := chain.NewWebSocketBlockHeightTrackerWithOptions(
+			ctx, NewFakeLogger(), client, server.URL(), chain.WebSocketBlockHeightTrackerOptions{
+				MaybePollingInterval: &anyPollingInterval,
+			},
+		)
+		heightCh := make(chan int64)
+		tracker.Subscribe(heightCh)
+		Eventually(server.SubscribedQuery).Should(Equal(chain.NEW_BLOCK_SUBSCRIPTION_QUERY))
+
+		for height := int64(2); height <= 5; height += 1 {
+			server.PushNewBlock(height)
+		}
+		Eventually(func() *int64 {
+			return tracker.GetLatestBlockHeight()
+		}).Should(Equal(primptr.Int64(5)))
+
+		var lastReceived int64
+		Eventually(func() int64 {
+			select {
+			case lastReceived = <-heightCh:
+			default:
+			}
+			return lastReceived
+		}).Should(Equal(int64(5)))
+	})
+
+	It("should fall back to polling when the websocket is unavailable", func() {
+		server := httptest.NewServer(http.NotFoundHandler())
+		defer server.Close()
+
+		client := &fakeLatestHeightClient{}
+		client.SetLatestBlockHeight(10)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		tracker := chain.NewWebSocketBlockHeightTrackerWithOptions(
+			ctx, NewFakeLogger(), client, "ws"+strings.TrimPrefix(server.URL, "http"), chain.WebSocketBlockHeightTrackerOptions{
+				MaybePollingInterval:      &anyPollingInterval,
+				MaybeMinReconnectInterval: &anyReconnectInterval,
+				MaybeMaxReconnectInterval: &anyReconnectInterval,
+			},
+		)
+		heightCh := make(chan int64, 1)
+		tracker.Subscribe(heightCh)
+
+		Eventually(heightCh).Should(Receive(Equal(int64(10))))
+
+		client.SetLatestBlockHeight(11)
+		Eventually(heightCh).Should(Receive(Equal(int64(11))))
+	})
+
+	It("should close the websocket connection and stop publishing when the context is cancelled", func() {
+		server := newFakeWebSocketServer()
+		defer server.Close()
+
+		client := &fakeLatestHeightClient{}
+		client.SetLatestBlockHeight(1)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		tracker := chain.NewWebSocketBlockHeightTrackerWithOptions(
+			ctx, NewFakeLogger(), client, server.URL(), chain.WebSocketBlockHeightTrackerOptions{
+				MaybePollingInterval:      &anyPollingInterval,
+				MaybeMinReconnectInterval: &anyReconnectInterval,
+				MaybeMaxReconnectInterval: &anyReconnectInterval,
+			},
+		)
+		heightCh := make(chan int64, 1)
+		tracker.Subscribe(heightCh)
+		Eventually(heightCh).Should(Receive(Equal(int64(1))))
+		Eventually(server.SubscribedQuery).Should(Equal(chain.NEW_BLOCK_SUBSCRIPTION_QUERY))
+
+		cancel()
+
+		Eventually(server.IsDisconnected).Should(BeTrue())
+		client.SetLatestBlockHeight(2)
+		Consistently(heightCh, 3*anyReconnectInterval).ShouldNot(Receive())
+	})
+
+	It("should stop polling when the context is cancelled", func() {
+		server := httptest.NewServer(http.NotFoundHandler())
+		defer server.Close()
+
+		client := &fakeLatestHeightClient{}
+		client.SetLatestBlockHeight(10)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		tracker := chain.NewWebSocketBlockHeightTrackerWithOptions(
+			ctx, NewFakeLogger(), client, "ws"+strings.TrimPrefix(server.URL, "http"), chain.WebSocketBlockHeightTrackerOptions{
+				MaybePollingInterval:      &anyPollingInterval,
+				MaybeMinReconnectInterval: &anyReconnectInterval,
+				MaybeMaxReconnectInterval: &anyReconnectInterval,
+			},
+		)
+		heightCh := make(chan int64, 1)
+		tracker.Subscribe(heightCh)
+		Eventually(heightCh).Should(Receive(Equal(int64(10))))
+
+		cancel()
+
+		client.SetLatestBlockHeight(11)
+		Consistently(heightCh, 3*anyReconnectInterval).ShouldNot(Receive())
+	})
+
+	Describe("ParseNewBlockEventHeight", func() {
+		It("should return nil height for subscription acknowledgement", func() {
+			height, err := chain.ParseNewBlockEventHeight([]byte(`{"jsonrpc":"2.0","id":0,"result":{}}`))
+			Expect(err).To(BeNil())
+			Expect(height).To(BeNil())
+		})
+
+		It("should return error on error response", func() {
+			_, err := chain.ParseNewBlockEventHeight(
+				[]byte(`{"jsonrpc":"2.0","id":0,"error":{"code":-32603,"message":"Internal error","data":"already subscribed"}}`),
+			)
+			Expect(err).To(MatchError("error response from websocket: Internal error (-32603): already subscribed"))
+		})
+	})
+
+	Describe("WebSocketURLFromHTTPRPCURL", func() {
+		It("should derive websocket URL from HTTP RPC URL", func() {
+			Expect(chain.WebSocketURLFromHTTPRPCURL("https://rpc.example.com:26657/")).To(
+				Equal("wss://rpc.example.com:26657/websocket"),
+			)
+			Expect(chain.WebSocketURLFromHTTPRPCURL("http://localhost:26657")).To(
+				Equal("ws://localhost:26657/websocket"),
+			)
+		})
+	})
+})
+
+type fakeWebSocketServer struct {
+	server *httptest.Server
+
+	mutex           sync.Mutex
+	conn            *websocket.Conn
+	subscribedQuery string
+	disconnected    bool
+}
+
+func newFakeWebSocketServer() *fakeWebSocketServer {
+	fakeServer := &fakeWebSocketServer{}
+	upgrader := websocket.Upgrader{}
+	fakeServer.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+
+		var request struct {
+			ID     int `json:"id"`
+			Params struct {
+				Query string `json:"query"`
+			} `json:"params"`
+		}
+		if err := conn.ReadJSON(&request); err != nil {
+			return
+		}
+
+		fakeServer.mutex.Lock()
+		fakeServer.conn = conn
+		fakeServer.subscribedQuery = request.Params.Query
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"jsonrpc":"2.0","id":0,"result":{}}`))
+		fakeServer.mutex.Unlock()
+
+		// Keep connection open until client closes it
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				fakeServer.mutex.Lock()
+				fakeServer.disconnected = true
+				fakeServer.mutex.Unlock()
+				return
+			}
+		}
+	}))
+
+	return fakeServer
+}
+
+func (fakeServer *fakeWebSocketServer) URL() string {
+	return "ws" + strings.TrimPrefix(fakeServer.server.URL, "http") + "/websocket"
+}
+
+func (fakeServer *fakeWebSocketServer) SubscribedQuery() string {
+	fakeServer.mutex.Lock()
+	defer fakeServer.mutex.Unlock()
+
+	return fakeServer.subscribedQuery
+}
+
+func (fakeServer *fakeWebSocketServer) IsDisconnected() bool {
+	fakeServer.mutex.Lock()
+	defer fakeServer.mutex.Unlock()
+
+	return fakeServer.disconnected
+}
+
+func (fakeServer *fakeWebSocketServer) PushNewBlock(height int64) {
+	fakeServer.mutex.Lock()
+	defer fakeServer.mutex.Unlock()
+
+	message := fmt.Sprintf(
+		`{"jsonrpc":"2.0","id":0,"result":{"query":"tm.event='NewBlock'","data":{"type":"tendermint/event/NewBlock","value":{"block":{"header":{"height":"%d"}}}}}}`,
+		height,
+	)
+	Expect(fakeServer.conn.WriteMessage(websocket.TextMessage, []byte(message))).To(Succeed())
+}
+
+func (fakeServer *fakeWebSocketServer) Close() {
+	fakeServer.mutex.Lock()
+	if fakeServer.conn != nil {
+		_ = fakeServer.conn.Close()
+	}
+	fakeServer.mutex.Unlock()
+	fakeServer.server.Close()
+}
+
+type fakeLatestHeightClient struct {
+	mutex             sync.Mutex
+	latestBlockHeight int64
+}
+
+func (client *fakeLatestHeightClient) SetLatestBlockHeight(height int64) {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+
+	client.latestBlockHeight = height
+}
+
+func (client *fakeLatestHeightClient) Genesis() (*genesis.Genesis, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (client *fakeLatestHeightClient) Block(_ int64) (*usecase_model.Block, *usecase_model.RawBlock, error) {
+	return nil, nil, errors.New("not implemented")
+}
+
+func (client *fakeLatestHeightClient) BlockResults(_ int64) (*usecase_model.BlockResults, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (client *fakeLatestHeightClient) LatestBlockHeight() (int64, error) {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+
+	return client.latestBlockHeight, nil
+}
@@ -0,0 +1,39 @@
+package httpapi
+
+import (
+	"bytes"
+	"crypto/subtle"
+
+	"github.com/valyala/fasthttp"
+)
+
+// Key of the request user value holding the name of the token the request is authenticated with
+const AUTHENTICATED_NAME_USER_VALUE_KEY = "authenticatedName"
+
+var bearerPrefix = []byte("Bearer ")
+
+// BearerAuth returns a middleware authenticating the requests by the `Authorization: Bearer <token>` header against
+// the tokens by name. The name of the matched token is set to the request user value
+// AUTHENTICATED_NAME_USER_VALUE_KEY. Requests without any matched token are responded with unauthorized.
+func BearerAuth(tokens map[string]string) Middleware {
+	return func(handler fasthttp.RequestHandler) fasthttp.RequestHandler {
+		return func(ctx *fasthttp.RequestCtx) {
+			authorization := ctx.Request.Header.Peek(fasthttp.HeaderAuthorization)
+			if !bytes.HasPrefix(authorization, bearerPrefix) {
+				Unauthorized(ctx)
+				return
+			}
+			requestToken := authorization[len(bearerPrefix):]
+
+			for name, token := range tokens {
+				if token != "" && subtle.ConstantTimeCompare(requestToken, []byte(token)) == 1 {
+					ctx.SetUserValue(AUTHENTICATED_NAME_USER_VALUE_KEY, name)
+					handler(ctx)
+					return
+				}
+			}
+
+			Unauthorized(ctx)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package httpapi_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/valyala/fasthttp"
+
+	"github.com/crypto-com/chain-indexing/infrastructure/httpapi"
+)
+
+var _ = Describe("BearerAuth", func() {
+	var handledCount int
+	var handler fasthttp.RequestHandler
+
+	handle := func(tokens map[string]string, maybeAuthorization *string) *fasthttp.RequestCtx {
+		ctx := &fasthttp.RequestCtx{}
+		if maybeAuthorization != nil {
+			ctx.Request.Header.Set(fasthttp.HeaderAuthorization, *maybeAuthorization)
+		}
+		httpapi.BearerAuth(tokens)(handler)(ctx)
+		return ctx
+	}
+	authorization := func(value string) *string {
+		return &value
+	}
+
+	BeforeEach(func() {
+		handledCount = 0
+		handler = func(ctx *fasthttp.RequestCtx) {
+			handledCount += 1
+			ctx.SetStatusCode(fasthttp.StatusOK)
+		}
+	})
+
+	It("should handle request with a matched token and set the token name", func() {
+		ctx := handle(map[string]string{
+			"alice": "alice-token",
+			"bob":   "bob-token",
+		}, authorization("Bearer bob-token"))
+
+		Expect(handledCount).To(Equal(1))
+		Expect(ctx.Response.StatusCode()).To(Equal(fasthttp.StatusOK))
+		Expect(ctx.UserValue(httpapi.AUTHENTICATED_NAME_USER_VALUE_KEY)).To(Equal("bob"))
+	})
+
+	It("should respond unauthorized when Authorization header is missing", func() {
+		ctx := handle(map[string]string{"alice": "alice-token"}, nil)
+
+		Expect(handledCount).To(Equal(0))
+		Expect(ctx.Response.StatusCode()).To(Equal(fasthttp.StatusUnauthorized))
+		Expect(ctx.UserValue(httpapi.AUTHENTICATED_NAME_USER_VALUE_KEY)).To(BeNil())
+	})
+
+	It("should respond unauthorized when Authorization header is not a bearer token", func() {
+		ctx := handle(map[string]string{"alice": "alice-token"}, authorization("Basic alice-token"))
+
+		Expect(handledCount).To(Equal(0))
+		Expect(ctx.Response.StatusCode()).To(Equal(fasthttp.StatusUnauthorized))
+	})
+
+	It("should respond unauthorized when the token is wrong", func() {
+		ctx := handle(map[string]string{"alice": "alice-token"}, authorization("Bearer wrong-token"))
+
+		Expect(handledCount).To(Equal(0))
+		Expect(ctx.Response.StatusCode()).To(Equal(fasthttp.StatusUnauthorized))
+		Expect(ctx.UserValue(httpapi.AUTHENTICATED_NAME_USER_VALUE_KEY)).To(BeNil())
+	})
+
+	It("should never match an empty configured token", func() {
+		ctx := handle(map[string]string{"alice": ""}, authorization("Bearer "))
+
+		Expect(handledCount).To(Equal(0))
+		Expect(ctx.Response.StatusCode()).To(Equal(fasthttp.StatusUnauthorized))
+		Expect(ctx.UserValue(httpapi.AUTHENTICATED_NAME_USER_VALUE_KEY)).To(BeNil())
+	})
+
+	It("should respond unauthorized when no token is configured", func() {
+		ctx := handle(map[string]string{}, authorization("Bearer alice-token"))
+
+		Expect(handledCount).To(Equal(0))
+		Expect(ctx.Response.StatusCode()).To(Equal(fasthttp.StatusUnauthorized))
+	})
+})
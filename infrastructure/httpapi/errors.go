@@ -4,6 +4,7 @@ import "errors"
 
 var (
 	ErrInternalServerError = errors.New("internal server error")
+	ErrUnauthorized        = errors.New("unauthorized")
 
 	ErrInvalidPagination = errors.New("invalid pagination type")
 	ErrInvalidPage       = errors.New("invalid page number")
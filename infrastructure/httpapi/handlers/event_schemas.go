@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/valyala/fasthttp"
+
+	event_interface "github.com/crypto-com/chain-indexing/appinterface/event"
+	"github.com/crypto-com/chain-indexing/entity/event"
+	applogger "github.com/crypto-com/chain-indexing/external/logger"
+	"github.com/crypto-com/chain-indexing/infrastructure/httpapi"
+)
+
+type EventSchemas struct {
+	logger applogger.Logger
+
+	summaries      []EventSchemaSummary
+	encodedSchemas map[string]json.RawMessage
+}
+
+type EventSchemaSummary struct {
+	Name    string `json:"name"`
+	Version int    `json:"version"`
+	Id      string `json:"id"`
+}
+
+// NewEventSchemas creates the handler serving the JSON Schema of every event registered. The schemas are generated
+// once on creation.
+func NewEventSchemas(logger applogger.Logger, registry *event.Registry) (*EventSchemas, error) {
+	schemas, err := event_interface.GenerateSchemas(registry)
+	if err != nil {
+		return nil, fmt.Errorf("error generating event schemas: %v", err)
+	}
+
+	summaries := make([]EventSchemaSummary, 0, len(schemas))
+	encodedSchemas := make(map[string]json.RawMessage, len(schemas))
+	for _, schema := range schemas {
+		id := event_interface.SchemaId(schema.Name, schema.Version)
+		summaries = append(summaries, EventSchemaSummary{
+			Name:    schema.Name,
+			Version: schema.Version,
+			Id:      id,
+		})
+		encodedSchemas[id] = schema.Schema.MustMarshal()
+	}
+
+	return &EventSchemas{
+		logger.WithFields(applogger.LogFields{
+			"module": "EventSchemasHandler",
+		}),
+
+		summaries,
+		encodedSchemas,
+	}, nil
+}
+
+func (handler *EventSchemas) List(ctx *fasthttp.RequestCtx) {
+	httpapi.Success(ctx, handler.summaries)
+}
+
+func (handler *EventSchemas) FindBy(ctx *fasthttp.RequestCtx) {
+	nameParam, nameParamOk := URLValueGuard(ctx, handler.logger, "name")
+	if !nameParamOk {
+		return
+	}
+	versionParam, versionParamOk := URLValueGuard(ctx, handler.logger, "version")
+	if !versionParamOk {
+		return
+	}
+	version, err := strconv.Atoi(versionParam)
+	if err != nil {
+		httpapi.BadRequest(ctx, fmt.Errorf("invalid version: %s", versionParam))
+		return
+	}
+
+	encodedSchema, exist := handler.encodedSchemas[event_interface.SchemaId(nameParam, version)]
+	if !exist {
+		httpapi.NotFound(ctx)
+		return
+	}
+
+	httpapi.Success(ctx, encodedSchema)
+}
@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/valyala/fasthttp"
+
+	"github.com/crypto-com/chain-indexing/appinterface/projection/rdbauditstore"
+	"github.com/crypto-com/chain-indexing/appinterface/rdb"
+	projection_entity "github.com/crypto-com/chain-indexing/entity/projection"
+	applogger "github.com/crypto-com/chain-indexing/external/logger"
+	"github.com/crypto-com/chain-indexing/infrastructure/httpapi"
+)
+
+// ProjectionAdministrator controls the running projections individually
+type ProjectionAdministrator interface {
+	projection_entity.Controller
+
+	// RebuildProjection starts rebuilding the projection in background and calls onFinished once finished
+	RebuildProjection(projectionId string, onFinished func(error)) error
+}
+
+// ProjectionAdmin pauses, resumes, rewinds and rebuilds projections on behalf of the authenticated operators. Every
+// action is audited with its outcome.
+type ProjectionAdmin struct {
+	logger applogger.Logger
+
+	administrator ProjectionAdministrator
+	auditStore    *rdbauditstore.RDbAuditStore
+}
+
+func NewProjectionAdmin(
+	logger applogger.Logger,
+	rdbHandle *rdb.Handle,
+	administrator ProjectionAdministrator,
+) *ProjectionAdmin {
+	return &ProjectionAdmin{
+		logger.WithFields(applogger.LogFields{
+			"module": "ProjectionAdminHandler",
+		}),
+
+		administrator,
+		rdbauditstore.NewRDbAuditStore(rdbHandle),
+	}
+}
+
+func (handler *ProjectionAdmin) Pause(ctx *fasthttp.RequestCtx) {
+	idParam, idParamOk := URLValueGuard(ctx, handler.logger, "id")
+	if !idParamOk {
+		return
+	}
+
+	err := handler.administrator.PauseProjection(idParam)
+	handler.audit(ctx, idParam, rdbauditstore.ACTION_PAUSE, nil, err)
+	handler.respond(ctx, idParam, err)
+}
+
+func (handler *ProjectionAdmin) Resume(ctx *fasthttp.RequestCtx) {
+	idParam, idParamOk := URLValueGuard(ctx, handler.logger, "id")
+	if !idParamOk {
+		return
+	}
+
+	err := handler.administrator.ResumeProjection(idParam)
+	handler.audit(ctx, idParam, rdbauditstore.ACTION_RESUME, nil, err)
+	handler.respond(ctx, idParam, err)
+}
+
+// Rewind rewinds the paused projection to the height in the `height` query argument
+func (handler *ProjectionAdmin) Rewind(ctx *fasthttp.RequestCtx) {
+	idParam, idParamOk := URLValueGuard(ctx, handler.logger, "id")
+	if !idParamOk {
+		return
+	}
+
+	queryArgs := httpapi.NewQueryArgs(ctx.QueryArgs())
+	height, err := strconv.ParseInt(queryArgs.Get("height"), 10, 64)
+	if err != nil {
+		httpapi.BadRequest(ctx, errors.New("invalid height"))
+		return
+	}
+
+	err = handler.administrator.RewindProjection(idParam, height)
+	handler.audit(ctx, idParam, rdbauditstore.ACTION_REWIND, &height, err)
+	handler.respond(ctx, idParam, err)
+}
+
+// Rebuild starts rebuilding the projection. The outcome of the rebuild is audited once it has finished.
+func (handler *ProjectionAdmin) Rebuild(ctx *fasthttp.RequestCtx) {
+	idParam, idParamOk := URLValueGuard(ctx, handler.logger, "id")
+	if !idParamOk {
+		return
+	}
+
+	actor := authenticatedName(ctx)
+	remoteAddress := ctx.RemoteIP().String()
+	err := handler.administrator.RebuildProjection(idParam, func(rebuildErr error) {
+		handler.insertAudit(&rdbauditstore.AuditRow{
+			ProjectionId:  idParam,
+			Action:        rdbauditstore.ACTION_REBUILD_FINISHED,
+			Actor:         actor,
+			RemoteAddress: remoteAddress,
+			MaybeError:    errMessage(rebuildErr),
+		})
+	})
+	handler.audit(ctx, idParam, rdbauditstore.ACTION_REBUILD, nil, err)
+	handler.respond(ctx, idParam, err)
+}
+
+func (handler *ProjectionAdmin) ListAudits(ctx *fasthttp.RequestCtx) {
+	pagination, paginationError := httpapi.ParsePagination(ctx)
+	if paginationError != nil {
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		return
+	}
+
+	audits, paginationResult, err := handler.auditStore.List(pagination)
+	if err != nil {
+		handler.logger.Errorf("error listing projection admin audits: %v", err)
+		httpapi.InternalServerError(ctx)
+		return
+	}
+
+	httpapi.SuccessWithPagination(ctx, audits, paginationResult)
+}
+
+func (handler *ProjectionAdmin) audit(
+	ctx *fasthttp.RequestCtx,
+	projectionId string,
+	action string,
+	maybeHeight *int64,
+	actionErr error,
+) {
+	handler.insertAudit(&rdbauditstore.AuditRow{
+		ProjectionId:  projectionId,
+		Action:        action,
+		MaybeHeight:   maybeHeight,
+		Actor:         authenticatedName(ctx),
+		RemoteAddress: ctx.RemoteIP().String(),
+		MaybeError:    errMessage(actionErr),
+	})
+}
+
+func (handler *ProjectionAdmin) insertAudit(audit *rdbauditstore.AuditRow) {
+	logger := handler.logger.WithFields(applogger.LogFields{
+		"projection": audit.ProjectionId,
+		"action":     audit.Action,
+		"actor":      audit.Actor,
+	})
+	if audit.MaybeError == nil {
+		logger.Infof("projection admin action succeeded")
+	} else {
+		logger.Errorf("projection admin action failed: %s", *audit.MaybeError)
+	}
+
+	if err := handler.auditStore.Insert(audit); err != nil {
+		logger.Errorf("error auditing projection admin action: %v", err)
+	}
+}
+
+func (handler *ProjectionAdmin) respond(ctx *fasthttp.RequestCtx, projectionId string, actionErr error) {
+	if actionErr == nil {
+		httpapi.Success(ctx, projectionId)
+		return
+	}
+	if errors.Is(actionErr, projection_entity.ErrProjectionNotFound) {
+		httpapi.NotFound(ctx)
+		return
+	}
+
+	httpapi.BadRequest(ctx, fmt.Errorf("error controlling projection `%s`: %v", projectionId, actionErr))
+}
+
+func authenticatedName(ctx *fasthttp.RequestCtx) string {
+	name, _ := ctx.UserValue(httpapi.AUTHENTICATED_NAME_USER_VALUE_KEY).(string)
+	return name
+}
+
+func errMessage(err error) *string {
+	if err == nil {
+		return nil
+	}
+
+	message := err.Error()
+	return &message
+}
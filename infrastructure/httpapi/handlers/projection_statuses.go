@@ -0,0 +1,407 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/valyala/fasthttp"
+
+	event_interface "github.com/crypto-com/chain-indexing/appinterface/event"
+	"github.com/crypto-com/chain-indexing/appinterface/pagination"
+	"github.com/crypto-com/chain-indexing/appinterface/polling"
+	"github.com/crypto-com/chain-indexing/appinterface/projection/rdbfailurestore"
+	"github.com/crypto-com/chain-indexing/appinterface/projection/rdbprojectionbase"
+	"github.com/crypto-com/chain-indexing/appinterface/rdb"
+	entity_event "github.com/crypto-com/chain-indexing/entity/event"
+	projection_entity "github.com/crypto-com/chain-indexing/entity/projection"
+	applogger "github.com/crypto-com/chain-indexing/external/logger"
+	"github.com/crypto-com/chain-indexing/external/utctime"
+	"github.com/crypto-com/chain-indexing/infrastructure/httpapi"
+	event_usecase "github.com/crypto-com/chain-indexing/usecase/event"
+)
+
+// Projection without any status recorded by the failure store
+const PROJECTION_STATE_UNKNOWN = "UNKNOWN"
+
+// ProjectionStatusesStore finds the statuses and dead letters recorded for the projections
+type ProjectionStatusesStore interface {
+	ListStatuses() ([]rdbfailurestore.StatusRow, error)
+	FindStatusById(projectionId string) (*rdbfailurestore.StatusRow, error)
+	ListDeadLettersByProjectionId(
+		projectionId string,
+		pagination *pagination.Pagination,
+	) ([]rdbfailurestore.DeadLetterRow, *pagination.PaginationResult, error)
+}
+
+// ChainStatusView finds the chain statuses recorded by the indexing, e.g. the latest height
+type ChainStatusView interface {
+	FindBy(statusId string) (string, error)
+}
+
+// Constructors of the stores and views read by the projection statuses handler, which can be replaced in tests
+var (
+	NewProjectionsProgressStore = func(rdbHandle *rdb.Handle, table string) projection_entity.ProgressStore {
+		return rdbprojectionbase.NewProgressStore(rdbHandle, table)
+	}
+	NewProjectionStatusesStore = func(rdbHandle *rdb.Handle) ProjectionStatusesStore {
+		return rdbfailurestore.NewRDbFailureStore(rdbHandle)
+	}
+	NewChainStatusView = func(rdbHandle *rdb.Handle) ChainStatusView {
+		return polling.NewStatus(rdbHandle)
+	}
+	// NewBlockCreatedEventStore creates the event store to read the block time from the BlockCreated events
+	NewBlockCreatedEventStore = func(rdbHandle *rdb.Handle) entity_event.RangeStore {
+		registry := entity_event.NewRegistry()
+		registry.Register(event_usecase.BLOCK_CREATED, 1, event_usecase.DecodeBlockCreated)
+
+		return event_interface.NewRDbStore(rdbHandle, registry)
+	}
+)
+
+type ProjectionStatuses struct {
+	logger applogger.Logger
+
+	progressStore projection_entity.ProgressStore
+	statusesStore ProjectionStatusesStore
+	statusView    ChainStatusView
+	eventStore    entity_event.RangeStore
+
+	projectionIds []string
+	config        ProjectionStatusesConfig
+}
+
+// ProjectionStatusesConfig decides when the projections are ready to serve. ProgressTable is the table the projections
+// record their last handled heights. Projections are required when RequiredProjections is empty. Zero MaxLagBlocks and
+// MaxLagDuration disable the respective check.
+type ProjectionStatusesConfig struct {
+	ProgressTable       string
+	RequiredProjections []string
+	MaxLagBlocks        int64
+	MaxLagDuration      time.Duration
+}
+
+// NewProjectionStatuses creates the handler reporting the statuses and progress of the projections with the ids
+func NewProjectionStatuses(
+	logger applogger.Logger,
+	rdbHandle *rdb.Handle,
+	projectionIds []string,
+	config ProjectionStatusesConfig,
+) *ProjectionStatuses {
+	return &ProjectionStatuses{
+		logger.WithFields(applogger.LogFields{
+			"module": "ProjectionStatusesHandler",
+		}),
+
+		NewProjectionsProgressStore(rdbHandle, config.ProgressTable),
+		NewProjectionStatusesStore(rdbHandle),
+		NewChainStatusView(rdbHandle),
+		NewBlockCreatedEventStore(rdbHandle),
+
+		projectionIds,
+		config,
+	}
+}
+
+// List responds with the status of every enabled projection followed by the other projections recorded
+func (handler *ProjectionStatuses) List(ctx *fasthttp.RequestCtx) {
+	statuses, err := handler.listStatuses()
+	if err != nil {
+		handler.logger.Errorf("error listing projection statuses: %v", err)
+		httpapi.InternalServerError(ctx)
+		return
+	}
+
+	httpapi.Success(ctx, statuses)
+}
+
+func (handler *ProjectionStatuses) FindById(ctx *fasthttp.RequestCtx) {
+	idParam, idParamOk := URLValueGuard(ctx, handler.logger, "id")
+	if !idParamOk {
+		return
+	}
+
+	maybeRecordedStatus, err := handler.statusesStore.FindStatusById(idParam)
+	if err != nil {
+		if !errors.Is(err, rdb.ErrNoRows) {
+			handler.logger.Errorf("error finding projection status by id: %v", err)
+			httpapi.InternalServerError(ctx)
+			return
+		}
+		// Enabled projection has a status before the failure store records any
+		if !handler.isEnabled(idParam) {
+			httpapi.NotFound(ctx)
+			return
+		}
+	}
+
+	maybeChainTip, err := handler.chainTip()
+	if err != nil {
+		handler.logger.Errorf("error finding projection status by id: %v", err)
+		httpapi.InternalServerError(ctx)
+		return
+	}
+	status, err := handler.newStatus(idParam, maybeRecordedStatus, maybeChainTip, time.Now())
+	if err != nil {
+		handler.logger.Errorf("error finding projection status by id: %v", err)
+		httpapi.InternalServerError(ctx)
+		return
+	}
+
+	httpapi.Success(ctx, status)
+}
+
+func (handler *ProjectionStatuses) ListDeadLettersById(ctx *fasthttp.RequestCtx) {
+	idParam, idParamOk := URLValueGuard(ctx, handler.logger, "id")
+	if !idParamOk {
+		return
+	}
+
+	pagination, paginationError := httpapi.ParsePagination(ctx)
+	if paginationError != nil {
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		return
+	}
+
+	deadLetters, paginationResult, err := handler.statusesStore.ListDeadLettersByProjectionId(idParam, pagination)
+	if err != nil {
+		handler.logger.Errorf("error listing projection dead letters: %v", err)
+		httpapi.InternalServerError(ctx)
+		return
+	}
+
+	httpapi.SuccessWithPagination(ctx, deadLetters, paginationResult)
+}
+
+// Readiness responds with service unavailable when any required projection is failed, has not handled any height or
+// lags behind the chain tip more than allowed
+func (handler *ProjectionStatuses) Readiness(ctx *fasthttp.RequestCtx) {
+	statuses, err := handler.listStatuses()
+	if err != nil {
+		handler.logger.Errorf("error listing projection statuses: %v", err)
+		httpapi.InternalServerError(ctx)
+		return
+	}
+
+	notReadyReasons := make([]string, 0)
+	for _, status := range statuses {
+		if !handler.isRequired(status.Id) {
+			continue
+		}
+		if reason := handler.notReadyReason(&status); reason != "" {
+			notReadyReasons = append(notReadyReasons, fmt.Sprintf("%s: %s", status.Id, reason))
+		}
+	}
+
+	if len(notReadyReasons) == 0 {
+		httpapi.Success(ctx, notReadyReasons)
+		return
+	}
+
+	ctx.Response.Header.Set("Content-Type", "application/json")
+	message, err := jsoniter.Marshal(httpapi.Response{
+		Result: notReadyReasons,
+		Err:    "Not ready",
+	})
+	if err != nil {
+		httpapi.InternalServerError(ctx)
+		return
+	}
+	ctx.SetStatusCode(fasthttp.StatusServiceUnavailable)
+	ctx.SetBody(message)
+}
+
+func (handler *ProjectionStatuses) isEnabled(projectionId string) bool {
+	for _, enabledProjectionId := range handler.projectionIds {
+		if enabledProjectionId == projectionId {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (handler *ProjectionStatuses) isRequired(projectionId string) bool {
+	if len(handler.config.RequiredProjections) == 0 {
+		return handler.isEnabled(projectionId)
+	}
+	for _, requiredProjectionId := range handler.config.RequiredProjections {
+		if requiredProjectionId == projectionId {
+			return true
+		}
+	}
+
+	return false
+}
+
+// notReadyReason returns why the projection is not ready, empty when it is ready
+func (handler *ProjectionStatuses) notReadyReason(status *ProjectionStatus) string {
+	if status.State == projection_entity.STATE_FAILED {
+		if status.MaybeFailedHeight != nil && status.MaybeError != nil {
+			return fmt.Sprintf("projection is failed at height %d: %s", *status.MaybeFailedHeight, *status.MaybeError)
+		}
+		return "projection is failed"
+	}
+	if status.MaybeLastHandledHeight == nil {
+		return "projection has not handled any height"
+	}
+	if status.MaybeLagBlocks == nil {
+		return "chain tip is unknown"
+	}
+	if handler.config.MaxLagBlocks > 0 && *status.MaybeLagBlocks > handler.config.MaxLagBlocks {
+		return fmt.Sprintf("lagging %d blocks behind", *status.MaybeLagBlocks)
+	}
+	if handler.config.MaxLagDuration > 0 && status.MaybeLagSeconds != nil &&
+		time.Duration(*status.MaybeLagSeconds)*time.Second > handler.config.MaxLagDuration {
+		return fmt.Sprintf("lagging %d seconds behind", *status.MaybeLagSeconds)
+	}
+
+	return ""
+}
+
+func (handler *ProjectionStatuses) listStatuses() ([]ProjectionStatus, error) {
+	maybeChainTip, err := handler.chainTip()
+	if err != nil {
+		return nil, err
+	}
+
+	recordedStatuses, err := handler.statusesStore.ListStatuses()
+	if err != nil {
+		return nil, fmt.Errorf("error listing recorded projection statuses: %v", err)
+	}
+	recordedStatusesById := make(map[string]*rdbfailurestore.StatusRow, len(recordedStatuses))
+	for i, recordedStatus := range recordedStatuses {
+		recordedStatusesById[recordedStatus.Id] = &recordedStatuses[i]
+	}
+
+	projectionIds := make([]string, 0, len(handler.projectionIds)+len(recordedStatuses))
+	projectionIds = append(projectionIds, handler.projectionIds...)
+	for _, recordedStatus := range recordedStatuses {
+		if !handler.isEnabled(recordedStatus.Id) {
+			projectionIds = append(projectionIds, recordedStatus.Id)
+		}
+	}
+
+	now := time.Now()
+	statuses := make([]ProjectionStatus, 0, len(projectionIds))
+	for _, projectionId := range projectionIds {
+		status, err := handler.newStatus(projectionId, recordedStatusesById[projectionId], maybeChainTip, now)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, *status)
+	}
+
+	return statuses, nil
+}
+
+// newStatus returns the status of the projection with its progress against the chain tip. The recorded status is nil
+// when the failure store has not recorded any for the projection.
+func (handler *ProjectionStatuses) newStatus(
+	projectionId string,
+	maybeRecordedStatus *rdbfailurestore.StatusRow,
+	maybeChainTip *int64,
+	now time.Time,
+) (*ProjectionStatus, error) {
+	status := ProjectionStatus{
+		Id:            projectionId,
+		State:         PROJECTION_STATE_UNKNOWN,
+		MaybeChainTip: maybeChainTip,
+	}
+	if maybeRecordedStatus != nil {
+		status.State = maybeRecordedStatus.State
+		status.MaybeFailedHeight = maybeRecordedStatus.MaybeFailedHeight
+		status.MaybeError = maybeRecordedStatus.MaybeError
+		status.MaybeUpdatedAt = &maybeRecordedStatus.UpdatedAt
+	}
+
+	var err error
+	status.MaybeLastHandledHeight, err = handler.progressStore.GetLastHandledEventHeight(projectionId)
+	if err != nil {
+		return nil, fmt.Errorf("error getting last handled event height of %s: %v", projectionId, err)
+	}
+	if status.MaybeLastHandledHeight == nil {
+		return &status, nil
+	}
+
+	if maybeChainTip != nil {
+		lagBlocks := *maybeChainTip - *status.MaybeLastHandledHeight
+		if lagBlocks < 0 {
+			lagBlocks = 0
+		}
+		status.MaybeLagBlocks = &lagBlocks
+	}
+
+	status.MaybeLagSeconds, err = handler.lagSeconds(*status.MaybeLastHandledHeight, now)
+	if err != nil {
+		return nil, fmt.Errorf("error getting lag time of %s: %v", projectionId, err)
+	}
+
+	return &status, nil
+}
+
+// chainTip returns the latest height of the chain, nil when it is not known yet
+func (handler *ProjectionStatuses) chainTip() (*int64, error) {
+	latestHeight, err := handler.statusView.FindBy("LatestHeight")
+	if err != nil {
+		if errors.Is(err, rdb.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting chain latest height: %v", err)
+	}
+
+	chainTip, err := strconv.ParseInt(latestHeight, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing chain latest height: %v", err)
+	}
+
+	return &chainTip, nil
+}
+
+// lagSeconds returns the seconds since the block of the height was produced, nil when the BlockCreated event of the
+// height is not stored
+func (handler *ProjectionStatuses) lagSeconds(height int64, now time.Time) (*int64, error) {
+	// Genesis has no block
+	if height == 0 {
+		return nil, nil
+	}
+
+	var maybeBlockTime *time.Time
+	if err := handler.eventStore.StreamByHeightRange(
+		height, height, []string{event_usecase.BLOCK_CREATED}, func(event entity_event.Event) error {
+			blockCreated, ok := event.(*event_usecase.BlockCreated)
+			if !ok {
+				return fmt.Errorf("error casting event %s to BlockCreated", event.UUID())
+			}
+			blockTime := time.Unix(0, blockCreated.Block.Time.UnixNano())
+			maybeBlockTime = &blockTime
+			return nil
+		},
+	); err != nil {
+		return nil, fmt.Errorf("error reading BlockCreated event of height %d: %v", height, err)
+	}
+	if maybeBlockTime == nil {
+		return nil, nil
+	}
+
+	lagSeconds := int64(now.Sub(*maybeBlockTime).Seconds())
+	if lagSeconds < 0 {
+		lagSeconds = 0
+	}
+
+	return &lagSeconds, nil
+}
+
+type ProjectionStatus struct {
+	Id                     string           `json:"id"`
+	State                  string           `json:"state"`
+	MaybeLastHandledHeight *int64           `json:"lastHandledHeight"`
+	MaybeChainTip          *int64           `json:"chainTip"`
+	MaybeLagBlocks         *int64           `json:"lagBlocks"`
+	MaybeLagSeconds        *int64           `json:"lagSeconds"`
+	MaybeFailedHeight      *int64           `json:"failedHeight"`
+	MaybeError             *string          `json:"error"`
+	MaybeUpdatedAt         *utctime.UTCTime `json:"updatedAt"`
+}
@@ -0,0 +1,413 @@
+package handlers_test
+
+import (
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	"github.com/valyala/fasthttp"
+
+	"github.com/crypto-com/chain-indexing/appinterface/pagination"
+	"github.com/crypto-com/chain-indexing/appinterface/projection/rdbfailurestore"
+	"github.com/crypto-com/chain-indexing/appinterface/rdb"
+	entity_event "github.com/crypto-com/chain-indexing/entity/event"
+	event_entity_test "github.com/crypto-com/chain-indexing/entity/event/test"
+	projection_entity "github.com/crypto-com/chain-indexing/entity/projection"
+	projection_entity_test "github.com/crypto-com/chain-indexing/entity/projection/test"
+	logger_test "github.com/crypto-com/chain-indexing/external/logger/test"
+	"github.com/crypto-com/chain-indexing/external/primptr"
+	"github.com/crypto-com/chain-indexing/external/utctime"
+	"github.com/crypto-com/chain-indexing/infrastructure/httpapi/handlers"
+	event_usecase "github.com/crypto-com/chain-indexing/usecase/event"
+	usecase_model "github.com/crypto-com/chain-indexing/usecase/model"
+)
+
+var _ = Describe("ProjectionStatuses", func() {
+	var mockProgressStore *projection_entity_test.MockProgressStore
+	var mockStatusesStore *MockProjectionStatusesStore
+	var mockStatusView *MockChainStatusView
+	var mockEventStore *event_entity_test.MockRangeEventStore
+	var progressTable string
+
+	var originalNewProgressStore func(*rdb.Handle, string) projection_entity.ProgressStore
+	var originalNewStatusesStore func(*rdb.Handle) handlers.ProjectionStatusesStore
+	var originalNewStatusView func(*rdb.Handle) handlers.ChainStatusView
+	var originalNewEventStore func(*rdb.Handle) entity_event.RangeStore
+
+	BeforeEach(func() {
+		mockProgressStore = projection_entity_test.NewMockProgressStore()
+		mockStatusesStore = &MockProjectionStatusesStore{}
+		mockStatusView = &MockChainStatusView{}
+		mockEventStore = event_entity_test.NewMockRangeEventStore()
+		progressTable = ""
+
+		originalNewProgressStore = handlers.NewProjectionsProgressStore
+		originalNewStatusesStore = handlers.NewProjectionStatusesStore
+		originalNewStatusView = handlers.NewChainStatusView
+		originalNewEventStore = handlers.NewBlockCreatedEventStore
+		handlers.NewProjectionsProgressStore = func(_ *rdb.Handle, table string) projection_entity.ProgressStore {
+			progressTable = table
+			return mockProgressStore
+		}
+		handlers.NewProjectionStatusesStore = func(_ *rdb.Handle) handlers.ProjectionStatusesStore {
+			return mockStatusesStore
+		}
+		handlers.NewChainStatusView = func(_ *rdb.Handle) handlers.ChainStatusView {
+			return mockStatusView
+		}
+		handlers.NewBlockCreatedEventStore = func(_ *rdb.Handle) entity_event.RangeStore {
+			return mockEventStore
+		}
+	})
+
+	AfterEach(func() {
+		handlers.NewProjectionsProgressStore = originalNewProgressStore
+		handlers.NewProjectionStatusesStore = originalNewStatusesStore
+		handlers.NewChainStatusView = originalNewStatusView
+		handlers.NewBlockCreatedEventStore = originalNewEventStore
+	})
+
+	newProjectionStatuses := func(config handlers.ProjectionStatusesConfig) *handlers.ProjectionStatuses {
+		return handlers.NewProjectionStatuses(
+			logger_test.NewFakeLogger(), nil, []string{"Validator", "Account"}, config,
+		)
+	}
+
+	givenStatuses := func(validatorState string, accountState string) {
+		mockStatusesStore.On("ListStatuses").Return([]rdbfailurestore.StatusRow{
+			{Id: "Account", State: accountState},
+			{Id: "Validator", State: validatorState},
+		}, nil)
+	}
+
+	givenProgresses := func(validatorHeight int64, accountHeight int64, chainTip string) {
+		mockStatusView.On("FindBy", "LatestHeight").Return(chainTip, nil)
+		mockProgressStore.On("GetLastHandledEventHeight", "Validator").Return(primptr.Int64(validatorHeight), nil)
+		mockProgressStore.On("GetLastHandledEventHeight", "Account").Return(primptr.Int64(accountHeight), nil)
+	}
+
+	givenBlockTime := func(height int64, blockTime time.Time) {
+		mockEventStore.On(
+			"StreamByHeightRange", height, height, []string{event_usecase.BLOCK_CREATED},
+		).Return([]entity_event.Event{
+			event_usecase.NewBlockCreated(&usecase_model.Block{Height: height, Time: utctime.FromTime(blockTime)}),
+		}, nil)
+	}
+
+	listStatuses := func(projectionStatuses *handlers.ProjectionStatuses) []handlers.ProjectionStatus {
+		var ctx fasthttp.RequestCtx
+		projectionStatuses.List(&ctx)
+		Expect(ctx.Response.StatusCode()).To(Equal(fasthttp.StatusOK))
+
+		var response struct {
+			Result []handlers.ProjectionStatus `json:"result"`
+		}
+		Expect(jsoniter.Unmarshal(ctx.Response.Body(), &response)).To(Succeed())
+
+		return response.Result
+	}
+
+	Describe("List", func() {
+		It("should report the lag of the projections behind the chain tip from the block time of the events", func() {
+			givenStatuses(projection_entity.STATE_RUNNING, projection_entity.STATE_RUNNING)
+			givenProgresses(100, 90, "110")
+			givenBlockTime(100, time.Now().Add(-10*time.Second))
+			givenBlockTime(90, time.Now().Add(-60*time.Second))
+
+			statuses := listStatuses(newProjectionStatuses(handlers.ProjectionStatusesConfig{
+				ProgressTable: "custom_projections",
+			}))
+
+			Expect(progressTable).To(Equal("custom_projections"))
+			Expect(statuses).To(HaveLen(2))
+			Expect(statuses[0].Id).To(Equal("Validator"))
+			Expect(*statuses[0].MaybeChainTip).To(Equal(int64(110)))
+			Expect(*statuses[0].MaybeLagBlocks).To(Equal(int64(10)))
+			Expect(*statuses[0].MaybeLagSeconds).To(BeNumerically("~", 10, 2))
+			Expect(statuses[1].Id).To(Equal("Account"))
+			Expect(statuses[1].State).To(Equal(projection_entity.STATE_RUNNING))
+			Expect(*statuses[1].MaybeLastHandledHeight).To(Equal(int64(90)))
+			Expect(*statuses[1].MaybeLagBlocks).To(Equal(int64(20)))
+			Expect(*statuses[1].MaybeLagSeconds).To(BeNumerically("~", 60, 2))
+		})
+
+		It("should report the lag seconds as unavailable when the BlockCreated event of the height is not stored", func() {
+			givenStatuses(projection_entity.STATE_RUNNING, projection_entity.STATE_RUNNING)
+			givenProgresses(50, 40, "110")
+			mockEventStore.On("StreamByHeightRange", mock.Anything, mock.Anything, mock.Anything).Return(
+				[]entity_event.Event{}, nil,
+			)
+
+			statuses := listStatuses(newProjectionStatuses(handlers.ProjectionStatusesConfig{}))
+
+			Expect(statuses).To(HaveLen(2))
+			for _, status := range statuses {
+				Expect(status.MaybeLagBlocks).NotTo(BeNil())
+				Expect(status.MaybeLagSeconds).To(BeNil())
+			}
+		})
+
+		It("should report unknown state and lag when nothing is recorded yet", func() {
+			mockStatusesStore.On("ListStatuses").Return([]rdbfailurestore.StatusRow{}, nil)
+			mockStatusView.On("FindBy", "LatestHeight").Return("", rdb.ErrNoRows)
+			mockProgressStore.On("GetLastHandledEventHeight", mock.Anything).Return((*int64)(nil), nil)
+
+			statuses := listStatuses(newProjectionStatuses(handlers.ProjectionStatusesConfig{}))
+
+			Expect(statuses).To(HaveLen(2))
+			for _, status := range statuses {
+				Expect(status.State).To(Equal(handlers.PROJECTION_STATE_UNKNOWN))
+				Expect(status.MaybeLastHandledHeight).To(BeNil())
+				Expect(status.MaybeChainTip).To(BeNil())
+				Expect(status.MaybeLagBlocks).To(BeNil())
+				Expect(status.MaybeLagSeconds).To(BeNil())
+			}
+			mockEventStore.AssertNotCalled(GinkgoT(), "StreamByHeightRange", mock.Anything, mock.Anything, mock.Anything)
+		})
+
+		It("should list the projections with a recorded status which are not enabled after the enabled ones", func() {
+			mockStatusesStore.On("ListStatuses").Return([]rdbfailurestore.StatusRow{
+				{Id: "Account", State: projection_entity.STATE_RUNNING},
+				{Id: "Block", State: projection_entity.STATE_PAUSED},
+			}, nil)
+			mockStatusView.On("FindBy", "LatestHeight").Return("", rdb.ErrNoRows)
+			mockProgressStore.On("GetLastHandledEventHeight", mock.Anything).Return((*int64)(nil), nil)
+
+			statuses := listStatuses(newProjectionStatuses(handlers.ProjectionStatusesConfig{}))
+
+			Expect(statuses).To(HaveLen(3))
+			Expect(statuses[0].Id).To(Equal("Validator"))
+			Expect(statuses[0].State).To(Equal(handlers.PROJECTION_STATE_UNKNOWN))
+			Expect(statuses[1].Id).To(Equal("Account"))
+			Expect(statuses[1].State).To(Equal(projection_entity.STATE_RUNNING))
+			Expect(statuses[2].Id).To(Equal("Block"))
+			Expect(statuses[2].State).To(Equal(projection_entity.STATE_PAUSED))
+		})
+
+		It("should report the failed height and error recorded by the failure store", func() {
+			mockStatusesStore.On("ListStatuses").Return([]rdbfailurestore.StatusRow{
+				{
+					Id:                "Account",
+					State:             projection_entity.STATE_FAILED,
+					MaybeFailedHeight: primptr.Int64(91),
+					MaybeError:        primptr.String("error handling events"),
+					UpdatedAt:         utctime.FromUnixNano(int64(1000000000)),
+				},
+			}, nil)
+			givenProgresses(100, 90, "110")
+			mockEventStore.On("StreamByHeightRange", mock.Anything, mock.Anything, mock.Anything).Return(
+				[]entity_event.Event{}, nil,
+			)
+
+			statuses := listStatuses(newProjectionStatuses(handlers.ProjectionStatusesConfig{}))
+
+			Expect(statuses).To(HaveLen(2))
+			Expect(statuses[0].Id).To(Equal("Validator"))
+			Expect(statuses[0].MaybeFailedHeight).To(BeNil())
+			Expect(statuses[0].MaybeError).To(BeNil())
+			Expect(statuses[1].Id).To(Equal("Account"))
+			Expect(statuses[1].State).To(Equal(projection_entity.STATE_FAILED))
+			Expect(*statuses[1].MaybeLastHandledHeight).To(Equal(int64(90)))
+			Expect(*statuses[1].MaybeFailedHeight).To(Equal(int64(91)))
+			Expect(*statuses[1].MaybeError).To(Equal("error handling events"))
+			Expect(statuses[1].MaybeUpdatedAt.UnixNano()).To(Equal(int64(1000000000)))
+		})
+	})
+
+	Describe("FindById", func() {
+		findById := func(projectionStatuses *handlers.ProjectionStatuses, id string) *fasthttp.RequestCtx {
+			var ctx fasthttp.RequestCtx
+			ctx.SetUserValue("id", id)
+			projectionStatuses.FindById(&ctx)
+
+			return &ctx
+		}
+
+		It("should report the status of an enabled projection without a recorded status", func() {
+			mockStatusesStore.On("FindStatusById", "Validator").Return(
+				(*rdbfailurestore.StatusRow)(nil), rdb.ErrNoRows,
+			)
+			mockStatusView.On("FindBy", "LatestHeight").Return("110", nil)
+			mockProgressStore.On("GetLastHandledEventHeight", "Validator").Return(primptr.Int64(100), nil)
+			givenBlockTime(100, time.Now())
+
+			ctx := findById(newProjectionStatuses(handlers.ProjectionStatusesConfig{}), "Validator")
+
+			Expect(ctx.Response.StatusCode()).To(Equal(fasthttp.StatusOK))
+			var response struct {
+				Result handlers.ProjectionStatus `json:"result"`
+			}
+			Expect(jsoniter.Unmarshal(ctx.Response.Body(), &response)).To(Succeed())
+			Expect(response.Result.State).To(Equal(handlers.PROJECTION_STATE_UNKNOWN))
+			Expect(*response.Result.MaybeLagBlocks).To(Equal(int64(10)))
+		})
+
+		It("should report the failed height and error of a failed projection", func() {
+			mockStatusesStore.On("FindStatusById", "Account").Return(&rdbfailurestore.StatusRow{
+				Id:                "Account",
+				State:             projection_entity.STATE_FAILED,
+				MaybeFailedHeight: primptr.Int64(91),
+				MaybeError:        primptr.String("error handling events"),
+			}, nil)
+			mockStatusView.On("FindBy", "LatestHeight").Return("110", nil)
+			mockProgressStore.On("GetLastHandledEventHeight", "Account").Return(primptr.Int64(90), nil)
+			givenBlockTime(90, time.Now())
+
+			ctx := findById(newProjectionStatuses(handlers.ProjectionStatusesConfig{}), "Account")
+
+			Expect(ctx.Response.StatusCode()).To(Equal(fasthttp.StatusOK))
+			var response struct {
+				Result handlers.ProjectionStatus `json:"result"`
+			}
+			Expect(jsoniter.Unmarshal(ctx.Response.Body(), &response)).To(Succeed())
+			Expect(response.Result.State).To(Equal(projection_entity.STATE_FAILED))
+			Expect(*response.Result.MaybeFailedHeight).To(Equal(int64(91)))
+			Expect(*response.Result.MaybeError).To(Equal("error handling events"))
+			Expect(*response.Result.MaybeLagBlocks).To(Equal(int64(20)))
+		})
+
+		It("should respond not found when the projection is neither enabled nor recorded", func() {
+			mockStatusesStore.On("FindStatusById", "Unknown").Return(
+				(*rdbfailurestore.StatusRow)(nil), rdb.ErrNoRows,
+			)
+
+			ctx := findById(newProjectionStatuses(handlers.ProjectionStatusesConfig{}), "Unknown")
+
+			Expect(ctx.Response.StatusCode()).To(Equal(fasthttp.StatusNotFound))
+		})
+	})
+
+	Describe("Readiness", func() {
+		readiness := func(projectionStatuses *handlers.ProjectionStatuses) (int, []string) {
+			var ctx fasthttp.RequestCtx
+			projectionStatuses.Readiness(&ctx)
+
+			var response struct {
+				Result []string `json:"result"`
+			}
+			Expect(jsoniter.Unmarshal(ctx.Response.Body(), &response)).To(Succeed())
+
+			return ctx.Response.StatusCode(), response.Result
+		}
+
+		It("should be ready when the projections are within the lag limits", func() {
+			givenStatuses(projection_entity.STATE_RUNNING, projection_entity.STATE_RUNNING)
+			givenProgresses(100, 95, "100")
+			givenBlockTime(100, time.Now())
+			givenBlockTime(95, time.Now().Add(-5*time.Second))
+
+			statusCode, reasons := readiness(newProjectionStatuses(handlers.ProjectionStatusesConfig{
+				MaxLagBlocks:   10,
+				MaxLagDuration: time.Minute,
+			}))
+
+			Expect(statusCode).To(Equal(fasthttp.StatusOK))
+			Expect(reasons).To(BeEmpty())
+		})
+
+		It("should not be ready when a required projection is failed or lags too far behind", func() {
+			givenStatuses(projection_entity.STATE_RUNNING, projection_entity.STATE_FAILED)
+			givenProgresses(80, 95, "100")
+			givenBlockTime(80, time.Now().Add(-time.Minute))
+			givenBlockTime(95, time.Now())
+
+			statusCode, reasons := readiness(newProjectionStatuses(handlers.ProjectionStatusesConfig{
+				MaxLagBlocks: 10,
+			}))
+
+			Expect(statusCode).To(Equal(fasthttp.StatusServiceUnavailable))
+			Expect(reasons).To(ConsistOf(
+				"Validator: lagging 20 blocks behind",
+				"Account: projection is failed",
+			))
+		})
+
+		It("should report the failed height and error of a failed required projection", func() {
+			mockStatusesStore.On("ListStatuses").Return([]rdbfailurestore.StatusRow{
+				{
+					Id:                "Account",
+					State:             projection_entity.STATE_FAILED,
+					MaybeFailedHeight: primptr.Int64(96),
+					MaybeError:        primptr.String("error handling events"),
+				},
+			}, nil)
+			givenProgresses(100, 95, "100")
+			givenBlockTime(100, time.Now())
+			givenBlockTime(95, time.Now())
+
+			statusCode, reasons := readiness(newProjectionStatuses(handlers.ProjectionStatusesConfig{}))
+
+			Expect(statusCode).To(Equal(fasthttp.StatusServiceUnavailable))
+			Expect(reasons).To(ConsistOf(
+				"Account: projection is failed at height 96: error handling events",
+			))
+		})
+
+		It("should not be ready when a required projection lags too long behind the block time", func() {
+			givenStatuses(projection_entity.STATE_RUNNING, projection_entity.STATE_RUNNING)
+			givenProgresses(100, 100, "100")
+			givenBlockTime(100, time.Now().Add(-time.Hour))
+
+			statusCode, reasons := readiness(newProjectionStatuses(handlers.ProjectionStatusesConfig{
+				MaxLagDuration:      time.Minute,
+				RequiredProjections: []string{"Account"},
+			}))
+
+			Expect(statusCode).To(Equal(fasthttp.StatusServiceUnavailable))
+			Expect(reasons).To(HaveLen(1))
+			Expect(reasons[0]).To(HavePrefix("Account: lagging"))
+		})
+
+		It("should only check the required projections", func() {
+			givenStatuses(projection_entity.STATE_RUNNING, projection_entity.STATE_FAILED)
+			givenProgresses(100, 95, "100")
+			givenBlockTime(100, time.Now())
+			givenBlockTime(95, time.Now())
+
+			statusCode, _ := readiness(newProjectionStatuses(handlers.ProjectionStatusesConfig{
+				RequiredProjections: []string{"Validator"},
+			}))
+
+			Expect(statusCode).To(Equal(fasthttp.StatusOK))
+		})
+	})
+})
+
+type MockProjectionStatusesStore struct {
+	mock.Mock
+}
+
+func (store *MockProjectionStatusesStore) ListStatuses() ([]rdbfailurestore.StatusRow, error) {
+	mockArgs := store.Called()
+
+	return mockArgs.Get(0).([]rdbfailurestore.StatusRow), mockArgs.Error(1)
+}
+
+func (store *MockProjectionStatusesStore) FindStatusById(projectionId string) (*rdbfailurestore.StatusRow, error) {
+	mockArgs := store.Called(projectionId)
+
+	return mockArgs.Get(0).(*rdbfailurestore.StatusRow), mockArgs.Error(1)
+}
+
+func (store *MockProjectionStatusesStore) ListDeadLettersByProjectionId(
+	projectionId string,
+	paginationParams *pagination.Pagination,
+) ([]rdbfailurestore.DeadLetterRow, *pagination.PaginationResult, error) {
+	mockArgs := store.Called(projectionId, paginationParams)
+
+	return mockArgs.Get(0).([]rdbfailurestore.DeadLetterRow),
+		mockArgs.Get(1).(*pagination.PaginationResult),
+		mockArgs.Error(2)
+}
+
+type MockChainStatusView struct {
+	mock.Mock
+}
+
+func (view *MockChainStatusView) FindBy(statusId string) (string, error) {
+	mockArgs := view.Called(statusId)
+
+	return mockArgs.String(0), mockArgs.Error(1)
+}
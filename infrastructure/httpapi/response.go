@@ -64,6 +64,20 @@ func BadRequest(ctx *fasthttp.RequestCtx, errResp error) {
 	ctx.SetBody(message)
 }
 
+func Unauthorized(ctx *fasthttp.RequestCtx) {
+	ctx.Response.Header.Set("Content-Type", "application/json")
+	message, err := jsoniter.Marshal(Response{
+		Err: ErrUnauthorized.Error(),
+	})
+	if err != nil {
+		InternalServerError(ctx)
+		return
+	}
+
+	ctx.SetStatusCode(fasthttp.StatusUnauthorized)
+	ctx.SetBody(message)
+}
+
 func InternalServerError(ctx *fasthttp.RequestCtx) {
 	ctx.Response.Header.Set("Content-Type", "application/json")
 	message, _ := jsoniter.Marshal(Response{
@@ -14,6 +14,7 @@ import (
 type Server struct {
 	router           *router.Router
 	listeningAddress string
+	httpServer       *fasthttp.Server
 
 	middlewares      []Middleware
 	corsMiddleware   Middleware
@@ -26,6 +27,7 @@ func NewServer(listeningAddress string) *Server {
 	return &Server{
 		r,
 		listeningAddress,
+		&fasthttp.Server{},
 
 		middlewares,
 		nil,
@@ -108,7 +110,14 @@ func (server *Server) ListenAndServe() error {
 	for _, middleware := range server.middlewares {
 		handler = middleware(handler)
 	}
-	return fasthttp.ListenAndServe(server.listeningAddress, handler)
+	server.httpServer.Handler = handler
+	return server.httpServer.ListenAndServe(server.listeningAddress)
+}
+
+// Shutdown gracefully shuts down the server by closing the listener and waiting for all the open connections to be
+// idle. ListenAndServe returns nil once the server is shut down.
+func (server *Server) Shutdown() error {
+	return server.httpServer.Shutdown()
 }
 
 type Middleware = func(fasthttp.RequestHandler) fasthttp.RequestHandler
@@ -0,0 +1,35 @@
+package prometheus
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	leaderElectionIsLeaderName            = "leader_election_is_leader"
+	leaderElectionLeadershipsAcquiredName = "leader_election_leaderships_acquired_total"
+)
+
+var (
+	leaderElectionIsLeader = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: leaderElectionIsLeaderName,
+		},
+	)
+	leaderElectionLeadershipsAcquired = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: leaderElectionLeadershipsAcquiredName,
+		},
+	)
+)
+
+func RecordLeaderElectionIsLeader(isLeader bool) {
+	if isLeader {
+		leaderElectionIsLeader.Set(1)
+	} else {
+		leaderElectionIsLeader.Set(0)
+	}
+}
+
+func RecordLeaderElectionLeadershipAcquired() {
+	leaderElectionLeadershipsAcquired.Inc()
+}
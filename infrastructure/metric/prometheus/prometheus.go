@@ -29,4 +29,9 @@ func Run(path, port string) error {
 func register() {
 	Registerer.MustRegister(projectionExecTime)
 	Registerer.MustRegister(projectionLatestHeight)
+	Registerer.MustRegister(rpcEndpointRequestDuration)
+	Registerer.MustRegister(rpcEndpointRequestTotal)
+	Registerer.MustRegister(rpcEndpointHealthScore)
+	Registerer.MustRegister(leaderElectionIsLeader)
+	Registerer.MustRegister(leaderElectionLeadershipsAcquired)
 }
@@ -0,0 +1,78 @@
+package prometheus
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	rpcEndpointRequestDurationName = "rpc_endpoint_request_duration"
+	rpcEndpointRequestTotalName    = "rpc_endpoint_request_total"
+	rpcEndpointHealthScoreName     = "rpc_endpoint_health_score"
+	rpcEndpointClientLabel         = "client"
+	rpcEndpointEndpointLabel       = "endpoint"
+	rpcEndpointResultLabel         = "result"
+
+	rpcEndpointResultSuccess = "success"
+	rpcEndpointResultFailure = "failure"
+)
+
+var (
+	rpcEndpointRequestDuration = prometheus.NewSummaryVec(
+		prometheus.SummaryOpts{
+			Name: rpcEndpointRequestDurationName,
+		},
+		[]string{
+			rpcEndpointClientLabel,
+			rpcEndpointEndpointLabel,
+		},
+	)
+	rpcEndpointRequestTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: rpcEndpointRequestTotalName,
+		},
+		[]string{
+			rpcEndpointClientLabel,
+			rpcEndpointEndpointLabel,
+			rpcEndpointResultLabel,
+		},
+	)
+	rpcEndpointHealthScore = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: rpcEndpointHealthScoreName,
+		},
+		[]string{
+			rpcEndpointClientLabel,
+			rpcEndpointEndpointLabel,
+		},
+	)
+)
+
+func RecordRPCEndpointRequest(clientName string, endpoint string, success bool, timeInMilliseconds int64) {
+	result := rpcEndpointResultSuccess
+	if !success {
+		result = rpcEndpointResultFailure
+	}
+
+	rpcEndpointRequestDuration.With(
+		prometheus.Labels{
+			rpcEndpointClientLabel:   clientName,
+			rpcEndpointEndpointLabel: endpoint,
+		},
+	).Observe(float64(timeInMilliseconds))
+	rpcEndpointRequestTotal.With(
+		prometheus.Labels{
+			rpcEndpointClientLabel:   clientName,
+			rpcEndpointEndpointLabel: endpoint,
+			rpcEndpointResultLabel:   result,
+		},
+	).Inc()
+}
+
+func RecordRPCEndpointHealthScore(clientName string, endpoint string, score float64) {
+	rpcEndpointHealthScore.With(
+		prometheus.Labels{
+			rpcEndpointClientLabel:   clientName,
+			rpcEndpointEndpointLabel: endpoint,
+		},
+	).Set(score)
+}
@@ -0,0 +1,13 @@
+package multiendpoint_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestMultiEndpoint(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "MultiEndpoint Suite")
+}
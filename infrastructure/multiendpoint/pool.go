@@ -0,0 +1,231 @@
+package multiendpoint
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/crypto-com/chain-indexing/infrastructure/metric/prometheus"
+)
+
+const DEFAULT_MIN_COOLDOWN = 1 * time.Second
+const DEFAULT_MAX_COOLDOWN = 5 * time.Minute
+
+// Weight of the latest observation in the exponentially weighted moving averages
+const EWMA_WEIGHT = 0.2
+
+// Floor of health score to avoid dividing by zero when ranking endpoints
+const MIN_HEALTH_SCORE = 0.01
+
+// Pool routes requests to a list of endpoints by their health score and latency. A request fails
+// over to the next endpoint when it returns a failover error.
+type Pool struct {
+	// Name of the pool used as metric label
+	name      string
+	endpoints []*endpoint
+	mutex     sync.Mutex
+
+	isFailoverError func(error) bool
+
+	minCooldown time.Duration
+	maxCooldown time.Duration
+}
+
+type endpoint struct {
+	url string
+
+	// Exponentially weighted moving average of request success rate, from 0 to 1
+	healthScore float64
+	// Exponentially weighted moving average of request latency
+	latency time.Duration
+
+	consecutiveFailures int
+	cooldownUntil       time.Time
+}
+
+func NewPool(name string, urls []string, isFailoverError func(error) bool) *Pool {
+	endpoints := make([]*endpoint, 0, len(urls))
+	for _, url := range urls {
+		endpoints = append(endpoints, &endpoint{
+			url: url,
+
+			healthScore: 1,
+			latency:     0,
+		})
+		prometheus.RecordRPCEndpointHealthScore(name, url, 1)
+	}
+
+	return &Pool{
+		name:      name,
+		endpoints: endpoints,
+
+		isFailoverError: isFailoverError,
+
+		minCooldown: DEFAULT_MIN_COOLDOWN,
+		maxCooldown: DEFAULT_MAX_COOLDOWN,
+	}
+}
+
+// Do executes the request on the healthiest endpoint first. When the request returns a failover
+// error, it is retried on the next endpoint. Returns the last error when all endpoints failed.
+func (pool *Pool) Do(request func(url string) error) error {
+	var lastErr error
+	for _, url := range pool.rankedURLs() {
+		startTime := time.Now()
+		err := request(url)
+		elapsed := time.Since(startTime)
+
+		if err != nil && pool.isFailoverError(err) {
+			pool.recordFailure(url, elapsed)
+			lastErr = err
+			continue
+		}
+
+		// Non-failover errors are responded by the endpoint and are considered healthy
+		pool.recordSuccess(url, elapsed)
+		return err
+	}
+
+	if lastErr == nil {
+		return errors.New("no endpoint available")
+	}
+	return fmt.Errorf("error requesting all endpoints, last error: %w", lastErr)
+}
+
+// URLs returns the endpoint URLs ranked by their health
+func (pool *Pool) URLs() []string {
+	return pool.rankedURLs()
+}
+
+// rankedURLs returns endpoints outside of cooldown first, each group ordered by expected latency
+// per successful request.
+func (pool *Pool) rankedURLs() []string {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+
+	now := time.Now()
+	endpoints := make([]*endpoint, len(pool.endpoints))
+	copy(endpoints, pool.endpoints)
+	sort.SliceStable(endpoints, func(i, j int) bool {
+		iCoolingDown := endpoints[i].cooldownUntil.After(now)
+		jCoolingDown := endpoints[j].cooldownUntil.After(now)
+		if iCoolingDown != jCoolingDown {
+			return !iCoolingDown
+		}
+		return endpoints[i].cost() < endpoints[j].cost()
+	})
+
+	urls := make([]string, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		urls = append(urls, endpoint.url)
+	}
+	return urls
+}
+
+func (pool *Pool) recordSuccess(url string, elapsed time.Duration) {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+
+	endpoint := pool.findEndpoint(url)
+	endpoint.observe(1, elapsed)
+	endpoint.consecutiveFailures = 0
+	endpoint.cooldownUntil = time.Time{}
+
+	prometheus.RecordRPCEndpointRequest(pool.name, url, true, elapsed.Milliseconds())
+	prometheus.RecordRPCEndpointHealthScore(pool.name, url, endpoint.healthScore)
+}
+
+func (pool *Pool) recordFailure(url string, elapsed time.Duration) {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+
+	endpoint := pool.findEndpoint(url)
+	endpoint.observe(0, elapsed)
+	endpoint.consecutiveFailures += 1
+
+	cooldown := pool.minCooldown << uint(endpoint.consecutiveFailures-1)
+	if cooldown <= 0 || cooldown > pool.maxCooldown {
+		cooldown = pool.maxCooldown
+	}
+	endpoint.cooldownUntil = time.Now().Add(cooldown)
+
+	prometheus.RecordRPCEndpointRequest(pool.name, url, false, elapsed.Milliseconds())
+	prometheus.RecordRPCEndpointHealthScore(pool.name, url, endpoint.healthScore)
+}
+
+func (pool *Pool) findEndpoint(url string) *endpoint {
+	for _, endpoint := range pool.endpoints {
+		if endpoint.url == url {
+			return endpoint
+		}
+	}
+	panic(fmt.Sprintf("unknown endpoint: %s", url))
+}
+
+func (endpoint *endpoint) observe(success float64, elapsed time.Duration) {
+	endpoint.healthScore = EWMA_WEIGHT*success + (1-EWMA_WEIGHT)*endpoint.healthScore
+	if endpoint.latency == 0 {
+		endpoint.latency = elapsed
+	} else {
+		endpoint.latency = time.Duration(EWMA_WEIGHT*float64(elapsed) + (1-EWMA_WEIGHT)*float64(endpoint.latency))
+	}
+}
+
+// cost is the expected latency for a successful request
+func (endpoint *endpoint) cost() float64 {
+	healthScore := endpoint.healthScore
+	if healthScore < MIN_HEALTH_SCORE {
+		healthScore = MIN_HEALTH_SCORE
+	}
+	return float64(endpoint.latency) / healthScore
+}
+
+// HTTPStatusError is returned when an endpoint responds with an unsuccessful HTTP status
+type HTTPStatusError struct {
+	StatusCode int
+	Status     string
+}
+
+func NewHTTPStatusError(statusCode int, status string) *HTTPStatusError {
+	return &HTTPStatusError{
+		StatusCode: statusCode,
+		Status:     status,
+	}
+}
+
+func (err *HTTPStatusError) Error() string {
+	if err.Status != "" {
+		return err.Status
+	}
+	return fmt.Sprintf("status code %d", err.StatusCode)
+}
+
+// IsFailoverError returns true when the error indicates the endpoint is unable to serve the
+// request: server errors, rate limiting, timeouts and connection failures.
+func IsFailoverError(err error) bool {
+	var statusErr *HTTPStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= 500 || statusErr.StatusCode == 429
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
+// IsHeightNotAvailableError returns true when the error indicates the endpoint does not have the
+// requested block height, either it is pruned or the endpoint has not caught up yet.
+func IsHeightNotAvailableError(err error) bool {
+	message := err.Error()
+	return strings.Contains(message, "is not available") ||
+		strings.Contains(message, "must be less than or equal to the current blockchain height")
+}
@@ -0,0 +1,126 @@
+package multiendpoint_test
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/crypto-com/chain-indexing/infrastructure/multiendpoint"
+)
+
+var _ = Describe("Pool", func() {
+	It("should fail over to next endpoint on failover error", func() {
+		pool := multiendpoint.NewPool("test", []string{"a", "b"}, multiendpoint.IsFailoverError)
+
+		requested := make([]string, 0)
+		err := pool.Do(func(url string) error {
+			requested = append(requested, url)
+			if url == "a" {
+				return fmt.Errorf("error requesting: %w", multiendpoint.NewHTTPStatusError(502, "502 Bad Gateway"))
+			}
+			return nil
+		})
+
+		Expect(err).To(BeNil())
+		Expect(requested).To(Equal([]string{"a", "b"}))
+	})
+
+	It("should not fail over on non-failover error", func() {
+		pool := multiendpoint.NewPool("test", []string{"a", "b"}, multiendpoint.IsFailoverError)
+
+		anyErr := errors.New("account not found")
+		requested := make([]string, 0)
+		err := pool.Do(func(url string) error {
+			requested = append(requested, url)
+			return anyErr
+		})
+
+		Expect(err).To(Equal(anyErr))
+		Expect(requested).To(Equal([]string{"a"}))
+	})
+
+	It("should return the last error when all endpoints failed", func() {
+		pool := multiendpoint.NewPool("test", []string{"a", "b"}, multiendpoint.IsFailoverError)
+
+		err := pool.Do(func(url string) error {
+			return multiendpoint.NewHTTPStatusError(503, fmt.Sprintf("503 from %s", url))
+		})
+
+		Expect(err).To(MatchError("error requesting all endpoints, last error: 503 from b"))
+	})
+
+	It("should rank failed endpoint after healthy endpoints", func() {
+		pool := multiendpoint.NewPool("test", []string{"a", "b", "c"}, multiendpoint.IsFailoverError)
+
+		_ = pool.Do(func(url string) error {
+			if url == "a" {
+				return multiendpoint.NewHTTPStatusError(500, "500 Internal Server Error")
+			}
+			return nil
+		})
+
+		Expect(pool.URLs()[2]).To(Equal("a"))
+	})
+
+	It("should prefer endpoint with lower latency", func() {
+		pool := multiendpoint.NewPool("test", []string{"slow", "fast"}, multiendpoint.IsFailoverError)
+
+		for _, targetUrl := range []string{"slow", "fast"} {
+			_ = pool.Do(func(url string) error {
+				if url != targetUrl {
+					return multiendpoint.NewHTTPStatusError(500, "500 Internal Server Error")
+				}
+				if url == "slow" {
+					<-time.After(20 * time.Millisecond)
+				}
+				return nil
+			})
+		}
+		// Let both endpoints recover from the failures above
+		for i := 0; i < 20; i += 1 {
+			for _, targetUrl := range pool.URLs() {
+				_ = pool.Do(func(url string) error {
+					if url != targetUrl {
+						return nil
+					}
+					if url == "slow" {
+						<-time.After(20 * time.Millisecond)
+					}
+					return nil
+				})
+			}
+		}
+
+		Expect(pool.URLs()[0]).To(Equal("fast"))
+	})
+
+	Describe("IsFailoverError", func() {
+		It("should return true for server errors, rate limiting and connection errors", func() {
+			Expect(multiendpoint.IsFailoverError(multiendpoint.NewHTTPStatusError(500, ""))).To(BeTrue())
+			Expect(multiendpoint.IsFailoverError(multiendpoint.NewHTTPStatusError(429, ""))).To(BeTrue())
+			Expect(multiendpoint.IsFailoverError(
+				fmt.Errorf("error requesting: %w", &url.Error{Op: "Get", URL: "http://a", Err: errors.New("refused")}),
+			)).To(BeTrue())
+		})
+
+		It("should return false for client errors", func() {
+			Expect(multiendpoint.IsFailoverError(multiendpoint.NewHTTPStatusError(404, ""))).To(BeFalse())
+			Expect(multiendpoint.IsFailoverError(errors.New("any error"))).To(BeFalse())
+		})
+	})
+
+	Describe("IsHeightNotAvailableError", func() {
+		It("should return true for Tendermint unavailable height errors", func() {
+			Expect(multiendpoint.IsHeightNotAvailableError(
+				errors.New("height 10 is not available, lowest height is 200"),
+			)).To(BeTrue())
+			Expect(multiendpoint.IsHeightNotAvailableError(
+				errors.New("height 300 must be less than or equal to the current blockchain height 200"),
+			)).To(BeTrue())
+		})
+	})
+})
@@ -38,6 +38,18 @@ func (fmh *FilesystemMigrationHelper) Migrate() {
 	}
 }
 
+// Implement DownMigrationHelper interface
+func (fmh *FilesystemMigrationHelper) MigrateDown() {
+	m, err := migrate.New(fmh.sourceURL, fmh.databaseURL)
+	if err != nil {
+		panic(fmt.Errorf("failed to init migration: %v", err))
+	}
+
+	if err := m.Down(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		panic(fmt.Errorf("failed to run down migration: %v", err))
+	}
+}
+
 // Generate the source URL to migration source files.
 // `sourceFolder` could be absolute or relative path.
 func GenerateSourceURL(sourceFolder string) string {
@@ -49,6 +49,18 @@ func (gmh *GithubMigrationHelper) Migrate() {
 	}
 }
 
+// Implement DownMigrationHelper interface
+func (gmh *GithubMigrationHelper) MigrateDown() {
+	m, err := migrate.New(gmh.sourceURL, gmh.databaseURL)
+	if err != nil {
+		panic(fmt.Errorf("failed to init migration: %v", err))
+	}
+
+	if err := m.Down(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		panic(fmt.Errorf("failed to run down migration: %v", err))
+	}
+}
+
 // Generate the Github URL to migration source files
 func GenerateSourceURL(
 	format string,
@@ -10,6 +10,14 @@ type MigrationHelper interface {
 	Migrate()
 }
 
+// DownMigrationHelper is an optional interface of MigrationHelper to revert all the migrations, e.g. when rebuilding a
+// projection from scratch
+type DownMigrationHelper interface {
+	MigrationHelper
+
+	MigrateDown()
+}
+
 const MIGRATION_TABLE_NAME_FORMAT = "%s_schema_migrations"
 
 // Generate PostgreSQL DB conn string with customized migration table name
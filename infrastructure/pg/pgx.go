@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/url"
 	"time"
 
 	sq "github.com/Masterminds/squirrel"
@@ -61,6 +62,7 @@ func (config *PgxConnPoolConfig) ToURL() string {
 }
 
 var _ rdb.Conn = &PgxConn{}
+var _ rdb.Listener = &PgxConn{}
 
 type PgxConn struct {
 	// pgxConn could be simple connection or connection pool
@@ -157,6 +159,75 @@ func (conn *PgxConn) ToHandle() *rdb.Handle {
 		StmtBuilder: PostgresStmtBuilder,
 	}
 }
+
+// Listen acquires a dedicated connection from the pool to LISTEN on the channel and calls the handler with the payload
+// of each notification in background. The connection is released once the listening ends.
+func (conn *PgxConn) Listen(ctx context.Context, channel string, handler func(payload string)) (<-chan error, error) {
+	pool, ok := conn.pgxConn.(*pgxpool.Pool)
+	if !ok {
+		return nil, fmt.Errorf("error listening to channel: %w", rdb.ErrListenUnsupported)
+	}
+	poolConn, err := pool.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error acquiring connection to listen to channel: %v", err)
+	}
+
+	listenConn := poolConn.Conn()
+	if _, err = listenConn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+		poolConn.Release()
+		return nil, fmt.Errorf("error listening to channel %s: %v", channel, err)
+	}
+
+	doneCh := make(chan error, 1)
+	go func() {
+		defer close(doneCh)
+		defer poolConn.Release()
+		defer func() {
+			// Connection is returned to the pool, it should not receive notifications anymore
+			_, _ = listenConn.Exec(context.Background(), "UNLISTEN "+pgx.Identifier{channel}.Sanitize())
+		}()
+
+		for {
+			notification, waitErr := listenConn.WaitForNotification(ctx)
+			if waitErr != nil {
+				doneCh <- fmt.Errorf("error waiting for notification of channel %s: %w", channel, waitErr)
+				return
+			}
+			handler(notification.Payload)
+		}
+	}()
+
+	return doneCh, nil
+}
+
+// TryAdvisoryLock acquires a dedicated connection from the pool and tries to acquire the session advisory lock of the
+// key on it. The connection is held until the lock is released.
+func (conn *PgxConn) TryAdvisoryLock(ctx context.Context, key int64) (rdb.AdvisoryLock, error) {
+	pool, ok := conn.pgxConn.(*pgxpool.Pool)
+	if !ok {
+		return nil, errors.New("error trying advisory lock: advisory lock requires a connection pool")
+	}
+	poolConn, err := pool.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error acquiring connection to try advisory lock: %v", err)
+	}
+
+	var acquired bool
+	if err = poolConn.QueryRow(ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&acquired); err != nil {
+		poolConn.Release()
+		return nil, fmt.Errorf("error trying advisory lock %d: %v", key, err)
+	}
+	if !acquired {
+		poolConn.Release()
+		return nil, nil
+	}
+
+	return &PgxAdvisoryLock{
+		poolConn,
+		key,
+	}, nil
+}
+
 func (conn *PgxConn) ConnString() string {
 	pool := conn.pgxConn.(*pgxpool.Pool)
 	authStr := pool.Config().ConnConfig.Config.User + ":" + pool.Config().ConnConfig.Config.Password + "@"
@@ -168,6 +239,14 @@ func (conn *PgxConn) ConnString() string {
 		pool.Config().ConnConfig.Config.Port,
 		pool.Config().ConnConfig.Config.Database,
 	)
+	if searchPath, ok := pool.Config().ConnConfig.RuntimeParams["search_path"]; ok {
+		connStr = connStr + "?search_path=" + url.QueryEscape(searchPath)
+		if pool.Config().ConnConfig.TLSConfig != nil {
+			return connStr
+		} else {
+			return connStr + "&sslmode=disable"
+		}
+	}
 	if pool.Config().ConnConfig.TLSConfig != nil {
 		return connStr + "?"
 	} else {
@@ -175,8 +254,61 @@ func (conn *PgxConn) ConnString() string {
 	}
 }
 
+// WithSearchPath returns a new connection pool to the same database with the schema search path, e.g. to build tables
+// in a separate schema. The returned pool should be closed once no longer needed.
+func (conn *PgxConn) WithSearchPath(searchPath string) (*PgxConn, error) {
+	pool, ok := conn.pgxConn.(*pgxpool.Pool)
+	if !ok {
+		return nil, errors.New("error setting search path: search path requires a connection pool")
+	}
+
+	pgxConfig := pool.Config()
+	pgxConfig.ConnConfig.RuntimeParams["search_path"] = searchPath
+	searchPathPool, err := pgxpool.ConnectConfig(context.Background(), pgxConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting with search path %s: %v", searchPath, err)
+	}
+
+	return &PgxConn{
+		searchPathPool,
+	}, nil
+}
+
+// Close closes the connection or all the connections of the pool
+func (conn *PgxConn) Close() {
+	switch pgxConn := conn.pgxConn.(type) {
+	case *pgxpool.Pool:
+		pgxConn.Close()
+	case *pgx.Conn:
+		_ = pgxConn.Close(context.Background())
+	}
+}
+
 var _ rdb.Tx = &PgxRDbTx{}
 
+// PgxAdvisoryLock is a session advisory lock held by a dedicated connection of the pool
+type PgxAdvisoryLock struct {
+	poolConn *pgxpool.Conn
+	key      int64
+}
+
+func (lock *PgxAdvisoryLock) Ping(ctx context.Context) error {
+	return lock.poolConn.Conn().Ping(ctx)
+}
+
+// Release unlocks the lock and returns the connection to the pool. The connection is closed instead when it fails to
+// unlock, such that the database releases the lock with the session.
+func (lock *PgxAdvisoryLock) Release() error {
+	defer lock.poolConn.Release()
+
+	if _, err := lock.poolConn.Exec(context.Background(), "SELECT pg_advisory_unlock($1)", lock.key); err != nil {
+		_ = lock.poolConn.Conn().Close(context.Background())
+		return fmt.Errorf("error unlocking advisory lock %d: %v", lock.key, err)
+	}
+
+	return nil
+}
+
 type PgxRDbTx struct {
 	tx pgx.Tx
 }
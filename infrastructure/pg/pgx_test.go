@@ -1,13 +1,16 @@
 package pg_test
 
 import (
+	"context"
 	"time"
 
+	. "github.com/crypto-com/chain-indexing/external/logger/test"
 	"github.com/crypto-com/chain-indexing/external/primptr"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
 	. "github.com/crypto-com/chain-indexing/infrastructure/pg"
+	. "github.com/crypto-com/chain-indexing/test"
 )
 
 var _ = Describe("PgxConnPoolConfig", func() {
@@ -97,3 +100,77 @@ var _ = Describe("PgxConnPoolConfig", func() {
 		})
 	})
 })
+
+var _ = Describe("PgxConn", func() {
+	WithTestPgConnConfig(func(config *ConnConfig) {
+		Describe("TryAdvisoryLock", func() {
+			const anyLockKey = int64(7001)
+
+			var (
+				leaderConn   *PgxConn
+				followerConn *PgxConn
+			)
+
+			BeforeEach(func() {
+				leaderConn = MustNewPgxConnPool(&PgxConnPoolConfig{ConnConfig: *config}, NewFakeLogger())
+				followerConn = MustNewPgxConnPool(&PgxConnPoolConfig{ConnConfig: *config}, NewFakeLogger())
+			})
+
+			AfterEach(func() {
+				leaderConn.Close()
+				followerConn.Close()
+			})
+
+			It("should acquire the advisory lock not held by any session", func() {
+				lock, err := leaderConn.TryAdvisoryLock(context.Background(), anyLockKey)
+				Expect(err).To(BeNil())
+				Expect(lock).NotTo(BeNil())
+
+				Expect(lock.Ping(context.Background())).To(BeNil())
+				Expect(lock.Release()).To(BeNil())
+			})
+
+			It("should not acquire the advisory lock held by another session until it is released", func() {
+				lock, err := leaderConn.TryAdvisoryLock(context.Background(), anyLockKey)
+				Expect(err).To(BeNil())
+				Expect(lock).NotTo(BeNil())
+
+				followerLock, err := followerConn.TryAdvisoryLock(context.Background(), anyLockKey)
+				Expect(err).To(BeNil())
+				Expect(followerLock).To(BeNil())
+
+				Expect(lock.Release()).To(BeNil())
+
+				followerLock, err = followerConn.TryAdvisoryLock(context.Background(), anyLockKey)
+				Expect(err).To(BeNil())
+				Expect(followerLock).NotTo(BeNil())
+				Expect(followerLock.Release()).To(BeNil())
+			})
+
+			It("should take over the advisory lock once the session holding it is lost", func() {
+				lock, err := leaderConn.TryAdvisoryLock(context.Background(), anyLockKey)
+				Expect(err).To(BeNil())
+				Expect(lock).NotTo(BeNil())
+
+				// Terminates the session of the leader as if its connection is lost
+				_, err = followerConn.Exec(
+					"SELECT pg_terminate_backend(pid) FROM pg_locks WHERE locktype = 'advisory' AND objid = $1",
+					anyLockKey,
+				)
+				Expect(err).To(BeNil())
+
+				Eventually(func() error {
+					return lock.Ping(context.Background())
+				}).ShouldNot(BeNil())
+
+				followerLock, err := followerConn.TryAdvisoryLock(context.Background(), anyLockKey)
+				Expect(err).To(BeNil())
+				Expect(followerLock).NotTo(BeNil())
+				Expect(followerLock.Release()).To(BeNil())
+
+				// Releasing the lost lock closes its connection without error on the lock taken over
+				_ = lock.Release()
+			})
+		})
+	})
+})
@@ -0,0 +1,287 @@
+package segmentstore
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+)
+
+// Segment record layout
+// | Field  | Size       | Description                         |
+// | ------ | ---------- | ----------------------------------- |
+// | length | 4 bytes    | Size of type and body               |
+// | crc    | 4 bytes    | CRC-32C of type and body            |
+// | type   | 1 byte     | RECORD_TYPE_EVENT or _COMMIT        |
+// | body   | length - 1 | Event or commit record body         |
+//
+// Event record body
+// | height (8 bytes) | version (4 bytes) | name length (2 bytes) | name | payload |
+//
+// Commit record body
+// | height (8 bytes) |
+const (
+	RECORD_TYPE_EVENT  byte = 1
+	RECORD_TYPE_COMMIT byte = 2
+
+	recordHeaderSize = 8
+	// Upper bound of a record to tell a corrupted length from a large event
+	maxRecordSize = 1 << 30
+)
+
+// Index entry layout
+// | height (8 bytes) | offset (8 bytes) | length (8 bytes) | crc (4 bytes) |
+const indexEntrySize = 28
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+var errTornRecord = errors.New("torn record")
+
+type eventRecord struct {
+	height  int64
+	name    string
+	version int
+	payload []byte
+}
+
+// indexEntry locates the consecutive event records of a height in a segment
+type indexEntry struct {
+	segment *segment
+	height  int64
+	offset  int64
+	length  int64
+}
+
+func appendRecord(buffer []byte, recordType byte, body []byte) []byte {
+	header := make([]byte, recordHeaderSize)
+	binary.BigEndian.PutUint32(header[0:4], uint32(1+len(body)))
+
+	crc := crc32.Update(0, crcTable, []byte{recordType})
+	crc = crc32.Update(crc, crcTable, body)
+	binary.BigEndian.PutUint32(header[4:8], crc)
+
+	buffer = append(buffer, header...)
+	buffer = append(buffer, recordType)
+	return append(buffer, body...)
+}
+
+// decodeRecord decodes the record at the start of the buffer and returns its type, body and total size. Returns
+// errTornRecord when the buffer ends before the record does.
+func decodeRecord(buffer []byte) (byte, []byte, int, error) {
+	if len(buffer) < recordHeaderSize {
+		return 0, nil, 0, errTornRecord
+	}
+	length := binary.BigEndian.Uint32(buffer[0:4])
+	if length == 0 || length > maxRecordSize {
+		return 0, nil, 0, fmt.Errorf("invalid record length %d", length)
+	}
+	size := recordHeaderSize + int(length)
+	if len(buffer) < size {
+		return 0, nil, 0, errTornRecord
+	}
+
+	content := buffer[recordHeaderSize:size]
+	if crc32.Checksum(content, crcTable) != binary.BigEndian.Uint32(buffer[4:8]) {
+		return 0, nil, 0, errors.New("mismatched record CRC")
+	}
+
+	return content[0], content[1:], size, nil
+}
+
+func encodeEventRecord(record eventRecord) []byte {
+	body := make([]byte, 14, 14+len(record.name)+len(record.payload))
+	binary.BigEndian.PutUint64(body[0:8], uint64(record.height))
+	binary.BigEndian.PutUint32(body[8:12], uint32(record.version))
+	binary.BigEndian.PutUint16(body[12:14], uint16(len(record.name)))
+	body = append(body, record.name...)
+	return append(body, record.payload...)
+}
+
+func decodeEventRecord(body []byte) (eventRecord, error) {
+	if len(body) < 14 {
+		return eventRecord{}, errors.New("event record too short")
+	}
+	nameLength := int(binary.BigEndian.Uint16(body[12:14]))
+	if len(body) < 14+nameLength {
+		return eventRecord{}, errors.New("event record name too short")
+	}
+
+	return eventRecord{
+		height:  int64(binary.BigEndian.Uint64(body[0:8])),
+		version: int(int32(binary.BigEndian.Uint32(body[8:12]))),
+		name:    string(body[14 : 14+nameLength]),
+		payload: body[14+nameLength:],
+	}, nil
+}
+
+func encodeCommitRecord(height int64) []byte {
+	body := make([]byte, 8)
+	binary.BigEndian.PutUint64(body, uint64(height))
+	return body
+}
+
+func encodeIndexEntries(entries []indexEntry) []byte {
+	encoded := make([]byte, 0, len(entries)*indexEntrySize)
+	for _, entry := range entries {
+		encodedEntry := make([]byte, indexEntrySize)
+		binary.BigEndian.PutUint64(encodedEntry[0:8], uint64(entry.height))
+		binary.BigEndian.PutUint64(encodedEntry[8:16], uint64(entry.offset))
+		binary.BigEndian.PutUint64(encodedEntry[16:24], uint64(entry.length))
+		binary.BigEndian.PutUint32(encodedEntry[24:28], crc32.Checksum(encodedEntry[0:24], crcTable))
+		encoded = append(encoded, encodedEntry...)
+	}
+
+	return encoded
+}
+
+// recoverSegment returns the index entries of the segment. The entries in the index file are loaded up to the first
+// invalid one, then the committed batches after the last loaded entry are indexed by scanning the segment. The index
+// file and the segment are truncated after the last valid entry and the last commit record respectively.
+func (store *SegmentStore) recoverSegment(segment *segment) ([]indexEntry, error) {
+	entries, err := loadIndexEntries(segment)
+	if err != nil {
+		return nil, err
+	}
+
+	scanOffset := int64(0)
+	if len(entries) > 0 {
+		lastEntry := entries[len(entries)-1]
+		scanOffset = lastEntry.offset + lastEntry.length
+		// Skip the commit record following the last indexed batch. The entries of a batch may be partially indexed
+		// when the index write was interrupted, in which case the rest of the batch is scanned.
+		recordType, _, size, err := readRecordAt(segment, scanOffset)
+		if err == nil && recordType == RECORD_TYPE_COMMIT {
+			scanOffset += int64(size)
+		}
+	}
+
+	scannedEntries, committedOffset, err := scanSegment(segment, scanOffset)
+	if err != nil {
+		return nil, err
+	}
+	if committedOffset < segment.size {
+		if err := os.Truncate(segment.path, committedOffset); err != nil {
+			return nil, fmt.Errorf("error truncating uncommitted records: %v", err)
+		}
+		segment.size = committedOffset
+	}
+
+	if len(scannedEntries) > 0 {
+		indexFile, err := os.OpenFile(segment.indexPath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("error opening segment index: %v", err)
+		}
+		defer indexFile.Close()
+		if _, err := indexFile.Write(encodeIndexEntries(scannedEntries)); err != nil {
+			return nil, fmt.Errorf("error writing recovered segment index: %v", err)
+		}
+	}
+
+	return append(entries, scannedEntries...), nil
+}
+
+func loadIndexEntries(segment *segment) ([]indexEntry, error) {
+	encoded, err := os.ReadFile(segment.indexPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []indexEntry{}, nil
+		}
+		return nil, fmt.Errorf("error reading segment index: %v", err)
+	}
+
+	entries := make([]indexEntry, 0, len(encoded)/indexEntrySize)
+	validSize := 0
+	for offset := 0; offset+indexEntrySize <= len(encoded); offset += indexEntrySize {
+		encodedEntry := encoded[offset : offset+indexEntrySize]
+		if crc32.Checksum(encodedEntry[0:24], crcTable) != binary.BigEndian.Uint32(encodedEntry[24:28]) {
+			break
+		}
+		entry := indexEntry{
+			segment: segment,
+			height:  int64(binary.BigEndian.Uint64(encodedEntry[0:8])),
+			offset:  int64(binary.BigEndian.Uint64(encodedEntry[8:16])),
+			length:  int64(binary.BigEndian.Uint64(encodedEntry[16:24])),
+		}
+		if entry.offset+entry.length > segment.size {
+			break
+		}
+		entries = append(entries, entry)
+		validSize = offset + indexEntrySize
+	}
+
+	if validSize < len(encoded) {
+		if err := os.Truncate(segment.indexPath, int64(validSize)); err != nil {
+			return nil, fmt.Errorf("error truncating invalid segment index entries: %v", err)
+		}
+	}
+
+	return entries, nil
+}
+
+// scanSegment indexes the committed batches from the offset and returns the offset after the last commit record
+func scanSegment(segment *segment, offset int64) ([]indexEntry, int64, error) {
+	entries := make([]indexEntry, 0)
+	pendingEntries := make([]indexEntry, 0)
+	committedOffset := offset
+
+	for offset < segment.size {
+		recordType, body, size, err := readRecordAt(segment, offset)
+		if err != nil {
+			// Records after the last commit are left by an interrupted insertion
+			break
+		}
+
+		switch recordType {
+		case RECORD_TYPE_EVENT:
+			record, err := decodeEventRecord(body)
+			if err != nil {
+				return nil, 0, fmt.Errorf("error decoding event record at offset %d: %v", offset, err)
+			}
+			if len(pendingEntries) > 0 && pendingEntries[len(pendingEntries)-1].height == record.height {
+				pendingEntries[len(pendingEntries)-1].length += int64(size)
+			} else {
+				pendingEntries = append(pendingEntries, indexEntry{
+					segment: segment,
+					height:  record.height,
+					offset:  offset,
+					length:  int64(size),
+				})
+			}
+		case RECORD_TYPE_COMMIT:
+			entries = append(entries, pendingEntries...)
+			pendingEntries = make([]indexEntry, 0)
+			committedOffset = offset + int64(size)
+		default:
+			return nil, 0, fmt.Errorf("unknown record type %d at offset %d", recordType, offset)
+		}
+		offset += int64(size)
+	}
+
+	return entries, committedOffset, nil
+}
+
+func readRecordAt(segment *segment, offset int64) (byte, []byte, int, error) {
+	header := make([]byte, recordHeaderSize)
+	if _, err := segment.readFile.ReadAt(header, offset); err != nil {
+		if errors.Is(err, io.EOF) {
+			return 0, nil, 0, errTornRecord
+		}
+		return 0, nil, 0, err
+	}
+	length := binary.BigEndian.Uint32(header[0:4])
+	if length == 0 || length > maxRecordSize {
+		return 0, nil, 0, fmt.Errorf("invalid record length %d", length)
+	}
+
+	buffer := make([]byte, recordHeaderSize+int(length))
+	if _, err := segment.readFile.ReadAt(buffer, offset); err != nil {
+		if errors.Is(err, io.EOF) {
+			return 0, nil, 0, errTornRecord
+		}
+		return 0, nil, 0, err
+	}
+
+	return decodeRecord(buffer)
+}
@@ -0,0 +1,510 @@
+package segmentstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	entity_event "github.com/crypto-com/chain-indexing/entity/event"
+)
+
+const DEFAULT_MAX_SEGMENT_SIZE = int64(256 * 1024 * 1024)
+
+const (
+	SEGMENT_FILE_SUFFIX = ".segment"
+	INDEX_FILE_SUFFIX   = ".index"
+)
+
+// ErrNonAppendableHeight is returned when inserting events below the latest height stored
+var ErrNonAppendableHeight = errors.New("events below the latest height cannot be appended")
+
+var _ entity_event.Store = &SegmentStore{}
+var _ entity_event.RangeStore = &SegmentStore{}
+var _ entity_event.LatestHeightSubscriber = &SegmentStore{}
+
+// SegmentStore is an event store backed by append-only segment files in a local directory.
+//
+// Each insertion appends the events as CRC-checked records followed by a commit record to the active segment, which is
+// rotated once it exceeds the maximum segment size. A batch of events never spans segments and a batch without its
+// commit record, e.g. torn by a crash, is truncated on open. Stored records are never rewritten so no compaction is
+// needed. A height index of each segment maps the heights to the records and is rebuilt from the segment when it falls
+// behind.
+//
+// Events are appended in height order by a single writer while any number of readers read concurrently.
+type SegmentStore struct {
+	directory      string
+	registry       *entity_event.Registry
+	maxSegmentSize int64
+
+	writeMutex      sync.Mutex
+	activeFile      *os.File
+	activeIndexFile *os.File
+
+	mutex    sync.RWMutex
+	segments []*segment
+	entries  []indexEntry
+
+	subscribersMutex sync.Mutex
+	subscribers      map[chan int64]struct{}
+}
+
+type segment struct {
+	sequence  int64
+	path      string
+	indexPath string
+	size      int64
+	// Shared by readers, ReadAt is safe for concurrent use
+	readFile *os.File
+}
+
+// NewSegmentStore opens the segment store in the directory, creating the directory when it does not exist. The
+// committed events are recovered and any uncommitted tail of the segments is truncated.
+func NewSegmentStore(directory string, registry *entity_event.Registry) (*SegmentStore, error) {
+	if err := os.MkdirAll(directory, 0755); err != nil {
+		return nil, fmt.Errorf("error creating segment store directory: %v", err)
+	}
+
+	store := &SegmentStore{
+		directory:      directory,
+		registry:       registry,
+		maxSegmentSize: DEFAULT_MAX_SEGMENT_SIZE,
+
+		segments: make([]*segment, 0),
+		entries:  make([]indexEntry, 0),
+
+		subscribers: make(map[chan int64]struct{}),
+	}
+	if err := store.open(); err != nil {
+		_ = store.Close()
+		return nil, err
+	}
+
+	return store, nil
+}
+
+// WithMaxSegmentSize sets the size in bytes above which the active segment is rotated
+func (store *SegmentStore) WithMaxSegmentSize(maxSegmentSize int64) *SegmentStore {
+	store.maxSegmentSize = maxSegmentSize
+	return store
+}
+
+func (store *SegmentStore) open() error {
+	files, err := filepath.Glob(filepath.Join(store.directory, "*"+SEGMENT_FILE_SUFFIX))
+	if err != nil {
+		return fmt.Errorf("error listing segment files: %v", err)
+	}
+	sequences := make([]int64, 0, len(files))
+	for _, file := range files {
+		var sequence int64
+		name := strings.TrimSuffix(filepath.Base(file), SEGMENT_FILE_SUFFIX)
+		if _, err := fmt.Sscanf(name, "%d", &sequence); err != nil {
+			return fmt.Errorf("error parsing segment file name %s: %v", file, err)
+		}
+		sequences = append(sequences, sequence)
+	}
+	sort.Slice(sequences, func(i, j int) bool {
+		return sequences[i] < sequences[j]
+	})
+
+	for _, sequence := range sequences {
+		segment, err := store.openSegment(sequence)
+		if err != nil {
+			return err
+		}
+		store.segments = append(store.segments, segment)
+
+		entries, err := store.recoverSegment(segment)
+		if err != nil {
+			return fmt.Errorf("error recovering segment %s: %v", segment.path, err)
+		}
+		for _, entry := range entries {
+			if len(store.entries) > 0 && entry.height < store.entries[len(store.entries)-1].height {
+				return fmt.Errorf("error recovering segment %s: height %d is out of order", segment.path, entry.height)
+			}
+			store.entries = append(store.entries, entry)
+		}
+	}
+
+	if len(store.segments) > 0 {
+		return store.openActiveSegment(store.segments[len(store.segments)-1])
+	}
+	return nil
+}
+
+func (store *SegmentStore) openSegment(sequence int64) (*segment, error) {
+	path := filepath.Join(store.directory, fmt.Sprintf("%012d%s", sequence, SEGMENT_FILE_SUFFIX))
+	readFile, err := os.OpenFile(path, os.O_RDONLY|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("error opening segment %s: %v", path, err)
+	}
+	stat, err := readFile.Stat()
+	if err != nil {
+		_ = readFile.Close()
+		return nil, fmt.Errorf("error reading segment %s size: %v", path, err)
+	}
+
+	return &segment{
+		sequence:  sequence,
+		path:      path,
+		indexPath: strings.TrimSuffix(path, SEGMENT_FILE_SUFFIX) + INDEX_FILE_SUFFIX,
+		size:      stat.Size(),
+		readFile:  readFile,
+	}, nil
+}
+
+func (store *SegmentStore) openActiveSegment(segment *segment) error {
+	activeFile, err := os.OpenFile(segment.path, os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("error opening active segment %s: %v", segment.path, err)
+	}
+	activeIndexFile, err := os.OpenFile(segment.indexPath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		_ = activeFile.Close()
+		return fmt.Errorf("error opening active segment index %s: %v", segment.indexPath, err)
+	}
+
+	store.activeFile = activeFile
+	store.activeIndexFile = activeIndexFile
+	return nil
+}
+
+// rotate starts a new active segment after the current one
+func (store *SegmentStore) rotate() error {
+	sequence := int64(0)
+	if len(store.segments) > 0 {
+		sequence = store.segments[len(store.segments)-1].sequence + 1
+	}
+	if err := store.closeActiveSegment(); err != nil {
+		return err
+	}
+
+	segment, err := store.openSegment(sequence)
+	if err != nil {
+		return err
+	}
+	if err := store.openActiveSegment(segment); err != nil {
+		_ = segment.readFile.Close()
+		return err
+	}
+	if err := syncDirectory(store.directory); err != nil {
+		return err
+	}
+
+	store.mutex.Lock()
+	store.segments = append(store.segments, segment)
+	store.mutex.Unlock()
+
+	return nil
+}
+
+func (store *SegmentStore) closeActiveSegment() error {
+	if store.activeFile != nil {
+		if err := store.activeFile.Close(); err != nil {
+			return fmt.Errorf("error closing active segment: %v", err)
+		}
+		store.activeFile = nil
+	}
+	if store.activeIndexFile != nil {
+		if err := store.activeIndexFile.Close(); err != nil {
+			return fmt.Errorf("error closing active segment index: %v", err)
+		}
+		store.activeIndexFile = nil
+	}
+
+	return nil
+}
+
+// Close closes all the segment files. The store cannot be used afterwards.
+func (store *SegmentStore) Close() error {
+	store.writeMutex.Lock()
+	defer store.writeMutex.Unlock()
+
+	var closeErr error
+	if err := store.closeActiveSegment(); err != nil {
+		closeErr = err
+	}
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	for _, segment := range store.segments {
+		if err := segment.readFile.Close(); err != nil && closeErr == nil {
+			closeErr = fmt.Errorf("error closing segment %s: %v", segment.path, err)
+		}
+	}
+
+	return closeErr
+}
+
+// GetLatestHeight returns latest event height, nil if no event is stored
+func (store *SegmentStore) GetLatestHeight() (*int64, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+
+	if len(store.entries) == 0 {
+		return nil, nil
+	}
+	latestHeight := store.entries[len(store.entries)-1].height
+	return &latestHeight, nil
+}
+
+func (store *SegmentStore) GetAllByHeight(height int64) ([]entity_event.Event, error) {
+	events := make([]entity_event.Event, 0)
+	if err := store.StreamByHeightRange(height, height, nil, func(event entity_event.Event) error {
+		events = append(events, event)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// StreamByHeightRange calls the handler with each event of the event names in the heights from fromHeight to toHeight
+// inclusively, in height order. All events are streamed when the event names are nil.
+func (store *SegmentStore) StreamByHeightRange(
+	fromHeight int64,
+	toHeight int64,
+	eventNames []string,
+	handler func(event entity_event.Event) error,
+) error {
+	var nameFilter map[string]bool
+	if eventNames != nil {
+		nameFilter = make(map[string]bool, len(eventNames))
+		for _, eventName := range eventNames {
+			nameFilter[eventName] = true
+		}
+	}
+
+	for _, entry := range store.entriesInRange(fromHeight, toHeight) {
+		records, err := store.readEntry(entry)
+		if err != nil {
+			return err
+		}
+
+		for _, record := range records {
+			if nameFilter != nil && !nameFilter[record.name] {
+				continue
+			}
+
+			event, err := store.registry.DecodeByType(record.name, record.version, record.payload)
+			if err != nil {
+				return fmt.Errorf("error decoding the event string into type: %v", err)
+			}
+			if err := handler(event); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (store *SegmentStore) entriesInRange(fromHeight int64, toHeight int64) []indexEntry {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+
+	start := sort.Search(len(store.entries), func(i int) bool {
+		return store.entries[i].height >= fromHeight
+	})
+	end := sort.Search(len(store.entries), func(i int) bool {
+		return store.entries[i].height > toHeight
+	})
+	if start >= end {
+		return nil
+	}
+
+	entries := make([]indexEntry, end-start)
+	copy(entries, store.entries[start:end])
+	return entries
+}
+
+func (store *SegmentStore) readEntry(entry indexEntry) ([]eventRecord, error) {
+	buffer := make([]byte, entry.length)
+	if _, err := entry.segment.readFile.ReadAt(buffer, entry.offset); err != nil {
+		return nil, fmt.Errorf("error reading events of height %d from segment %s: %v", entry.height, entry.segment.path, err)
+	}
+
+	records := make([]eventRecord, 0)
+	for offset := 0; offset < len(buffer); {
+		recordType, body, size, err := decodeRecord(buffer[offset:])
+		if err != nil {
+			return nil, fmt.Errorf(
+				"error decoding record of height %d at offset %d of segment %s: %v",
+				entry.height, entry.offset+int64(offset), entry.segment.path, err,
+			)
+		}
+		if recordType != RECORD_TYPE_EVENT {
+			return nil, fmt.Errorf("error decoding record of height %d: unexpected record type %d", entry.height, recordType)
+		}
+		record, err := decodeEventRecord(body)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding event record of height %d: %v", entry.height, err)
+		}
+		records = append(records, record)
+		offset += size
+	}
+
+	return records, nil
+}
+
+func (store *SegmentStore) Insert(event entity_event.Event) error {
+	return store.InsertAll([]entity_event.Event{event})
+}
+
+// InsertAll appends all the events to the active segment. The events must be in height order and not below the
+// latest height stored. None of the events is stored when the insert fails at any point.
+func (store *SegmentStore) InsertAll(events []entity_event.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	store.writeMutex.Lock()
+	defer store.writeMutex.Unlock()
+
+	maybeLatestHeight, err := store.GetLatestHeight()
+	if err != nil {
+		return err
+	}
+	for i, event := range events {
+		if maybeLatestHeight != nil && event.Height() < *maybeLatestHeight {
+			return fmt.Errorf("error inserting event of height %d: %w", event.Height(), ErrNonAppendableHeight)
+		}
+		if i > 0 && event.Height() < events[i-1].Height() {
+			return fmt.Errorf("error inserting events: height %d is out of order", event.Height())
+		}
+	}
+
+	if store.activeFile == nil || store.activeSegment().size >= store.maxSegmentSize {
+		if err := store.rotate(); err != nil {
+			return fmt.Errorf("error rotating segment: %v", err)
+		}
+	}
+	segment := store.activeSegment()
+
+	buffer := make([]byte, 0)
+	entries := make([]indexEntry, 0)
+	for _, event := range events {
+		encodedEvent, err := event.ToJSON()
+		if err != nil {
+			return fmt.Errorf("error encoding event to json: %v", err)
+		}
+
+		recordOffset := segment.size + int64(len(buffer))
+		buffer = appendRecord(buffer, RECORD_TYPE_EVENT, encodeEventRecord(eventRecord{
+			height:  event.Height(),
+			name:    event.Name(),
+			version: event.Version(),
+			payload: []byte(encodedEvent),
+		}))
+		recordLength := segment.size + int64(len(buffer)) - recordOffset
+
+		if len(entries) > 0 && entries[len(entries)-1].height == event.Height() {
+			entries[len(entries)-1].length += recordLength
+		} else {
+			entries = append(entries, indexEntry{
+				segment: segment,
+				height:  event.Height(),
+				offset:  recordOffset,
+				length:  recordLength,
+			})
+		}
+	}
+	buffer = appendRecord(buffer, RECORD_TYPE_COMMIT, encodeCommitRecord(events[len(events)-1].Height()))
+
+	if err := store.appendToActiveSegment(segment, buffer); err != nil {
+		return err
+	}
+	// The index is rebuilt from the segment on open when the write fails, so the committed events are kept
+	_, _ = store.activeIndexFile.Write(encodeIndexEntries(entries))
+
+	store.mutex.Lock()
+	segment.size += int64(len(buffer))
+	store.entries = append(store.entries, entries...)
+	store.mutex.Unlock()
+
+	store.notifyLatestHeight(events[len(events)-1].Height())
+
+	return nil
+}
+
+// appendToActiveSegment writes and syncs the records to the end of the active segment. The segment is truncated back
+// when it fails so that no partial batch is left behind.
+func (store *SegmentStore) appendToActiveSegment(segment *segment, buffer []byte) error {
+	if _, err := store.activeFile.WriteAt(buffer, segment.size); err != nil {
+		_ = store.activeFile.Truncate(segment.size)
+		return fmt.Errorf("error writing events to segment %s: %v", segment.path, err)
+	}
+	if err := store.activeFile.Sync(); err != nil {
+		_ = store.activeFile.Truncate(segment.size)
+		return fmt.Errorf("error syncing segment %s: %v", segment.path, err)
+	}
+
+	return nil
+}
+
+func (store *SegmentStore) activeSegment() *segment {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+
+	return store.segments[len(store.segments)-1]
+}
+
+// SubscribeLatestHeight returns a channel receiving the latest event height whenever events are inserted through this
+// store. Only the latest height is kept when the receiver is falling behind. The events inserted by the other processes
+// sharing the segment directory are not notified; they have to be polled.
+func (store *SegmentStore) SubscribeLatestHeight(ctx context.Context) (<-chan int64, error) {
+	latestHeightCh := make(chan int64, 1)
+
+	store.subscribersMutex.Lock()
+	store.subscribers[latestHeightCh] = struct{}{}
+	store.subscribersMutex.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		store.subscribersMutex.Lock()
+		delete(store.subscribers, latestHeightCh)
+		store.subscribersMutex.Unlock()
+		close(latestHeightCh)
+	}()
+
+	return latestHeightCh, nil
+}
+
+func (store *SegmentStore) notifyLatestHeight(height int64) {
+	store.subscribersMutex.Lock()
+	defer store.subscribersMutex.Unlock()
+
+	for latestHeightCh := range store.subscribers {
+		for {
+			select {
+			case latestHeightCh <- height:
+			default:
+				// Drop the stale height not yet received
+				select {
+				case <-latestHeightCh:
+				default:
+				}
+				continue
+			}
+			break
+		}
+	}
+}
+
+func syncDirectory(directory string) error {
+	dir, err := os.Open(directory)
+	if err != nil {
+		return fmt.Errorf("error opening segment store directory: %v", err)
+	}
+	defer dir.Close()
+
+	if err := dir.Sync(); err != nil {
+		return fmt.Errorf("error syncing segment store directory: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,13 @@
+package segmentstore_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestSegmentStore(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "SegmentStore Suite")
+}
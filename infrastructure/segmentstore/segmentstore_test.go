@@ -0,0 +1,223 @@
+package segmentstore_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/crypto-com/chain-indexing/entity/event"
+	"github.com/crypto-com/chain-indexing/infrastructure/segmentstore"
+)
+
+var _ = Describe("SegmentStore", func() {
+	var directory string
+
+	BeforeEach(func() {
+		var err error
+		directory, err = ioutil.TempDir("", "segmentstore")
+		Expect(err).To(BeNil())
+	})
+
+	AfterEach(func() {
+		_ = os.RemoveAll(directory)
+	})
+
+	It("should return the inserted events by height and the latest height", func() {
+		store, err := segmentstore.NewSegmentStore(directory, newTestEventRegistry())
+		Expect(err).To(BeNil())
+		defer store.Close()
+
+		latestHeight, err := store.GetLatestHeight()
+		Expect(err).To(BeNil())
+		Expect(latestHeight).To(BeNil())
+
+		Expect(store.InsertAll([]event.Event{
+			newTestEvent(1, "a"), newTestEvent(1, "b"), newTestEvent(2, "c"),
+		})).To(Succeed())
+		Expect(store.Insert(newTestEvent(2, "d"))).To(Succeed())
+
+		latestHeight, err = store.GetLatestHeight()
+		Expect(err).To(BeNil())
+		Expect(*latestHeight).To(Equal(int64(2)))
+
+		actual, err := store.GetAllByHeight(1)
+		Expect(err).To(BeNil())
+		Expect(actual).To(Equal([]event.Event{newTestEvent(1, "a"), newTestEvent(1, "b")}))
+
+		actual, err = store.GetAllByHeight(2)
+		Expect(err).To(BeNil())
+		Expect(actual).To(Equal([]event.Event{newTestEvent(2, "c"), newTestEvent(2, "d")}))
+
+		actual, err = store.GetAllByHeight(3)
+		Expect(err).To(BeNil())
+		Expect(actual).To(BeEmpty())
+	})
+
+	It("should reject events below the latest height", func() {
+		store, err := segmentstore.NewSegmentStore(directory, newTestEventRegistry())
+		Expect(err).To(BeNil())
+		defer store.Close()
+
+		Expect(store.Insert(newTestEvent(2, "a"))).To(Succeed())
+
+		err = store.Insert(newTestEvent(1, "b"))
+		Expect(err).To(MatchError(ContainSubstring(segmentstore.ErrNonAppendableHeight.Error())))
+	})
+
+	It("should stream the events of the names in the height range across segments", func() {
+		store, err := segmentstore.NewSegmentStore(directory, newTestEventRegistry())
+		Expect(err).To(BeNil())
+		defer store.Close()
+		store.WithMaxSegmentSize(1)
+
+		for height := int64(1); height <= 5; height++ {
+			Expect(store.InsertAll([]event.Event{
+				newTestEvent(height, "a"), newNamedTestEvent(otherTestEventName, height, "b"),
+			})).To(Succeed())
+		}
+		segmentFiles, _ := filepath.Glob(filepath.Join(directory, "*"+segmentstore.SEGMENT_FILE_SUFFIX))
+		Expect(segmentFiles).To(HaveLen(5))
+
+		actual := make([]event.Event, 0)
+		Expect(store.StreamByHeightRange(2, 4, []string{testEventName}, func(evt event.Event) error {
+			actual = append(actual, evt)
+			return nil
+		})).To(Succeed())
+		Expect(actual).To(Equal([]event.Event{
+			newTestEvent(2, "a"), newTestEvent(3, "a"), newTestEvent(4, "a"),
+		}))
+	})
+
+	It("should recover the committed events and truncate the torn tail on reopen", func() {
+		store, err := segmentstore.NewSegmentStore(directory, newTestEventRegistry())
+		Expect(err).To(BeNil())
+		Expect(store.InsertAll([]event.Event{newTestEvent(1, "a"), newTestEvent(2, "b")})).To(Succeed())
+		Expect(store.Insert(newTestEvent(3, "c"))).To(Succeed())
+		Expect(store.Close()).To(Succeed())
+
+		segmentFiles, _ := filepath.Glob(filepath.Join(directory, "*"+segmentstore.SEGMENT_FILE_SUFFIX))
+		Expect(segmentFiles).To(HaveLen(1))
+		segmentFile := segmentFiles[0]
+		indexFile := segmentFile[:len(segmentFile)-len(segmentstore.SEGMENT_FILE_SUFFIX)] + segmentstore.INDEX_FILE_SUFFIX
+		// Simulate a crash in the middle of the last insertion before its index is written
+		stat, err := os.Stat(segmentFile)
+		Expect(err).To(BeNil())
+		Expect(os.Truncate(segmentFile, stat.Size()-3)).To(Succeed())
+		Expect(os.Remove(indexFile)).To(Succeed())
+
+		store, err = segmentstore.NewSegmentStore(directory, newTestEventRegistry())
+		Expect(err).To(BeNil())
+		defer store.Close()
+
+		latestHeight, err := store.GetLatestHeight()
+		Expect(err).To(BeNil())
+		Expect(*latestHeight).To(Equal(int64(2)))
+
+		Expect(store.Insert(newTestEvent(3, "d"))).To(Succeed())
+		actual, err := store.GetAllByHeight(3)
+		Expect(err).To(BeNil())
+		Expect(actual).To(Equal([]event.Event{newTestEvent(3, "d")}))
+	})
+
+	It("should return error when a record does not match its CRC", func() {
+		store, err := segmentstore.NewSegmentStore(directory, newTestEventRegistry())
+		Expect(err).To(BeNil())
+		defer store.Close()
+		Expect(store.Insert(newTestEvent(1, "value"))).To(Succeed())
+
+		segmentFiles, _ := filepath.Glob(filepath.Join(directory, "*"+segmentstore.SEGMENT_FILE_SUFFIX))
+		content, err := ioutil.ReadFile(segmentFiles[0])
+		Expect(err).To(BeNil())
+		content[20] ^= 0xff
+		Expect(ioutil.WriteFile(segmentFiles[0], content, 0644)).To(Succeed())
+
+		_, err = store.GetAllByHeight(1)
+		Expect(err).To(MatchError(ContainSubstring("mismatched record CRC")))
+	})
+
+	It("should serve concurrent readers while appending and notify the latest height", func() {
+		store, err := segmentstore.NewSegmentStore(directory, newTestEventRegistry())
+		Expect(err).To(BeNil())
+		defer store.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		latestHeightCh, err := store.SubscribeLatestHeight(ctx)
+		Expect(err).To(BeNil())
+
+		var waitGroup sync.WaitGroup
+		for reader := 0; reader < 4; reader++ {
+			waitGroup.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer waitGroup.Done()
+				for i := 0; i < 100; i++ {
+					_, readErr := store.GetAllByHeight(int64(i%10 + 1))
+					Expect(readErr).To(BeNil())
+				}
+			}()
+		}
+		for height := int64(1); height <= 10; height++ {
+			Expect(store.Insert(newTestEvent(height, fmt.Sprintf("%d", height)))).To(Succeed())
+		}
+		waitGroup.Wait()
+
+		Eventually(latestHeightCh).Should(Receive(Equal(int64(10))))
+	})
+})
+
+const testEventName = "TestEvent"
+const otherTestEventName = "OtherTestEvent"
+
+type testEvent struct {
+	event.Base
+
+	Value string `json:"value"`
+}
+
+func newTestEvent(height int64, value string) *testEvent {
+	return newNamedTestEvent(testEventName, height, value)
+}
+
+func newNamedTestEvent(name string, height int64, value string) *testEvent {
+	return &testEvent{
+		Base: event.Base{
+			EventName:    name,
+			EventVersion: 1,
+			BlockHeight:  height,
+			EventUUID:    fmt.Sprintf("%s-%d-%s", name, height, value),
+		},
+		Value: value,
+	}
+}
+
+func (evt *testEvent) ToJSON() (string, error) {
+	encoded, err := json.Marshal(evt)
+	return string(encoded), err
+}
+
+func (evt *testEvent) String() string {
+	return evt.Name()
+}
+
+func newTestEventRegistry() *event.Registry {
+	registry := event.NewRegistry()
+	decoder := func(encoded []byte) (event.Event, error) {
+		var evt *testEvent
+		if err := json.Unmarshal(encoded, &evt); err != nil {
+			return nil, err
+		}
+		return evt, nil
+	}
+	registry.Register(testEventName, 1, decoder)
+	registry.Register(otherTestEventName, 1, decoder)
+
+	return registry
+}
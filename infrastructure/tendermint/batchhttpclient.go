@@ -0,0 +1,335 @@
+package tendermint
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/crypto-com/chain-indexing/appinterface/tendermint"
+	"github.com/crypto-com/chain-indexing/infrastructure/multiendpoint"
+	usecase_model "github.com/crypto-com/chain-indexing/usecase/model"
+	"github.com/crypto-com/chain-indexing/usecase/model/genesis"
+)
+
+var _ tendermint.Client = &BatchHTTPClient{}
+
+const DEFAULT_BATCH_SIZE = 20
+
+// Number of batches of prefetched heights kept in memory before the oldest heights are evicted
+const BATCH_CACHE_SIZE_IN_BATCHES = 4
+
+// Time batching is backed off after the first failed batch request. It doubles on each consecutive failure up to
+// MAX_BATCH_BACKOFF.
+const DEFAULT_BATCH_BACKOFF = 30 * time.Second
+const MAX_BATCH_BACKOFF = 10 * time.Minute
+
+// BatchHTTPClient is a Tendermint client which prefetches block and block results of a window of heights in a single
+// JSON-RPC 2.0 batch request. A height is served by the regular per-height requests when its batch request fails, and
+// batching is backed off for a while after a failure such that an endpoint rejecting batches is not requested twice
+// per height.
+type BatchHTTPClient struct {
+	client    *HTTPClient
+	batchSize int
+	backoff   time.Duration
+
+	mutex   sync.Mutex
+	entries map[int64]*batchEntry
+	// Number of batch requests failed in a row and until when batching is backed off, guarded by the mutex
+	consecutiveFailures int
+	backoffUntil        time.Time
+}
+
+// batchEntry holds the prefetched raw responses of a height. done is closed once the batch request completes.
+type batchEntry struct {
+	done chan struct{}
+
+	maybeRawBlock        []byte
+	maybeRawBlockResults []byte
+	err                  error
+
+	isBlockConsumed        bool
+	isBlockResultsConsumed bool
+}
+
+func NewBatchHTTPClient(client *HTTPClient, batchSize int) *BatchHTTPClient {
+	if batchSize <= 0 {
+		batchSize = DEFAULT_BATCH_SIZE
+	}
+
+	return &BatchHTTPClient{
+		client:    client,
+		batchSize: batchSize,
+		backoff:   DEFAULT_BATCH_BACKOFF,
+
+		entries: make(map[int64]*batchEntry),
+	}
+}
+
+// WithBatchBackoff overrides the time batching is backed off after the first failed batch request
+func (client *BatchHTTPClient) WithBatchBackoff(backoff time.Duration) *BatchHTTPClient {
+	client.backoff = backoff
+	return client
+}
+
+func (client *BatchHTTPClient) Genesis() (*genesis.Genesis, error) {
+	return client.client.Genesis()
+}
+
+func (client *BatchHTTPClient) Block(height int64) (*usecase_model.Block, *usecase_model.RawBlock, error) {
+	maybeRawResp := client.prefetched(height, ARCHIVE_METHOD_BLOCK)
+	if maybeRawResp == nil {
+		return client.client.Block(height)
+	}
+
+	return ParseBlockResp(bytes.NewReader(maybeRawResp))
+}
+
+func (client *BatchHTTPClient) BlockResults(height int64) (*usecase_model.BlockResults, error) {
+	maybeRawResp := client.prefetched(height, ARCHIVE_METHOD_BLOCK_RESULTS)
+	if maybeRawResp == nil {
+		return client.client.BlockResults(height)
+	}
+
+	return ParseBlockResultsResp(bytes.NewReader(maybeRawResp))
+}
+
+func (client *BatchHTTPClient) LatestBlockHeight() (int64, error) {
+	return client.client.LatestBlockHeight()
+}
+
+func (client *BatchHTTPClient) Status() (*map[string]interface{}, error) {
+	return client.client.Status()
+}
+
+// prefetched returns the raw response of the method at the height from the batch covering the height. A batch
+// starting from the height is requested when no batch covers it. Returns nil when the batch request failed or batching
+// is backed off.
+func (client *BatchHTTPClient) prefetched(height int64, method string) []byte {
+	client.mutex.Lock()
+	entry, exist := client.entries[height]
+	if !exist {
+		if time.Now().Before(client.backoffUntil) {
+			client.mutex.Unlock()
+			return nil
+		}
+		entry = client.requestBatchInBackground(height)
+	}
+	client.mutex.Unlock()
+
+	<-entry.done
+
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+
+	var maybeRawResp []byte
+	if method == ARCHIVE_METHOD_BLOCK {
+		maybeRawResp = entry.maybeRawBlock
+		entry.isBlockConsumed = true
+	} else {
+		maybeRawResp = entry.maybeRawBlockResults
+		entry.isBlockResultsConsumed = true
+	}
+	if entry.err != nil || (entry.isBlockConsumed && entry.isBlockResultsConsumed) {
+		if client.entries[height] == entry {
+			delete(client.entries, height)
+		}
+	}
+
+	return maybeRawResp
+}
+
+// requestBatchInBackground registers entries for the heights of the batch starting from the height and requests them
+// in a goroutine. Heights already registered are skipped. Must be called with the mutex locked.
+func (client *BatchHTTPClient) requestBatchInBackground(beginHeight int64) *batchEntry {
+	heights := make([]int64, 0, client.batchSize)
+	entries := make([]*batchEntry, 0, client.batchSize)
+	for height := beginHeight; height < beginHeight+int64(client.batchSize); height += 1 {
+		if _, exist := client.entries[height]; exist {
+			continue
+		}
+		entry := &batchEntry{
+			done: make(chan struct{}),
+		}
+		client.entries[height] = entry
+		heights = append(heights, height)
+		entries = append(entries, entry)
+	}
+	client.evictOldEntries()
+
+	go func() {
+		rawResps, err := client.requestBatch(heights)
+		client.recordBatchResult(err)
+		for i, height := range heights {
+			entry := entries[i]
+			if err != nil {
+				entry.err = err
+			} else {
+				entry.maybeRawBlock = rawResps[batchRequestId(ARCHIVE_METHOD_BLOCK, height)]
+				entry.maybeRawBlockResults = rawResps[batchRequestId(ARCHIVE_METHOD_BLOCK_RESULTS, height)]
+			}
+			close(entry.done)
+		}
+	}()
+
+	return entries[0]
+}
+
+// recordBatchResult backs off batching after a failed batch request, for twice as long on each consecutive failure.
+// A successful batch request resets the backoff.
+func (client *BatchHTTPClient) recordBatchResult(err error) {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+
+	if err == nil {
+		client.consecutiveFailures = 0
+		client.backoffUntil = time.Time{}
+		return
+	}
+
+	backoff := client.backoff
+	for i := 0; i < client.consecutiveFailures && backoff < MAX_BATCH_BACKOFF; i += 1 {
+		backoff *= 2
+	}
+	if backoff > MAX_BATCH_BACKOFF {
+		backoff = MAX_BATCH_BACKOFF
+	}
+	client.consecutiveFailures += 1
+	client.backoffUntil = time.Now().Add(backoff)
+}
+
+// evictOldEntries drops the lowest completed heights when the cache is full. Must be called with the mutex locked.
+func (client *BatchHTTPClient) evictOldEntries() {
+	maxEntries := client.batchSize * BATCH_CACHE_SIZE_IN_BATCHES
+	if len(client.entries) <= maxEntries {
+		return
+	}
+
+	heights := make([]int64, 0, len(client.entries))
+	for height := range client.entries {
+		heights = append(heights, height)
+	}
+	sort.Slice(heights, func(i, j int) bool {
+		return heights[i] < heights[j]
+	})
+	for _, height := range heights {
+		if len(client.entries) <= maxEntries {
+			return
+		}
+		select {
+		case <-client.entries[height].done:
+			delete(client.entries, height)
+		default:
+		}
+	}
+}
+
+// requestBatch issues a JSON-RPC batch request of block and block_results of the heights. Returns the successful raw
+// responses by request id. Failed items are left out.
+func (client *BatchHTTPClient) requestBatch(heights []int64) (map[int64][]byte, error) {
+	requests := make([]batchRequest, 0, len(heights)*2)
+	for _, height := range heights {
+		for _, method := range []string{ARCHIVE_METHOD_BLOCK, ARCHIVE_METHOD_BLOCK_RESULTS} {
+			requests = append(requests, batchRequest{
+				Jsonrpc: "2.0",
+				ID:      batchRequestId(method, height),
+				Method:  method,
+				Params: batchRequestParams{
+					Height: strconv.FormatInt(height, 10),
+				},
+			})
+		}
+	}
+	body, err := json.Marshal(requests)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding JSON-RPC batch request: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(
+		context.Background(), http.MethodPost, client.client.tendermintRPCUrl, bytes.NewReader(body),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error creating HTTP request with context: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	rawResp, err := client.client.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error requesting Tendermint JSON-RPC batch: %w", err)
+	}
+	defer rawResp.Body.Close()
+
+	if rawResp.StatusCode != 200 {
+		return nil, fmt.Errorf(
+			"error requesting Tendermint JSON-RPC batch: %w",
+			multiendpoint.NewHTTPStatusError(rawResp.StatusCode, rawResp.Status),
+		)
+	}
+
+	rawBody, err := ioutil.ReadAll(rawResp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading Tendermint JSON-RPC batch response: %v", err)
+	}
+	var rawItems []json.RawMessage
+	if err = json.Unmarshal(rawBody, &rawItems); err != nil {
+		return nil, fmt.Errorf("error decoding Tendermint JSON-RPC batch response: %v", err)
+	}
+
+	rawResps := make(map[int64][]byte, len(rawItems))
+	for _, rawItem := range rawItems {
+		var item batchResponseItem
+		if err = json.Unmarshal(rawItem, &item); err != nil {
+			return nil, fmt.Errorf("error decoding Tendermint JSON-RPC batch response item: %v", err)
+		}
+		if item.MaybeError != nil {
+			continue
+		}
+		rawResps[item.ID] = rawItem
+	}
+
+	if client.client.maybeArchiver != nil {
+		for _, height := range heights {
+			for _, method := range []string{ARCHIVE_METHOD_BLOCK, ARCHIVE_METHOD_BLOCK_RESULTS} {
+				rawResp, ok := rawResps[batchRequestId(method, height)]
+				if !ok {
+					continue
+				}
+				if err = client.client.maybeArchiver.Put(method, height, rawResp); err != nil {
+					return nil, fmt.Errorf("error archiving Tendermint %s response at height %d: %v", method, height, err)
+				}
+			}
+		}
+	}
+
+	return rawResps, nil
+}
+
+// batchRequestId returns a numeric request id unique to the method and height within a batch. Tendermint responses
+// are parsed with numeric ids.
+func batchRequestId(method string, height int64) int64 {
+	if method == ARCHIVE_METHOD_BLOCK {
+		return height * 2
+	}
+	return height*2 + 1
+}
+
+type batchRequest struct {
+	Jsonrpc string             `json:"jsonrpc"`
+	ID      int64              `json:"id"`
+	Method  string             `json:"method"`
+	Params  batchRequestParams `json:"params"`
+}
+
+type batchRequestParams struct {
+	Height string `json:"height"`
+}
+
+type batchResponseItem struct {
+	ID         int64           `json:"id"`
+	MaybeError json.RawMessage `json:"error"`
+}
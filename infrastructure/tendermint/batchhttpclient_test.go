@@ -0,0 +1,175 @@
+package tendermint_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+
+	"github.com/crypto-com/chain-indexing/appinterface/tendermint"
+	. "github.com/crypto-com/chain-indexing/infrastructure/tendermint"
+	infrastructure_tendermint_test "github.com/crypto-com/chain-indexing/infrastructure/tendermint/test"
+)
+
+var _ = Describe("BatchHTTPClient", func() {
+	var server *ghttp.Server
+
+	BeforeEach(func() {
+		server = ghttp.NewServer()
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should implement Client", func() {
+		var _ tendermint.Client = NewBatchHTTPClient(NewHTTPClient("http://localhost:26657", true), 10)
+	})
+
+	It("should serve block and block results of the whole batch with a single request", func() {
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("POST", "/"),
+				respondWithBatch(),
+			),
+		)
+
+		client := NewBatchHTTPClient(NewHTTPClient(server.URL(), true), 3)
+
+		for height := int64(100); height < 103; height += 1 {
+			block, _, err := client.Block(height)
+			Expect(err).To(BeNil())
+			Expect(block).NotTo(BeNil())
+
+			blockResults, err := client.BlockResults(height)
+			Expect(err).To(BeNil())
+			Expect(blockResults).NotTo(BeNil())
+		}
+		Expect(server.ReceivedRequests()).To(HaveLen(1))
+	})
+
+	It("should fall back to per-height requests when the batch request failed", func() {
+		anyBlockHeight := int64(100)
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("POST", "/"),
+				ghttp.RespondWith(http.StatusInternalServerError, ""),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/block", fmt.Sprintf("height=%d", anyBlockHeight)),
+				ghttp.RespondWith(http.StatusOK, infrastructure_tendermint_test.BLOCK_JSON),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/block_results", fmt.Sprintf("height=%d", anyBlockHeight)),
+				ghttp.RespondWith(http.StatusOK, infrastructure_tendermint_test.BLOCK_RESULTS_JSON),
+			),
+		)
+
+		client := NewBatchHTTPClient(NewHTTPClient(server.URL(), true), 3)
+
+		block, _, err := client.Block(anyBlockHeight)
+		Expect(err).To(BeNil())
+		Expect(block).NotTo(BeNil())
+
+		blockResults, err := client.BlockResults(anyBlockHeight)
+		Expect(err).To(BeNil())
+		Expect(blockResults).NotTo(BeNil())
+		Expect(server.ReceivedRequests()).To(HaveLen(3))
+	})
+
+	It("should back off from batching after the batch request failed", func() {
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("POST", "/"),
+				ghttp.RespondWith(http.StatusInternalServerError, ""),
+			),
+		)
+		for height := int64(100); height < 103; height += 1 {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/block", fmt.Sprintf("height=%d", height)),
+					ghttp.RespondWith(http.StatusOK, infrastructure_tendermint_test.BLOCK_JSON),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/block_results", fmt.Sprintf("height=%d", height)),
+					ghttp.RespondWith(http.StatusOK, infrastructure_tendermint_test.BLOCK_RESULTS_JSON),
+				),
+			)
+		}
+
+		client := NewBatchHTTPClient(NewHTTPClient(server.URL(), true), 3)
+
+		for height := int64(100); height < 103; height += 1 {
+			_, _, err := client.Block(height)
+			Expect(err).To(BeNil())
+			_, err = client.BlockResults(height)
+			Expect(err).To(BeNil())
+		}
+		Expect(server.ReceivedRequests()).To(HaveLen(7))
+	})
+
+	It("should batch again once the backoff has elapsed", func() {
+		anyBackoff := 50 * time.Millisecond
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("POST", "/"),
+				ghttp.RespondWith(http.StatusInternalServerError, ""),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/block", "height=100"),
+				ghttp.RespondWith(http.StatusOK, infrastructure_tendermint_test.BLOCK_JSON),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("POST", "/"),
+				respondWithBatch(),
+			),
+		)
+
+		client := NewBatchHTTPClient(NewHTTPClient(server.URL(), true), 3).WithBatchBackoff(anyBackoff)
+
+		_, _, err := client.Block(100)
+		Expect(err).To(BeNil())
+
+		time.Sleep(anyBackoff)
+
+		for height := int64(200); height < 203; height += 1 {
+			_, _, err = client.Block(height)
+			Expect(err).To(BeNil())
+			_, err = client.BlockResults(height)
+			Expect(err).To(BeNil())
+		}
+		Expect(server.ReceivedRequests()).To(HaveLen(3))
+	})
+})
+
+// respondWithBatch responds to each request of a JSON-RPC batch with the block or block results fixture
+func respondWithBatch() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		body, err := ioutil.ReadAll(req.Body)
+		Expect(err).To(BeNil())
+
+		var requests []struct {
+			ID     int64  `json:"id"`
+			Method string `json:"method"`
+		}
+		Expect(json.Unmarshal(body, &requests)).To(Succeed())
+
+		items := make([]string, 0, len(requests))
+		for _, request := range requests {
+			fixture := infrastructure_tendermint_test.BLOCK_JSON
+			if request.Method == "block_results" {
+				fixture = infrastructure_tendermint_test.BLOCK_RESULTS_JSON
+			}
+			items = append(items, strings.Replace(fixture, `"id": -1`, fmt.Sprintf(`"id": %d`, request.ID), 1))
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("[" + strings.Join(items, ",") + "]"))
+	}
+}
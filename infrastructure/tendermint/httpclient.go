@@ -1,6 +1,7 @@
 package tendermint
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
 	"encoding/json"
@@ -8,11 +9,13 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
+	"os"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/crypto-com/chain-indexing/appinterface/tendermint"
+	"github.com/crypto-com/chain-indexing/infrastructure/multiendpoint"
 
 	"github.com/crypto-com/chain-indexing/usecase/model/genesis"
 
@@ -21,10 +24,23 @@ import (
 
 var _ tendermint.Client = &HTTPClient{}
 
+const ARCHIVE_METHOD_GENESIS = "genesis"
+const ARCHIVE_METHOD_BLOCK = "block"
+const ARCHIVE_METHOD_BLOCK_RESULTS = "block_results"
+
+// Archiver archives the raw RPC responses of a height
+type Archiver interface {
+	// Put archives the raw response of the method at the height. Height is 0 for genesis.
+	Put(method string, height int64, rawResp []byte) error
+}
+
 type HTTPClient struct {
 	httpClient           *http.Client
 	tendermintRPCUrl     string
 	strictGenesisParsing bool
+
+	maybeArchiver   Archiver
+	genesisFilePath string
 }
 
 // NewHTTPClient returns a new HTTPClient for tendermint request
@@ -37,6 +53,9 @@ func NewHTTPClient(tendermintRPCUrl string, strictGenesisParsing bool) *HTTPClie
 		httpClient,
 		strings.TrimSuffix(tendermintRPCUrl, "/"),
 		strictGenesisParsing,
+
+		nil,
+		"",
 	}
 }
 
@@ -55,13 +74,30 @@ func NewInsecureHTTPClient(tendermintRPCUrl string, strictGenesisParsing bool) *
 		httpClient,
 		strings.TrimSuffix(tendermintRPCUrl, "/"),
 		strictGenesisParsing,
+
+		nil,
+		"",
 	}
 }
 
+// WithArchiver archives the raw responses of genesis, block and block results requests
+func (client *HTTPClient) WithArchiver(archiver Archiver) *HTTPClient {
+	client.maybeArchiver = archiver
+	return client
+}
+
+// WithGenesisFile serves genesis from the local genesis JSON file instead of requesting the node
+func (client *HTTPClient) WithGenesisFile(genesisFilePath string) *HTTPClient {
+	client.genesisFilePath = genesisFilePath
+	return client
+}
+
+// Genesis gets the genesis from the local genesis file when provided. Otherwise it is requested from `/genesis` and
+// falls back to reassemble the chunks from `/genesis_chunked` when the genesis is too large to be served at once.
 func (client *HTTPClient) Genesis() (*genesis.Genesis, error) {
 	var err error
 
-	rawRespBody, err := client.request("genesis")
+	rawRespBody, err := client.genesisResp()
 	if err != nil {
 		return nil, err
 	}
@@ -75,11 +111,75 @@ func (client *HTTPClient) Genesis() (*genesis.Genesis, error) {
 	return genesis, nil
 }
 
+func (client *HTTPClient) genesisResp() (io.ReadCloser, error) {
+	if client.genesisFilePath != "" {
+		genesisFile, err := os.Open(client.genesisFilePath)
+		if err != nil {
+			return nil, fmt.Errorf("error opening genesis file: %v", err)
+		}
+		return client.archive(ARCHIVE_METHOD_GENESIS, 0, "genesis", wrapGenesisResp(genesisFile))
+	}
+
+	rawRespBody, err := client.request("genesis")
+	if err != nil {
+		rawGenesis, chunkedErr := client.genesisChunked()
+		if chunkedErr != nil {
+			// Wraps the error of the genesis request such that its HTTP status still decides the failover
+			return nil, fmt.Errorf("%w; error requesting chunked genesis: %v", err, chunkedErr)
+		}
+		rawRespBody = wrapGenesisResp(ioutil.NopCloser(bytes.NewReader(rawGenesis)))
+	}
+
+	return client.archive(ARCHIVE_METHOD_GENESIS, 0, "genesis", rawRespBody)
+}
+
+// genesisChunked requests all the chunks from `/genesis_chunked` and returns the reassembled genesis document
+func (client *HTTPClient) genesisChunked() ([]byte, error) {
+	var rawGenesis bytes.Buffer
+	for chunk, total := 0, 1; chunk < total; chunk += 1 {
+		rawRespBody, err := client.request("genesis_chunked", "chunk="+strconv.Itoa(chunk))
+		if err != nil {
+			return nil, err
+		}
+		genesisChunk, err := ParseGenesisChunkedResp(rawRespBody)
+		rawRespBody.Close()
+		if err != nil {
+			return nil, err
+		}
+		if genesisChunk.Chunk != chunk {
+			return nil, fmt.Errorf("error requesting genesis chunk %d: got chunk %d", chunk, genesisChunk.Chunk)
+		}
+
+		total = genesisChunk.Total
+		rawGenesis.Write(genesisChunk.Data)
+	}
+
+	return rawGenesis.Bytes(), nil
+}
+
+// wrapGenesisResp wraps the raw genesis document as a `/genesis` response, so that it is archived and parsed the same
+// way as the genesis requested from the node
+func wrapGenesisResp(rawGenesis io.ReadCloser) io.ReadCloser {
+	return struct {
+		io.Reader
+		io.Closer
+	}{
+		io.MultiReader(
+			strings.NewReader(`{"jsonrpc":"2.0","id":-1,"result":{"genesis":`),
+			rawGenesis,
+			strings.NewReader(`}}`),
+		),
+		rawGenesis,
+	}
+}
+
 // Block gets the block response with target height
 func (client *HTTPClient) Block(height int64) (*usecase_model.Block, *usecase_model.RawBlock, error) {
 	var err error
 
-	rawRespBody, err := client.request("block", "height="+strconv.FormatInt(height, 10))
+	rawRespBody, err := client.archivedRequest(
+		ARCHIVE_METHOD_BLOCK, height, "block", "height="+strconv.FormatInt(height, 10),
+	)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -96,7 +196,9 @@ func (client *HTTPClient) Block(height int64) (*usecase_model.Block, *usecase_mo
 func (client *HTTPClient) BlockResults(height int64) (*usecase_model.BlockResults, error) {
 	var err error
 
-	rawRespBody, err := client.request("block_results", "height="+strconv.FormatInt(height, 10))
+	rawRespBody, err := client.archivedRequest(
+		ARCHIVE_METHOD_BLOCK_RESULTS, height, "block_results", "height="+strconv.FormatInt(height, 10),
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -115,7 +217,7 @@ func (client *HTTPClient) LatestBlockHeight() (int64, error) {
 	var err error
 	rawRespBody, err := client.request("block")
 	if err != nil {
-		return int64(0), fmt.Errorf("error getting /block: %v", err)
+		return int64(0), fmt.Errorf("error getting /block: %w", err)
 	}
 	defer rawRespBody.Close()
 
@@ -127,6 +229,44 @@ func (client *HTTPClient) LatestBlockHeight() (int64, error) {
 	return block.Height, nil
 }
 
+// archivedRequest issues an HTTP request and archives the raw response when an archiver is provided
+func (client *HTTPClient) archivedRequest(
+	archiveMethod string,
+	height int64,
+	method string,
+	queryString ...string,
+) (io.ReadCloser, error) {
+	rawRespBody, err := client.request(method, queryString...)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.archive(archiveMethod, height, method, rawRespBody)
+}
+
+// archive archives the raw response when an archiver is provided and returns a reader of the same response
+func (client *HTTPClient) archive(
+	archiveMethod string,
+	height int64,
+	method string,
+	rawRespBody io.ReadCloser,
+) (io.ReadCloser, error) {
+	if client.maybeArchiver == nil {
+		return rawRespBody, nil
+	}
+	defer rawRespBody.Close()
+
+	rawResp, err := ioutil.ReadAll(rawRespBody)
+	if err != nil {
+		return nil, fmt.Errorf("error reading Tendermint %s response: %v", method, err)
+	}
+	if err = client.maybeArchiver.Put(archiveMethod, height, rawResp); err != nil {
+		return nil, fmt.Errorf("error archiving Tendermint %s response at height %d: %v", method, height, err)
+	}
+
+	return ioutil.NopCloser(bytes.NewReader(rawResp)), nil
+}
+
 // request construct tendermint url and issues an HTTP request
 // returns the success http Body
 func (client *HTTPClient) request(method string, queryString ...string) (io.ReadCloser, error) {
@@ -143,12 +283,15 @@ func (client *HTTPClient) request(method string, queryString ...string) (io.Read
 	}
 	rawResp, err := client.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("error requesting Tendermint %s endpoint: %v", url, err)
+		return nil, fmt.Errorf("error requesting Tendermint %s endpoint: %w", url, err)
 	}
 
 	if rawResp.StatusCode != 200 {
 		rawResp.Body.Close()
-		return nil, fmt.Errorf("error requesting Tendermint %s endpoint: %s", method, rawResp.Status)
+		return nil, fmt.Errorf(
+			"error requesting Tendermint %s endpoint: %w",
+			method, multiendpoint.NewHTTPStatusError(rawResp.StatusCode, rawResp.Status),
+		)
 	}
 
 	return rawResp.Body, nil
@@ -181,6 +324,25 @@ type GenesisRespResult struct {
 	Genesis genesis.Genesis `json:"genesis"`
 }
 
+type GenesisChunkedResp struct {
+	Jsonrpc string                   `json:"jsonrpc"`
+	ID      int64                    `json:"id"`
+	Result  GenesisChunkedRespResult `json:"result"`
+}
+
+type GenesisChunkedRespResult struct {
+	Chunk string `json:"chunk"`
+	Total string `json:"total"`
+	Data  string `json:"data"`
+}
+
+// GenesisChunk is a decoded chunk of the genesis document
+type GenesisChunk struct {
+	Chunk int
+	Total int
+	Data  []byte
+}
+
 type RawBlockResp struct {
 	Jsonrpc string                 `json:"jsonrpc"`
 	ID      int                    `json:"id"`
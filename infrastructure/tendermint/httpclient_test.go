@@ -1,8 +1,14 @@
 package tendermint_test
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io/ioutil"
 	"net/http"
+	"os"
+	"strings"
 
 	infrastructure_tendermint_test "github.com/crypto-com/chain-indexing/infrastructure/tendermint/test"
 
@@ -14,6 +20,7 @@ import (
 
 	"github.com/crypto-com/chain-indexing/appinterface/tendermint"
 	"github.com/crypto-com/chain-indexing/external/utctime"
+	"github.com/crypto-com/chain-indexing/infrastructure/multiendpoint"
 	. "github.com/crypto-com/chain-indexing/infrastructure/tendermint"
 
 	usecase_model "github.com/crypto-com/chain-indexing/usecase/model"
@@ -163,5 +170,94 @@ var _ = Describe("HTTPClient", func() {
 			_, err := client.Genesis()
 			Expect(err).To(BeNil())
 		})
+
+		It("should reassemble genesis from genesis_chunked when genesis request failed", func() {
+			rawGenesis := rawGenesisFromResp(infrastructure_tendermint_test.GENESIS_MIXED_NUMBER_AND_STRING_JSON)
+			chunks := []string{rawGenesis[:len(rawGenesis)/2], rawGenesis[len(rawGenesis)/2:]}
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/genesis"),
+					ghttp.RespondWith(http.StatusInternalServerError, ""),
+				),
+			)
+			for i, chunk := range chunks {
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", "/genesis_chunked", fmt.Sprintf("chunk=%d", i)),
+						ghttp.RespondWith(http.StatusOK, fmt.Sprintf(
+							`{"jsonrpc":"2.0","id":-1,"result":{"chunk":"%d","total":"%d","data":"%s"}}`,
+							i, len(chunks), base64.StdEncoding.EncodeToString([]byte(chunk)),
+						)),
+					),
+				)
+			}
+
+			client := NewHTTPClient(server.URL(), true)
+			genesis, err := client.Genesis()
+			Expect(err).To(BeNil())
+			Expect(genesis).To(Equal(mustParseGenesisResp(
+				infrastructure_tendermint_test.GENESIS_MIXED_NUMBER_AND_STRING_JSON,
+			)))
+			Expect(server.ReceivedRequests()).To(HaveLen(3))
+		})
+
+		It("should keep the HTTP status of genesis request when genesis_chunked request also failed", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/genesis"),
+					ghttp.RespondWith(http.StatusServiceUnavailable, ""),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/genesis_chunked", "chunk=0"),
+					ghttp.RespondWith(http.StatusNotFound, ""),
+				),
+			)
+
+			client := NewHTTPClient(server.URL(), true)
+			_, err := client.Genesis()
+
+			var statusErr *multiendpoint.HTTPStatusError
+			Expect(errors.As(err, &statusErr)).To(BeTrue())
+			Expect(statusErr.StatusCode).To(Equal(http.StatusServiceUnavailable))
+			Expect(multiendpoint.IsFailoverError(err)).To(BeTrue())
+		})
+
+		It("should read genesis from the local genesis file without requesting the node", func() {
+			genesisFile, err := ioutil.TempFile("", "genesis-*.json")
+			Expect(err).To(BeNil())
+			defer os.Remove(genesisFile.Name())
+			_, err = genesisFile.WriteString(
+				rawGenesisFromResp(infrastructure_tendermint_test.GENESIS_MIXED_NUMBER_AND_STRING_JSON),
+			)
+			Expect(err).To(BeNil())
+			Expect(genesisFile.Close()).To(Succeed())
+
+			client := NewHTTPClient(server.URL(), true).WithGenesisFile(genesisFile.Name())
+			genesis, err := client.Genesis()
+			Expect(err).To(BeNil())
+			Expect(genesis).To(Equal(mustParseGenesisResp(
+				infrastructure_tendermint_test.GENESIS_MIXED_NUMBER_AND_STRING_JSON,
+			)))
+			Expect(server.ReceivedRequests()).To(BeEmpty())
+		})
 	})
 })
+
+// rawGenesisFromResp extracts the raw genesis document from a genesis response
+func rawGenesisFromResp(rawResp string) string {
+	var resp struct {
+		Result struct {
+			Genesis json.RawMessage `json:"genesis"`
+		} `json:"result"`
+	}
+	Expect(json.Unmarshal([]byte(rawResp), &resp)).To(Succeed())
+
+	return string(resp.Result.Genesis)
+}
+
+func mustParseGenesisResp(rawResp string) interface{} {
+	genesis, err := ParseGenesisResp(strings.NewReader(rawResp), true)
+	Expect(err).To(BeNil())
+
+	return genesis
+}
@@ -0,0 +1,151 @@
+package tendermint
+
+import (
+	"github.com/crypto-com/chain-indexing/appinterface/tendermint"
+	"github.com/crypto-com/chain-indexing/infrastructure/multiendpoint"
+	usecase_model "github.com/crypto-com/chain-indexing/usecase/model"
+	"github.com/crypto-com/chain-indexing/usecase/model/genesis"
+)
+
+var _ tendermint.Client = &MultiEndpointHTTPClient{}
+
+const MULTI_ENDPOINT_POOL_NAME = "tendermint"
+
+// MultiEndpointHTTPClient is a Tendermint client over multiple RPC endpoints. Requests are routed by
+// endpoint health score and latency and fail over on server errors, timeouts and unavailable heights.
+type MultiEndpointHTTPClient struct {
+	pool        *multiendpoint.Pool
+	httpClients map[string]*HTTPClient
+	clients     map[string]endpointClient
+}
+
+// endpointClient is the client serving requests of a single endpoint
+type endpointClient interface {
+	tendermint.Client
+	Status() (*map[string]interface{}, error)
+}
+
+// NewMultiEndpointHTTPClient returns a new MultiEndpointHTTPClient for tendermint requests
+func NewMultiEndpointHTTPClient(tendermintRPCUrls []string, strictGenesisParsing bool) *MultiEndpointHTTPClient {
+	clients := make(map[string]*HTTPClient, len(tendermintRPCUrls))
+	for _, url := range tendermintRPCUrls {
+		clients[url] = NewHTTPClient(url, strictGenesisParsing)
+	}
+
+	return newMultiEndpointHTTPClient(tendermintRPCUrls, clients)
+}
+
+// NewInsecureMultiEndpointHTTPClient returns a new MultiEndpointHTTPClient which skips TLS
+// certificate verification
+func NewInsecureMultiEndpointHTTPClient(
+	tendermintRPCUrls []string,
+	strictGenesisParsing bool,
+) *MultiEndpointHTTPClient {
+	clients := make(map[string]*HTTPClient, len(tendermintRPCUrls))
+	for _, url := range tendermintRPCUrls {
+		clients[url] = NewInsecureHTTPClient(url, strictGenesisParsing)
+	}
+
+	return newMultiEndpointHTTPClient(tendermintRPCUrls, clients)
+}
+
+func newMultiEndpointHTTPClient(
+	tendermintRPCUrls []string,
+	httpClients map[string]*HTTPClient,
+) *MultiEndpointHTTPClient {
+	clients := make(map[string]endpointClient, len(httpClients))
+	for url, httpClient := range httpClients {
+		clients[url] = httpClient
+	}
+
+	return &MultiEndpointHTTPClient{
+		pool:        multiendpoint.NewPool(MULTI_ENDPOINT_POOL_NAME, tendermintRPCUrls, isFailoverError),
+		httpClients: httpClients,
+		clients:     clients,
+	}
+}
+
+// WithArchiver archives the raw responses of genesis, block and block results requests of all endpoints
+func (client *MultiEndpointHTTPClient) WithArchiver(archiver Archiver) *MultiEndpointHTTPClient {
+	for _, httpClient := range client.httpClients {
+		httpClient.WithArchiver(archiver)
+	}
+	return client
+}
+
+// WithGenesisFile serves genesis from the local genesis JSON file instead of requesting the endpoints
+func (client *MultiEndpointHTTPClient) WithGenesisFile(genesisFilePath string) *MultiEndpointHTTPClient {
+	for _, httpClient := range client.httpClients {
+		httpClient.WithGenesisFile(genesisFilePath)
+	}
+	return client
+}
+
+// WithBatchSize prefetches block and block results of batchSize heights in a single JSON-RPC batch request on each
+// endpoint
+func (client *MultiEndpointHTTPClient) WithBatchSize(batchSize int) *MultiEndpointHTTPClient {
+	for url, httpClient := range client.httpClients {
+		client.clients[url] = NewBatchHTTPClient(httpClient, batchSize)
+	}
+	return client
+}
+
+func (client *MultiEndpointHTTPClient) Genesis() (*genesis.Genesis, error) {
+	var result *genesis.Genesis
+	err := client.pool.Do(func(url string) error {
+		var err error
+		result, err = client.clients[url].Genesis()
+		return err
+	})
+
+	return result, err
+}
+
+func (client *MultiEndpointHTTPClient) Block(height int64) (*usecase_model.Block, *usecase_model.RawBlock, error) {
+	var block *usecase_model.Block
+	var rawBlock *usecase_model.RawBlock
+	err := client.pool.Do(func(url string) error {
+		var err error
+		block, rawBlock, err = client.clients[url].Block(height)
+		return err
+	})
+
+	return block, rawBlock, err
+}
+
+func (client *MultiEndpointHTTPClient) BlockResults(height int64) (*usecase_model.BlockResults, error) {
+	var result *usecase_model.BlockResults
+	err := client.pool.Do(func(url string) error {
+		var err error
+		result, err = client.clients[url].BlockResults(height)
+		return err
+	})
+
+	return result, err
+}
+
+func (client *MultiEndpointHTTPClient) LatestBlockHeight() (int64, error) {
+	var result int64
+	err := client.pool.Do(func(url string) error {
+		var err error
+		result, err = client.clients[url].LatestBlockHeight()
+		return err
+	})
+
+	return result, err
+}
+
+func (client *MultiEndpointHTTPClient) Status() (*map[string]interface{}, error) {
+	var result *map[string]interface{}
+	err := client.pool.Do(func(url string) error {
+		var err error
+		result, err = client.clients[url].Status()
+		return err
+	})
+
+	return result, err
+}
+
+func isFailoverError(err error) bool {
+	return multiendpoint.IsFailoverError(err) || multiendpoint.IsHeightNotAvailableError(err)
+}
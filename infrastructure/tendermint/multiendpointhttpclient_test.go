@@ -0,0 +1,68 @@
+package tendermint_test
+
+import (
+	"fmt"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+
+	"github.com/crypto-com/chain-indexing/appinterface/tendermint"
+	. "github.com/crypto-com/chain-indexing/infrastructure/tendermint"
+	infrastructure_tendermint_test "github.com/crypto-com/chain-indexing/infrastructure/tendermint/test"
+)
+
+var _ = Describe("MultiEndpointHTTPClient", func() {
+	var failingServer *ghttp.Server
+	var healthyServer *ghttp.Server
+
+	BeforeEach(func() {
+		failingServer = ghttp.NewServer()
+		healthyServer = ghttp.NewServer()
+	})
+
+	AfterEach(func() {
+		failingServer.Close()
+		healthyServer.Close()
+	})
+
+	It("should implement Client", func() {
+		var _ tendermint.Client = NewMultiEndpointHTTPClient([]string{"http://localhost:26657"}, true)
+	})
+
+	It("should fail over to next endpoint when endpoint responds with server error", func() {
+		anyBlockHeight := int64(3813)
+		failingServer.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/block_results", fmt.Sprintf("height=%d", anyBlockHeight)),
+				ghttp.RespondWith(http.StatusBadGateway, ""),
+			),
+		)
+		healthyServer.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/block_results", fmt.Sprintf("height=%d", anyBlockHeight)),
+				ghttp.RespondWith(http.StatusOK, infrastructure_tendermint_test.BLOCK_RESULTS_JSON),
+			),
+		)
+
+		client := NewMultiEndpointHTTPClient([]string{failingServer.URL(), healthyServer.URL()}, true)
+
+		blockResults, err := client.BlockResults(anyBlockHeight)
+		Expect(err).To(BeNil())
+		Expect(blockResults).NotTo(BeNil())
+		Expect(failingServer.ReceivedRequests()).To(HaveLen(1))
+		Expect(healthyServer.ReceivedRequests()).To(HaveLen(1))
+	})
+
+	It("should return error when all endpoints failed", func() {
+		anyBlockHeight := int64(3813)
+		failingServer.AppendHandlers(ghttp.RespondWith(http.StatusServiceUnavailable, ""))
+		healthyServer.AppendHandlers(ghttp.RespondWith(http.StatusServiceUnavailable, ""))
+
+		client := NewMultiEndpointHTTPClient([]string{failingServer.URL(), healthyServer.URL()}, true)
+
+		_, err := client.BlockResults(anyBlockHeight)
+		Expect(err).NotTo(BeNil())
+	})
+})
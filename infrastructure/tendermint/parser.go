@@ -30,6 +30,32 @@ func ParseGenesisResp(rawRespReader io.Reader, strictParsing bool) (*genesis.Gen
 	return &genesisResp.Result.Genesis, nil
 }
 
+func ParseGenesisChunkedResp(rawRespReader io.Reader) (*GenesisChunk, error) {
+	var genesisChunkedResp GenesisChunkedResp
+	if err := jsoniter.NewDecoder(rawRespReader).Decode(&genesisChunkedResp); err != nil {
+		return nil, fmt.Errorf("error decoding Tendermint genesis_chunked response: %v", err)
+	}
+
+	chunk, err := strconv.Atoi(genesisChunkedResp.Result.Chunk)
+	if err != nil {
+		return nil, fmt.Errorf("error converting genesis chunk index to integer: %v", err)
+	}
+	total, err := strconv.Atoi(genesisChunkedResp.Result.Total)
+	if err != nil {
+		return nil, fmt.Errorf("error converting genesis chunk total to integer: %v", err)
+	}
+	data, err := base64.StdEncoding.DecodeString(genesisChunkedResp.Result.Data)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding genesis chunk data: %v", err)
+	}
+
+	return &GenesisChunk{
+		Chunk: chunk,
+		Total: total,
+		Data:  data,
+	}, nil
+}
+
 func ParseBlockResp(rawRespReader io.Reader) (*model.Block, *model.RawBlock, error) {
 	var err error
 
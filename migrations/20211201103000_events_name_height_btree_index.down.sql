@@ -0,0 +1 @@
+DROP INDEX IF EXISTS events_name_height_btree_index;
@@ -0,0 +1 @@
+CREATE INDEX events_name_height_btree_index ON events USING btree (name, height);
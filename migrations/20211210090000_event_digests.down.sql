@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS events_digest_height_btree_index;
+ALTER TABLE events DROP COLUMN IF EXISTS digest;
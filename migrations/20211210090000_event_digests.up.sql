@@ -0,0 +1,2 @@
+ALTER TABLE events ADD COLUMN digest BIGINT;
+CREATE INDEX events_digest_height_btree_index ON events USING btree (height) WHERE digest IS NOT NULL;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS projection_dead_letters;
+DROP TABLE IF EXISTS projection_statuses;
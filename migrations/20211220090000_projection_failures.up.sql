@@ -0,0 +1,21 @@
+CREATE TABLE projection_statuses (
+    id VARCHAR NOT NULL,
+    state VARCHAR NOT NULL,
+    maybe_failed_height BIGINT NULL,
+    maybe_error VARCHAR NULL,
+    updated_at BIGINT NOT NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE TABLE projection_dead_letters (
+    id BIGSERIAL,
+    projection_id VARCHAR NOT NULL,
+    height BIGINT NOT NULL,
+    events JSONB NOT NULL,
+    error VARCHAR NOT NULL,
+    created_at BIGINT NOT NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX projection_dead_letters_projection_id_btree_index
+    ON projection_dead_letters USING btree (projection_id, id);
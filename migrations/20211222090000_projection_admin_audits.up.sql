@@ -0,0 +1,14 @@
+CREATE TABLE projection_admin_audits (
+    id BIGSERIAL,
+    projection_id VARCHAR NOT NULL,
+    action VARCHAR NOT NULL,
+    maybe_height BIGINT NULL,
+    actor VARCHAR NOT NULL,
+    remote_address VARCHAR NOT NULL,
+    maybe_error VARCHAR NULL,
+    created_at BIGINT NOT NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX projection_admin_audits_projection_id_btree_index
+    ON projection_admin_audits USING btree (projection_id, id);
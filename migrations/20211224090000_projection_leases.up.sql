@@ -0,0 +1,6 @@
+CREATE TABLE projection_leases (
+    projection_id VARCHAR NOT NULL,
+    holder VARCHAR NOT NULL,
+    expires_at BIGINT NOT NULL,
+    PRIMARY KEY (projection_id)
+);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS projection_dead_letters_projection_id_height_unique_index;
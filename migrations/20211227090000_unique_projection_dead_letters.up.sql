@@ -0,0 +1,8 @@
+DELETE FROM projection_dead_letters dead_letters
+    USING projection_dead_letters duplicated_dead_letters
+    WHERE dead_letters.projection_id = duplicated_dead_letters.projection_id
+        AND dead_letters.height = duplicated_dead_letters.height
+        AND dead_letters.id < duplicated_dead_letters.id;
+
+CREATE UNIQUE INDEX projection_dead_letters_projection_id_height_unique_index
+    ON projection_dead_letters USING btree (projection_id, height);
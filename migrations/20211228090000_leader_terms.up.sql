@@ -0,0 +1,5 @@
+CREATE TABLE leader_terms (
+    lock_key BIGINT NOT NULL,
+    term BIGINT NOT NULL,
+    PRIMARY KEY (lock_key)
+);
@@ -57,10 +57,15 @@ var (
 func (_ *Account) GetEventsToListen() []string {
 	return []string{
 		// TODO: Genesis account
+		event_usecase.BOOTSTRAP_ACCOUNT_CREATED,
 		event_usecase.ACCOUNT_TRANSFERRED,
 	}
 }
 
+func (projection *Account) MigrationHelper() migrationhelper.MigrationHelper {
+	return projection.migrationHelper
+}
+
 func (projection *Account) OnInit() error {
 	if projection.migrationHelper != nil {
 		projection.migrationHelper.Migrate()
@@ -90,6 +95,12 @@ func (projection *Account) HandleEvents(height int64, events []event_entity.Even
 			if handleErr := projection.handleAccountCreatedEvent(accountsView, accountCreatedEvent); handleErr != nil {
 				return fmt.Errorf("error handling AccountCreatedEvent: %v", handleErr)
 			}
+		} else if bootstrapAccountEvent, ok := event.(*event_usecase.BootstrapAccountCreated); ok {
+			if handleErr := projection.handleBootstrapAccountCreatedEvent(
+				accountsView, bootstrapAccountEvent,
+			); handleErr != nil {
+				return fmt.Errorf("error handling BootstrapAccountCreatedEvent: %v", handleErr)
+			}
 		}
 	}
 
@@ -120,6 +131,19 @@ func (projection *Account) handleAccountCreatedEvent(accountsView account_view.A
 	return nil
 }
 
+// handleBootstrapAccountCreatedEvent writes the account as of the bootstrapped state, which is the state at the height
+// before the start height where the event is created
+func (projection *Account) handleBootstrapAccountCreatedEvent(
+	accountsView account_view.Accounts, event *event_usecase.BootstrapAccountCreated,
+) error {
+	accountInfo, err := projection.cosmosClient.AccountAtHeight(event.Address, event.Height()-1)
+	if err != nil {
+		return err
+	}
+
+	return projection.upsertAccountInfo(accountsView, event.Address, accountInfo, event.Balance)
+}
+
 func (projection *Account) getAccountInfo(address string) (*cosmosapp_interface.Account, error) {
 	var accountInfo, accountInfoError = projection.cosmosClient.Account(address)
 	if accountInfoError != nil {
@@ -143,7 +167,20 @@ func (projection *Account) writeAccountInfo(accountsView account_view.Accounts,
 	if err != nil {
 		return err
 	}
+	balances, err := projection.getAccountBalances(address)
+	if err != nil {
+		return err
+	}
+
+	return projection.upsertAccountInfo(accountsView, address, accountInfo, balances)
+}
 
+func (projection *Account) upsertAccountInfo(
+	accountsView account_view.Accounts,
+	address string,
+	accountInfo *cosmosapp_interface.Account,
+	balances coin.Coins,
+) error {
 	accountType := accountInfo.Type
 	var name *string
 	if accountInfo.Type == cosmosapp_interface.ACCOUNT_MODULE {
@@ -156,10 +193,6 @@ func (projection *Account) writeAccountInfo(accountsView account_view.Accounts,
 	accountNumber := accountInfo.AccountNumber
 	sequenceNumber := accountInfo.Sequence
 
-	balances, err := projection.getAccountBalances(address)
-	if err != nil {
-		return err
-	}
 	if err := accountsView.Upsert(&account_view.AccountRow{
 		Type:           accountType,
 		Address:        address,
@@ -14,6 +14,7 @@ import (
 	account_view "github.com/crypto-com/chain-indexing/projection/account/view"
 	"github.com/crypto-com/chain-indexing/usecase/coin"
 	event_usecase "github.com/crypto-com/chain-indexing/usecase/event"
+	"github.com/crypto-com/chain-indexing/usecase/model"
 	"github.com/stretchr/testify/assert"
 	testify_mock "github.com/stretchr/testify/mock"
 )
@@ -222,6 +223,68 @@ func TestAccount_HandleEvents(t *testing.T) {
 					return nil
 				}
 
+				return mocks
+			},
+		},
+		{
+			Name: "HandleBootstrapAccountCreated",
+			Events: []entity_event.Event{
+				event_usecase.NewBootstrapAccountCreated(100, model.BootstrapAccountParams{
+					Address: "Address",
+					Balance: coin.Coins{
+						coin.Coin{
+							Denom:  "Denom",
+							Amount: coin.NewInt(100),
+						},
+					},
+				}),
+			},
+			MockFunc: func(mockClient *cosmosapp.MockClient) (mocks []*testify_mock.Mock) {
+				pubkey := "Key"
+
+				mockClient.On("AccountAtHeight", "Address", int64(99)).Return(
+					&cosmosapp.Account{
+						Type:    "AccountType",
+						Address: "Address",
+						MaybePubkey: &cosmosapp.PubKey{
+							Type: "PubKeyType",
+							Key:  pubkey,
+						},
+						AccountNumber: "AccountNumber",
+						Sequence:      "Sequence",
+					},
+					nil,
+				)
+
+				mockAccountsView := account_view.NewMockAccountsView(nil).(*account_view.MockAccountsView)
+				mocks = append(mocks, &mockAccountsView.Mock)
+
+				account.NewAccountsView = func(_ *rdb.Handle) account_view.Accounts {
+					return mockAccountsView
+				}
+
+				mockAccountsView.On(
+					"Upsert",
+					&account_view.AccountRow{
+						Address:        "Address",
+						Type:           "AccountType",
+						MaybeName:      (*string)(nil),
+						MaybePubkey:    &pubkey,
+						AccountNumber:  "AccountNumber",
+						SequenceNumber: "Sequence",
+						Balance: coin.Coins{
+							{
+								Denom:  "Denom",
+								Amount: coin.NewInt(100),
+							},
+						},
+					},
+				).Return(nil)
+
+				account.UpdateLastHandledEventHeight = func(_ *account.Account, _ *rdb.Handle, _ int64) error {
+					return nil
+				}
+
 				return mocks
 			},
 		},
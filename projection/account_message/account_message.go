@@ -19,6 +19,7 @@ import (
 )
 
 var _ projection_entity.Projection = &AccountMessage{}
+var _ projection_entity.BatchProjection = &AccountMessage{}
 
 var (
 	NewAccountMessages           = view.NewAccountMessagesView
@@ -63,6 +64,10 @@ func (_ *AccountMessage) GetEventsToListen() []string {
 	}, event_usecase.MSG_EVENTS...)
 }
 
+func (projection *AccountMessage) MigrationHelper() migrationhelper.MigrationHelper {
+	return projection.migrationHelper
+}
+
 func (projection *AccountMessage) OnInit() error {
 	if projection.migrationHelper != nil {
 		projection.migrationHelper.Migrate()
@@ -98,6 +103,67 @@ func (projection *AccountMessage) HandleEvents(height int64, events []event_enti
 		return nil
 	}
 
+	if err := projection.handleEventsWithRDbHandle(rdbTxHandle, height, events); err != nil {
+		return err
+	}
+
+	if err := UpdateLastHandledEventHeight(projection, rdbTxHandle, height); err != nil {
+		return fmt.Errorf("error updating last handled event height: %v", err)
+	}
+
+	if err := rdbTx.Commit(); err != nil {
+		return fmt.Errorf("error committing changes: %v", err)
+	}
+	committed = true
+	return nil
+}
+
+// HandleEventsBatch handles the events of a range of heights in a single transaction
+func (projection *AccountMessage) HandleEventsBatch(fromHeight int64, toHeight int64, events []event_entity.Event) error {
+	rdbTx, err := projection.rdbConn.Begin()
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %v", err)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			_ = rdbTx.Rollback()
+		}
+	}()
+
+	rdbTxHandle := rdbTx.ToHandle()
+
+	eventsByHeight := make(map[int64][]event_entity.Event)
+	for _, event := range events {
+		eventsByHeight[event.Height()] = append(eventsByHeight[event.Height()], event)
+	}
+	for height := fromHeight; height <= toHeight; height += 1 {
+		// TODO: Handle genesis transaction
+		if height == int64(0) || len(eventsByHeight[height]) == 0 {
+			continue
+		}
+		if err := projection.handleEventsWithRDbHandle(rdbTxHandle, height, eventsByHeight[height]); err != nil {
+			return err
+		}
+	}
+
+	if err := UpdateLastHandledEventHeight(projection, rdbTxHandle, toHeight); err != nil {
+		return fmt.Errorf("error updating last handled event height: %v", err)
+	}
+
+	if err := rdbTx.Commit(); err != nil {
+		return fmt.Errorf("error committing changes: %v", err)
+	}
+	committed = true
+	return nil
+}
+
+func (projection *AccountMessage) handleEventsWithRDbHandle(
+	rdbTxHandle *rdb.Handle,
+	height int64,
+	events []event_entity.Event,
+) error {
 	accountMessagesView := NewAccountMessages(rdbTxHandle)
 	accountMessagesTotalView := NewAccountMessagesTotal(rdbTxHandle)
 
@@ -977,13 +1043,5 @@ func (projection *AccountMessage) HandleEvents(height int64, events []event_enti
 		}
 	}
 
-	if err := UpdateLastHandledEventHeight(projection, rdbTxHandle, height); err != nil {
-		return fmt.Errorf("error updating last handled event height: %v", err)
-	}
-
-	if err := rdbTx.Commit(); err != nil {
-		return fmt.Errorf("error committing changes: %v", err)
-	}
-	committed = true
 	return nil
 }
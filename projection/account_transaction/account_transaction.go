@@ -58,6 +58,10 @@ func (_ *AccountTransaction) GetEventsToListen() []string {
 	}, event_usecase.MSG_EVENTS...)
 }
 
+func (projection *AccountTransaction) MigrationHelper() migrationhelper.MigrationHelper {
+	return projection.migrationHelper
+}
+
 func (projection *AccountTransaction) OnInit() error {
 	if projection.migrationHelper != nil {
 		projection.migrationHelper.Migrate()
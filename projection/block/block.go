@@ -14,6 +14,7 @@ import (
 )
 
 var _ entity_projection.Projection = &Block{}
+var _ entity_projection.RewindableProjection = &Block{}
 
 // TODO: Listen to council node related events and project council node
 type Block struct {
@@ -47,6 +48,10 @@ func (_ *Block) GetEventsToListen() []string {
 	return []string{event_usecase.BLOCK_CREATED}
 }
 
+func (projection *Block) MigrationHelper() migrationhelper.MigrationHelper {
+	return projection.migrationHelper
+}
+
 func (projection *Block) OnInit() error {
 	if projection.migrationHelper != nil {
 		projection.migrationHelper.Migrate()
@@ -88,6 +93,35 @@ func (projection *Block) HandleEvents(height int64, events []event_entity.Event)
 	return nil
 }
 
+// Implements projection.RewindableProjection.Rewind() by deleting the blocks after the height
+func (projection *Block) Rewind(height int64) error {
+	rdbTx, err := projection.rdbConn.Begin()
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %v", err)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			_ = rdbTx.Rollback()
+		}
+	}()
+
+	rdbTxHandle := rdbTx.ToHandle()
+	if _, err = view.NewBlocks(rdbTxHandle).DeleteAfterHeight(height); err != nil {
+		return fmt.Errorf("error deleting blocks after height %d: %v", height, err)
+	}
+	if err = projection.UpdateLastHandledEventHeight(rdbTxHandle, height); err != nil {
+		return fmt.Errorf("error updating last handled event height: %v", err)
+	}
+
+	if err = rdbTx.Commit(); err != nil {
+		return fmt.Errorf("error committing changes: %v", err)
+	}
+	committed = true
+	return nil
+}
+
 func (projection *Block) handleBlockCreatedEvent(blocksView *view.Blocks, event *event_usecase.BlockCreated) error {
 	committedCouncilNodes := make([]view.BlockCommittedCouncilNode, 0)
 	for _, signature := range event.Block.Signatures {
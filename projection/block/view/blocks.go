@@ -70,6 +70,23 @@ func (blocksView *Blocks) Insert(block *Block) error {
 	return nil
 }
 
+// DeleteAfterHeight deletes the blocks after the height and returns the number of blocks deleted
+func (blocksView *Blocks) DeleteAfterHeight(height int64) (int64, error) {
+	sql, sqlArgs, err := blocksView.rdb.StmtBuilder.Delete(
+		"view_blocks",
+	).Where("height > ?", height).ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("error building blocks deletion sql: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	result, err := blocksView.rdb.Exec(sql, sqlArgs...)
+	if err != nil {
+		return 0, fmt.Errorf("error deleting blocks from the table: %v: %w", err, rdb.ErrWrite)
+	}
+
+	return result.RowsAffected(), nil
+}
+
 func (blocksView *Blocks) List(order BlocksListOrder, pagination *pagination.Pagination) ([]Block, *pagination.PaginationResult, error) {
 	stmtBuilder := blocksView.rdb.StmtBuilder.Select(
 		"height",
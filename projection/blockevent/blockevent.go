@@ -64,6 +64,10 @@ const (
 	MIGRATION_DIRECOTRY = "projection/blockevent/migrations"
 )
 
+func (projection *BlockEvent) MigrationHelper() migrationhelper.MigrationHelper {
+	return projection.migrationHelper
+}
+
 func (projection *BlockEvent) OnInit() error {
 	if projection.migrationHelper != nil {
 		projection.migrationHelper.Migrate()
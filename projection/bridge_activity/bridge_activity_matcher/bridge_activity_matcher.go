@@ -1,11 +1,13 @@
 package bridge_activity_matcher
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
 	"time"
 
+	"github.com/crypto-com/chain-indexing/appinterface/eventhandler"
 	"github.com/crypto-com/chain-indexing/appinterface/rdb"
 	projection_entity "github.com/crypto-com/chain-indexing/entity/projection"
 	applogger "github.com/crypto-com/chain-indexing/external/logger"
@@ -26,6 +28,7 @@ var (
 	}
 )
 var _ projection_entity.CronJob = &BridgeActivityMatcher{}
+var _ eventhandler.WriteFenced = &BridgeActivityMatcher{}
 
 type Config struct {
 	Interval           time.Duration             `mapstructure:"interval"`
@@ -90,6 +93,8 @@ type BridgeActivityMatcher struct {
 	logger                 applogger.Logger
 
 	migrationHelper migrationhelper.MigrationHelper
+
+	maybeWriteFence eventhandler.WriteFence
 }
 
 const (
@@ -121,6 +126,20 @@ func (cronJob *BridgeActivityMatcher) Id() string {
 	return "BridgeActivityMatcher"
 }
 
+// SetWriteFence implements eventhandler.WriteFenced.SetWriteFence(). The matched activities are only committed when the
+// write fence is passed in the same transaction.
+func (cronJob *BridgeActivityMatcher) SetWriteFence(writeFence eventhandler.WriteFence) {
+	cronJob.maybeWriteFence = writeFence
+}
+
+func (cronJob *BridgeActivityMatcher) fenceWithRDbHandle(rdbHandle *rdb.Handle) error {
+	if cronJob.maybeWriteFence == nil {
+		return nil
+	}
+
+	return cronJob.maybeWriteFence.FenceWithRDbHandle(rdbHandle)
+}
+
 func (cronJob *BridgeActivityMatcher) Config() *Config {
 	return &cronJob.config
 }
@@ -171,7 +190,7 @@ func (cronJob *BridgeActivityMatcher) Interval() time.Duration {
 	return cronJob.Config().Interval
 }
 
-func (cronJob *BridgeActivityMatcher) Exec() error {
+func (cronJob *BridgeActivityMatcher) Exec(ctx context.Context) error {
 	thisBridgePendingActivities := NewBridgePendingActivitiesView(cronJob.thisRDbConn.ToHandle())
 
 	thisAllUnprocessedOutgoing, thisAllUnprocessedOutgoingErr := thisBridgePendingActivities.ListAllUnprocessedOutgoing()
@@ -211,6 +230,10 @@ func (cronJob *BridgeActivityMatcher) Exec() error {
 	}
 
 	for _, counterpartyRDbConfig := range cronJob.counterpartyRDbConfigs {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
 		counterpartyBridgePendingActivities := NewBridgePendingActivitiesView(counterpartyRDbConfig.Conn.ToHandle())
 
 		counterpartyAllUnprocessedOutgoing, counterpartyAllUnprocessedOutgoingErr := counterpartyBridgePendingActivities.
@@ -296,6 +319,9 @@ func (cronJob *BridgeActivityMatcher) handleOutgoingRow(
 	}()
 
 	thisRDbTxHandle := thisRDbTx.ToHandle()
+	if err := cronJob.fenceWithRDbHandle(thisRDbTxHandle); err != nil {
+		return fmt.Errorf("error fencing writes: %v", err)
+	}
 
 	bridgeActivities := NewBridgeActivitiesView(thisRDbTxHandle)
 
@@ -379,6 +405,9 @@ func (cronJob *BridgeActivityMatcher) handleIncomingRow(
 	}()
 
 	thisRDbTxHandle := thisRDbTx.ToHandle()
+	if err := cronJob.fenceWithRDbHandle(thisRDbTxHandle); err != nil {
+		return fmt.Errorf("error fencing writes: %v", err)
+	}
 
 	bridgeActivities := NewBridgeActivitiesView(thisRDbTxHandle)
 
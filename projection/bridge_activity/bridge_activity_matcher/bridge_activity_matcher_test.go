@@ -1,6 +1,7 @@
 package bridge_activity_matcher_test
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -1620,7 +1621,7 @@ func TestBridgeActivityMatcher_HandleEvents(t *testing.T) {
 		onInitErr := projection.OnInit()
 		assert.NoError(t, onInitErr)
 
-		execErr := projection.Exec()
+		execErr := projection.Exec(context.Background())
 		assert.NoError(t, execErr)
 
 		for _, m := range mocks {
@@ -113,6 +113,10 @@ func (_ *BridgePendingActivity) GetEventsToListen() []string {
 	}
 }
 
+func (projection *BridgePendingActivity) MigrationHelper() migrationhelper.MigrationHelper {
+	return projection.migrationHelper
+}
+
 func (projection *BridgePendingActivity) OnInit() error {
 	if projection.migrationHelper != nil {
 		projection.migrationHelper.Migrate()
@@ -70,6 +70,10 @@ const (
 	MIGRATION_DIRECOTRY = "projection/chainstats/migrations"
 )
 
+func (projection *ChainStats) MigrationHelper() migrationhelper.MigrationHelper {
+	return projection.migrationHelper
+}
+
 func (projection *ChainStats) OnInit() error {
 	if projection.migrationHelper != nil {
 		projection.migrationHelper.Migrate()
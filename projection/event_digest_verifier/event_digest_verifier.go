@@ -0,0 +1,112 @@
+package event_digest_verifier
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+
+	event_interface "github.com/crypto-com/chain-indexing/appinterface/event"
+	"github.com/crypto-com/chain-indexing/appinterface/rdb"
+	event_entity "github.com/crypto-com/chain-indexing/entity/event"
+	projection_entity "github.com/crypto-com/chain-indexing/entity/projection"
+	applogger "github.com/crypto-com/chain-indexing/external/logger"
+)
+
+var _ projection_entity.CronJob = &EventDigestVerifier{}
+
+const DEFAULT_INTERVAL = time.Hour
+
+type Config struct {
+	Interval time.Duration `mapstructure:"interval"`
+}
+
+func ConfigFromInterface(data interface{}) (Config, error) {
+	config := Config{
+		Interval: DEFAULT_INTERVAL,
+	}
+	if data == nil {
+		return config, nil
+	}
+
+	decoderConfig := &mapstructure.DecoderConfig{
+		WeaklyTypedInput: true,
+		DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+		Result:           &config,
+	}
+	decoder, decoderErr := mapstructure.NewDecoder(decoderConfig)
+	if decoderErr != nil {
+		return config, fmt.Errorf("error creating cron job config decoder: %v", decoderErr)
+	}
+
+	if err := decoder.Decode(data); err != nil {
+		return config, fmt.Errorf("error decoding cron job EventDigestVerifier config: %v", err)
+	}
+
+	return config, nil
+}
+
+// EventDigestVerifier walks the event digest chain on regular interval and reports the first inconsistent height and
+// the heights stored before digests were introduced, which cannot be verified
+type EventDigestVerifier struct {
+	config Config
+
+	eventStore *event_interface.RDbStore
+	logger     applogger.Logger
+
+	maybeLastVerification *event_interface.DigestVerification
+}
+
+func New(config Config, logger applogger.Logger, rdbConn rdb.Conn) *EventDigestVerifier {
+	return &EventDigestVerifier{
+		config: config,
+
+		// Verification works on the stored payloads so no event has to be decoded
+		eventStore: event_interface.NewRDbStore(rdbConn.ToHandle(), event_entity.NewRegistry()),
+		logger: logger.WithFields(applogger.LogFields{
+			"module": "EventDigestVerifier",
+		}),
+	}
+}
+
+func (cronJob *EventDigestVerifier) Id() string {
+	return "EventDigestVerifier"
+}
+
+func (cronJob *EventDigestVerifier) OnInit() error {
+	return nil
+}
+
+func (cronJob *EventDigestVerifier) Interval() time.Duration {
+	return cronJob.config.Interval
+}
+
+// LastVerification returns the outcome of the last execution, nil when no execution has completed yet
+func (cronJob *EventDigestVerifier) LastVerification() *event_interface.DigestVerification {
+	return cronJob.maybeLastVerification
+}
+
+func (cronJob *EventDigestVerifier) Exec(ctx context.Context) error {
+	verification, err := cronJob.eventStore.VerifyDigests(ctx)
+	if err != nil {
+		return fmt.Errorf("error verifying event digests: %v", err)
+	}
+	cronJob.maybeLastVerification = verification
+
+	if verification.MaybeUnverifiedFromHeight != nil {
+		cronJob.logger.WithFields(applogger.LogFields{
+			"fromHeight": *verification.MaybeUnverifiedFromHeight,
+			"toHeight":   *verification.MaybeUnverifiedToHeight,
+		}).Error("events stored before digests were introduced are unverified")
+	}
+	if verification.MaybeInconsistentHeight != nil {
+		cronJob.logger.WithFields(applogger.LogFields{
+			"height": *verification.MaybeInconsistentHeight,
+		}).Error("event digest chain is inconsistent")
+		return fmt.Errorf("event digest chain is inconsistent from height %d", *verification.MaybeInconsistentHeight)
+	}
+
+	cronJob.logger.Info("event digest chain is consistent")
+	return nil
+}
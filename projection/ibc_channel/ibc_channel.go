@@ -100,6 +100,10 @@ const (
 	MIGRATION_DIRECOTRY  = "projection/ibc_channel/migrations"
 )
 
+func (projection *IBCChannel) MigrationHelper() migrationhelper.MigrationHelper {
+	return projection.migrationHelper
+}
+
 func (projection *IBCChannel) OnInit() error {
 	if projection.migrationHelper != nil {
 		projection.migrationHelper.Migrate()
@@ -68,6 +68,10 @@ func (_ *IBCChannelMessage) GetEventsToListen() []string {
 	}
 }
 
+func (projection *IBCChannelMessage) MigrationHelper() migrationhelper.MigrationHelper {
+	return projection.migrationHelper
+}
+
 func (projection *IBCChannelMessage) OnInit() error {
 	if projection.migrationHelper != nil {
 		projection.migrationHelper.Migrate()
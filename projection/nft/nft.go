@@ -85,6 +85,10 @@ const (
 	MIGRATION_DIRECOTRY  = "projection/nft/migrations"
 )
 
+func (projection *NFT) MigrationHelper() migrationhelper.MigrationHelper {
+	return projection.migrationHelper
+}
+
 func (projection *NFT) OnInit() error {
 	if projection.migrationHelper != nil {
 		projection.migrationHelper.Migrate()
@@ -19,6 +19,7 @@ import (
 	"github.com/crypto-com/chain-indexing/infrastructure/pg/migrationhelper"
 	"github.com/crypto-com/chain-indexing/projection/proposal/types"
 	"github.com/crypto-com/chain-indexing/projection/proposal/view"
+	"github.com/crypto-com/chain-indexing/usecase/coin"
 	event_usecase "github.com/crypto-com/chain-indexing/usecase/event"
 )
 
@@ -95,6 +96,7 @@ func (proposal *Proposal) GetEventsToListen() []string {
 		append(
 			[]string{
 				event_usecase.BLOCK_CREATED,
+				event_usecase.BOOTSTRAP_PROPOSAL_CREATED,
 				event_usecase.MSG_SUBMIT_TEXT_PROPOSAL_CREATED,
 				event_usecase.MSG_SUBMIT_COMMUNITY_POOL_SPEND_PROPOSAL_CREATED,
 				event_usecase.MSG_SUBMIT_PARAM_CHANGE_PROPOSAL_CREATED,
@@ -117,6 +119,10 @@ func (proposal *Proposal) GetEventsToListen() []string {
 // 	MIGRATION_DIRECOTRY  = "projection/proposal/migrations"
 // )
 
+func (projection *Proposal) MigrationHelper() migrationhelper.MigrationHelper {
+	return projection.migrationHelper
+}
+
 func (projection *Proposal) OnInit() error {
 	if projection.migrationHelper != nil {
 		projection.migrationHelper.Migrate()
@@ -425,6 +431,37 @@ func (projection *Proposal) HandleEvents(height int64, events []event_entity.Eve
 				return fmt.Errorf("error inserting proposer deposit total record into view: %v", updateDepositorTotalErr)
 			}
 
+		} else if bootstrapProposal, ok := event.(*event_usecase.BootstrapProposalCreated); ok {
+			// The proposer, the deposits and the votes before the bootstrap height are not known
+			status := view.PROPOSAL_STATUS_DEPOSIT_PERIOD
+			if bootstrapProposal.MaybeVotingStartTime != nil {
+				status = view.PROPOSAL_STATUS_VOTING_PERIOD
+			}
+			row := view.ProposalRow{
+				ProposalId:                   bootstrapProposal.ProposalId,
+				Title:                        bootstrapProposal.Title,
+				Description:                  bootstrapProposal.Description,
+				Type:                         bootstrapProposal.Type,
+				Status:                       status,
+				ProposerAddress:              "",
+				MaybeProposerOperatorAddress: nil,
+				Data:                         nil,
+				InitialDeposit:               coin.NewEmptyCoins(),
+				TotalDeposit:                 bootstrapProposal.TotalDeposit,
+				TotalVote:                    big.NewInt(0),
+				TransactionHash:              "",
+				SubmitBlockHeight:            height,
+				SubmitTime:                   bootstrapProposal.SubmitTime,
+				DepositEndTime:               bootstrapProposal.DepositEndTime,
+				MaybeVotingStartTime:         bootstrapProposal.MaybeVotingStartTime,
+				MaybeVotingEndTime:           bootstrapProposal.MaybeVotingEndTime,
+				MaybeVotingEndBlockHeight:    nil,
+			}
+
+			if insertProposalErr := proposalsView.Insert(&row); insertProposalErr != nil {
+				return fmt.Errorf("error inserting bootstrap proposal into view: %v", insertProposalErr)
+			}
+
 		} else if proposalVotingPeriodStarted, ok := event.(*event_usecase.ProposalVotingPeriodStarted); ok {
 			mutProposal, err := proposalsView.FindById(proposalVotingPeriodStarted.ProposalId)
 			if err != nil {
@@ -830,6 +830,58 @@ func TestProposal_HandleEvents(t *testing.T) {
 				return mocks
 			},
 		},
+		{
+			Name: "HandleBootstrapProposalCreated",
+			Events: []entity_event.Event{
+				usecase_event.NewBootstrapProposalCreated(1, model.BootstrapProposalParams{
+					ProposalId:           "ProposalId",
+					Type:                 "Type",
+					Title:                "Title",
+					Description:          "Description",
+					Status:               "PROPOSAL_STATUS_VOTING_PERIOD",
+					TotalDeposit:         coin.MustParseCoinsNormalized("10000000basetcro"),
+					SubmitTime:           utctime.FromUnixNano(1),
+					DepositEndTime:       utctime.FromUnixNano(3),
+					MaybeVotingStartTime: primptr.UTCTime(utctime.FromUnixNano(2)),
+					MaybeVotingEndTime:   primptr.UTCTime(utctime.FromUnixNano(4)),
+				}),
+			},
+			MockFunc: func(events []entity_event.Event) (mocks []*testify_mock.Mock) {
+
+				mockProposalsView := &view.MockProposalsView{}
+				mocks = append(mocks, &mockProposalsView.Mock)
+				mockProposalsView.
+					On("Insert", &view.ProposalRow{
+						ProposalId:                   "ProposalId",
+						Title:                        "Title",
+						Description:                  "Description",
+						Type:                         "Type",
+						Status:                       view.PROPOSAL_STATUS_VOTING_PERIOD,
+						ProposerAddress:              "",
+						MaybeProposerOperatorAddress: nil,
+						Data:                         nil,
+						InitialDeposit:               coin.NewEmptyCoins(),
+						TotalDeposit:                 coin.MustParseCoinsNormalized("10000000basetcro"),
+						TotalVote:                    big.NewInt(0),
+						TransactionHash:              "",
+						SubmitBlockHeight:            1,
+						SubmitTime:                   utctime.FromUnixNano(1),
+						DepositEndTime:               utctime.FromUnixNano(3),
+						MaybeVotingStartTime:         primptr.UTCTime(utctime.FromUnixNano(2)),
+						MaybeVotingEndTime:           primptr.UTCTime(utctime.FromUnixNano(4)),
+						MaybeVotingEndBlockHeight:    nil,
+					}).
+					Return(nil)
+
+				proposal.NewProposals = func(
+					_ *rdb.Handle,
+				) view.Proposals {
+					return mockProposalsView
+				}
+
+				return mocks
+			},
+		},
 		{
 			Name: "HandleProposalInactived",
 			Events: []entity_event.Event{
@@ -62,6 +62,10 @@ func (_ *Transaction) GetEventsToListen() []string {
 // 	MIGRATION_DIRECOTRY  = "projection/transaction/migrations"
 // )
 
+func (projection *Transaction) MigrationHelper() migrationhelper.MigrationHelper {
+	return projection.migrationHelper
+}
+
 func (projection *Transaction) OnInit() error {
 	if projection.migrationHelper != nil {
 		projection.migrationHelper.Migrate()
@@ -60,6 +60,9 @@ func (_ *Validator) GetEventsToListen() []string {
 		event_usecase.GENESIS_CREATED,
 		event_usecase.BLOCK_CREATED,
 		event_usecase.GENESIS_VALIDATOR_CREATED,
+		event_usecase.STATE_BOOTSTRAPPED,
+		event_usecase.BOOTSTRAP_VALIDATOR_CREATED,
+		event_usecase.BOOTSTRAP_DELEGATION_CREATED,
 		event_usecase.MSG_CREATE_VALIDATOR_CREATED,
 		event_usecase.MSG_EDIT_VALIDATOR_CREATED,
 		event_usecase.MSG_DELEGATE_CREATED,
@@ -83,6 +86,10 @@ func (_ *Validator) GetEventsToListen() []string {
 // 	MIGRATION_DIRECOTRY  = "projection/validator/migrations"
 // )
 
+func (projection *Validator) MigrationHelper() migrationhelper.MigrationHelper {
+	return projection.migrationHelper
+}
+
 func (projection *Validator) OnInit() error {
 	if projection.migrationHelper != nil {
 		projection.migrationHelper.Migrate()
@@ -118,6 +125,9 @@ func (projection *Validator) HandleEvents(height int64, events []event_entity.Ev
 		if genesisEvent, ok := event.(*event_usecase.GenesisCreated); ok {
 			blockTime = utctime.MustParse(time.RFC3339, genesisEvent.Genesis.GenesisTime)
 			blockHash = "genesis"
+		} else if stateBootstrappedEvent, ok := event.(*event_usecase.StateBootstrapped); ok {
+			blockTime = stateBootstrappedEvent.BlockTime
+			blockHash = stateBootstrappedEvent.BlockHash
 		} else if blockCreatedEvent, ok := event.(*event_usecase.BlockCreated); ok {
 			blockTime = blockCreatedEvent.Block.Time
 			blockHash = blockCreatedEvent.Block.Hash
@@ -132,6 +132,26 @@ func (projection *Validator) projectValidatorActivitiesView(
 				fmt.Sprintf("%s:%s", delegateEvent.ValidatorAddress, delegateEvent.Name()),
 			)
 			totalIncrementalMap.IncrementByOne(fmt.Sprintf("-:%s", delegateEvent.Name()))
+		} else if bootstrapDelegationEvent, ok := event.(*event_usecase.BootstrapDelegationCreated); ok {
+			activityRows = append(activityRows, view.ValidatorActivityRow{
+				BlockHeight:          bootstrapDelegationEvent.BlockHeight,
+				BlockHash:            blockHash,
+				BlockTime:            blockTime,
+				MaybeTransactionHash: nil,
+				OperatorAddress:      bootstrapDelegationEvent.ValidatorAddress,
+				Success:              true,
+				Data: view.ValidatorActivityRowData{
+					Type:    bootstrapDelegationEvent.Name(),
+					Content: bootstrapDelegationEvent,
+				},
+			})
+
+			totalIncrementalMap.IncrementByOne("-")
+			totalIncrementalMap.IncrementByOne(bootstrapDelegationEvent.ValidatorAddress)
+			totalIncrementalMap.IncrementByOne(
+				fmt.Sprintf("%s:%s", bootstrapDelegationEvent.ValidatorAddress, bootstrapDelegationEvent.Name()),
+			)
+			totalIncrementalMap.IncrementByOne(fmt.Sprintf("-:%s", bootstrapDelegationEvent.Name()))
 		} else if redelegateEvent, ok := event.(*event_usecase.MsgBeginRedelegate); ok {
 			activityRows = append(activityRows, view.ValidatorActivityRow{
 				BlockHeight:          redelegateEvent.BlockHeight,
@@ -51,6 +51,7 @@ func NewValidatorStats(
 func (_ *ValidatorStats) GetEventsToListen() []string {
 	return []string{
 		event_usecase.GENESIS_VALIDATOR_CREATED,
+		event_usecase.BOOTSTRAP_DELEGATION_CREATED,
 		event_usecase.MSG_CREATE_VALIDATOR_CREATED,
 		event_usecase.BLOCK_PROPOSER_REWARDED,
 		event_usecase.BLOCK_REWARDED,
@@ -65,6 +66,10 @@ const (
 	MIGRATION_DIRECOTRY  = "projection/validatorstats/migrations"
 )
 
+func (projection *ValidatorStats) MigrationHelper() migrationhelper.MigrationHelper {
+	return projection.migrationHelper
+}
+
 func (projection *ValidatorStats) OnInit() error {
 	if projection.migrationHelper != nil {
 		projection.migrationHelper.Migrate()
@@ -115,6 +120,9 @@ func (projection *ValidatorStats) HandleEvents(height int64, events []event_enti
 	for _, event := range events {
 		if createValidatorEvent, ok := event.(*event_usecase.CreateGenesisValidator); ok {
 			totalDelegate = totalDelegate.Add(createValidatorEvent.Amount)
+		} else if bootstrapDelegationEvent, ok := event.(*event_usecase.BootstrapDelegationCreated); ok {
+			// Bootstrap validators are not counted, as their tokens are the sum of the bootstrap delegations
+			totalDelegate = totalDelegate.Add(bootstrapDelegationEvent.Amount)
 		} else if createValidatorEvent, ok := event.(*event_usecase.MsgCreateValidator); ok {
 			totalDelegate = totalDelegate.Add(createValidatorEvent.Amount)
 		} else if blockProposerRewardedEvent, ok := event.(*event_usecase.BlockProposerRewarded); ok {
@@ -18,6 +18,7 @@ import (
 	event_usecase "github.com/crypto-com/chain-indexing/usecase/event"
 	"github.com/crypto-com/chain-indexing/usecase/model"
 	model_usecase "github.com/crypto-com/chain-indexing/usecase/model"
+	"github.com/crypto-com/chain-indexing/usecase/model/genesis"
 )
 
 var VALIDATORSTATS_MIGRATIONS_PATH = func() string {
@@ -169,6 +170,45 @@ var _ = Describe("Validator Stats", func() {
 			Expect(errAfterHandling).To(BeNil())
 		})
 
+		It("should add the bootstrap delegations but not the bootstrap validators to the totalDelegate amount", func() {
+			validatorStatsView := validatorstats_view.NewValidatorStats(pgxConn.ToHandle())
+
+			anyHeight := int64(101)
+			bootstrapValidatorEvent := event_usecase.NewCreateBootstrapValidator(
+				anyHeight,
+				genesis.CreateGenesisValidatorParams{
+					DelegatorAddress: "tcro1fmprm0sjy6lz9llv7rltn0v2azzwcwzvk2lsyn",
+					ValidatorAddress: "tcrocncl1fmprm0sjy6lz9llv7rltn0v2azzwcwzvr4ufus",
+					Amount:           coin.MustParseCoinNormalized("30basetcro"),
+				},
+			)
+			bootstrapDelegationEvents := []event_entity.Event{
+				event_usecase.NewBootstrapDelegationCreated(anyHeight, model.BootstrapDelegationParams{
+					DelegatorAddress: "tcro1fmprm0sjy6lz9llv7rltn0v2azzwcwzvk2lsyn",
+					ValidatorAddress: "tcrocncl1fmprm0sjy6lz9llv7rltn0v2azzwcwzvr4ufus",
+					Shares:           "10.000000000000000000",
+					Amount:           coin.MustParseCoinNormalized("10basetcro"),
+				}),
+				event_usecase.NewBootstrapDelegationCreated(anyHeight, model.BootstrapDelegationParams{
+					DelegatorAddress: "tcro1q435860mlxc8954ye4v6vghwge8rw5eq5newp7",
+					ValidatorAddress: "tcrocncl1fmprm0sjy6lz9llv7rltn0v2azzwcwzvr4ufus",
+					Shares:           "20.000000000000000000",
+					Amount:           coin.MustParseCoinNormalized("20basetcro"),
+				}),
+			}
+
+			projection := validatorstats.NewValidatorStats(NewFakeLogger(), pgxConn, nil)
+
+			handleEventsErr := projection.HandleEvents(
+				anyHeight, append([]event_entity.Event{bootstrapValidatorEvent}, bootstrapDelegationEvents...),
+			)
+			Expect(handleEventsErr).To(BeNil())
+
+			totalDelegate, err := validatorStatsView.FindBy("total_delegate")
+			Expect(err).To(BeNil())
+			Expect(totalDelegate).To(Equal("[{\"denom\":\"basetcro\",\"amount\":\"30\"}]"))
+		})
+
 		It("should update projection last handled event height when there is no event at the height", func() {
 			anyHeight := int64(1)
 
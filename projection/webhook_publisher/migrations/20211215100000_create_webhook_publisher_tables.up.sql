@@ -0,0 +1,23 @@
+CREATE TABLE webhook_subscriber_cursors (
+    subscriber VARCHAR NOT NULL,
+    last_event_height BIGINT NOT NULL,
+    last_event_id BIGINT NOT NULL,
+    PRIMARY KEY (subscriber)
+);
+
+CREATE TABLE webhook_dead_letters (
+    id BIGSERIAL,
+    subscriber VARCHAR NOT NULL,
+    event_id BIGINT NOT NULL,
+    event_uuid VARCHAR NOT NULL,
+    event_name VARCHAR NOT NULL,
+    event_version INT NOT NULL,
+    event_height BIGINT NOT NULL,
+    payload JSONB NOT NULL,
+    error VARCHAR NOT NULL,
+    created_at BIGINT NOT NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX webhook_dead_letters_subscriber_event_id_btree_index
+    ON webhook_dead_letters USING btree (subscriber, event_id);
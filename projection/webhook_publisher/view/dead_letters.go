@@ -0,0 +1,76 @@
+package view
+
+import (
+	"fmt"
+
+	"github.com/crypto-com/chain-indexing/appinterface/rdb"
+	"github.com/crypto-com/chain-indexing/external/utctime"
+)
+
+type WebhookDeadLetters interface {
+	Insert(deadLetter *WebhookDeadLetterRow) error
+}
+
+const TABLE_WEBHOOK_DEAD_LETTERS = "webhook_dead_letters"
+
+// WebhookDeadLettersView keeps the events failed to be delivered to a subscriber after all retries
+type WebhookDeadLettersView struct {
+	rdb *rdb.Handle
+}
+
+func NewWebhookDeadLettersView(handle *rdb.Handle) WebhookDeadLetters {
+	return &WebhookDeadLettersView{
+		handle,
+	}
+}
+
+func (view *WebhookDeadLettersView) Insert(deadLetter *WebhookDeadLetterRow) error {
+	sql, sqlArgs, err := view.rdb.StmtBuilder.Insert(
+		TABLE_WEBHOOK_DEAD_LETTERS,
+	).Columns(
+		"subscriber",
+		"event_id",
+		"event_uuid",
+		"event_name",
+		"event_version",
+		"event_height",
+		"payload",
+		"error",
+		"created_at",
+	).Values(
+		deadLetter.Subscriber,
+		deadLetter.EventId,
+		deadLetter.EventUUID,
+		deadLetter.EventName,
+		deadLetter.EventVersion,
+		deadLetter.EventHeight,
+		deadLetter.Payload,
+		deadLetter.Error,
+		view.rdb.Tton(&deadLetter.CreatedAt),
+	).ToSql()
+	if err != nil {
+		return fmt.Errorf("error building webhook dead letter insertion SQL: %v", err)
+	}
+
+	result, err := view.rdb.Exec(sql, sqlArgs...)
+	if err != nil {
+		return fmt.Errorf("error inserting webhook dead letter: %v", err)
+	}
+	if result.RowsAffected() != 1 {
+		return fmt.Errorf("error inserting webhook dead letter: %d rows inserted", result.RowsAffected())
+	}
+
+	return nil
+}
+
+type WebhookDeadLetterRow struct {
+	Subscriber   string
+	EventId      int64
+	EventUUID    string
+	EventName    string
+	EventVersion int
+	EventHeight  int64
+	Payload      string
+	Error        string
+	CreatedAt    utctime.UTCTime
+}
@@ -0,0 +1,20 @@
+package view
+
+import (
+	"github.com/stretchr/testify/mock"
+)
+
+type MockWebhookDeadLettersView struct {
+	mock.Mock
+}
+
+var _ WebhookDeadLetters = &MockWebhookDeadLettersView{}
+
+func NewMockWebhookDeadLettersView() WebhookDeadLetters {
+	return &MockWebhookDeadLettersView{}
+}
+
+func (view *MockWebhookDeadLettersView) Insert(deadLetter *WebhookDeadLetterRow) error {
+	mockArgs := view.Called(deadLetter)
+	return mockArgs.Error(0)
+}
@@ -0,0 +1,92 @@
+package view
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/crypto-com/chain-indexing/appinterface/rdb"
+)
+
+type WebhookSubscriberCursors interface {
+	FindBy(subscriber string) (WebhookSubscriberCursor, error)
+	Upsert(subscriber string, cursor WebhookSubscriberCursor) error
+}
+
+const TABLE_WEBHOOK_SUBSCRIBER_CURSORS = "webhook_subscriber_cursors"
+
+// WebhookSubscriberCursorsView keeps the height and id of the last event handled, i.e. delivered or dead-lettered, of
+// each subscriber
+type WebhookSubscriberCursorsView struct {
+	rdb *rdb.Handle
+}
+
+func NewWebhookSubscriberCursorsView(handle *rdb.Handle) WebhookSubscriberCursors {
+	return &WebhookSubscriberCursorsView{
+		handle,
+	}
+}
+
+// FindBy returns the cursor of the subscriber, the initial cursor when no event is handled yet
+func (view *WebhookSubscriberCursorsView) FindBy(subscriber string) (WebhookSubscriberCursor, error) {
+	sql, sqlArgs, err := view.rdb.StmtBuilder.Select(
+		"last_event_height",
+		"last_event_id",
+	).From(
+		TABLE_WEBHOOK_SUBSCRIBER_CURSORS,
+	).Where(
+		"subscriber = ?", subscriber,
+	).ToSql()
+	if err != nil {
+		return InitialWebhookSubscriberCursor, fmt.Errorf(
+			"error building webhook subscriber cursor selection SQL: %v", err,
+		)
+	}
+
+	var cursor WebhookSubscriberCursor
+	if err := view.rdb.QueryRow(sql, sqlArgs...).Scan(&cursor.LastEventHeight, &cursor.LastEventId); err != nil {
+		if errors.Is(err, rdb.ErrNoRows) {
+			return InitialWebhookSubscriberCursor, nil
+		}
+		return InitialWebhookSubscriberCursor, fmt.Errorf("error scanning webhook subscriber cursor: %v", err)
+	}
+
+	return cursor, nil
+}
+
+func (view *WebhookSubscriberCursorsView) Upsert(subscriber string, cursor WebhookSubscriberCursor) error {
+	sql, sqlArgs, err := view.rdb.StmtBuilder.Insert(
+		TABLE_WEBHOOK_SUBSCRIBER_CURSORS,
+	).Columns(
+		"subscriber", "last_event_height", "last_event_id",
+	).Values(
+		subscriber, cursor.LastEventHeight, cursor.LastEventId,
+	).Suffix(`ON CONFLICT (subscriber) DO UPDATE SET
+		last_event_height = EXCLUDED.last_event_height,
+		last_event_id = EXCLUDED.last_event_id`,
+	).ToSql()
+	if err != nil {
+		return fmt.Errorf("error building webhook subscriber cursor upsertion SQL: %v", err)
+	}
+
+	result, err := view.rdb.Exec(sql, sqlArgs...)
+	if err != nil {
+		return fmt.Errorf("error upserting webhook subscriber cursor: %v", err)
+	}
+	if result.RowsAffected() != 1 {
+		return fmt.Errorf("error upserting webhook subscriber cursor: %d rows affected", result.RowsAffected())
+	}
+
+	return nil
+}
+
+// WebhookSubscriberCursor is the position of the last event handled of a subscriber, ordered by height then id
+type WebhookSubscriberCursor struct {
+	LastEventHeight int64
+	LastEventId     int64
+}
+
+// InitialWebhookSubscriberCursor is before the first event of the event store
+var InitialWebhookSubscriberCursor = WebhookSubscriberCursor{
+	LastEventHeight: -1,
+	LastEventId:     0,
+}
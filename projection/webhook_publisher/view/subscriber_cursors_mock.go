@@ -0,0 +1,25 @@
+package view
+
+import (
+	"github.com/stretchr/testify/mock"
+)
+
+type MockWebhookSubscriberCursorsView struct {
+	mock.Mock
+}
+
+var _ WebhookSubscriberCursors = &MockWebhookSubscriberCursorsView{}
+
+func NewMockWebhookSubscriberCursorsView() WebhookSubscriberCursors {
+	return &MockWebhookSubscriberCursorsView{}
+}
+
+func (view *MockWebhookSubscriberCursorsView) FindBy(subscriber string) (WebhookSubscriberCursor, error) {
+	mockArgs := view.Called(subscriber)
+	return mockArgs.Get(0).(WebhookSubscriberCursor), mockArgs.Error(1)
+}
+
+func (view *MockWebhookSubscriberCursorsView) Upsert(subscriber string, cursor WebhookSubscriberCursor) error {
+	mockArgs := view.Called(subscriber, cursor)
+	return mockArgs.Error(0)
+}
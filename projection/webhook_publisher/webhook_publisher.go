@@ -0,0 +1,439 @@
+package webhook_publisher
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/mitchellh/mapstructure"
+
+	event_interface "github.com/crypto-com/chain-indexing/appinterface/event"
+	"github.com/crypto-com/chain-indexing/appinterface/eventhandler"
+	"github.com/crypto-com/chain-indexing/appinterface/rdb"
+	event_entity "github.com/crypto-com/chain-indexing/entity/event"
+	projection_entity "github.com/crypto-com/chain-indexing/entity/projection"
+	applogger "github.com/crypto-com/chain-indexing/external/logger"
+	"github.com/crypto-com/chain-indexing/external/utctime"
+	"github.com/crypto-com/chain-indexing/infrastructure/pg/migrationhelper"
+	"github.com/crypto-com/chain-indexing/projection/webhook_publisher/view"
+)
+
+var (
+	NewWebhookSubscriberCursorsView = view.NewWebhookSubscriberCursorsView
+	NewWebhookDeadLettersView       = view.NewWebhookDeadLettersView
+	NewEventSource                  = func(handle *rdb.Handle, registry *event_entity.Registry) EventSource {
+		return event_interface.NewRDbStore(handle, registry)
+	}
+)
+var _ projection_entity.CronJob = &WebhookPublisher{}
+var _ eventhandler.WriteFenced = &WebhookPublisher{}
+
+const (
+	MIGRATION_DIRECOTRY = "projection/webhook_publisher/migrations"
+
+	DEFAULT_INTERVAL                  = 5 * time.Second
+	DEFAULT_BATCH_SIZE                = 100
+	DEFAULT_REQUEST_TIMEOUT           = 10 * time.Second
+	DEFAULT_MAX_RETRIES               = 5
+	DEFAULT_INITIAL_RETRY_INTERVAL    = time.Second
+	DEFAULT_MAX_RETRY_INTERVAL        = time.Minute
+	SIGNATURE_HEADER                  = "X-Signature-256"
+	SIGNATURE_PREFIX                  = "sha256="
+	EVENT_ID_HEADER                   = "X-Event-Id"
+	EVENT_UUID_HEADER                 = "X-Event-UUID"
+	EVENT_NAME_HEADER                 = "X-Event-Name"
+	EVENT_VERSION_HEADER              = "X-Event-Version"
+	EVENT_HEIGHT_HEADER               = "X-Event-Height"
+	SUBSCRIBER_HEADER                 = "X-Webhook-Subscriber"
+	MAX_DEAD_LETTER_ERROR_MESSAGE_LEN = 1024
+)
+
+// EventSource lists the committed events in commit order, by height then insertion sequence id
+type EventSource interface {
+	ListAfter(afterHeight int64, afterId int64, eventNames []string, limit uint64) ([]event_interface.OutboxEvent, error)
+}
+
+type Config struct {
+	Interval             time.Duration      `mapstructure:"interval"`
+	BatchSize            uint64             `mapstructure:"batch_size"`
+	RequestTimeout       time.Duration      `mapstructure:"request_timeout"`
+	MaxRetries           uint64             `mapstructure:"max_retries"`
+	InitialRetryInterval time.Duration      `mapstructure:"initial_retry_interval"`
+	MaxRetryInterval     time.Duration      `mapstructure:"max_retry_interval"`
+	Subscribers          []SubscriberConfig `mapstructure:"subscribers"`
+}
+
+type SubscriberConfig struct {
+	// Unique name of the subscriber, which identifies its cursor
+	Name string `mapstructure:"name"`
+	URL  string `mapstructure:"url"`
+	// Name of the environment variable of the HMAC-SHA256 secret signing the request body
+	SecretEnv string `mapstructure:"secret_env"`
+	// Secret read from the environment variable of SecretEnv
+	Secret string `mapstructure:"-"`
+	// Names of the events to deliver. All events are delivered when empty.
+	EventNames []string `mapstructure:"event_names"`
+}
+
+func ConfigFromInterface(data interface{}) (Config, error) {
+	config := Config{
+		Interval:             DEFAULT_INTERVAL,
+		BatchSize:            DEFAULT_BATCH_SIZE,
+		RequestTimeout:       DEFAULT_REQUEST_TIMEOUT,
+		MaxRetries:           DEFAULT_MAX_RETRIES,
+		InitialRetryInterval: DEFAULT_INITIAL_RETRY_INTERVAL,
+		MaxRetryInterval:     DEFAULT_MAX_RETRY_INTERVAL,
+	}
+
+	decoderConfig := &mapstructure.DecoderConfig{
+		WeaklyTypedInput: true,
+		DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+		Result:           &config,
+	}
+	decoder, decoderErr := mapstructure.NewDecoder(decoderConfig)
+	if decoderErr != nil {
+		return config, fmt.Errorf("error creating cron job config decoder: %v", decoderErr)
+	}
+
+	if err := decoder.Decode(data); err != nil {
+		return config, fmt.Errorf("error decoding cron job WebhookPublisher config: %v", err)
+	}
+
+	if config.Interval <= 0 {
+		return config, fmt.Errorf("error validating cron job WebhookPublisher config: interval must be positive")
+	}
+
+	subscriberNames := make(map[string]bool, len(config.Subscribers))
+	for i, subscriber := range config.Subscribers {
+		if subscriber.Name == "" || subscriber.URL == "" {
+			return config, fmt.Errorf("error validating cron job WebhookPublisher config: missing subscriber name or url")
+		}
+		if subscriber.SecretEnv == "" {
+			return config, fmt.Errorf(
+				"error validating cron job WebhookPublisher config: missing secret_env of subscriber %s", subscriber.Name,
+			)
+		}
+		config.Subscribers[i].Secret = os.Getenv(subscriber.SecretEnv)
+		if config.Subscribers[i].Secret == "" {
+			return config, fmt.Errorf(
+				"error validating cron job WebhookPublisher config: environment variable %s of subscriber %s is unset or empty",
+				subscriber.SecretEnv, subscriber.Name,
+			)
+		}
+		if subscriberNames[subscriber.Name] {
+			return config, fmt.Errorf(
+				"error validating cron job WebhookPublisher config: duplicated subscriber %s", subscriber.Name,
+			)
+		}
+		subscriberNames[subscriber.Name] = true
+	}
+
+	return config, nil
+}
+
+// WebhookPublisher delivers the committed events to the subscribers' HTTP webhook endpoints. Each subscriber has its
+// own cursor over the event store so a slow or failing subscriber does not hold back the others. An event is retried
+// with exponential backoff and moved to the dead letters once the retries are exhausted, then the cursor proceeds.
+// Delivery is at-least-once and in commit order, by height then insertion, per subscriber.
+type WebhookPublisher struct {
+	config Config
+
+	rdbConn    rdb.Conn
+	registry   *event_entity.Registry
+	httpClient *http.Client
+	logger     applogger.Logger
+
+	migrationHelper migrationhelper.MigrationHelper
+
+	maybeWriteFence eventhandler.WriteFence
+}
+
+func New(
+	config Config,
+	logger applogger.Logger,
+	rdbConn rdb.Conn,
+	registry *event_entity.Registry,
+	migrationHelper migrationhelper.MigrationHelper,
+) *WebhookPublisher {
+	return &WebhookPublisher{
+		config: config,
+
+		rdbConn:  rdbConn,
+		registry: registry,
+		httpClient: &http.Client{
+			Timeout: config.RequestTimeout,
+		},
+		logger: logger.WithFields(applogger.LogFields{
+			"module": "WebhookPublisher",
+		}),
+
+		migrationHelper: migrationHelper,
+	}
+}
+
+func (cronJob *WebhookPublisher) Id() string {
+	return "WebhookPublisher"
+}
+
+// SetWriteFence implements eventhandler.WriteFenced.SetWriteFence(). The subscriber cursors and the dead letters are only
+// written when the write fence is passed in the same transaction.
+func (cronJob *WebhookPublisher) SetWriteFence(writeFence eventhandler.WriteFence) {
+	cronJob.maybeWriteFence = writeFence
+}
+
+func (cronJob *WebhookPublisher) Config() *Config {
+	return &cronJob.config
+}
+
+func (cronJob *WebhookPublisher) OnInit() error {
+	if cronJob.migrationHelper != nil {
+		cronJob.migrationHelper.Migrate()
+	}
+
+	return nil
+}
+
+func (cronJob *WebhookPublisher) Interval() time.Duration {
+	return cronJob.config.Interval
+}
+
+func (cronJob *WebhookPublisher) Exec(ctx context.Context) error {
+	handle := cronJob.rdbConn.ToHandle()
+	eventSource := NewEventSource(handle, cronJob.registry)
+	cursorsView := NewWebhookSubscriberCursorsView(handle)
+
+	// Subscribers are published to concurrently such that a failing subscriber retrying its delivery does not delay
+	// the others
+	var waitGroup sync.WaitGroup
+	errs := make([]error, len(cronJob.config.Subscribers))
+	for i, subscriber := range cronJob.config.Subscribers {
+		waitGroup.Add(1)
+		go func(i int, subscriber SubscriberConfig) {
+			defer waitGroup.Done()
+			errs[i] = cronJob.publishTo(ctx, subscriber, eventSource, cursorsView)
+		}(i, subscriber)
+	}
+	waitGroup.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return fmt.Errorf("error publishing events to subscriber %s: %v", cronJob.config.Subscribers[i].Name, err)
+		}
+	}
+
+	return nil
+}
+
+func (cronJob *WebhookPublisher) publishTo(
+	ctx context.Context,
+	subscriber SubscriberConfig,
+	eventSource EventSource,
+	cursorsView view.WebhookSubscriberCursors,
+) error {
+	cursor, err := cursorsView.FindBy(subscriber.Name)
+	if err != nil {
+		return fmt.Errorf("error finding subscriber cursor: %v", err)
+	}
+
+	for {
+		outboxEvents, err := eventSource.ListAfter(
+			cursor.LastEventHeight, cursor.LastEventId, subscriber.EventNames, cronJob.config.BatchSize,
+		)
+		if err != nil {
+			return fmt.Errorf(
+				"error listing events after id %d at height %d: %v", cursor.LastEventId, cursor.LastEventHeight, err,
+			)
+		}
+		if len(outboxEvents) == 0 {
+			return nil
+		}
+
+		for _, outboxEvent := range outboxEvents {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			payload, payloadErr := encodeOutboxEvent(outboxEvent)
+			// An event which cannot be decoded or encoded would never be delivered, skip it to the dead letters
+			maybeDeadLetterCause := payloadErr
+			if payloadErr == nil {
+				if deliverErr := cronJob.deliver(ctx, subscriber, outboxEvent, payload); deliverErr != nil {
+					if ctx.Err() != nil {
+						return ctx.Err()
+					}
+					maybeDeadLetterCause = deliverErr
+				}
+			}
+
+			cursor = view.WebhookSubscriberCursor{
+				LastEventHeight: outboxEvent.Height,
+				LastEventId:     outboxEvent.Id,
+			}
+			if err := cronJob.commitDelivery(subscriber, outboxEvent, payload, maybeDeadLetterCause, cursor); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// commitDelivery advances the subscriber cursor past the event, and moves the event to the dead letters when it fails
+// to be delivered, in a single transaction fenced by the write fence
+func (cronJob *WebhookPublisher) commitDelivery(
+	subscriber SubscriberConfig,
+	outboxEvent event_interface.OutboxEvent,
+	payload string,
+	maybeDeadLetterCause error,
+	cursor view.WebhookSubscriberCursor,
+) error {
+	tx, err := cronJob.rdbConn.Begin()
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %v", err)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			_ = tx.Rollback()
+		}
+	}()
+
+	txHandle := tx.ToHandle()
+	if cronJob.maybeWriteFence != nil {
+		if err := cronJob.maybeWriteFence.FenceWithRDbHandle(txHandle); err != nil {
+			return fmt.Errorf("error fencing writes: %v", err)
+		}
+	}
+
+	if maybeDeadLetterCause != nil {
+		if err := cronJob.moveToDeadLetters(
+			NewWebhookDeadLettersView(txHandle), subscriber, outboxEvent, payload, maybeDeadLetterCause,
+		); err != nil {
+			return err
+		}
+	}
+	if err := NewWebhookSubscriberCursorsView(txHandle).Upsert(subscriber.Name, cursor); err != nil {
+		return fmt.Errorf("error updating subscriber cursor: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing subscriber cursor: %v", err)
+	}
+	committed = true
+
+	return nil
+}
+
+// encodeOutboxEvent returns the JSON payload of the event to deliver. The stored payload is returned together with the
+// error when the event fails to be decoded or encoded.
+func encodeOutboxEvent(outboxEvent event_interface.OutboxEvent) (string, error) {
+	if outboxEvent.DecodeErr != nil {
+		return outboxEvent.Payload, outboxEvent.DecodeErr
+	}
+
+	payload, err := outboxEvent.Event.ToJSON()
+	if err != nil {
+		return outboxEvent.Payload, fmt.Errorf("error encoding event to json: %v", err)
+	}
+
+	return payload, nil
+}
+
+func (cronJob *WebhookPublisher) moveToDeadLetters(
+	deadLettersView view.WebhookDeadLetters,
+	subscriber SubscriberConfig,
+	outboxEvent event_interface.OutboxEvent,
+	payload string,
+	cause error,
+) error {
+	cronJob.logger.WithFields(applogger.LogFields{
+		"subscriber": subscriber.Name,
+		"eventId":    outboxEvent.Id,
+		"error":      cause,
+	}).Error("error delivering event to subscriber, moving it to dead letters")
+
+	errorMessage := cause.Error()
+	if len(errorMessage) > MAX_DEAD_LETTER_ERROR_MESSAGE_LEN {
+		errorMessage = errorMessage[:MAX_DEAD_LETTER_ERROR_MESSAGE_LEN]
+	}
+	if err := deadLettersView.Insert(&view.WebhookDeadLetterRow{
+		Subscriber:   subscriber.Name,
+		EventId:      outboxEvent.Id,
+		EventUUID:    outboxEvent.UUID,
+		EventName:    outboxEvent.Name,
+		EventVersion: outboxEvent.Version,
+		EventHeight:  outboxEvent.Height,
+		Payload:      payload,
+		Error:        errorMessage,
+		CreatedAt:    utctime.Now(),
+	}); err != nil {
+		return fmt.Errorf("error inserting dead letter of event %d: %v", outboxEvent.Id, err)
+	}
+
+	return nil
+}
+
+// deliver posts the event payload to the subscriber, retrying with exponential backoff until the response status is
+// 2xx or the retries are exhausted
+func (cronJob *WebhookPublisher) deliver(
+	ctx context.Context,
+	subscriber SubscriberConfig,
+	outboxEvent event_interface.OutboxEvent,
+	payload string,
+) error {
+	operation := func() error {
+		request, err := http.NewRequestWithContext(ctx, http.MethodPost, subscriber.URL, strings.NewReader(payload))
+		if err != nil {
+			return backoff.Permanent(fmt.Errorf("error creating webhook request: %v", err))
+		}
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set(SUBSCRIBER_HEADER, subscriber.Name)
+		request.Header.Set(EVENT_ID_HEADER, strconv.FormatInt(outboxEvent.Id, 10))
+		request.Header.Set(EVENT_UUID_HEADER, outboxEvent.UUID)
+		request.Header.Set(EVENT_NAME_HEADER, outboxEvent.Event.Name())
+		request.Header.Set(EVENT_VERSION_HEADER, strconv.Itoa(outboxEvent.Event.Version()))
+		request.Header.Set(EVENT_HEIGHT_HEADER, strconv.FormatInt(outboxEvent.Event.Height(), 10))
+		request.Header.Set(SIGNATURE_HEADER, SIGNATURE_PREFIX+Sign(subscriber.Secret, []byte(payload)))
+
+		response, err := cronJob.httpClient.Do(request)
+		if err != nil {
+			return fmt.Errorf("error requesting webhook: %v", err)
+		}
+		defer response.Body.Close()
+		// Drain the body so the connection can be reused
+		_, _ = io.Copy(ioutil.Discard, response.Body)
+
+		if response.StatusCode < 200 || response.StatusCode >= 300 {
+			return fmt.Errorf("error requesting webhook: non-2xx status code %d", response.StatusCode)
+		}
+
+		return nil
+	}
+
+	exponentialBackoff := backoff.NewExponentialBackOff()
+	exponentialBackoff.InitialInterval = cronJob.config.InitialRetryInterval
+	exponentialBackoff.MaxInterval = cronJob.config.MaxRetryInterval
+	exponentialBackoff.MaxElapsedTime = 0
+
+	return backoff.Retry(
+		operation,
+		backoff.WithContext(backoff.WithMaxRetries(exponentialBackoff, cronJob.config.MaxRetries), ctx),
+	)
+}
+
+// Sign returns the hex-encoded HMAC-SHA256 of the body with the secret. Subscribers verify a request by comparing it
+// with the X-Signature-256 header without the "sha256=" prefix.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,378 @@
+package webhook_publisher_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	testify_mock "github.com/stretchr/testify/mock"
+
+	event_interface "github.com/crypto-com/chain-indexing/appinterface/event"
+	"github.com/crypto-com/chain-indexing/appinterface/rdb"
+	rdb_test "github.com/crypto-com/chain-indexing/appinterface/rdb/test"
+	event_entity "github.com/crypto-com/chain-indexing/entity/event"
+	event_test "github.com/crypto-com/chain-indexing/entity/event/test"
+	test_logger "github.com/crypto-com/chain-indexing/external/logger/test"
+	"github.com/crypto-com/chain-indexing/projection/webhook_publisher"
+	"github.com/crypto-com/chain-indexing/projection/webhook_publisher/view"
+)
+
+type mockEventSource struct {
+	testify_mock.Mock
+}
+
+func (source *mockEventSource) ListAfter(
+	afterHeight int64,
+	afterId int64,
+	eventNames []string,
+	limit uint64,
+) ([]event_interface.OutboxEvent, error) {
+	mockArgs := source.Called(afterHeight, afterId, eventNames, limit)
+	outboxEvents, _ := mockArgs.Get(0).([]event_interface.OutboxEvent)
+	return outboxEvents, mockArgs.Error(1)
+}
+
+func newOutboxEvent(height int64, id int64) event_interface.OutboxEvent {
+	mockEvent := event_test.NewMockEvent()
+	mockEvent.On("Height").Return(height)
+	mockEvent.On("Name").Return("MockEvent")
+	mockEvent.On("Version").Return(1)
+	mockEvent.On("UUID").Return("mock-event-uuid")
+	mockEvent.On("ToJSON").Return("{\"name\":\"MockEvent\"}", nil)
+
+	return event_interface.OutboxEvent{
+		Id:      id,
+		UUID:    "mock-event-uuid",
+		Height:  height,
+		Name:    "MockEvent",
+		Version: 1,
+		Payload: "{\"name\":\"MockEvent\"}",
+		Event:   mockEvent,
+	}
+}
+
+type fakeWriteFence struct {
+	err error
+}
+
+func (fence *fakeWriteFence) FenceWithRDbHandle(_ *rdb.Handle) error {
+	return fence.err
+}
+
+type receivedRequest struct {
+	Header http.Header
+	Body   string
+}
+
+func newWebhookServer(statusCode int) (*httptest.Server, func() []receivedRequest) {
+	var mutex sync.Mutex
+	requests := make([]receivedRequest, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		mutex.Lock()
+		requests = append(requests, receivedRequest{Header: r.Header, Body: string(body)})
+		mutex.Unlock()
+		w.WriteHeader(statusCode)
+	}))
+
+	return server, func() []receivedRequest {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return requests
+	}
+}
+
+func setupMocks(
+	outboxEvents []event_interface.OutboxEvent,
+) (*mockEventSource, *view.MockWebhookSubscriberCursorsView, *view.MockWebhookDeadLettersView) {
+	eventSource := &mockEventSource{}
+	lastOutboxEvent := outboxEvents[len(outboxEvents)-1]
+	eventSource.On(
+		"ListAfter", int64(-1), int64(0), testify_mock.Anything, testify_mock.Anything,
+	).Return(outboxEvents, nil)
+	eventSource.On(
+		"ListAfter", lastOutboxEvent.Height, lastOutboxEvent.Id, testify_mock.Anything, testify_mock.Anything,
+	).Return([]event_interface.OutboxEvent{}, nil)
+	webhook_publisher.NewEventSource = func(_ *rdb.Handle, _ *event_entity.Registry) webhook_publisher.EventSource {
+		return eventSource
+	}
+
+	cursorsView := view.NewMockWebhookSubscriberCursorsView().(*view.MockWebhookSubscriberCursorsView)
+	cursorsView.On("FindBy", testify_mock.Anything).Return(view.InitialWebhookSubscriberCursor, nil)
+	cursorsView.On("Upsert", testify_mock.Anything, testify_mock.Anything).Return(nil)
+	webhook_publisher.NewWebhookSubscriberCursorsView = func(_ *rdb.Handle) view.WebhookSubscriberCursors {
+		return cursorsView
+	}
+
+	deadLettersView := view.NewMockWebhookDeadLettersView().(*view.MockWebhookDeadLettersView)
+	deadLettersView.On("Insert", testify_mock.Anything).Return(nil)
+	webhook_publisher.NewWebhookDeadLettersView = func(_ *rdb.Handle) view.WebhookDeadLetters {
+		return deadLettersView
+	}
+
+	return eventSource, cursorsView, deadLettersView
+}
+
+func TestWebhookPublisher_Exec(t *testing.T) {
+	t.Run("It should deliver the events with HMAC signature and advance the subscriber cursor", func(t *testing.T) {
+		server, receivedRequests := newWebhookServer(http.StatusOK)
+		defer server.Close()
+		assert.Nil(t, os.Setenv("TEST_WEBHOOK_SECRET", "secret"))
+		defer os.Unsetenv("TEST_WEBHOOK_SECRET")
+
+		outboxEvents := []event_interface.OutboxEvent{
+			// Event of a lower id committed after the one of a higher id at a later height
+			newOutboxEvent(10, 3),
+			newOutboxEvent(11, 1),
+		}
+		eventSource, cursorsView, deadLettersView := setupMocks(outboxEvents)
+
+		config, err := webhook_publisher.ConfigFromInterface(map[string]interface{}{
+			"subscribers": []map[string]interface{}{
+				{
+					"name":        "subscriber",
+					"url":         server.URL,
+					"secret_env":  "TEST_WEBHOOK_SECRET",
+					"event_names": []string{"MockEvent"},
+				},
+			},
+		})
+		assert.Nil(t, err)
+		publisher := webhook_publisher.New(
+			config, test_logger.NewFakeLogger(), rdb_test.NewFakeRDbConn(), event_entity.NewRegistry(), nil,
+		)
+
+		assert.Nil(t, publisher.Exec(context.Background()))
+
+		requests := receivedRequests()
+		assert.Len(t, requests, 2)
+		expectedBody, _ := outboxEvents[0].Event.ToJSON()
+		assert.Equal(t, expectedBody, requests[0].Body)
+		assert.Equal(t, "3", requests[0].Header.Get(webhook_publisher.EVENT_ID_HEADER))
+		assert.Equal(t, "MockEvent", requests[0].Header.Get(webhook_publisher.EVENT_NAME_HEADER))
+		assert.Equal(
+			t,
+			webhook_publisher.SIGNATURE_PREFIX+webhook_publisher.Sign("secret", []byte(expectedBody)),
+			requests[0].Header.Get(webhook_publisher.SIGNATURE_HEADER),
+		)
+		assert.Equal(t, "1", requests[1].Header.Get(webhook_publisher.EVENT_ID_HEADER))
+
+		eventSource.AssertCalled(t, "ListAfter", int64(-1), int64(0), []string{"MockEvent"}, uint64(100))
+		eventSource.AssertCalled(t, "ListAfter", int64(11), int64(1), []string{"MockEvent"}, uint64(100))
+		cursorsView.AssertCalled(t, "Upsert", "subscriber", view.WebhookSubscriberCursor{LastEventHeight: 10, LastEventId: 3})
+		cursorsView.AssertCalled(t, "Upsert", "subscriber", view.WebhookSubscriberCursor{LastEventHeight: 11, LastEventId: 1})
+		deadLettersView.AssertNotCalled(t, "Insert", testify_mock.Anything)
+	})
+
+	t.Run("It should not advance the subscriber cursor when the write fence is not passed", func(t *testing.T) {
+		server, _ := newWebhookServer(http.StatusOK)
+		defer server.Close()
+		assert.Nil(t, os.Setenv("TEST_WEBHOOK_SECRET", "secret"))
+		defer os.Unsetenv("TEST_WEBHOOK_SECRET")
+
+		outboxEvents := []event_interface.OutboxEvent{
+			newOutboxEvent(10, 3),
+		}
+		_, cursorsView, _ := setupMocks(outboxEvents)
+
+		config, err := webhook_publisher.ConfigFromInterface(map[string]interface{}{
+			"subscribers": []map[string]interface{}{
+				{"name": "subscriber", "url": server.URL, "secret_env": "TEST_WEBHOOK_SECRET"},
+			},
+		})
+		assert.Nil(t, err)
+		publisher := webhook_publisher.New(
+			config, test_logger.NewFakeLogger(), rdb_test.NewFakeRDbConn(), event_entity.NewRegistry(), nil,
+		)
+		publisher.SetWriteFence(&fakeWriteFence{err: errors.New("not the leader")})
+
+		assert.NotNil(t, publisher.Exec(context.Background()))
+
+		cursorsView.AssertNotCalled(t, "Upsert", testify_mock.Anything, testify_mock.Anything)
+	})
+
+	t.Run("It should move the event to dead letters after the retries are exhausted", func(t *testing.T) {
+		server, receivedRequests := newWebhookServer(http.StatusInternalServerError)
+		defer server.Close()
+		assert.Nil(t, os.Setenv("TEST_WEBHOOK_SECRET", "secret"))
+		defer os.Unsetenv("TEST_WEBHOOK_SECRET")
+
+		outboxEvents := []event_interface.OutboxEvent{
+			newOutboxEvent(10, 2),
+		}
+		_, cursorsView, deadLettersView := setupMocks(outboxEvents)
+
+		config, err := webhook_publisher.ConfigFromInterface(map[string]interface{}{
+			"max_retries":            2,
+			"initial_retry_interval": "1ms",
+			"max_retry_interval":     "1ms",
+			"subscribers": []map[string]interface{}{
+				{"name": "subscriber", "url": server.URL, "secret_env": "TEST_WEBHOOK_SECRET"},
+			},
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, time.Millisecond, config.InitialRetryInterval)
+		publisher := webhook_publisher.New(
+			config, test_logger.NewFakeLogger(), rdb_test.NewFakeRDbConn(), event_entity.NewRegistry(), nil,
+		)
+
+		assert.Nil(t, publisher.Exec(context.Background()))
+
+		requests := receivedRequests()
+		assert.Len(t, requests, 3)
+
+		deadLettersView.AssertCalled(t, "Insert", testify_mock.MatchedBy(func(row *view.WebhookDeadLetterRow) bool {
+			return row.Subscriber == "subscriber" && row.EventId == 2 && row.EventName == "MockEvent"
+		}))
+		cursorsView.AssertCalled(t, "Upsert", "subscriber", view.WebhookSubscriberCursor{LastEventHeight: 10, LastEventId: 2})
+	})
+
+	t.Run("It should move the event failing to be decoded to dead letters and deliver the events after it", func(t *testing.T) {
+		server, receivedRequests := newWebhookServer(http.StatusOK)
+		defer server.Close()
+		assert.Nil(t, os.Setenv("TEST_WEBHOOK_SECRET", "secret"))
+		defer os.Unsetenv("TEST_WEBHOOK_SECRET")
+
+		outboxEvents := []event_interface.OutboxEvent{
+			{
+				Id:        2,
+				UUID:      "undecodable-event-uuid",
+				Height:    10,
+				Name:      "MockEvent",
+				Version:   1,
+				Payload:   "{\"invalid\"",
+				DecodeErr: errors.New("error decoding the event string into type"),
+			},
+			newOutboxEvent(10, 3),
+		}
+		_, cursorsView, deadLettersView := setupMocks(outboxEvents)
+
+		config, err := webhook_publisher.ConfigFromInterface(map[string]interface{}{
+			"subscribers": []map[string]interface{}{
+				{"name": "subscriber", "url": server.URL, "secret_env": "TEST_WEBHOOK_SECRET"},
+			},
+		})
+		assert.Nil(t, err)
+		publisher := webhook_publisher.New(
+			config, test_logger.NewFakeLogger(), rdb_test.NewFakeRDbConn(), event_entity.NewRegistry(), nil,
+		)
+
+		assert.Nil(t, publisher.Exec(context.Background()))
+
+		requests := receivedRequests()
+		assert.Len(t, requests, 1)
+		assert.Equal(t, "3", requests[0].Header.Get(webhook_publisher.EVENT_ID_HEADER))
+
+		deadLettersView.AssertCalled(t, "Insert", testify_mock.MatchedBy(func(row *view.WebhookDeadLetterRow) bool {
+			return row.EventId == 2 && row.EventUUID == "undecodable-event-uuid" && row.EventName == "MockEvent" &&
+				row.Payload == "{\"invalid\""
+		}))
+		cursorsView.AssertCalled(t, "Upsert", "subscriber", view.WebhookSubscriberCursor{LastEventHeight: 10, LastEventId: 3})
+	})
+
+	t.Run("It should not delay a subscriber while another subscriber is retrying", func(t *testing.T) {
+		fastDelivered := make(chan struct{})
+		var fastDeliveredOnce sync.Once
+		fastServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fastDeliveredOnce.Do(func() { close(fastDelivered) })
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer fastServer.Close()
+		slowServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-fastDelivered:
+				w.WriteHeader(http.StatusOK)
+			case <-time.After(5 * time.Second):
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+		}))
+		defer slowServer.Close()
+		assert.Nil(t, os.Setenv("TEST_WEBHOOK_SECRET", "secret"))
+		defer os.Unsetenv("TEST_WEBHOOK_SECRET")
+
+		_, cursorsView, deadLettersView := setupMocks([]event_interface.OutboxEvent{
+			newOutboxEvent(10, 2),
+		})
+
+		config, err := webhook_publisher.ConfigFromInterface(map[string]interface{}{
+			"max_retries": 0,
+			"subscribers": []map[string]interface{}{
+				{"name": "slow", "url": slowServer.URL, "secret_env": "TEST_WEBHOOK_SECRET"},
+				{"name": "fast", "url": fastServer.URL, "secret_env": "TEST_WEBHOOK_SECRET"},
+			},
+		})
+		assert.Nil(t, err)
+		publisher := webhook_publisher.New(
+			config, test_logger.NewFakeLogger(), rdb_test.NewFakeRDbConn(), event_entity.NewRegistry(), nil,
+		)
+
+		assert.Nil(t, publisher.Exec(context.Background()))
+
+		deadLettersView.AssertNotCalled(t, "Insert", testify_mock.Anything)
+		cursorsView.AssertCalled(t, "Upsert", "slow", view.WebhookSubscriberCursor{LastEventHeight: 10, LastEventId: 2})
+		cursorsView.AssertCalled(t, "Upsert", "fast", view.WebhookSubscriberCursor{LastEventHeight: 10, LastEventId: 2})
+	})
+}
+
+func TestConfigFromInterface(t *testing.T) {
+	assert.Nil(t, os.Setenv("TEST_WEBHOOK_SECRET", "secret"))
+	defer os.Unsetenv("TEST_WEBHOOK_SECRET")
+
+	t.Run("It should reject duplicated subscribers", func(t *testing.T) {
+		_, err := webhook_publisher.ConfigFromInterface(map[string]interface{}{
+			"subscribers": []map[string]interface{}{
+				{"name": "subscriber", "url": "http://localhost", "secret_env": "TEST_WEBHOOK_SECRET"},
+				{"name": "subscriber", "url": "http://localhost", "secret_env": "TEST_WEBHOOK_SECRET"},
+			},
+		})
+		assert.NotNil(t, err)
+	})
+
+	t.Run("It should default the interval", func(t *testing.T) {
+		config, err := webhook_publisher.ConfigFromInterface(map[string]interface{}{})
+		assert.Nil(t, err)
+		assert.Equal(t, webhook_publisher.DEFAULT_INTERVAL, config.Interval)
+	})
+
+	t.Run("It should reject non-positive interval", func(t *testing.T) {
+		_, err := webhook_publisher.ConfigFromInterface(map[string]interface{}{
+			"interval": "0s",
+		})
+		assert.NotNil(t, err)
+	})
+
+	t.Run("It should reject subscriber without secret_env", func(t *testing.T) {
+		_, err := webhook_publisher.ConfigFromInterface(map[string]interface{}{
+			"subscribers": []map[string]interface{}{
+				{"name": "subscriber", "url": "http://localhost"},
+			},
+		})
+		assert.NotNil(t, err)
+	})
+
+	t.Run("It should reject subscriber of which the secret variable is unset", func(t *testing.T) {
+		_, err := webhook_publisher.ConfigFromInterface(map[string]interface{}{
+			"subscribers": []map[string]interface{}{
+				{"name": "subscriber", "url": "http://localhost", "secret_env": "TEST_UNSET_WEBHOOK_SECRET"},
+			},
+		})
+		assert.NotNil(t, err)
+	})
+
+	t.Run("It should read the secret of the subscriber from the environment variable", func(t *testing.T) {
+		config, err := webhook_publisher.ConfigFromInterface(map[string]interface{}{
+			"subscribers": []map[string]interface{}{
+				{"name": "subscriber", "url": "http://localhost", "secret_env": "TEST_WEBHOOK_SECRET"},
+			},
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, "secret", config.Subscribers[0].Secret)
+	})
+}
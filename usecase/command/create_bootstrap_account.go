@@ -0,0 +1,35 @@
+package command
+
+import (
+	entity_event "github.com/crypto-com/chain-indexing/entity/event"
+	"github.com/crypto-com/chain-indexing/usecase/event"
+	"github.com/crypto-com/chain-indexing/usecase/model"
+)
+
+type CreateBootstrapAccount struct {
+	blockHeight int64
+	params      model.BootstrapAccountParams
+}
+
+func NewCreateBootstrapAccount(blockHeight int64, params model.BootstrapAccountParams) *CreateBootstrapAccount {
+	return &CreateBootstrapAccount{
+		blockHeight,
+		params,
+	}
+}
+
+// Name returns name of command
+func (*CreateBootstrapAccount) Name() string {
+	return "CreateBootstrapAccount"
+}
+
+// Version returns version of command
+func (*CreateBootstrapAccount) Version() int {
+	return 1
+}
+
+// Exec process the command data and return the event accordingly
+func (cmd *CreateBootstrapAccount) Exec() (entity_event.Event, error) {
+	event := event.NewBootstrapAccountCreated(cmd.blockHeight, cmd.params)
+	return event, nil
+}
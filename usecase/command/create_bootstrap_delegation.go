@@ -0,0 +1,38 @@
+package command
+
+import (
+	entity_event "github.com/crypto-com/chain-indexing/entity/event"
+	"github.com/crypto-com/chain-indexing/usecase/event"
+	"github.com/crypto-com/chain-indexing/usecase/model"
+)
+
+type CreateBootstrapDelegation struct {
+	blockHeight int64
+	params      model.BootstrapDelegationParams
+}
+
+func NewCreateBootstrapDelegation(
+	blockHeight int64,
+	params model.BootstrapDelegationParams,
+) *CreateBootstrapDelegation {
+	return &CreateBootstrapDelegation{
+		blockHeight,
+		params,
+	}
+}
+
+// Name returns name of command
+func (*CreateBootstrapDelegation) Name() string {
+	return "CreateBootstrapDelegation"
+}
+
+// Version returns version of command
+func (*CreateBootstrapDelegation) Version() int {
+	return 1
+}
+
+// Exec process the command data and return the event accordingly
+func (cmd *CreateBootstrapDelegation) Exec() (entity_event.Event, error) {
+	event := event.NewBootstrapDelegationCreated(cmd.blockHeight, cmd.params)
+	return event, nil
+}
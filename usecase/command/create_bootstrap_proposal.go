@@ -0,0 +1,38 @@
+package command
+
+import (
+	entity_event "github.com/crypto-com/chain-indexing/entity/event"
+	"github.com/crypto-com/chain-indexing/usecase/event"
+	"github.com/crypto-com/chain-indexing/usecase/model"
+)
+
+type CreateBootstrapProposal struct {
+	blockHeight int64
+	params      model.BootstrapProposalParams
+}
+
+func NewCreateBootstrapProposal(
+	blockHeight int64,
+	params model.BootstrapProposalParams,
+) *CreateBootstrapProposal {
+	return &CreateBootstrapProposal{
+		blockHeight,
+		params,
+	}
+}
+
+// Name returns name of command
+func (*CreateBootstrapProposal) Name() string {
+	return "CreateBootstrapProposal"
+}
+
+// Version returns version of command
+func (*CreateBootstrapProposal) Version() int {
+	return 1
+}
+
+// Exec process the command data and return the event accordingly
+func (cmd *CreateBootstrapProposal) Exec() (entity_event.Event, error) {
+	event := event.NewBootstrapProposalCreated(cmd.blockHeight, cmd.params)
+	return event, nil
+}
@@ -0,0 +1,38 @@
+package command
+
+import (
+	entity_event "github.com/crypto-com/chain-indexing/entity/event"
+	"github.com/crypto-com/chain-indexing/usecase/event"
+	"github.com/crypto-com/chain-indexing/usecase/model/genesis"
+)
+
+type CreateBootstrapValidator struct {
+	blockHeight int64
+	params      genesis.CreateGenesisValidatorParams
+}
+
+func NewCreateBootstrapValidator(
+	blockHeight int64,
+	params genesis.CreateGenesisValidatorParams,
+) *CreateBootstrapValidator {
+	return &CreateBootstrapValidator{
+		blockHeight,
+		params,
+	}
+}
+
+// Name returns name of command
+func (*CreateBootstrapValidator) Name() string {
+	return "CreateBootstrapValidator"
+}
+
+// Version returns version of command
+func (*CreateBootstrapValidator) Version() int {
+	return 1
+}
+
+// Exec process the command data and return the event accordingly
+func (cmd *CreateBootstrapValidator) Exec() (entity_event.Event, error) {
+	event := event.NewCreateBootstrapValidator(cmd.blockHeight, cmd.params)
+	return event, nil
+}
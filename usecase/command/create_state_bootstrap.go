@@ -0,0 +1,35 @@
+package command
+
+import (
+	entity_event "github.com/crypto-com/chain-indexing/entity/event"
+	"github.com/crypto-com/chain-indexing/usecase/event"
+	"github.com/crypto-com/chain-indexing/usecase/model"
+)
+
+type CreateStateBootstrap struct {
+	blockHeight int64
+	params      model.StateBootstrapParams
+}
+
+func NewCreateStateBootstrap(blockHeight int64, params model.StateBootstrapParams) *CreateStateBootstrap {
+	return &CreateStateBootstrap{
+		blockHeight,
+		params,
+	}
+}
+
+// Name returns name of command
+func (*CreateStateBootstrap) Name() string {
+	return "CreateStateBootstrap"
+}
+
+// Version returns version of command
+func (*CreateStateBootstrap) Version() int {
+	return 1
+}
+
+// Exec process the command data and return the event accordingly
+func (cmd *CreateStateBootstrap) Exec() (entity_event.Event, error) {
+	event := event.NewStateBootstrapped(cmd.blockHeight, cmd.params)
+	return event, nil
+}
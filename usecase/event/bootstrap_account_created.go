@@ -0,0 +1,59 @@
+package event
+
+import (
+	"bytes"
+
+	jsoniter "github.com/json-iterator/go"
+
+	event_entity "github.com/crypto-com/chain-indexing/entity/event"
+	"github.com/crypto-com/chain-indexing/usecase/coin"
+	"github.com/crypto-com/chain-indexing/usecase/model"
+	"github.com/luci/go-render/render"
+)
+
+const BOOTSTRAP_ACCOUNT_CREATED = "BootstrapAccountCreated"
+
+type BootstrapAccountCreated struct {
+	event_entity.Base
+
+	Address string     `json:"address"`
+	Balance coin.Coins `json:"balance"`
+}
+
+func NewBootstrapAccountCreated(blockHeight int64, params model.BootstrapAccountParams) *BootstrapAccountCreated {
+	return &BootstrapAccountCreated{
+		event_entity.NewBase(event_entity.BaseParams{
+			Name:        BOOTSTRAP_ACCOUNT_CREATED,
+			Version:     1,
+			BlockHeight: blockHeight,
+		}),
+
+		params.Address,
+		params.Balance,
+	}
+}
+
+func (event *BootstrapAccountCreated) ToJSON() (string, error) {
+	encoded, err := jsoniter.Marshal(event)
+	if err != nil {
+		return "", err
+	}
+
+	return string(encoded), nil
+}
+
+func (event *BootstrapAccountCreated) String() string {
+	return render.Render(event)
+}
+
+func DecodeBootstrapAccountCreated(encoded []byte) (event_entity.Event, error) {
+	jsonDecoder := jsoniter.NewDecoder(bytes.NewReader(encoded))
+	jsonDecoder.DisallowUnknownFields()
+
+	var event *BootstrapAccountCreated
+	if err := jsonDecoder.Decode(&event); err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
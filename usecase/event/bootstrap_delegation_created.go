@@ -0,0 +1,66 @@
+package event
+
+import (
+	"bytes"
+
+	jsoniter "github.com/json-iterator/go"
+
+	event_entity "github.com/crypto-com/chain-indexing/entity/event"
+	"github.com/crypto-com/chain-indexing/usecase/coin"
+	"github.com/crypto-com/chain-indexing/usecase/model"
+	"github.com/luci/go-render/render"
+)
+
+const BOOTSTRAP_DELEGATION_CREATED = "BootstrapDelegationCreated"
+
+type BootstrapDelegationCreated struct {
+	event_entity.Base
+
+	DelegatorAddress string    `json:"delegatorAddress"`
+	ValidatorAddress string    `json:"validatorAddress"`
+	Shares           string    `json:"shares"`
+	Amount           coin.Coin `json:"amount"`
+}
+
+func NewBootstrapDelegationCreated(
+	blockHeight int64,
+	params model.BootstrapDelegationParams,
+) *BootstrapDelegationCreated {
+	return &BootstrapDelegationCreated{
+		event_entity.NewBase(event_entity.BaseParams{
+			Name:        BOOTSTRAP_DELEGATION_CREATED,
+			Version:     1,
+			BlockHeight: blockHeight,
+		}),
+
+		params.DelegatorAddress,
+		params.ValidatorAddress,
+		params.Shares,
+		params.Amount,
+	}
+}
+
+func (event *BootstrapDelegationCreated) ToJSON() (string, error) {
+	encoded, err := jsoniter.Marshal(event)
+	if err != nil {
+		return "", err
+	}
+
+	return string(encoded), nil
+}
+
+func (event *BootstrapDelegationCreated) String() string {
+	return render.Render(event)
+}
+
+func DecodeBootstrapDelegationCreated(encoded []byte) (event_entity.Event, error) {
+	jsonDecoder := jsoniter.NewDecoder(bytes.NewReader(encoded))
+	jsonDecoder.DisallowUnknownFields()
+
+	var event *BootstrapDelegationCreated
+	if err := jsonDecoder.Decode(&event); err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
@@ -0,0 +1,78 @@
+package event
+
+import (
+	"bytes"
+
+	jsoniter "github.com/json-iterator/go"
+
+	event_entity "github.com/crypto-com/chain-indexing/entity/event"
+	"github.com/crypto-com/chain-indexing/external/utctime"
+	"github.com/crypto-com/chain-indexing/usecase/coin"
+	"github.com/crypto-com/chain-indexing/usecase/model"
+	"github.com/luci/go-render/render"
+)
+
+const BOOTSTRAP_PROPOSAL_CREATED = "BootstrapProposalCreated"
+
+// BootstrapProposalCreated is a proposal submitted before the bootstrap height which is still in deposit or voting
+// period at the bootstrap height
+type BootstrapProposalCreated struct {
+	event_entity.Base
+
+	ProposalId           string           `json:"proposalId"`
+	Type                 string           `json:"type"`
+	Title                string           `json:"title"`
+	Description          string           `json:"description"`
+	Status               string           `json:"status"`
+	TotalDeposit         coin.Coins       `json:"totalDeposit"`
+	SubmitTime           utctime.UTCTime  `json:"submitTime"`
+	DepositEndTime       utctime.UTCTime  `json:"depositEndTime"`
+	MaybeVotingStartTime *utctime.UTCTime `json:"votingStartTime"`
+	MaybeVotingEndTime   *utctime.UTCTime `json:"votingEndTime"`
+}
+
+func NewBootstrapProposalCreated(blockHeight int64, params model.BootstrapProposalParams) *BootstrapProposalCreated {
+	return &BootstrapProposalCreated{
+		event_entity.NewBase(event_entity.BaseParams{
+			Name:        BOOTSTRAP_PROPOSAL_CREATED,
+			Version:     1,
+			BlockHeight: blockHeight,
+		}),
+
+		params.ProposalId,
+		params.Type,
+		params.Title,
+		params.Description,
+		params.Status,
+		params.TotalDeposit,
+		params.SubmitTime,
+		params.DepositEndTime,
+		params.MaybeVotingStartTime,
+		params.MaybeVotingEndTime,
+	}
+}
+
+func (event *BootstrapProposalCreated) ToJSON() (string, error) {
+	encoded, err := jsoniter.Marshal(event)
+	if err != nil {
+		return "", err
+	}
+
+	return string(encoded), nil
+}
+
+func (event *BootstrapProposalCreated) String() string {
+	return render.Render(event)
+}
+
+func DecodeBootstrapProposalCreated(encoded []byte) (event_entity.Event, error) {
+	jsonDecoder := jsoniter.NewDecoder(bytes.NewReader(encoded))
+	jsonDecoder.DisallowUnknownFields()
+
+	var event *BootstrapProposalCreated
+	if err := jsonDecoder.Decode(&event); err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
@@ -0,0 +1,33 @@
+package event
+
+import (
+	event_entity "github.com/crypto-com/chain-indexing/entity/event"
+	"github.com/crypto-com/chain-indexing/usecase/model/genesis"
+)
+
+const BOOTSTRAP_VALIDATOR_CREATED = "BootstrapValidatorCreated"
+
+// NewCreateBootstrapValidator creates a validator existing at the bootstrap height. It shares the payload of
+// CreateGenesisValidator so that projections handle bootstrap and genesis validators alike.
+func NewCreateBootstrapValidator(
+	blockHeight int64,
+	params genesis.CreateGenesisValidatorParams,
+) *CreateGenesisValidator {
+	return &CreateGenesisValidator{
+		event_entity.NewBase(event_entity.BaseParams{
+			Name:        BOOTSTRAP_VALIDATOR_CREATED,
+			Version:     1,
+			BlockHeight: blockHeight,
+		}),
+
+		params.Status,
+		params.Description,
+		params.Commission,
+		params.MinSelfDelegation,
+		params.DelegatorAddress,
+		params.ValidatorAddress,
+		params.TendermintPubkey,
+		params.Amount,
+		params.Jailed,
+	}
+}
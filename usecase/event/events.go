@@ -7,6 +7,12 @@ import (
 func RegisterEvents(registry *event.Registry) {
 	registry.Register(GENESIS_CREATED, 1, DecodeGenesisCreated)
 
+	registry.Register(STATE_BOOTSTRAPPED, 1, DecodeStateBootstrapped)
+	registry.Register(BOOTSTRAP_VALIDATOR_CREATED, 1, DecodeCreateGenesisValidator)
+	registry.Register(BOOTSTRAP_DELEGATION_CREATED, 1, DecodeBootstrapDelegationCreated)
+	registry.Register(BOOTSTRAP_ACCOUNT_CREATED, 1, DecodeBootstrapAccountCreated)
+	registry.Register(BOOTSTRAP_PROPOSAL_CREATED, 1, DecodeBootstrapProposalCreated)
+
 	registry.Register(BLOCK_CREATED, 1, DecodeBlockCreated)
 	registry.Register(RAW_BLOCK_CREATED, 1, DecodeRawBlockCreated)
 	registry.Register(TRANSACTION_CREATED, 1, DecodeTransactionCreated)
@@ -23,8 +29,10 @@ func RegisterEvents(registry *event.Registry) {
 	registry.Register(VALIDATOR_JAILED, 1, DecodeValidatorJailed)
 
 	// Bank
-	registry.Register(MSG_SEND_CREATED, 1, DecodeMsgSend)
-	registry.Register(MSG_SEND_FAILED, 1, DecodeMsgSend)
+	registry.Register(MSG_SEND_CREATED, 2, DecodeMsgSend)
+	registry.RegisterUpcaster(MSG_SEND_CREATED, 1, UpcastMsgSendV1)
+	registry.Register(MSG_SEND_FAILED, 2, DecodeMsgSend)
+	registry.RegisterUpcaster(MSG_SEND_FAILED, 1, UpcastMsgSendV1)
 	registry.Register(MSG_MULTI_SEND_CREATED, 1, DecodeMsgMultiSend)
 	registry.Register(MSG_MULTI_SEND_FAILED, 1, DecodeMsgMultiSend)
 
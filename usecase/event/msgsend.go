@@ -14,25 +14,47 @@ const MSG_SEND = "MsgSend"
 const MSG_SEND_CREATED = "MsgSendCreated"
 const MSG_SEND_FAILED = "MsgSendFailed"
 
+// MsgSend is of version 2 since the coins spent and received are recorded. Version 1 events are upcasted by
+// UpcastMsgSendV1.
 type MsgSend struct {
 	MsgBase
 
-	FromAddress string     `json:"fromAddress"`
-	ToAddress   string     `json:"toAddress"`
-	Amount      coin.Coins `json:"amount"`
+	FromAddress  string                `json:"fromAddress"`
+	ToAddress    string                `json:"toAddress"`
+	Amount       coin.Coins            `json:"amount"`
+	CoinSpent    []MsgSendCoinSpent    `json:"coinSpent"`
+	CoinReceived []MsgSendCoinReceived `json:"coinReceived"`
+}
+
+type MsgSendCoinSpent struct {
+	Spender string     `json:"spender"`
+	Amount  coin.Coins `json:"amount"`
+}
+
+type MsgSendCoinReceived struct {
+	Receiver string     `json:"receiver"`
+	Amount   coin.Coins `json:"amount"`
 }
 
 func NewMsgSend(msgCommonParams MsgCommonParams, params MsgSendCreatedParams) *MsgSend {
 	return &MsgSend{
 		NewMsgBase(MsgBaseParams{
 			MsgName:         MSG_SEND,
-			Version:         1,
+			Version:         2,
 			MsgCommonParams: msgCommonParams,
 		}),
 
 		params.FromAddress,
 		params.ToAddress,
 		params.Amount,
+		[]MsgSendCoinSpent{{
+			Spender: params.FromAddress,
+			Amount:  params.Amount,
+		}},
+		[]MsgSendCoinReceived{{
+			Receiver: params.ToAddress,
+			Amount:   params.Amount,
+		}},
 	}
 }
 
@@ -66,3 +88,22 @@ func DecodeMsgSend(encoded []byte) (entity_event.Event, error) {
 
 	return event, nil
 }
+
+// UpcastMsgSendV1 upcasts a version 1 MsgSend event to version 2. The whole amount is spent by the sender and received
+// by the recipient.
+var UpcastMsgSendV1 = entity_event.NewJSONUpcaster(func(payload map[string]interface{}) error {
+	payload["coinSpent"] = []interface{}{
+		map[string]interface{}{
+			"spender": payload["fromAddress"],
+			"amount":  payload["amount"],
+		},
+	}
+	payload["coinReceived"] = []interface{}{
+		map[string]interface{}{
+			"receiver": payload["toAddress"],
+			"amount":   payload["amount"],
+		},
+	}
+
+	return nil
+})
@@ -37,19 +37,25 @@ var _ = Describe("Event", func() {
 			Expect(err).To(BeNil())
 
 			decodedEvent, err := registry.DecodeByType(
-				event_usecase.MSG_SEND_CREATED, 1, []byte(encoded),
+				event_usecase.MSG_SEND_CREATED, 2, []byte(encoded),
 			)
 			Expect(err).To(BeNil())
 			Expect(decodedEvent).To(Equal(event))
 			typedEvent, _ := decodedEvent.(*event_usecase.MsgSend)
 			Expect(typedEvent.Name()).To(Equal(event_usecase.MSG_SEND_CREATED))
-			Expect(typedEvent.Version()).To(Equal(1))
+			Expect(typedEvent.Version()).To(Equal(2))
 
 			Expect(typedEvent.MsgTxHash).To(Equal(anyTxHash))
 			Expect(typedEvent.MsgIndex).To(Equal(anyMsgIndex))
 			Expect(typedEvent.FromAddress).To(Equal(anyFromAddress))
 			Expect(typedEvent.ToAddress).To(Equal(anyToAddress))
 			Expect(typedEvent.Amount).To(Equal(anyAmount))
+			Expect(typedEvent.CoinSpent).To(Equal([]event_usecase.MsgSendCoinSpent{
+				{Spender: anyFromAddress, Amount: anyAmount},
+			}))
+			Expect(typedEvent.CoinReceived).To(Equal([]event_usecase.MsgSendCoinReceived{
+				{Receiver: anyToAddress, Amount: anyAmount},
+			}))
 		})
 
 		It("should able to encode and decode to failed event", func() {
@@ -75,13 +81,58 @@ var _ = Describe("Event", func() {
 			Expect(err).To(BeNil())
 
 			decodedEvent, err := registry.DecodeByType(
-				event_usecase.MSG_SEND_FAILED, 1, []byte(encoded),
+				event_usecase.MSG_SEND_FAILED, 2, []byte(encoded),
 			)
 			Expect(err).To(BeNil())
 			Expect(decodedEvent).To(Equal(event))
 			typedEvent, _ := decodedEvent.(*event_usecase.MsgSend)
 			Expect(typedEvent.Name()).To(Equal(event_usecase.MSG_SEND_FAILED))
-			Expect(typedEvent.Version()).To(Equal(1))
+			Expect(typedEvent.Version()).To(Equal(2))
+		})
+
+		It("should upcast the stored version 1 events to the current version", func() {
+			anyAmount := coin.MustParseCoinsNormalized("123456basetcro,456789tcro")
+			for _, eventName := range []string{event_usecase.MSG_SEND_CREATED, event_usecase.MSG_SEND_FAILED} {
+				decodedEvent, err := registry.DecodeByType(eventName, 1, []byte(msgSendV1Fixture(eventName)))
+				Expect(err).To(BeNil())
+
+				typedEvent, ok := decodedEvent.(*event_usecase.MsgSend)
+				Expect(ok).To(BeTrue())
+				Expect(typedEvent.Name()).To(Equal(eventName))
+				Expect(typedEvent.Version()).To(Equal(2))
+				Expect(typedEvent.Height()).To(Equal(int64(1000)))
+				Expect(typedEvent.UUID()).To(Equal("f6ed9de5-a3e7-4a82-9ee1-7fb2bd3e4f4c"))
+				Expect(typedEvent.MsgTxHash).To(Equal("4936522F7391D425F2A93AD47576F8AEC3947DC907113BE8A2FBCFF8E9F2A416"))
+				Expect(typedEvent.MsgIndex).To(Equal(2))
+				Expect(typedEvent.FromAddress).To(Equal("tcro165tzcrh2yl83g8qeqxueg2g5gzgu57y3fe3kc3"))
+				Expect(typedEvent.ToAddress).To(Equal("tcro184lta2lsyu47vwyp2e8zmtca3k5yq85p6c4vp3"))
+				Expect(typedEvent.Amount).To(Equal(anyAmount))
+				Expect(typedEvent.CoinSpent).To(Equal([]event_usecase.MsgSendCoinSpent{
+					{Spender: "tcro165tzcrh2yl83g8qeqxueg2g5gzgu57y3fe3kc3", Amount: anyAmount},
+				}))
+				Expect(typedEvent.CoinReceived).To(Equal([]event_usecase.MsgSendCoinReceived{
+					{Receiver: "tcro184lta2lsyu47vwyp2e8zmtca3k5yq85p6c4vp3", Amount: anyAmount},
+				}))
+			}
 		})
 	})
 })
+
+// msgSendV1Fixture returns a MsgSend event of the name as stored in version 1
+func msgSendV1Fixture(eventName string) string {
+	return `{
+		"name": "` + eventName + `",
+		"version": 1,
+		"height": 1000,
+		"uuid": "f6ed9de5-a3e7-4a82-9ee1-7fb2bd3e4f4c",
+		"msgName": "MsgSend",
+		"txHash": "4936522F7391D425F2A93AD47576F8AEC3947DC907113BE8A2FBCFF8E9F2A416",
+		"msgIndex": 2,
+		"fromAddress": "tcro165tzcrh2yl83g8qeqxueg2g5gzgu57y3fe3kc3",
+		"toAddress": "tcro184lta2lsyu47vwyp2e8zmtca3k5yq85p6c4vp3",
+		"amount": [
+			{"denom": "basetcro", "amount": "123456"},
+			{"denom": "tcro", "amount": "456789"}
+		]
+	}`
+}
@@ -0,0 +1,55 @@
+package event_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	event_interface "github.com/crypto-com/chain-indexing/appinterface/event"
+	event_entity "github.com/crypto-com/chain-indexing/entity/event"
+	event_usecase "github.com/crypto-com/chain-indexing/usecase/event"
+)
+
+// Directory of the generated event schemas. Run `make generate_event_schemas` to generate the schemas of new event
+// names and versions.
+const EVENT_SCHEMAS_DIRECTORY = "../../documentation/events/schemas"
+
+var _ = Describe("Event schemas", func() {
+	It("should match the generated schema of every registered event name and version", func() {
+		registry := event_entity.NewRegistry()
+		event_usecase.RegisterEvents(registry)
+
+		schemas, err := event_interface.GenerateSchemas(registry)
+		Expect(err).To(BeNil())
+		Expect(schemas).NotTo(BeEmpty())
+
+		writeMissing := os.Getenv("UPDATE_EVENT_SCHEMAS") != ""
+		for _, schema := range schemas {
+			filePath := filepath.Join(
+				EVENT_SCHEMAS_DIRECTORY, event_interface.SchemaFileName(schema.Name, schema.Version),
+			)
+			encoded := schema.Schema.MustMarshal()
+
+			existing, readErr := ioutil.ReadFile(filePath)
+			if os.IsNotExist(readErr) && writeMissing {
+				Expect(ioutil.WriteFile(filePath, encoded, 0644)).To(Succeed())
+				continue
+			}
+			Expect(readErr).To(
+				BeNil(),
+				"missing schema of %s, run `make generate_event_schemas`", schema.Schema.Id,
+			)
+			// Never overwrite an existing schema, the version of a changed event must be bumped instead
+			Expect(bytes.Equal(existing, encoded)).To(
+				BeTrue(),
+				"schema of %s does not match its event struct, bump the event version and register an upcaster "+
+					"when the event struct is changed:\n%s",
+				schema.Schema.Id, encoded,
+			)
+		}
+	})
+})
@@ -0,0 +1,62 @@
+package event
+
+import (
+	"bytes"
+
+	jsoniter "github.com/json-iterator/go"
+
+	event_entity "github.com/crypto-com/chain-indexing/entity/event"
+	"github.com/crypto-com/chain-indexing/external/utctime"
+	"github.com/crypto-com/chain-indexing/usecase/model"
+	"github.com/luci/go-render/render"
+)
+
+const STATE_BOOTSTRAPPED = "StateBootstrapped"
+
+// StateBootstrapped is produced in place of GenesisCreated when indexing starts from a height other than genesis
+type StateBootstrapped struct {
+	event_entity.Base
+
+	BlockHash string                   `json:"blockHash"`
+	BlockTime utctime.UTCTime          `json:"blockTime"`
+	GovParams model.BootstrapGovParams `json:"govParams"`
+}
+
+func NewStateBootstrapped(blockHeight int64, params model.StateBootstrapParams) *StateBootstrapped {
+	return &StateBootstrapped{
+		event_entity.NewBase(event_entity.BaseParams{
+			Name:        STATE_BOOTSTRAPPED,
+			Version:     1,
+			BlockHeight: blockHeight,
+		}),
+
+		params.BlockHash,
+		params.BlockTime,
+		params.GovParams,
+	}
+}
+
+func (event *StateBootstrapped) ToJSON() (string, error) {
+	encoded, err := jsoniter.Marshal(event)
+	if err != nil {
+		return "", err
+	}
+
+	return string(encoded), nil
+}
+
+func (event *StateBootstrapped) String() string {
+	return render.Render(event)
+}
+
+func DecodeStateBootstrapped(encoded []byte) (event_entity.Event, error) {
+	jsonDecoder := jsoniter.NewDecoder(bytes.NewReader(encoded))
+	jsonDecoder.DisallowUnknownFields()
+
+	var event *StateBootstrapped
+	if err := jsonDecoder.Decode(&event); err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
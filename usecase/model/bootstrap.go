@@ -0,0 +1,49 @@
+package model
+
+import (
+	"github.com/crypto-com/chain-indexing/external/utctime"
+	"github.com/crypto-com/chain-indexing/usecase/coin"
+)
+
+// StateBootstrapParams is the chain state which indexing starts from when it does not start from genesis
+type StateBootstrapParams struct {
+	BlockHash string
+	BlockTime utctime.UTCTime
+	GovParams BootstrapGovParams
+}
+
+type BootstrapGovParams struct {
+	MinDeposit       coin.Coins `json:"minDeposit"`
+	MaxDepositPeriod string     `json:"maxDepositPeriod"`
+	VotingPeriod     string     `json:"votingPeriod"`
+	Quorum           string     `json:"quorum"`
+	Threshold        string     `json:"threshold"`
+	VetoThreshold    string     `json:"vetoThreshold"`
+}
+
+type BootstrapDelegationParams struct {
+	DelegatorAddress string
+	ValidatorAddress string
+	Shares           string
+	Amount           coin.Coin
+}
+
+type BootstrapAccountParams struct {
+	Address string
+	Balance coin.Coins
+}
+
+// BootstrapProposalParams is a proposal in deposit or voting period at the bootstrap height, such that the deposits and
+// votes after the bootstrap height can be applied to it
+type BootstrapProposalParams struct {
+	ProposalId           string
+	Type                 string
+	Title                string
+	Description          string
+	Status               string
+	TotalDeposit         coin.Coins
+	SubmitTime           utctime.UTCTime
+	DepositEndTime       utctime.UTCTime
+	MaybeVotingStartTime *utctime.UTCTime
+	MaybeVotingEndTime   *utctime.UTCTime
+}
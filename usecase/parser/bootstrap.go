@@ -0,0 +1,222 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	cosmosapp_interface "github.com/crypto-com/chain-indexing/appinterface/cosmosapp"
+	"github.com/crypto-com/chain-indexing/entity/command"
+	"github.com/crypto-com/chain-indexing/external/tmcosmosutils"
+	"github.com/crypto-com/chain-indexing/external/utctime"
+	"github.com/crypto-com/chain-indexing/usecase/coin"
+	command_usecase "github.com/crypto-com/chain-indexing/usecase/command"
+	"github.com/crypto-com/chain-indexing/usecase/model"
+	"github.com/crypto-com/chain-indexing/usecase/model/genesis"
+)
+
+// ParseBootstrapCommands creates the commands bootstrapping the state in place of genesis when indexing starts from
+// the block. The state is queried at the height before the block, so that the block itself can be indexed on top of
+// the bootstrapped state. Accounts bootstrapped are the validator operators and delegators. Proposals bootstrapped are
+// the ones still in deposit or voting period.
+func ParseBootstrapCommands(
+	cosmosClient cosmosapp_interface.Client,
+	block *model.Block,
+	accountAddressPrefix string,
+	stakingDenom string,
+) ([]command.Command, error) {
+	stateHeight := block.Height - 1
+	if stateHeight < 1 {
+		return nil, fmt.Errorf("error bootstrapping state: start height must be greater than 1, got %d", block.Height)
+	}
+
+	govParams, err := cosmosClient.GovParamsAtHeight(stateHeight)
+	if err != nil {
+		return nil, fmt.Errorf("error querying gov params at height %d: %v", stateHeight, err)
+	}
+	minDeposit := coin.NewEmptyCoins()
+	for _, deposit := range govParams.DepositParams.MinDeposit {
+		depositCoin, coinErr := coin.NewCoinFromString(deposit.Denom, deposit.Amount)
+		if coinErr != nil {
+			return nil, fmt.Errorf("error parsing gov min deposit: %v", coinErr)
+		}
+		minDeposit = minDeposit.Add(depositCoin)
+	}
+	commands := []command.Command{
+		command_usecase.NewCreateStateBootstrap(block.Height, model.StateBootstrapParams{
+			BlockHash: block.Hash,
+			BlockTime: block.Time,
+			GovParams: model.BootstrapGovParams{
+				MinDeposit:       minDeposit,
+				MaxDepositPeriod: govParams.DepositParams.MaxDepositPeriod,
+				VotingPeriod:     govParams.VotingParams.VotingPeriod,
+				Quorum:           govParams.TallyParams.Quorum,
+				Threshold:        govParams.TallyParams.Threshold,
+				VetoThreshold:    govParams.TallyParams.VetoThreshold,
+			},
+		}),
+	}
+
+	validators, err := cosmosClient.ValidatorsAtHeight(stateHeight)
+	if err != nil {
+		return nil, fmt.Errorf("error querying validators at height %d: %v", stateHeight, err)
+	}
+	accountAddresses := make(map[string]bool)
+	delegationCommands := make([]command.Command, 0)
+	for _, validator := range validators {
+		amountInt, parseAmountIntOk := coin.NewIntFromString(validator.Tokens)
+		if !parseAmountIntOk {
+			return nil, errors.New("error parsing bootstrap validator amount")
+		}
+		amount, parseAmountErr := coin.NewCoin(stakingDenom, amountInt)
+		if parseAmountErr != nil {
+			return nil, fmt.Errorf("error parsing bootstrap validator amount: %v", parseAmountErr)
+		}
+		delegatorAddress, err := tmcosmosutils.AccountAddressFromValidatorAddress(
+			accountAddressPrefix, validator.OperatorAddress,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error converting validator address to account address: %v", err)
+		}
+		accountAddresses[delegatorAddress] = true
+
+		commands = append(commands, command_usecase.NewCreateBootstrapValidator(
+			block.Height,
+			genesis.CreateGenesisValidatorParams{
+				Status: parseBondStatus(validator.Status),
+				Description: model.ValidatorDescription{
+					Moniker:         validator.Description.Moniker,
+					Identity:        validator.Description.Identity,
+					Website:         validator.Description.Website,
+					SecurityContact: validator.Description.SecurityContact,
+					Details:         validator.Description.Details,
+				},
+				Commission: model.ValidatorCommission{
+					Rate:          validator.Commission.CommissionRates.Rate,
+					MaxRate:       validator.Commission.CommissionRates.MaxRate,
+					MaxChangeRate: validator.Commission.CommissionRates.MaxChangeRate,
+				},
+				MinSelfDelegation: validator.MinSelfDelegation,
+				DelegatorAddress:  delegatorAddress,
+				ValidatorAddress:  validator.OperatorAddress,
+				TendermintPubkey:  validator.ConsensusPubkey.Key,
+				Amount:            amount,
+				Jailed:            validator.Jailed,
+			},
+		))
+
+		delegations, err := cosmosClient.ValidatorDelegationsAtHeight(validator.OperatorAddress, stateHeight)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"error querying delegations of validator %s at height %d: %v",
+				validator.OperatorAddress, stateHeight, err,
+			)
+		}
+		for _, delegation := range delegations {
+			delegationAmount, coinErr := coin.NewCoinFromString(delegation.Balance.Denom, delegation.Balance.Amount)
+			if coinErr != nil {
+				return nil, fmt.Errorf("error parsing bootstrap delegation amount: %v", coinErr)
+			}
+			accountAddresses[delegation.Delegation.DelegatorAddress] = true
+
+			delegationCommands = append(delegationCommands, command_usecase.NewCreateBootstrapDelegation(
+				block.Height,
+				model.BootstrapDelegationParams{
+					DelegatorAddress: delegation.Delegation.DelegatorAddress,
+					ValidatorAddress: delegation.Delegation.ValidatorAddress,
+					Shares:           delegation.Delegation.Shares,
+					Amount:           delegationAmount,
+				},
+			))
+		}
+	}
+	commands = append(commands, delegationCommands...)
+
+	sortedAccountAddresses := make([]string, 0, len(accountAddresses))
+	for address := range accountAddresses {
+		sortedAccountAddresses = append(sortedAccountAddresses, address)
+	}
+	sort.Strings(sortedAccountAddresses)
+	for _, address := range sortedAccountAddresses {
+		balance, err := cosmosClient.BalancesAtHeight(address, stateHeight)
+		if err != nil {
+			return nil, fmt.Errorf("error querying balances of account %s at height %d: %v", address, stateHeight, err)
+		}
+
+		commands = append(commands, command_usecase.NewCreateBootstrapAccount(block.Height, model.BootstrapAccountParams{
+			Address: address,
+			Balance: balance,
+		}))
+	}
+
+	proposals, err := cosmosClient.ProposalsAtHeight(stateHeight)
+	if err != nil {
+		return nil, fmt.Errorf("error querying proposals at height %d: %v", stateHeight, err)
+	}
+	for _, proposal := range proposals {
+		if proposal.Status != BOOTSTRAP_PROPOSAL_STATUS_DEPOSIT_PERIOD &&
+			proposal.Status != BOOTSTRAP_PROPOSAL_STATUS_VOTING_PERIOD {
+			continue
+		}
+
+		params, err := parseBootstrapProposalParams(proposal)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing bootstrap proposal %s: %v", proposal.ProposalID, err)
+		}
+		commands = append(commands, command_usecase.NewCreateBootstrapProposal(block.Height, *params))
+	}
+
+	return commands, nil
+}
+
+// Statuses of the proposals still open to deposits or votes, which are bootstrapped
+const (
+	BOOTSTRAP_PROPOSAL_STATUS_DEPOSIT_PERIOD = "PROPOSAL_STATUS_DEPOSIT_PERIOD"
+	BOOTSTRAP_PROPOSAL_STATUS_VOTING_PERIOD  = "PROPOSAL_STATUS_VOTING_PERIOD"
+)
+
+func parseBootstrapProposalParams(proposal cosmosapp_interface.Proposal) (*model.BootstrapProposalParams, error) {
+	totalDeposit := coin.NewEmptyCoins()
+	for _, deposit := range proposal.TotalDeposit {
+		depositCoin, err := coin.NewCoinFromString(deposit.Denom, deposit.Amount)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing total deposit: %v", err)
+		}
+		totalDeposit = totalDeposit.Add(depositCoin)
+	}
+	submitTime, err := utctime.Parse(time.RFC3339, proposal.SubmitTime)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing submit time: %v", err)
+	}
+	depositEndTime, err := utctime.Parse(time.RFC3339, proposal.DepositEndTime)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing deposit end time: %v", err)
+	}
+
+	params := model.BootstrapProposalParams{
+		ProposalId:     proposal.ProposalID,
+		Type:           proposal.Content.Type,
+		Title:          proposal.Content.Title,
+		Description:    proposal.Content.Description,
+		Status:         proposal.Status,
+		TotalDeposit:   totalDeposit,
+		SubmitTime:     submitTime,
+		DepositEndTime: depositEndTime,
+	}
+	// Voting times are zero before the voting period starts
+	if proposal.Status == BOOTSTRAP_PROPOSAL_STATUS_VOTING_PERIOD {
+		votingStartTime, err := utctime.Parse(time.RFC3339, proposal.VotingStartTime)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing voting start time: %v", err)
+		}
+		votingEndTime, err := utctime.Parse(time.RFC3339, proposal.VotingEndTime)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing voting end time: %v", err)
+		}
+		params.MaybeVotingStartTime = &votingStartTime
+		params.MaybeVotingEndTime = &votingEndTime
+	}
+
+	return &params, nil
+}
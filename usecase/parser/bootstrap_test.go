@@ -0,0 +1,182 @@
+package parser_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	cosmosapp_interface "github.com/crypto-com/chain-indexing/appinterface/cosmosapp"
+	"github.com/crypto-com/chain-indexing/external/utctime"
+	"github.com/crypto-com/chain-indexing/projection/validator/constants"
+	"github.com/crypto-com/chain-indexing/usecase/coin"
+	command_usecase "github.com/crypto-com/chain-indexing/usecase/command"
+	"github.com/crypto-com/chain-indexing/usecase/model"
+	"github.com/crypto-com/chain-indexing/usecase/model/genesis"
+	"github.com/crypto-com/chain-indexing/usecase/parser"
+)
+
+var _ = Describe("Parse Bootstrap", func() {
+	It("should return bootstrap commands from the state at the height before the block", func() {
+		anyBlock := &model.Block{
+			Height: 101,
+			Hash:   "82C25937191D1CF73BE9222CB04CE35B7A1366CC5BB08D9BB9AB457712E4F2D1",
+			Time:   utctime.FromUnixNano(int64(1000000)),
+		}
+		stateHeight := int64(100)
+
+		mockClient := cosmosapp_interface.NewMockClient()
+		mockClient.On("GovParamsAtHeight", stateHeight).Return(&cosmosapp_interface.GovParams{
+			DepositParams: cosmosapp_interface.GovDepositParams{
+				MinDeposit: []cosmosapp_interface.MinDeposit{{
+					Denom:  "basetcro",
+					Amount: "10000000",
+				}},
+				MaxDepositPeriod: "43200s",
+			},
+			VotingParams: cosmosapp_interface.GovVotingParams{
+				VotingPeriod: "43200s",
+			},
+			TallyParams: cosmosapp_interface.GovTallyParams{
+				Quorum:        "0.334000000000000000",
+				Threshold:     "0.500000000000000000",
+				VetoThreshold: "0.334000000000000000",
+			},
+		}, nil)
+		mockClient.On("ValidatorsAtHeight", stateHeight).Return([]cosmosapp_interface.Validator{{
+			OperatorAddress: "tcrocncl1q435860mlxc8954ye4v6vghwge8rw5eqpv6hea",
+			ConsensusPubkey: cosmosapp_interface.PubKey{
+				Type: "/cosmos.crypto.ed25519.PubKey",
+				Key:  "npeBO7O/zYRoGCwjTKf04ZBMkvwDWOF5FbiU6t3u2Kc=",
+			},
+			Jailed: false,
+			Status: "BOND_STATUS_BONDED",
+			Tokens: "499500000",
+			Description: cosmosapp_interface.ValidatorDescription{
+				Moniker: "sg42-node",
+			},
+			Commission: cosmosapp_interface.ValidatorCommission{
+				CommissionRates: cosmosapp_interface.ValidatorCommissionRates{
+					Rate:          "0.100000000000000000",
+					MaxRate:       "0.200000000000000000",
+					MaxChangeRate: "0.010000000000000000",
+				},
+			},
+			MinSelfDelegation: "1",
+		}}, nil)
+		mockClient.On(
+			"ValidatorDelegationsAtHeight", "tcrocncl1q435860mlxc8954ye4v6vghwge8rw5eqpv6hea", stateHeight,
+		).Return([]cosmosapp_interface.DelegationResponse{{
+			Delegation: cosmosapp_interface.Delegation{
+				DelegatorAddress: "tcro1q435860mlxc8954ye4v6vghwge8rw5eq5newp7",
+				ValidatorAddress: "tcrocncl1q435860mlxc8954ye4v6vghwge8rw5eqpv6hea",
+				Shares:           "499500000.000000000000000000",
+			},
+			Balance: cosmosapp_interface.DelegationBalance{
+				Denom:  "basetcro",
+				Amount: "499500000",
+			},
+		}}, nil)
+		mockClient.On("BalancesAtHeight", "tcro1q435860mlxc8954ye4v6vghwge8rw5eq5newp7", stateHeight).Return(
+			coin.MustParseCoinsNormalized("1000basetcro"), nil,
+		)
+
+		mockClient.On("ProposalsAtHeight", stateHeight).Return([]cosmosapp_interface.Proposal{{
+			ProposalID: "1",
+			Content: cosmosapp_interface.Content{
+				Type:        "/cosmos.gov.v1beta1.TextProposal",
+				Title:       "Title",
+				Description: "Description",
+			},
+			Status:          "PROPOSAL_STATUS_VOTING_PERIOD",
+			SubmitTime:      "2021-05-11T03:57:46.554829451Z",
+			DepositEndTime:  "2021-05-13T03:57:46.554829451Z",
+			TotalDeposit:    []cosmosapp_interface.TotalDeposit{{Denom: "basetcro", Amount: "10000000"}},
+			VotingStartTime: "2021-05-11T03:57:46.554829451Z",
+			VotingEndTime:   "2021-05-13T03:57:46.554829451Z",
+		}, {
+			ProposalID:      "0",
+			Status:          "PROPOSAL_STATUS_PASSED",
+			SubmitTime:      "2021-05-10T03:57:46.554829451Z",
+			DepositEndTime:  "2021-05-12T03:57:46.554829451Z",
+			VotingStartTime: "2021-05-10T03:57:46.554829451Z",
+			VotingEndTime:   "2021-05-12T03:57:46.554829451Z",
+		}}, nil)
+
+		cmds, err := parser.ParseBootstrapCommands(mockClient, anyBlock, "tcro", "basetcro")
+		Expect(err).To(BeNil())
+		Expect(cmds).To(HaveLen(5))
+		Expect(cmds[0]).To(Equal(command_usecase.NewCreateStateBootstrap(anyBlock.Height, model.StateBootstrapParams{
+			BlockHash: anyBlock.Hash,
+			BlockTime: anyBlock.Time,
+			GovParams: model.BootstrapGovParams{
+				MinDeposit:       coin.MustParseCoinsNormalized("10000000basetcro"),
+				MaxDepositPeriod: "43200s",
+				VotingPeriod:     "43200s",
+				Quorum:           "0.334000000000000000",
+				Threshold:        "0.500000000000000000",
+				VetoThreshold:    "0.334000000000000000",
+			},
+		})))
+		Expect(cmds[1]).To(Equal(command_usecase.NewCreateBootstrapValidator(
+			anyBlock.Height,
+			genesis.CreateGenesisValidatorParams{
+				Status: constants.BONDED,
+				Description: model.ValidatorDescription{
+					Moniker: "sg42-node",
+				},
+				Commission: model.ValidatorCommission{
+					Rate:          "0.100000000000000000",
+					MaxRate:       "0.200000000000000000",
+					MaxChangeRate: "0.010000000000000000",
+				},
+				MinSelfDelegation: "1",
+				DelegatorAddress:  "tcro1q435860mlxc8954ye4v6vghwge8rw5eq5newp7",
+				ValidatorAddress:  "tcrocncl1q435860mlxc8954ye4v6vghwge8rw5eqpv6hea",
+				TendermintPubkey:  "npeBO7O/zYRoGCwjTKf04ZBMkvwDWOF5FbiU6t3u2Kc=",
+				Amount:            coin.MustParseCoinNormalized("499500000basetcro"),
+				Jailed:            false,
+			},
+		)))
+		Expect(cmds[2]).To(Equal(command_usecase.NewCreateBootstrapDelegation(
+			anyBlock.Height,
+			model.BootstrapDelegationParams{
+				DelegatorAddress: "tcro1q435860mlxc8954ye4v6vghwge8rw5eq5newp7",
+				ValidatorAddress: "tcrocncl1q435860mlxc8954ye4v6vghwge8rw5eqpv6hea",
+				Shares:           "499500000.000000000000000000",
+				Amount:           coin.MustParseCoinNormalized("499500000basetcro"),
+			},
+		)))
+		Expect(cmds[3]).To(Equal(command_usecase.NewCreateBootstrapAccount(
+			anyBlock.Height,
+			model.BootstrapAccountParams{
+				Address: "tcro1q435860mlxc8954ye4v6vghwge8rw5eq5newp7",
+				Balance: coin.MustParseCoinsNormalized("1000basetcro"),
+			},
+		)))
+		votingTime := utctime.MustParse(time.RFC3339, "2021-05-11T03:57:46.554829451Z")
+		votingEndTime := utctime.MustParse(time.RFC3339, "2021-05-13T03:57:46.554829451Z")
+		Expect(cmds[4]).To(Equal(command_usecase.NewCreateBootstrapProposal(
+			anyBlock.Height,
+			model.BootstrapProposalParams{
+				ProposalId:           "1",
+				Type:                 "/cosmos.gov.v1beta1.TextProposal",
+				Title:                "Title",
+				Description:          "Description",
+				Status:               "PROPOSAL_STATUS_VOTING_PERIOD",
+				TotalDeposit:         coin.MustParseCoinsNormalized("10000000basetcro"),
+				SubmitTime:           votingTime,
+				DepositEndTime:       votingEndTime,
+				MaybeVotingStartTime: &votingTime,
+				MaybeVotingEndTime:   &votingEndTime,
+			},
+		)))
+	})
+
+	It("should return error when the start height is not greater than 1", func() {
+		mockClient := cosmosapp_interface.NewMockClient()
+
+		_, err := parser.ParseBootstrapCommands(mockClient, &model.Block{Height: 1}, "tcro", "basetcro")
+		Expect(err).NotTo(BeNil())
+	})
+})
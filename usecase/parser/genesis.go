@@ -28,17 +28,7 @@ func ParseGenesisCommands(
 		}
 	}
 	for _, validator := range rawGenesis.AppState.Staking.Validators {
-		var status constants.Status
-		switch validator.Status {
-		case "BOND_STATUS_UNSPECIFIED":
-			status = constants.INACTIVE
-		case "BOND_STATUS_BONDED":
-			status = constants.BONDED
-		case "BOND_STATUS_UNBONDING":
-			status = constants.UNBONDING
-		case "BOND_STATUS_UNBONDED":
-			status = constants.UNBONDED
-		}
+		status := parseBondStatus(validator.Status)
 		amountInt, parseAmountIntOk := coin.NewIntFromString(validator.Tokens)
 		if !parseAmountIntOk {
 			return nil, errors.New("error parsing genesis validator amount")
@@ -77,3 +67,18 @@ func ParseGenesisCommands(
 	}
 	return commands, nil
 }
+
+func parseBondStatus(bondStatus string) constants.Status {
+	var status constants.Status
+	switch bondStatus {
+	case "BOND_STATUS_UNSPECIFIED":
+		status = constants.INACTIVE
+	case "BOND_STATUS_BONDED":
+		status = constants.BONDED
+	case "BOND_STATUS_UNBONDING":
+		status = constants.UNBONDING
+	case "BOND_STATUS_UNBONDED":
+		status = constants.UNBONDED
+	}
+	return status
+}
@@ -39,7 +39,7 @@ var _ = Describe("ParseMsgCommands", func() {
 
 			expectedInnerMsg := `{
 				"name": "MsgSendCreated",
-				"version": 1,
+				"version": 2,
 				"height": 113382,
 				"uuid": "{UUID}",
 				"msgName": "MsgSend",
@@ -52,6 +52,28 @@ var _ = Describe("ParseMsgCommands", func() {
 						"denom": "basetcro",
 						"amount": "100000000"
 					}
+				],
+				"coinSpent": [
+					{
+						"spender": "tcro1vurfhqf0j2jgfpjahlja6g6uq2ts2r60swm2d9",
+						"amount": [
+							{
+								"denom": "basetcro",
+								"amount": "100000000"
+							}
+						]
+					}
+				],
+				"coinReceived": [
+					{
+						"receiver": "tcro1a93yfnsc3x7m0m445cjsvee2n7qz9c0purlzwq",
+						"amount": [
+							{
+								"denom": "basetcro",
+								"amount": "100000000"
+							}
+						]
+					}
 				]
 			}`
 
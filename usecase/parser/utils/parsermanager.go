@@ -2,6 +2,7 @@ package utils
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/crypto-com/chain-indexing/entity/command"
 	applogger "github.com/crypto-com/chain-indexing/external/logger"
@@ -10,9 +11,10 @@ import (
 )
 
 type CosmosParserManager struct {
-	store  map[CosmosParserKey]BlockHeightToCosmosParserMap
-	logger applogger.Logger
-	config CosmosParserManagerConfig
+	store      map[CosmosParserKey]BlockHeightToCosmosParserMap
+	parserSets map[string]CosmosParserSet
+	logger     applogger.Logger
+	config     CosmosParserManagerConfig
 }
 
 type CosmosParserKey string
@@ -23,8 +25,19 @@ type BlockHeightToCosmosParserMap map[ParserBlockHeight]CosmosParser
 
 type CosmosParserManagerConfig struct {
 	CosmosVersionBlockHeight
+	Eras []CosmosParserEra
 }
 
+// CosmosParserEra is a block height range of the chain starting at StartHeight and parsed with the named parser set
+// until the start of the next era
+type CosmosParserEra struct {
+	StartHeight ParserBlockHeight
+	ParserSet   string
+}
+
+// CosmosParserSet registers all the parsers of a named set to be enabled from a starting block height
+type CosmosParserSet func(manager *CosmosParserManager, fromHeight ParserBlockHeight)
+
 type CosmosVersionBlockHeight struct {
 	V0_42_7 ParserBlockHeight
 }
@@ -50,9 +63,10 @@ type CosmosParserParams struct {
 
 func NewCosmosParserManager(params CosmosParserManagerParams) *CosmosParserManager {
 	cpm := &CosmosParserManager{
-		store:  make(map[CosmosParserKey]BlockHeightToCosmosParserMap),
-		logger: params.Logger,
-		config: params.Config,
+		store:      make(map[CosmosParserKey]BlockHeightToCosmosParserMap),
+		parserSets: make(map[string]CosmosParserSet),
+		logger:     params.Logger,
+		config:     params.Config,
 	}
 
 	return cpm
@@ -71,6 +85,31 @@ func (cpm *CosmosParserManager) RegisterParser(name CosmosParserKey, fromHeight
 	cpm.store[name][fromHeight] = parser
 }
 
+// RegisterParserSet register a named parser set which can be enabled by the eras
+func (cpm *CosmosParserManager) RegisterParserSet(name string, parserSet CosmosParserSet) {
+	cpm.parserSets[name] = parserSet
+}
+
+// EnableEraParserSets register the parsers of each era's parser set from the era start height, in the order of the
+// start height. Returns error if an era refers to a parser set not registered
+func (cpm *CosmosParserManager) EnableEraParserSets() error {
+	eras := make([]CosmosParserEra, len(cpm.config.Eras))
+	copy(eras, cpm.config.Eras)
+	sort.SliceStable(eras, func(i, j int) bool {
+		return eras[i].StartHeight < eras[j].StartHeight
+	})
+
+	for _, era := range eras {
+		parserSet, ok := cpm.parserSets[era.ParserSet]
+		if !ok {
+			return fmt.Errorf("unknown parser set `%s` of era starting at height %d", era.ParserSet, era.StartHeight)
+		}
+		parserSet(cpm, era.StartHeight)
+	}
+
+	return nil
+}
+
 // GetParser return a cosmos message parser from a registered key and a specific block height.
 // Panic if the key is not found in the registered store
 func (cpm *CosmosParserManager) GetParser(name CosmosParserKey, blockHeight ParserBlockHeight) CosmosParser {
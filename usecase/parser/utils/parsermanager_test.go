@@ -111,4 +111,56 @@ var _ = Describe("ParseManager", func() {
 
 		Expect(cmds[0].Name()).To(Equal("commandB"))
 	})
+
+	It("should enable parser set of each era from its start height", func() {
+
+		pm := utils.NewCosmosParserManager(
+			utils.CosmosParserManagerParams{
+				Logger: nil,
+				Config: utils.CosmosParserManagerConfig{
+					Eras: []utils.CosmosParserEra{
+						{StartHeight: 20, ParserSet: "setA"},
+						{StartHeight: 10, ParserSet: "setB"},
+					},
+				},
+			},
+		)
+
+		parserKey := utils.CosmosParserKey("parser")
+
+		pm.RegisterParser(parserKey, 0, test.ParserA)
+		pm.RegisterParserSet("setA", func(manager *utils.CosmosParserManager, fromHeight utils.ParserBlockHeight) {
+			manager.RegisterParser(parserKey, fromHeight, test.ParserA)
+		})
+		pm.RegisterParserSet("setB", func(manager *utils.CosmosParserManager, fromHeight utils.ParserBlockHeight) {
+			manager.RegisterParser(parserKey, fromHeight, test.ParserB)
+		})
+
+		Expect(pm.EnableEraParserSets()).To(Succeed())
+
+		cmds, _ := pm.GetParser(parserKey, 9)(utils.CosmosParserParams{})
+		Expect(cmds[0].Name()).To(Equal("commandA"))
+
+		cmds, _ = pm.GetParser(parserKey, 10)(utils.CosmosParserParams{})
+		Expect(cmds[0].Name()).To(Equal("commandB"))
+
+		cmds, _ = pm.GetParser(parserKey, 20)(utils.CosmosParserParams{})
+		Expect(cmds[0].Name()).To(Equal("commandA"))
+	})
+
+	It("should return error when an era refers to an unknown parser set", func() {
+
+		pm := utils.NewCosmosParserManager(
+			utils.CosmosParserManagerParams{
+				Logger: nil,
+				Config: utils.CosmosParserManagerConfig{
+					Eras: []utils.CosmosParserEra{
+						{StartHeight: 10, ParserSet: "unknown"},
+					},
+				},
+			},
+		)
+
+		Expect(pm.EnableEraParserSets()).NotTo(Succeed())
+	})
 })
@@ -0,0 +1,310 @@
+package syncstrategy
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/crypto-com/chain-indexing/entity/command"
+	applogger "github.com/crypto-com/chain-indexing/external/logger"
+)
+
+var _ Strategy = &Adaptive{}
+
+const DEFAULT_ADAPTIVE_MIN_CONCURRENCY = 1
+const DEFAULT_ADAPTIVE_MAX_CONCURRENCY = 50
+
+// Concurrency is halved on worker error
+const ADAPTIVE_ERROR_DECREASE_FACTOR = 0.5
+
+// Concurrency is reduced when latency rises above the tolerated multiple of the baseline latency
+const ADAPTIVE_LATENCY_DECREASE_FACTOR = 0.8
+const ADAPTIVE_LATENCY_TOLERANCE_FACTOR = 2.0
+
+const ADAPTIVE_LATENCY_EWMA_WEIGHT = 0.1
+const ADAPTIVE_BASELINE_LATENCY_DRIFT_WEIGHT = 0.01
+
+// Adaptive sync strategy keeps a sliding pipeline of concurrent block syncs and adjusts its
+// concurrency with additive-increase/multiplicative-decrease (AIMD) on observed worker latency and
+// errors.
+//
+// Blocks are synced out of order and buffered until all the blocks before them are completed. A slow
+// block therefore does not stall the rest of the pipeline, and blocks completed before a failing
+// block are kept and returned on the next Sync call.
+//
+// Adaptive is stateful across Sync calls. Never call Sync from multiple goroutines.
+type Adaptive struct {
+	logger applogger.Logger
+
+	minConcurrency int
+	maxConcurrency int
+	// Maximum number of blocks ahead of the next expected block that can be in-flight or buffered
+	maxLookahead int64
+
+	concurrency        float64
+	latencyEWMA        float64
+	baselineLatency    float64
+	lastDecreaseSeq    int64
+	nextDispatchSeq    int64
+	nextDispatchHeight int64
+	nextExpectedHeight int64
+	inFlightHeights    map[int64]bool
+	completedBlocks    map[int64][]command.Command
+	workerResultCh     chan adaptiveWorkerResult
+}
+
+type adaptiveWorkerResult struct {
+	height      int64
+	dispatchSeq int64
+	commands    []command.Command
+	err         error
+	duration    time.Duration
+}
+
+func NewAdaptive(logger applogger.Logger, minConcurrency int, maxConcurrency int) *Adaptive {
+	if minConcurrency < 1 {
+		minConcurrency = DEFAULT_ADAPTIVE_MIN_CONCURRENCY
+	}
+	if maxConcurrency < minConcurrency {
+		maxConcurrency = minConcurrency
+	}
+
+	return &Adaptive{
+		logger: logger.WithFields(applogger.LogFields{
+			"module":         "AdaptiveStrategy",
+			"minConcurrency": minConcurrency,
+			"maxConcurrency": maxConcurrency,
+		}),
+
+		minConcurrency: minConcurrency,
+		maxConcurrency: maxConcurrency,
+		maxLookahead:   int64(maxConcurrency) * 2,
+
+		concurrency:     float64(minConcurrency),
+		lastDecreaseSeq: -1,
+		inFlightHeights: make(map[int64]bool),
+		completedBlocks: make(map[int64][]command.Command),
+		// Buffered for the maximum number of in-flight workers so that workers never block when their
+		// result is not consumed until the next Sync call
+		workerResultCh: make(chan adaptiveWorkerResult, maxConcurrency),
+	}
+}
+
+// Concurrency returns the current number of concurrent block syncs
+func (adaptive *Adaptive) Concurrency() int {
+	return int(adaptive.concurrency)
+}
+
+func (adaptive *Adaptive) Sync(
+	ctx context.Context,
+	currentHeight int64,
+	latestHeight int64,
+	worker SyncBlockWorker,
+) ([][]command.Command, SyncedHeight, error) {
+	adaptive.resetTo(currentHeight)
+
+	logger := adaptive.logger.WithFields(applogger.LogFields{
+		"beginHeight":  currentHeight,
+		"latestHeight": latestHeight,
+	})
+
+	for {
+		adaptive.collectCompletedResults(logger)
+
+		syncedHeight := adaptive.contiguousCompletedHeight(currentHeight, latestHeight)
+		// Return once a batch of blocks is ready so that the blocks are handled while the pipeline keeps
+		// syncing the following blocks
+		if syncedHeight == latestHeight || syncedHeight-currentHeight+1 >= int64(adaptive.maxConcurrency) {
+			return adaptive.export(currentHeight, syncedHeight), syncedHeight, nil
+		}
+
+		adaptive.dispatch(ctx, latestHeight, worker)
+
+		var result adaptiveWorkerResult
+		select {
+		case <-ctx.Done():
+			// In-flight blocks are kept and collected on next Sync call
+			return nil, currentHeight - 1, ctx.Err()
+		case result = <-adaptive.workerResultCh:
+		}
+		if err := adaptive.handleResult(logger, result); err != nil {
+			// Blocks completed before the failing block are kept and returned. The failing block is
+			// re-dispatched on next Sync call.
+			adaptive.collectCompletedResults(logger)
+			syncedHeight = adaptive.contiguousCompletedHeight(currentHeight, latestHeight)
+			if syncedHeight >= currentHeight {
+				return adaptive.export(currentHeight, syncedHeight), syncedHeight, nil
+			}
+			return nil, currentHeight - 1, err
+		}
+	}
+}
+
+// resetTo discards buffered blocks before the provided height and rewinds the dispatch cursor when
+// the caller restarts from an earlier height
+func (adaptive *Adaptive) resetTo(currentHeight int64) {
+	if currentHeight == adaptive.nextExpectedHeight {
+		return
+	}
+
+	for height := range adaptive.completedBlocks {
+		if height < currentHeight {
+			delete(adaptive.completedBlocks, height)
+		}
+	}
+	adaptive.nextExpectedHeight = currentHeight
+	adaptive.nextDispatchHeight = currentHeight
+}
+
+func (adaptive *Adaptive) dispatch(ctx context.Context, latestHeight int64, worker SyncBlockWorker) {
+	if ctx.Err() != nil {
+		return
+	}
+
+	maxDispatchHeight := adaptive.nextExpectedHeight + adaptive.maxLookahead - 1
+	if maxDispatchHeight > latestHeight {
+		maxDispatchHeight = latestHeight
+	}
+
+	for len(adaptive.inFlightHeights) < int(adaptive.concurrency) {
+		for adaptive.nextDispatchHeight <= maxDispatchHeight && adaptive.isTracked(adaptive.nextDispatchHeight) {
+			adaptive.nextDispatchHeight += 1
+		}
+		if adaptive.nextDispatchHeight > maxDispatchHeight {
+			return
+		}
+
+		height := adaptive.nextDispatchHeight
+		dispatchSeq := adaptive.nextDispatchSeq
+		adaptive.inFlightHeights[height] = true
+		adaptive.nextDispatchHeight += 1
+		adaptive.nextDispatchSeq += 1
+
+		go func() {
+			startTime := time.Now()
+			commands, err := worker(ctx, height)
+			adaptive.workerResultCh <- adaptiveWorkerResult{
+				height:      height,
+				dispatchSeq: dispatchSeq,
+				commands:    commands,
+				err:         err,
+				duration:    time.Since(startTime),
+			}
+		}()
+	}
+}
+
+func (adaptive *Adaptive) isTracked(height int64) bool {
+	if adaptive.inFlightHeights[height] {
+		return true
+	}
+	_, completed := adaptive.completedBlocks[height]
+	return completed
+}
+
+// collectCompletedResults handles all the worker results available without blocking. Failed blocks
+// are re-dispatched later.
+func (adaptive *Adaptive) collectCompletedResults(logger applogger.Logger) {
+	for {
+		select {
+		case result := <-adaptive.workerResultCh:
+			_ = adaptive.handleResult(logger, result)
+		default:
+			return
+		}
+	}
+}
+
+func (adaptive *Adaptive) handleResult(logger applogger.Logger, result adaptiveWorkerResult) error {
+	delete(adaptive.inFlightHeights, result.height)
+
+	if result.err != nil {
+		logger.Errorf("received error from sync block worker #%d: %v", result.height, result.err)
+		adaptive.decrease(result.dispatchSeq, ADAPTIVE_ERROR_DECREASE_FACTOR)
+		adaptive.rewindDispatch(result.height)
+		return fmt.Errorf("error syncing block #%d: %v", result.height, result.err)
+	}
+
+	if result.height >= adaptive.nextExpectedHeight {
+		adaptive.completedBlocks[result.height] = result.commands
+	}
+	adaptive.observeLatency(result.dispatchSeq, result.duration)
+
+	return nil
+}
+
+func (adaptive *Adaptive) rewindDispatch(height int64) {
+	if height >= adaptive.nextExpectedHeight && height < adaptive.nextDispatchHeight {
+		adaptive.nextDispatchHeight = height
+	}
+}
+
+func (adaptive *Adaptive) observeLatency(dispatchSeq int64, duration time.Duration) {
+	latency := float64(duration)
+	if adaptive.latencyEWMA == 0 {
+		adaptive.latencyEWMA = latency
+	} else {
+		adaptive.latencyEWMA = ADAPTIVE_LATENCY_EWMA_WEIGHT*latency +
+			(1-ADAPTIVE_LATENCY_EWMA_WEIGHT)*adaptive.latencyEWMA
+	}
+
+	if adaptive.baselineLatency == 0 || adaptive.latencyEWMA < adaptive.baselineLatency {
+		adaptive.baselineLatency = adaptive.latencyEWMA
+	} else {
+		// Drift the baseline slowly so that a single lucky fast round does not pin it forever
+		adaptive.baselineLatency = ADAPTIVE_BASELINE_LATENCY_DRIFT_WEIGHT*adaptive.latencyEWMA +
+			(1-ADAPTIVE_BASELINE_LATENCY_DRIFT_WEIGHT)*adaptive.baselineLatency
+	}
+
+	if adaptive.latencyEWMA > adaptive.baselineLatency*ADAPTIVE_LATENCY_TOLERANCE_FACTOR {
+		adaptive.decrease(dispatchSeq, ADAPTIVE_LATENCY_DECREASE_FACTOR)
+		return
+	}
+
+	// Additive increase: grows by one after a full round of successful block syncs
+	adaptive.concurrency = math.Min(
+		adaptive.concurrency+1/adaptive.concurrency,
+		float64(adaptive.maxConcurrency),
+	)
+}
+
+// decrease reduces the concurrency at most once per round: results of blocks dispatched before the
+// last decrease do not decrease it again
+func (adaptive *Adaptive) decrease(dispatchSeq int64, factor float64) {
+	if dispatchSeq <= adaptive.lastDecreaseSeq {
+		return
+	}
+	adaptive.lastDecreaseSeq = adaptive.nextDispatchSeq - 1
+
+	adaptive.concurrency = math.Max(adaptive.concurrency*factor, float64(adaptive.minConcurrency))
+	adaptive.logger.Infof("decreased concurrency to %d", adaptive.Concurrency())
+}
+
+// contiguousCompletedHeight returns the highest height up to which all blocks from currentHeight are
+// completed. Returns currentHeight - 1 when the current block is not completed.
+func (adaptive *Adaptive) contiguousCompletedHeight(currentHeight int64, latestHeight int64) int64 {
+	height := currentHeight
+	for height <= latestHeight {
+		if _, completed := adaptive.completedBlocks[height]; !completed {
+			break
+		}
+		height += 1
+	}
+	return height - 1
+}
+
+func (adaptive *Adaptive) export(beginHeight int64, endHeight int64) [][]command.Command {
+	blocksCommands := make([][]command.Command, 0, endHeight-beginHeight+1)
+	for height := beginHeight; height <= endHeight; height += 1 {
+		commands := adaptive.completedBlocks[height]
+		if commands == nil {
+			commands = make([]command.Command, 0)
+		}
+		blocksCommands = append(blocksCommands, commands)
+		delete(adaptive.completedBlocks, height)
+	}
+	adaptive.nextExpectedHeight = endHeight + 1
+
+	return blocksCommands
+}
@@ -0,0 +1,144 @@
+package syncstrategy_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/crypto-com/chain-indexing/entity/command"
+	entity_event "github.com/crypto-com/chain-indexing/entity/event"
+	. "github.com/crypto-com/chain-indexing/external/logger/test"
+	"github.com/crypto-com/chain-indexing/usecase/syncstrategy"
+)
+
+var _ = Describe("Adaptive", func() {
+	successWorker := func(_ context.Context, _ int64) ([]command.Command, error) {
+		<-time.After(time.Millisecond)
+		return []command.Command{}, nil
+	}
+	failureWorker := func(_ context.Context, _ int64) ([]command.Command, error) {
+		return nil, errors.New("any error")
+	}
+
+	It("should return blocks commands in height order", func() {
+		adaptive := syncstrategy.NewAdaptive(NewFakeLogger(), 4, 4)
+
+		worker := func(_ context.Context, blockHeight int64) ([]command.Command, error) {
+			// Later blocks complete first
+			<-time.After(time.Duration(5-blockHeight) * 5 * time.Millisecond)
+			return []command.Command{fakeCommand{blockHeight}}, nil
+		}
+		blocksCommands, syncedHeight, err := adaptive.Sync(context.Background(), 1, 4, worker)
+
+		Expect(err).To(BeNil())
+		Expect(syncedHeight).To(Equal(int64(4)))
+		Expect(blocksCommands).To(Equal([][]command.Command{
+			{fakeCommand{1}},
+			{fakeCommand{2}},
+			{fakeCommand{3}},
+			{fakeCommand{4}},
+		}))
+	})
+
+	It("should keep blocks synced before a failing block and retry the failing block", func() {
+		adaptive := syncstrategy.NewAdaptive(NewFakeLogger(), 4, 4)
+
+		var mutex sync.Mutex
+		attempts := make(map[int64]int)
+		worker := func(_ context.Context, blockHeight int64) ([]command.Command, error) {
+			mutex.Lock()
+			attempts[blockHeight] += 1
+			attempt := attempts[blockHeight]
+			mutex.Unlock()
+
+			if blockHeight == 3 && attempt == 1 {
+				return nil, errors.New("any error")
+			}
+			return []command.Command{fakeCommand{blockHeight}}, nil
+		}
+
+		currentHeight := int64(1)
+		for currentHeight <= 4 {
+			blocksCommands, syncedHeight, err := adaptive.Sync(context.Background(), currentHeight, 4, worker)
+			if err != nil {
+				continue
+			}
+			for i, commands := range blocksCommands {
+				Expect(commands).To(Equal([]command.Command{fakeCommand{currentHeight + int64(i)}}))
+			}
+			currentHeight = syncedHeight + 1
+		}
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		Expect(attempts).To(Equal(map[int64]int{1: 1, 2: 1, 3: 2, 4: 1}))
+	})
+
+	It("should return error when the first block fails", func() {
+		adaptive := syncstrategy.NewAdaptive(NewFakeLogger(), 1, 1)
+
+		_, syncedHeight, err := adaptive.Sync(context.Background(), 10, 20, failureWorker)
+
+		Expect(err).NotTo(BeNil())
+		Expect(syncedHeight).To(Equal(int64(9)))
+	})
+
+	It("should return context error when the context is cancelled", func() {
+		adaptive := syncstrategy.NewAdaptive(NewFakeLogger(), 1, 1)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, syncedHeight, err := adaptive.Sync(ctx, 10, 20, successWorker)
+
+		Expect(err).To(Equal(context.Canceled))
+		Expect(syncedHeight).To(Equal(int64(9)))
+	})
+
+	It("should increase concurrency when blocks are synced successfully", func() {
+		adaptive := syncstrategy.NewAdaptive(NewFakeLogger(), 1, 8)
+
+		currentHeight := int64(0)
+		for currentHeight < 200 {
+			_, syncedHeight, err := adaptive.Sync(context.Background(), currentHeight, 200, successWorker)
+			Expect(err).To(BeNil())
+			currentHeight = syncedHeight + 1
+		}
+
+		Expect(adaptive.Concurrency()).To(Equal(8))
+	})
+
+	It("should decrease concurrency on error", func() {
+		adaptive := syncstrategy.NewAdaptive(NewFakeLogger(), 1, 8)
+
+		currentHeight := int64(0)
+		for currentHeight < 200 {
+			_, syncedHeight, _ := adaptive.Sync(context.Background(), currentHeight, 200, successWorker)
+			currentHeight = syncedHeight + 1
+		}
+		Expect(adaptive.Concurrency()).To(Equal(8))
+
+		_, _, err := adaptive.Sync(context.Background(), currentHeight, currentHeight, failureWorker)
+		Expect(err).NotTo(BeNil())
+		Expect(adaptive.Concurrency()).To(Equal(4))
+	})
+})
+
+type fakeCommand struct {
+	height int64
+}
+
+func (command fakeCommand) Name() string {
+	return "FakeCommand"
+}
+
+func (command fakeCommand) Version() int {
+	return 1
+}
+
+func (command fakeCommand) Exec() (entity_event.Event, error) {
+	return nil, nil
+}
@@ -1,13 +1,22 @@
 package syncstrategy
 
 import (
+	"context"
+
 	"github.com/crypto-com/chain-indexing/entity/command"
 )
 
 type Strategy interface {
-	Sync(currentHeight int64, latestHeight int64, worker SyncBlockWorker) ([][]command.Command, SyncedHeight, error)
+	// Sync blocks starting from currentHeight. Returns the context error when the context is cancelled before any
+	// block is synced.
+	Sync(
+		ctx context.Context,
+		currentHeight int64,
+		latestHeight int64,
+		worker SyncBlockWorker,
+	) ([][]command.Command, SyncedHeight, error)
 }
 
-type SyncBlockWorker = func(blockHeight int64) ([]command.Command, error)
+type SyncBlockWorker = func(ctx context.Context, blockHeight int64) ([]command.Command, error)
 
 type SyncedHeight = int64
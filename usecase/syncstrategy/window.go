@@ -29,6 +29,7 @@ func NewWindow(logger applogger.Logger, size int) *Window {
 }
 
 func (window *Window) Sync(
+	ctx context.Context,
 	currentHeight int64,
 	latestHeight int64,
 	worker SyncBlockWorker,
@@ -47,14 +48,14 @@ func (window *Window) Sync(
 	})
 	logger.Debug("spawning goroutines for sync block workers")
 
-	workersErrGroup, _ := errgroup.WithContext(context.Background())
+	workersErrGroup, workersCtx := errgroup.WithContext(ctx)
 
 	commandWindow := newUnsafeCommandWindow(beginHeight, endHeight)
 
 	for height := beginHeight; height <= endHeight; height += 1 {
 		height := height
 		workersErrGroup.Go(func() error {
-			commands, err := worker(height)
+			commands, err := worker(workersCtx, height)
 			if err != nil {
 				logger.Errorf("received error from sync block worker #%d: %v", height, err)
 				return err